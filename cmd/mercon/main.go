@@ -11,15 +11,42 @@ import (
 
 	coreConfig "github.com/metfin/core/config"
 	"github.com/metfin/core/database"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wnt/mercon/internal/adminapi"
+	querygrpc "github.com/wnt/mercon/internal/api/grpc"
 	"github.com/wnt/mercon/internal/config"
+	analyticsdb "github.com/wnt/mercon/internal/database"
+	"github.com/wnt/mercon/internal/database/migrations"
+	"github.com/wnt/mercon/internal/grpcapi"
+	"github.com/wnt/mercon/internal/leader"
 	"github.com/wnt/mercon/internal/logger"
+	"github.com/wnt/mercon/internal/meteorametrics"
+	"github.com/wnt/mercon/internal/models"
 	"github.com/wnt/mercon/internal/queue"
 	"github.com/wnt/mercon/internal/rpc"
+	"github.com/wnt/mercon/internal/services"
 	"github.com/wnt/mercon/internal/worker"
+	"gorm.io/gorm"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "backfill-prices" {
+		if err := runBackfillPrices(); err != nil {
+			fmt.Fprintf(os.Stderr, "backfill-prices: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -33,7 +60,7 @@ func main() {
 
 	// Initialize databases
 	log.Info().Msg("Initializing database connections")
-	
+
 	// Initialize chain database (for raw transactions)
 	chainDBConfig := &coreConfig.DatabaseConfig{
 		Host:     cfg.ChainDBHost,
@@ -43,9 +70,9 @@ func main() {
 		Port:     cfg.ChainDBPort,
 		SSLMode:  cfg.ChainDBSSLMode,
 	}
-	
+
 	database.InitDB(chainDBConfig)
-	
+
 	// Run raw chain migrations
 	if err := database.RunRawMigrations(); err != nil {
 		log.Error().Err(err).Msg("Failed to run raw chain migrations")
@@ -61,12 +88,96 @@ func main() {
 	defer queueClient.Close()
 
 	// Initialize RPC pool
-	log.Info().Int("endpoints", len(cfg.RPCEndpoints)).Msg("Initializing RPC pool")
-	rpcPool := rpc.NewPool(cfg.RPCEndpoints, log)
+	log.Info().
+		Int("endpoints", len(cfg.RPCEndpoints)).
+		Str("scheduler_mode", cfg.RPCSchedulerMode).
+		Bool("sticky_wallets", cfg.RPCStickyWallets).
+		Msg("Initializing RPC pool")
+
+	poolOpts := []rpc.PoolOption{rpc.WithSchedulerMode(rpc.SchedulerMode(cfg.RPCSchedulerMode))}
+	if cfg.RPCStickyWallets {
+		poolOpts = append(poolOpts, rpc.WithStickyWallets())
+	}
+	rpcPool := rpc.NewPool(cfg.RPCEndpoints, log, poolOpts...)
+
+	// Initialize analytics database and data enricher
+	log.Info().Msg("Connecting to analytics database")
+	analyticsDB, err := analyticsdb.Connect()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to analytics database")
+	}
+	dataEnricher := services.NewMeteoraDataEnricher(analyticsDB)
+
+	// Leader election so only one pod in the fleet reaps stuck wallets or
+	// runs the enrichment sweep at a time. Both are off (nil elector) if
+	// this fails, same "log and continue without it" treatment as the
+	// RPC_VERIFY_QUORUM/Streams cases in worker.NewManager.
+	leaderElector, err := leader.NewElector(cfg.RedisURL, log)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialize leader elector, reaper and enrichment sweep will not run")
+	} else {
+		defer leaderElector.Close()
+	}
 
 	// Initialize worker manager
 	log.Info().Msg("Initializing worker manager")
-	workerManager := worker.NewManager(cfg, queueClient, rpcPool, log)
+	workerManager := worker.NewManager(cfg, queueClient, rpcPool, log, dataEnricher, leaderElector)
+
+	// Initialize the admin/control JSON-RPC API, if configured
+	var adminServer *adminapi.Server
+	if len(cfg.HTTPRPCAPI) > 0 {
+		adminServer = adminapi.NewServer(adminapi.Config{
+			Addr:           cfg.HTTPRPCAddr,
+			EnabledModules: cfg.HTTPRPCAPI,
+			BearerToken:    cfg.HTTPRPCBearerToken,
+		}, logger.WithComponent(log, "adminapi"))
+
+		adminapi.RegisterScraperMethods(adminServer, workerManager)
+		adminapi.RegisterEnricherMethods(adminServer, dataEnricher)
+		adminapi.RegisterAdminMethods(adminServer, workerManager, dataEnricher)
+
+		go func() {
+			if err := adminServer.Start(); err != nil {
+				log.Error().Err(err).Msg("Admin API server stopped")
+			}
+		}()
+	}
+
+	// Initialize the gRPC control plane, if configured
+	var grpcServer *grpcapi.Server
+	if cfg.GRPCAddr != "" {
+		grpcServer = grpcapi.NewServer(grpcapi.Config{
+			Addr:   cfg.GRPCAddr,
+			Tokens: cfg.GRPCTokens,
+		}, queueClient, workerManager, log)
+
+		go func() {
+			if err := grpcServer.Start(); err != nil {
+				log.Error().Err(err).Msg("gRPC control plane stopped")
+			}
+		}()
+	}
+
+	// Initialize the gRPC query API, if configured
+	var queryGRPCServer *querygrpc.Server
+	if cfg.QueryGRPCAddr != "" {
+		queryGRPCServer = querygrpc.NewServer(querygrpc.Config{
+			Addr: cfg.QueryGRPCAddr,
+		}, analyticsDB, rpcPool, workerManager, log)
+
+		go func() {
+			if err := queryGRPCServer.Start(); err != nil {
+				log.Error().Err(err).Msg("gRPC query API stopped")
+			}
+		}()
+	}
+
+	// Register the Meteora pair/position/reward metrics exporter, if enabled
+	if cfg.MeteoraMetricsEnabled {
+		log.Info().Msg("Registering Meteora metrics collector")
+		meteoraCollector := meteorametrics.NewCollector(analyticsDB, meteorametrics.NewInstrumentedMeteoraPubClient(), log)
+		prometheus.MustRegister(meteoraCollector)
+	}
 
 	// Initialize metrics HTTP server
 	mux := http.NewServeMux()
@@ -132,6 +243,20 @@ func main() {
 		log.Error().Err(err).Msg("Failed to shutdown metrics server gracefully")
 	}
 
+	if adminServer != nil {
+		if err := adminServer.Stop(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("Failed to shutdown admin API server gracefully")
+		}
+	}
+
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
+
+	if queryGRPCServer != nil {
+		queryGRPCServer.Stop()
+	}
+
 	// Close database connections
 	if err := database.CloseDB(); err != nil {
 		log.Error().Err(err).Msg("Error closing database connections")
@@ -139,3 +264,82 @@ func main() {
 
 	log.Info().Msg("Mercon shutdown complete")
 }
+
+// runMigrate implements the "mercon migrate up|down|status" subcommand. It
+// connects to the analytics database without running migrations (that's the
+// whole point of ConnectRaw) so down and status can inspect or roll back
+// schema state without Up applying pending migrations first.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: mercon migrate up|down|status")
+	}
+
+	db, err := analyticsdb.ConnectRaw()
+	if err != nil {
+		return fmt.Errorf("failed to connect to analytics database: %w", err)
+	}
+
+	runner := migrations.NewRunner(db)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return runner.Up(ctx)
+	case "down":
+		return runner.Down(ctx)
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("%03d_%s: applied at %s\n", s.Version, s.Name, s.AppliedAt.Format(time.RFC3339))
+			} else {
+				fmt.Printf("%03d_%s: pending\n", s.Version, s.Name)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q (want up, down, or status)", args[0])
+	}
+}
+
+// runBackfillPrices implements the "mercon backfill-prices" subcommand. It
+// walks every transaction PostProcessTransaction already marked
+// "enriched", oldest BlockTime first, and re-runs it through
+// PostProcessTransaction so its swaps/fee claims/liquidity entries get
+// re-stamped with services.PriceHistoryStore's historical price instead of
+// whatever was live at scrape time - the gap request metfin/mercon#chunk9-2
+// closes for transactions enriched going forward, and this command closes
+// for transactions already enriched against today's price before that
+// change landed.
+func runBackfillPrices() error {
+	db, err := analyticsdb.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to analytics database: %w", err)
+	}
+
+	enricher := services.NewMeteoraDataEnricher(db)
+
+	var total, failed int
+	var txs []models.Transaction
+	err = db.Where("enrichment_status = ?", "enriched").
+		Order("block_time ASC").
+		FindInBatches(&txs, 500, func(tx *gorm.DB, batch int) error {
+			for i := range txs {
+				total++
+				if err := enricher.PostProcessTransaction(&txs[i]); err != nil {
+					failed++
+					fmt.Printf("backfill-prices: %s: %v\n", txs[i].Signature, err)
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to walk already-enriched transactions: %w", err)
+	}
+
+	fmt.Printf("backfill-prices: processed %d transactions, %d failed\n", total, failed)
+	return nil
+}
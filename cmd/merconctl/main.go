@@ -0,0 +1,227 @@
+// Command merconctl is a CLI operator client for the admin JSON-RPC API
+// (internal/adminapi) exposed by cmd/mercon. It wraps the scraper_, queue_
+// and admin_ namespaces in a subcommand tree - workers resize, workers drain,
+// queue requeue-stuck, queue peek, queue purge, and stats - so an operator
+// can change scale or clear a stuck wallet without restarting the process
+// or waiting for the 30s autoscaler tick.
+//
+// There's no "cache invalidate" subcommand: the pair-metadata cache it
+// would target isn't a long-lived, shared instance reachable from the
+// admin API yet (see the note in internal/adminapi/methods.go), so there's
+// nothing for it to call.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", envOr("MERCONCTL_ADDR", "http://localhost:8090"), "Admin API address")
+	token := flag.String("token", os.Getenv("MERCONCTL_TOKEN"), "Admin API bearer token")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	client := &rpcClient{addr: *addr, token: *token}
+
+	var err error
+	switch args[0] {
+	case "workers":
+		err = runWorkers(client, args[1:])
+	case "queue":
+		err = runQueue(client, args[1:])
+	case "stats":
+		err = runStats(client)
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "merconctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: merconctl [-addr <url>] [-token <token>] <command> [args]
+
+Commands:
+  workers resize <n>              Scale the worker pool to exactly n workers
+  workers drain <worker-id>       Stop and remove a worker once it finishes its current wallet
+  queue requeue-stuck <duration>  Requeue wallets in flight longer than duration (e.g. 15m)
+  queue peek <limit>              List up to limit queued wallets without removing them
+  queue purge <address>           Remove a wallet from the queue, in-flight, and paused state
+  queue dlq list                  List wallets parked in the dead-letter queue
+  queue dlq retry <address>       Requeue a wallet out of the DLQ with a clean requeue count
+  queue dlq purge                 Clear every wallet out of the DLQ without requeuing them
+  stats                           Print manager statistics as JSON`)
+}
+
+func runWorkers(c *rpcClient, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: workers resize <n> | workers drain <worker-id>")
+	}
+	switch args[0] {
+	case "resize":
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid worker count %q: %w", args[1], err)
+		}
+		_, err = c.call("scraper_resizeWorkers", map[string]interface{}{"count": n})
+		return err
+	case "drain":
+		_, err := c.call("scraper_drainWorker", map[string]interface{}{"id": args[1]})
+		return err
+	default:
+		return fmt.Errorf("unknown workers subcommand %q", args[0])
+	}
+}
+
+func runQueue(c *rpcClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: queue requeue-stuck <duration> | queue peek <limit> | queue purge <address> | queue dlq list|retry|purge")
+	}
+
+	if args[0] == "dlq" {
+		return runQueueDLQ(c, args[1:])
+	}
+
+	if len(args) != 2 {
+		return fmt.Errorf("usage: queue requeue-stuck <duration> | queue peek <limit> | queue purge <address>")
+	}
+	switch args[0] {
+	case "requeue-stuck":
+		d, err := time.ParseDuration(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", args[1], err)
+		}
+		_, err = c.call("queue_requeueStuck", map[string]interface{}{"olderThanMinutes": int(d.Minutes())})
+		return err
+	case "peek":
+		limit, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid limit %q: %w", args[1], err)
+		}
+		result, err := c.call("queue_peek", map[string]interface{}{"limit": limit})
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	case "purge":
+		_, err := c.call("queue_purge", map[string]interface{}{"address": args[1]})
+		return err
+	default:
+		return fmt.Errorf("unknown queue subcommand %q", args[0])
+	}
+}
+
+func runQueueDLQ(c *rpcClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: queue dlq list | queue dlq retry <address> | queue dlq purge")
+	}
+	switch args[0] {
+	case "list":
+		result, err := c.call("queue_listDLQ", nil)
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	case "retry":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: queue dlq retry <address>")
+		}
+		_, err := c.call("queue_retryDLQ", map[string]interface{}{"wallet": args[1]})
+		return err
+	case "purge":
+		_, err := c.call("queue_purgeDLQ", nil)
+		return err
+	default:
+		return fmt.Errorf("unknown queue dlq subcommand %q", args[0])
+	}
+}
+
+func runStats(c *rpcClient) error {
+	result, err := c.call("admin_metrics", nil)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func envOr(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// rpcClient is a minimal JSON-RPC 2.0 client for internal/adminapi.Server.
+type rpcClient struct {
+	addr  string
+	token string
+}
+
+func (c *rpcClient) call(method string, params interface{}) (json.RawMessage, error) {
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.addr, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach admin API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("admin API error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	return rpcResp.Result, nil
+}
@@ -8,10 +8,11 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/joho/godotenv"
 	"github.com/metfin/core/parsers/damm"
 	"github.com/metfin/core/parsers/dlmm"
@@ -19,6 +20,9 @@ import (
 	externalConfig "github.com/metfin/external/config"
 	externalRPC "github.com/metfin/external/rpc"
 	externalServices "github.com/metfin/external/service"
+	"github.com/wnt/mercon/internal/conformance"
+	"github.com/wnt/mercon/internal/scanner"
+	"github.com/wnt/mercon/internal/sink"
 )
 
 func main() {
@@ -50,16 +54,61 @@ func main() {
 	// Parse command line arguments
 	var walletAddress string
 	var limit int
+	var format string
+	var resume bool
+	var cursorPath string
+	var since string
+	var emitVectorSig string
+	var vectorsDir string
+	var vectorsBranch string
 	flag.StringVar(&walletAddress, "wallet", "", "Wallet address to scan (required)")
 	flag.IntVar(&limit, "limit", 3000, "Maximum number of transactions to process")
+	flag.StringVar(&format, "format", "pretty", "Output format: pretty, ndjson, csv, or parquet")
+	flag.BoolVar(&resume, "resume", false, "Skip signatures already recorded in the cursor file from a previous run")
+	flag.StringVar(&cursorPath, "cursor", "", "Cursor file path (default: <wallet>.cursor.json)")
+	flag.StringVar(&since, "since", "", "Only scan transactions at or after this slot (integer) or block time (RFC3339)")
+	flag.StringVar(&emitVectorSig, "emit-vector", "", "Signature of a scanned transaction to capture as a DAMM/DLMM conformance vector")
+	defaultVectorsDir := os.Getenv("MERCON_METEORA_VECTORS_DIR")
+	if defaultVectorsDir == "" {
+		defaultVectorsDir = conformance.DefaultVectorsDir
+	}
+	flag.StringVar(&vectorsDir, "vectors-dir", defaultVectorsDir, "Directory -emit-vector writes into (default: $MERCON_METEORA_VECTORS_DIR or internal/conformance/testdata/vectors)")
+	flag.StringVar(&vectorsBranch, "vectors-branch", "", "Git ref the vector corpus should be checked out at before running (not yet wired up - the corpus lives in-tree, not as a submodule, so this only logs a reminder today)")
 	flag.Parse()
 
 	if walletAddress == "" {
-		println("Usage: go run main.go -wallet <wallet_address> [-limit <number>]")
+		println("Usage: go run main.go -wallet <wallet_address> [-limit <number>] [-format pretty|ndjson|csv|parquet] [-resume] [-since <slot|RFC3339>] [-emit-vector <signature>]")
 		println("Example: go run main.go -wallet 9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM -limit 50")
 		os.Exit(1)
 	}
 
+	if cursorPath == "" {
+		cursorPath = walletAddress + ".cursor.json"
+	}
+
+	sinceFilter, err := parseSince(since)
+	if err != nil {
+		log.Fatalf("❌ Invalid -since value: %v", err)
+	}
+
+	var cursor *scanner.Cursor
+	if resume {
+		cursor, err = scanner.LoadCursor(cursorPath, walletAddress)
+		if err != nil {
+			log.Fatalf("❌ Failed to load cursor: %v", err)
+		}
+	}
+
+	out, err := sinkFor(format, multiWriter)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer out.Close()
+
+	if vectorsBranch != "" {
+		printf("⚠️  -vectors-branch=%s requested, but the corpus isn't a git submodule yet - running against whatever is checked out locally\n", vectorsBranch)
+	}
+
 	printf("🔍 Scanning wallet: %s\n", walletAddress)
 	printf("📊 Transaction limit: %d\n", limit)
 	printf("📄 Log file: %s\n", logFileName)
@@ -95,103 +144,58 @@ func main() {
 
 	ctx := context.Background()
 
-	// Get transaction signatures for the wallet
-	println("🔄 Fetching transaction signatures...")
-	signatures, err := getWalletTransactionSignatures(ctx, svc, walletAddress, limit)
-	if err != nil {
-		log.Fatalf("❌ Failed to get wallet transactions: %v", err)
-	}
-
-	if len(signatures) == 0 {
-		println("📭 No transactions found for this wallet")
-		return
-	}
-
-	printf("✅ Found %d transaction signatures\n", len(signatures))
-
-	// Fetch transaction details in batches
-	println("🔄 Fetching transaction details...")
-	transactions, err := getTransactionsInBulk(ctx, svc, signatures)
+	println("🔄 Fetching transaction signatures and transaction details...")
+	scan := scanner.New(svc)
+	stream, err := scan.Stream(ctx, walletAddress, scanner.Options{
+		Limit:  limit,
+		Since:  sinceFilter,
+		Resume: cursor,
+	})
 	if err != nil {
-		log.Fatalf("❌ Failed to get transaction details: %v", err)
+		log.Fatalf("❌ Failed to scan wallet: %v", err)
 	}
 
-	printf("✅ Fetched %d transactions\n", len(transactions))
-	println(strings.Repeat("=", 80))
-
-	// Process and parse each transaction
-	var totalParsed int
+	var totalScanned, totalParsed int
 	var dammCount, dlmmCount int
 
-	for i, txResult := range transactions {
-		if txResult == nil || txResult.Transaction == nil {
-			continue
-		}
-
-		printf("\n🔍 Transaction #%d\n", i+1)
-		printf("📝 Signature: %s\n", signatures[i])
-
-		if txResult.BlockTime != nil {
-			blockTime := time.Unix(int64(*txResult.BlockTime), 0)
-			printf("⏰ Block Time: %s\n", blockTime.Format("2006-01-02 15:04:05 UTC"))
-		}
-
-		if txResult.Slot != 0 {
-			printf("🎯 Slot: %d\n", txResult.Slot)
-		}
-
-		// Convert transaction to a format we can parse
-		tx, err := txResult.Transaction.GetTransaction()
-		if err != nil {
-			printf("❌ Failed to parse transaction: %v\n", err)
-			continue
-		}
+	for tx := range stream {
+		totalScanned++
 
-		// Parse DAMM instructions
-		dammInstructions, err := damm.ParseDAMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
-		if err != nil {
-			printf("⚠️  Failed to parse DAMM instructions: %v\n", err)
-		} else if len(dammInstructions) > 0 {
-			printf("🟢 DAMM Instructions Found: %d\n", len(dammInstructions))
-			dammCount += len(dammInstructions)
-			for j, inst := range dammInstructions {
-				printf("  %d. Type: %s\n", j+1, inst.Type)
-				if inst.Parsed != nil {
-					printf("     Parsed Data: %s\n", formatInstruction(inst.Parsed))
-				}
+		if tx.Err != nil {
+			printf("❌ Failed to process transaction %s: %v\n", tx.Signature, tx.Err)
+		} else {
+			if len(tx.DAMM) > 0 || len(tx.DLMM) > 0 {
+				totalParsed++
 			}
-		}
-
-		// Parse DLMM instructions
-		dlmmInstructions, err := dlmm.ParseDLMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
-		if err != nil {
-			printf("⚠️  Failed to parse DLMM instructions: %v\n", err)
-		} else if len(dlmmInstructions) > 0 {
-			printf("🔵 DLMM Instructions Found: %d\n", len(dlmmInstructions))
-			dlmmCount += len(dlmmInstructions)
-			for j, inst := range dlmmInstructions {
-				printf("  %d. Type: %s\n", j+1, inst.Type)
-				if inst.Parsed != nil {
-					printf("     Parsed Data: %s\n", formatInstruction(inst.Parsed))
+			dammCount += len(tx.DAMM)
+			dlmmCount += len(tx.DLMM)
+
+			if emitVectorSig != "" && tx.Signature == emitVectorSig {
+				if err := emitVector(ctx, svc, vectorsDir, tx.Signature); err != nil {
+					printf("❌ Failed to emit conformance vector: %v\n", err)
+				} else {
+					printf("📦 Wrote conformance vector for %s to %s\n", tx.Signature, vectorsDir)
 				}
 			}
 		}
 
-		// Show if no relevant instructions found
-		if len(dammInstructions) == 0 && len(dlmmInstructions) == 0 {
-			printf("⚪ No Meteora instructions found\n")
-		} else {
-			totalParsed++
+		if err := out.Write(tx); err != nil {
+			log.Fatalf("❌ Failed to write output: %v", err)
 		}
 
-		println(strings.Repeat("-", 40))
+		if resume {
+			cursor.Advance(tx.Signature, tx.Slot)
+			if err := cursor.Save(cursorPath); err != nil {
+				log.Fatalf("❌ Failed to save cursor: %v", err)
+			}
+		}
 	}
 
 	// Summary
 	printf("\n📈 SCAN SUMMARY\n")
 	println(strings.Repeat("=", 80))
 	printf("💳 Wallet: %s\n", walletAddress)
-	printf("🔢 Total Transactions Scanned: %d\n", len(transactions))
+	printf("🔢 Total Transactions Scanned: %d\n", totalScanned)
 	printf("✅ Transactions with Meteora Instructions: %d\n", totalParsed)
 	printf("🟢 Total DAMM Instructions: %d\n", dammCount)
 	printf("🔵 Total DLMM Instructions: %d\n", dlmmCount)
@@ -199,43 +203,110 @@ func main() {
 	printf("📄 Full log saved to: %s\n", logFileName)
 }
 
-// Helper function to get wallet transaction signatures
-func getWalletTransactionSignatures(ctx context.Context, svc externalServices.ExternalService, walletAddress string, limit int) ([]string, error) {
-	// Use type assertion to access the method
-	extImpl, ok := svc.(interface {
-		GetWalletTransactionSignatures(ctx context.Context, walletAddress string, limit int) ([]string, error)
-	})
-	if !ok {
-		return nil, fmt.Errorf("external service does not support GetWalletTransactionSignatures")
+// sinkFor constructs the sink.Sink selected by -format, writing to w.
+func sinkFor(format string, w io.Writer) (sink.Sink, error) {
+	switch format {
+	case "pretty", "":
+		return sink.Pretty(w), nil
+	case "ndjson":
+		return sink.NDJSON(w), nil
+	case "csv":
+		return sink.CSV(w), nil
+	case "parquet":
+		return sink.Parquet(w), nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q (want pretty, ndjson, csv, or parquet)", format)
 	}
+}
 
-	return extImpl.GetWalletTransactionSignatures(ctx, walletAddress, limit)
+// parseSince parses -since as either a slot number or an RFC3339 timestamp.
+// An empty string means no filter.
+func parseSince(since string) (*scanner.Since, error) {
+	if since == "" {
+		return nil, nil
+	}
+	if slot, err := strconv.ParseUint(since, 10, 64); err == nil {
+		return &scanner.Since{Slot: slot}, nil
+	}
+	ts, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a slot number nor an RFC3339 timestamp", since)
+	}
+	return &scanner.Since{Timestamp: ts}, nil
 }
 
-// Helper function to get transactions in bulk
-func getTransactionsInBulk(ctx context.Context, svc externalServices.ExternalService, signatures []string) ([]*rpc.GetTransactionResult, error) {
-	// Use type assertion to access the method
-	extImpl, ok := svc.(interface {
-		GetTransactionsInBulk(ctx context.Context, txHashes []string) ([]*rpc.GetTransactionResult, error)
-	})
-	if !ok {
-		return nil, fmt.Errorf("external service does not support GetTransactionsInBulk")
+// emitVector writes a DAMM/DLMM conformance vector for sig into dir. It
+// re-fetches the raw transaction rather than threading it through
+// scanner.ParsedTx, since conformance.Vector needs the original base64
+// encoding and scanner intentionally doesn't expose that for every
+// transaction it streams. As with vectorgen's vectors for internal/solana,
+// this is a starting point - review it before trusting it as a regression
+// baseline, since the "expected" output here is just this run's actual
+// output, not an independently-verified expectation.
+func emitVector(ctx context.Context, svc externalServices.ExternalService, dir, sig string) error {
+	results, err := svc.GetTransactionsInBulk(ctx, []string{sig})
+	if err != nil {
+		return fmt.Errorf("re-fetching transaction: %w", err)
+	}
+	if len(results) == 0 || results[0] == nil || results[0].Transaction == nil {
+		return fmt.Errorf("transaction %s not found", sig)
 	}
 
-	return extImpl.GetTransactionsInBulk(ctx, signatures)
-}
+	tx, err := results[0].Transaction.GetTransaction()
+	if err != nil {
+		return fmt.Errorf("decoding transaction: %w", err)
+	}
+
+	txBase64, err := tx.ToBase64()
+	if err != nil {
+		return fmt.Errorf("encoding transaction: %w", err)
+	}
+
+	dammInstructions, err := damm.ParseDAMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
+	if err != nil {
+		return fmt.Errorf("parsing DAMM instructions: %w", err)
+	}
+	dlmmInstructions, err := dlmm.ParseDLMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
+	if err != nil {
+		return fmt.Errorf("parsing DLMM instructions: %w", err)
+	}
+
+	gotDAMM, err := conformance.ToInstructions(dammInstructions)
+	if err != nil {
+		return fmt.Errorf("encoding DAMM output: %w", err)
+	}
+	gotDLMM, err := conformance.ToInstructions(dlmmInstructions)
+	if err != nil {
+		return fmt.Errorf("encoding DLMM output: %w", err)
+	}
+
+	name := sig
+	if len(name) > 12 {
+		name = name[:12]
+	}
 
-// Helper function to format instruction data for display
-func formatInstruction(parsed interface{}) string {
-	if parsed == nil {
-		return "N/A"
+	v := conformance.Vector{
+		Name:         name,
+		Description:  fmt.Sprintf("Captured from live signature %s - review before relying on it", sig),
+		Signature:    sig,
+		TxBase64:     txBase64,
+		ExpectedDAMM: gotDAMM,
+		ExpectedDLMM: gotDLMM,
 	}
 
-	// Convert to JSON for pretty printing
-	data, err := json.MarshalIndent(parsed, "     ", "  ")
+	encoded, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return fmt.Sprintf("%+v", parsed)
+		return fmt.Errorf("encoding vector: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating vectors dir: %w", err)
+	}
+
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, append(encoded, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing vector file: %w", err)
 	}
 
-	return string(data)
+	return nil
 }
@@ -0,0 +1,104 @@
+// Command vectorgen grows the internal/solana conformance corpus from a
+// live transaction: it fetches the transaction by signature, runs it
+// through the real parser, and writes the raw transaction plus the parser's
+// output as a new testdata/vectors/*.json file.
+//
+// The written vector's "expected" section (or "expectedErrorContains", if
+// the transaction doesn't parse) reflects whatever the parser produced at
+// generation time - it is a starting point, not a verified result. Review
+// and hand-correct it before committing, the same way you would review a
+// snapshot-test's first recorded snapshot.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wnt/mercon/internal/parserstore"
+	"github.com/wnt/mercon/internal/solana"
+)
+
+func main() {
+	var signature string
+	var name string
+	var outDir string
+	flag.StringVar(&signature, "sig", "", "Signature of the transaction to capture (required)")
+	flag.StringVar(&name, "name", "", "Name for the generated vector file (default: derived from -sig)")
+	defaultOutDir := os.Getenv("MERCON_VECTORS_DIR")
+	if defaultOutDir == "" {
+		defaultOutDir = "internal/solana/testdata/vectors"
+	}
+	flag.StringVar(&outDir, "out", defaultOutDir, "Directory to write the vector file into (default: $MERCON_VECTORS_DIR or internal/solana/testdata/vectors)")
+	flag.Parse()
+
+	if signature == "" {
+		fmt.Println("Usage: go run ./cmd/vectorgen -sig <signature> [-name <vector_name>] [-out <dir>]")
+		os.Exit(1)
+	}
+
+	if name == "" {
+		name = strings.ToLower(signature)
+		if len(name) > 12 {
+			name = name[:12]
+		}
+	}
+
+	client, err := solana.NewClient()
+	if err != nil {
+		log.Fatalf("failed to create solana client: %v", err)
+	}
+
+	ctx := context.Background()
+	tx, err := client.GetTransactionBySignature(ctx, signature)
+	if err != nil {
+		log.Fatalf("failed to fetch transaction %s: %v", signature, err)
+	}
+
+	var parserOpts []solana.TransactionParserOption
+	if registry, err := solana.NewDefaultInstructionRegistry(); err != nil {
+		log.Printf("Warning: failed to load Meteora DLMM IDL, swap fee/bin fields will stay zeroed: %v", err)
+	} else {
+		parserOpts = append(parserOpts, solana.WithInstructionRegistry(registry))
+	}
+
+	// vectorgen has no database to persist derived rows to - it's just
+	// generating a fixture file - so an in-memory store stands in, the same
+	// as a test would use.
+	parser := solana.NewTransactionParser(client, parserstore.NewMemoryStore(), parserOpts...)
+	parsed, parseErr := parser.ProcessTransaction(ctx, *tx)
+
+	out := map[string]interface{}{
+		"name":        name,
+		"description": fmt.Sprintf("Captured from live signature %s - review before relying on it", signature),
+		"transaction": tx,
+	}
+	if parseErr != nil {
+		out["expectError"] = true
+		out["expectedErrorContains"] = parseErr.Error()
+	} else {
+		out["expectError"] = false
+		out["expected"] = parsed
+	}
+
+	encoded, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		log.Fatalf("failed to encode vector: %v", err)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		log.Fatalf("failed to create output directory: %v", err)
+	}
+
+	path := filepath.Join(outDir, name+".json")
+	if err := os.WriteFile(path, append(encoded, '\n'), 0o644); err != nil {
+		log.Fatalf("failed to write vector file: %v", err)
+	}
+
+	fmt.Printf("wrote %s\n", path)
+}
@@ -0,0 +1,270 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wnt/mercon/internal/logger"
+	"github.com/wnt/mercon/internal/queue"
+	"github.com/wnt/mercon/internal/services"
+	"github.com/wnt/mercon/internal/worker"
+)
+
+// RegisterScraperMethods registers the scraper_ namespace: worker visibility
+// plus wallet pause/resume/backfill and RPC endpoint rotation.
+func RegisterScraperMethods(s *Server, manager *worker.Manager) {
+	s.Register("scraper_listWorkers", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		workers := manager.Workers()
+		statuses := make([]worker.Status, len(workers))
+		for i, w := range workers {
+			statuses[i] = w.Status()
+		}
+		return statuses, nil
+	})
+
+	s.Register("scraper_pauseWallet", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("scraper_pauseWallet requires an \"address\" string param")
+		}
+		if err := manager.Queue().PauseWallet(ctx, p.Address); err != nil {
+			return nil, fmt.Errorf("failed to pause wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_resumeWallet", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("scraper_resumeWallet requires an \"address\" string param")
+		}
+		if err := manager.Queue().ResumeWallet(ctx, p.Address); err != nil {
+			return nil, fmt.Errorf("failed to resume wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_backfill", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address  string `json:"address"`
+			FromSlot uint64 `json:"fromSlot"`
+			ToSlot   uint64 `json:"toSlot"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("scraper_backfill requires \"address\", \"fromSlot\", and \"toSlot\" params")
+		}
+		if p.ToSlot < p.FromSlot {
+			return nil, invalidParams("toSlot must be greater than or equal to fromSlot")
+		}
+
+		job := queue.BackfillJob{Wallet: p.Address, FromSlot: p.FromSlot, ToSlot: p.ToSlot}
+		if err := manager.Queue().PushBackfillJob(ctx, job); err != nil {
+			return nil, fmt.Errorf("failed to queue backfill job: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_setRPCEndpoints", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Endpoints []string `json:"endpoints"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || len(p.Endpoints) == 0 {
+			return nil, invalidParams("scraper_setRPCEndpoints requires a non-empty \"endpoints\" array param")
+		}
+		manager.SetRPCEndpoints(p.Endpoints)
+		return true, nil
+	})
+
+	s.Register("scraper_resizeWorkers", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Count < 0 {
+			return nil, invalidParams("scraper_resizeWorkers requires a non-negative \"count\" int param")
+		}
+		if err := manager.Resize(p.Count); err != nil {
+			return nil, fmt.Errorf("failed to resize workers: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_drainWorker", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.ID == "" {
+			return nil, invalidParams("scraper_drainWorker requires an \"id\" string param")
+		}
+		if err := manager.DrainWorker(p.ID); err != nil {
+			return nil, fmt.Errorf("failed to drain worker: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_subscribeWallet", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("scraper_subscribeWallet requires an \"address\" string param")
+		}
+		if err := manager.SubscribeWallet(p.Address); err != nil {
+			return nil, fmt.Errorf("failed to subscribe wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("scraper_unsubscribeWallet", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("scraper_unsubscribeWallet requires an \"address\" string param")
+		}
+		if err := manager.UnsubscribeWallet(p.Address); err != nil {
+			return nil, fmt.Errorf("failed to unsubscribe wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("queue_requeueStuck", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			OlderThanMinutes int `json:"olderThanMinutes"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.OlderThanMinutes <= 0 {
+			return nil, invalidParams("queue_requeueStuck requires a positive \"olderThanMinutes\" int param")
+		}
+		if err := manager.RequeueStuckWallets(p.OlderThanMinutes); err != nil {
+			return nil, fmt.Errorf("failed to requeue stuck wallets: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("queue_listDLQ", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		entries, err := manager.ListDLQ()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DLQ: %w", err)
+		}
+		return entries, nil
+	})
+
+	s.Register("queue_retryDLQ", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Wallet string `json:"wallet"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Wallet == "" {
+			return nil, invalidParams("queue_retryDLQ requires a \"wallet\" string param")
+		}
+		if err := manager.RetryDLQ(p.Wallet); err != nil {
+			return nil, fmt.Errorf("failed to retry DLQ wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("queue_purgeDLQ", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if err := manager.PurgeDLQ(); err != nil {
+			return nil, fmt.Errorf("failed to purge DLQ: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("queue_peek", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Limit int `json:"limit"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Limit <= 0 {
+			return nil, invalidParams("queue_peek requires a positive \"limit\" int param")
+		}
+		wallets, err := manager.Queue().PeekQueue(ctx, p.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to peek queue: %w", err)
+		}
+		return wallets, nil
+	})
+
+	s.Register("queue_purge", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("queue_purge requires an \"address\" string param")
+		}
+		if err := manager.Queue().PurgeWallet(ctx, p.Address); err != nil {
+			return nil, fmt.Errorf("failed to purge wallet: %w", err)
+		}
+		return true, nil
+	})
+
+	// NOTE: the request behind this namespace also asked for a
+	// "cache_invalidate <pair>" method. There's currently no long-lived,
+	// shared solana.PairMetadataResolver instance reachable from here to
+	// invalidate - GetAndParseTransactions builds a fresh one per call
+	// (see internal/solana/client.go) - so wiring that through would mean
+	// first making the resolver long-lived and shared across the worker
+	// pool, which is a bigger change than this ticket. PairMetadataResolver
+	// does now expose an Invalidate method for whenever that refactor
+	// happens.
+}
+
+// RegisterEnricherMethods registers the enricher_ namespace: on-demand
+// pair/transaction re-enrichment and oracle health.
+func RegisterEnricherMethods(s *Server, enricher *services.MeteoraDataEnricher) {
+	s.Register("enricher_enrichPair", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Address == "" {
+			return nil, invalidParams("enricher_enrichPair requires an \"address\" string param")
+		}
+		if err := enricher.EnrichPairByAddress(p.Address); err != nil {
+			return nil, fmt.Errorf("failed to enrich pair: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("enricher_enrichTransaction", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Signature string `json:"signature"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Signature == "" {
+			return nil, invalidParams("enricher_enrichTransaction requires a \"signature\" string param")
+		}
+		if err := enricher.EnrichTransactionBySignature(p.Signature); err != nil {
+			return nil, fmt.Errorf("failed to enrich transaction: %w", err)
+		}
+		return true, nil
+	})
+
+	s.Register("enricher_oracleStatus", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return enricher.OracleStatus(), nil
+	})
+}
+
+// RegisterAdminMethods registers the admin_ namespace: process-wide controls
+// that don't belong to the scraper or enricher specifically.
+func RegisterAdminMethods(s *Server, manager *worker.Manager, enricher *services.MeteoraDataEnricher) {
+	s.Register("admin_logLevel", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Level == "" {
+			return nil, invalidParams("admin_logLevel requires a \"level\" string param")
+		}
+		if err := logger.SetLevel(p.Level); err != nil {
+			return nil, invalidParams("%s", err)
+		}
+		return true, nil
+	})
+
+	s.Register("admin_metrics", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return map[string]interface{}{
+			"manager":  manager.GetStats(),
+			"enricher": enricher.Metrics(),
+		}, nil
+	})
+}
@@ -0,0 +1,203 @@
+// Package adminapi exposes an HTTP JSON-RPC 2.0 server for operating the
+// scraper and enricher at runtime: pausing a hot wallet, kicking off a
+// backfill, rotating RPC endpoints, forcing a pair/transaction re-enrich, or
+// just checking what a worker is doing right now - all without restarting
+// the process.
+//
+// Methods are namespaced like Solana's own RPC API (scraper_, enricher_,
+// admin_) and gated module-by-module through Config.EnabledModules, the same
+// way geth or solana-validator let operators opt into only the RPC surface
+// they want exposed.
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Config configures the admin API server.
+type Config struct {
+	// Addr is the address to bind the HTTP server to, e.g. ":8090".
+	Addr string
+	// EnabledModules is the set of method namespaces to register, e.g.
+	// []string{"scraper", "enricher", "admin"}. A method whose namespace
+	// isn't enabled is rejected as not found, the same as if it didn't exist.
+	EnabledModules []string
+	// BearerToken is required on every request via the Authorization header
+	// ("Bearer <token>"). An empty token disables the server entirely;
+	// Server is never constructed in that case by the caller.
+	BearerToken string
+}
+
+// Request is a JSON-RPC 2.0 request.
+type Request struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// Response is a JSON-RPC 2.0 response.
+type Response struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Standard JSON-RPC 2.0 error codes, per the spec.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// handlerFunc executes one RPC method against its raw params and returns the
+// result to place in a Response, or an error to translate into one.
+type handlerFunc func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// Server is an HTTP JSON-RPC 2.0 server exposing admin/control methods.
+type Server struct {
+	config     Config
+	logger     zerolog.Logger
+	httpServer *http.Server
+	methods    map[string]handlerFunc
+}
+
+// NewServer builds a Server with no methods registered. Call Register for
+// each method the caller wants to expose, gated by module, then Start.
+func NewServer(cfg Config, logger zerolog.Logger) *Server {
+	return &Server{
+		config:  cfg,
+		logger:  logger,
+		methods: make(map[string]handlerFunc),
+	}
+}
+
+// Register adds a method to the server's dispatch table if its namespace
+// (the part of the name before the first underscore) is in the config's
+// enabled modules. Calling Register for a disabled module's method is a
+// no-op, so callers can unconditionally register every method they know
+// about and let the config decide what's actually exposed.
+func (s *Server) Register(method string, fn handlerFunc) {
+	namespace, _, ok := strings.Cut(method, "_")
+	if !ok {
+		namespace = method
+	}
+
+	if !s.moduleEnabled(namespace) {
+		return
+	}
+
+	s.methods[method] = fn
+}
+
+func (s *Server) moduleEnabled(namespace string) bool {
+	for _, m := range s.config.EnabledModules {
+		if m == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Start begins serving JSON-RPC requests on Config.Addr. It returns once the
+// listener fails to start; callers typically run it in a goroutine the same
+// way cmd/mercon runs the metrics server.
+func (s *Server) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleRPC)
+
+	s.httpServer = &http.Server{
+		Addr:    s.config.Addr,
+		Handler: mux,
+	}
+
+	s.logger.Info().Str("addr", s.config.Addr).Int("methods", len(s.methods)).Msg("Starting admin API server")
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("admin API server failed: %w", err)
+	}
+	return nil
+}
+
+// Stop gracefully shuts down the server.
+func (s *Server) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, Response{Jsonrpc: "2.0", Error: &Error{Code: codeParseError, Message: "parse error"}})
+		return
+	}
+
+	if req.Jsonrpc != "2.0" || req.Method == "" {
+		writeResponse(w, Response{Jsonrpc: "2.0", ID: req.ID, Error: &Error{Code: codeInvalidRequest, Message: "invalid request"}})
+		return
+	}
+
+	fn, ok := s.methods[req.Method]
+	if !ok {
+		writeResponse(w, Response{Jsonrpc: "2.0", ID: req.ID, Error: &Error{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}})
+		return
+	}
+
+	result, err := fn(r.Context(), req.Params)
+	if err != nil {
+		code := codeInternalError
+		if _, ok := err.(*invalidParamsError); ok {
+			code = codeInvalidParams
+		}
+		writeResponse(w, Response{Jsonrpc: "2.0", ID: req.ID, Error: &Error{Code: code, Message: err.Error()}})
+		return
+	}
+
+	writeResponse(w, Response{Jsonrpc: "2.0", ID: req.ID, Result: result})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	return header == "Bearer "+s.config.BearerToken
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// invalidParamsError marks an error as the caller's fault (bad params),
+// translated to JSON-RPC's -32602 instead of the default internal-error code.
+type invalidParamsError struct{ err error }
+
+func (e *invalidParamsError) Error() string { return e.err.Error() }
+
+func invalidParams(format string, args ...interface{}) error {
+	return &invalidParamsError{err: fmt.Errorf(format, args...)}
+}
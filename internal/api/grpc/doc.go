@@ -0,0 +1,13 @@
+// Package grpc is the read-only gRPC query API - the typed counterpart to
+// polling internal/database directly or scraping cmd/mercon's /stats
+// endpoint. internal/grpcapi is the sibling control plane (enqueue, pause,
+// drain); this package only answers questions, it never mutates scrape
+// state. Services are defined in mercon_query.proto; generated bindings
+// live in the sibling pb package, produced by:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	       mercon_query.proto
+//
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative mercon_query.proto
+package grpc
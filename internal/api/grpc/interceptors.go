@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wnt/mercon/internal/metrics"
+)
+
+// loggingMetricsUnaryInterceptor logs each call and records its latency and
+// final status via internal/metrics, the query API's counterpart to
+// internal/rpc.Fetcher's own RecordRPCRequest/RecordRPCError bookkeeping.
+func (s *Server) loggingMetricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	duration := time.Since(start)
+
+	statusLabel := "ok"
+	logEvent := s.logger.Debug()
+	if err != nil {
+		statusLabel = "error"
+		logEvent = s.logger.Warn().Err(err)
+	}
+	logEvent.Str("method", info.FullMethod).Dur("duration", duration).Msg("Handled query API call")
+
+	metrics.RecordQueryGRPCRequest(info.FullMethod, statusLabel, duration.Seconds())
+	return resp, err
+}
+
+// recoveryUnaryInterceptor turns a handler panic into an Internal status
+// error instead of crashing the process - cmd/mercon runs the worker
+// fleet in the same binary, so a panic here must not take that down too.
+func (s *Server) recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecordQueryGRPCPanic(info.FullMethod)
+			s.logger.Error().Interface("panic", r).Str("method", info.FullMethod).Msg("Recovered from panic in query API handler")
+			err = status.Error(codes.Internal, fmt.Sprintf("internal error handling %s", info.FullMethod))
+		}
+	}()
+	return handler(ctx, req)
+}
@@ -0,0 +1,254 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	pb "github.com/wnt/mercon/internal/api/grpc/pb"
+)
+
+// positionEventsChannel is the Postgres NOTIFY channel a trigger on
+// meteora_fee_claims, meteora_liquidity_additions, and
+// meteora_liquidity_removals publishes to. The triggers are created by
+// notifyPositionEventSQL below, which newNotifier applies on start.
+const positionEventsChannel = "mercon_position_events"
+
+// positionEventPayload is the JSON body the trigger function (see
+// notifyPositionEventSQL below) puts in each NOTIFY.
+type positionEventPayload struct {
+	Type                 string `json:"type"`
+	PositionAddress      string `json:"position_address"`
+	WalletAddress        string `json:"wallet_address"`
+	RecordID             int64  `json:"record_id"`
+	OnchainTimestampUnix int64  `json:"onchain_timestamp_unix"`
+}
+
+// notifier owns a single dedicated Postgres connection LISTENing on
+// positionEventsChannel and fans each NOTIFY out to every subscribed
+// StreamPositionEvents call, filtering by wallet address where requested.
+// A single shared connection is enough: Postgres fans a NOTIFY out to
+// every session listening on the channel regardless of how many
+// application-level subscribers sit behind it.
+type notifier struct {
+	db     *gorm.DB
+	logger zerolog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	subs map[int]*positionEventSub
+	next int
+}
+
+type positionEventSub struct {
+	walletAddress string
+	ch            chan *pb.PositionEvent
+}
+
+func newNotifier(db *gorm.DB, logger zerolog.Logger) *notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &notifier{
+		db:     db,
+		logger: logger.With().Str("component", "position_notifier").Logger(),
+		ctx:    ctx,
+		cancel: cancel,
+		subs:   make(map[int]*positionEventSub),
+	}
+
+	if err := db.Exec(notifyPositionEventSQL).Error; err != nil {
+		n.logger.Error().Err(err).Msg("Failed to install position event triggers, StreamPositionEvents will see nothing")
+	}
+
+	go n.run()
+	return n
+}
+
+// subscribe registers a new StreamPositionEvents listener, optionally
+// filtered to walletAddress ("" subscribes to every wallet's events).
+func (n *notifier) subscribe(ctx context.Context, walletAddress string) (<-chan *pb.PositionEvent, func(), error) {
+	sub := &positionEventSub{
+		walletAddress: walletAddress,
+		ch:            make(chan *pb.PositionEvent, 16),
+	}
+
+	n.mu.Lock()
+	id := n.next
+	n.next++
+	n.subs[id] = sub
+	n.mu.Unlock()
+
+	unsubscribe := func() {
+		n.mu.Lock()
+		delete(n.subs, id)
+		n.mu.Unlock()
+	}
+	return sub.ch, unsubscribe, nil
+}
+
+// Close stops the listening connection and every subscriber's stream.
+func (n *notifier) Close() {
+	n.cancel()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for id, sub := range n.subs {
+		close(sub.ch)
+		delete(n.subs, id)
+	}
+}
+
+// run holds one LISTEN connection open for as long as n.ctx is alive,
+// reconnecting with a fixed delay if the connection drops - there's no
+// Pool to share backoff/health bookkeeping with here since this is a
+// database connection, not an RPC endpoint.
+func (n *notifier) run() {
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		default:
+		}
+
+		if err := n.listenUntilError(); err != nil {
+			n.logger.Warn().Err(err).Msg("Position event listener dropped, reconnecting")
+		}
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (n *notifier) listenUntilError() error {
+	sqlDB, err := n.db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(n.ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(n.ctx, "LISTEN "+positionEventsChannel); err != nil {
+		return err
+	}
+
+	return conn.Raw(func(driverConn interface{}) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn().PgConn()
+		for {
+			notification, err := pgConn.WaitForNotification(n.ctx)
+			if err != nil {
+				return err
+			}
+			n.dispatch(notification.Payload)
+		}
+	})
+}
+
+// dispatch decodes one NOTIFY payload and fans it out to every matching
+// subscriber, dropping it for a subscriber whose channel is full rather
+// than blocking the shared listener on a slow client.
+func (n *notifier) dispatch(payload string) {
+	var p positionEventPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		n.logger.Warn().Err(err).Msg("Failed to decode position event payload")
+		return
+	}
+
+	event := &pb.PositionEvent{
+		Type:                 positionEventType(p.Type),
+		PositionAddress:      p.PositionAddress,
+		RecordId:             p.RecordID,
+		OnchainTimestampUnix: p.OnchainTimestampUnix,
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, sub := range n.subs {
+		if sub.walletAddress != "" && sub.walletAddress != p.WalletAddress {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+func positionEventType(t string) pb.PositionEventType {
+	switch t {
+	case "claim_fee":
+		return pb.PositionEventType_POSITION_EVENT_TYPE_CLAIM_FEE
+	case "claim_reward":
+		return pb.PositionEventType_POSITION_EVENT_TYPE_CLAIM_REWARD
+	case "deposit":
+		return pb.PositionEventType_POSITION_EVENT_TYPE_DEPOSIT
+	case "withdraw":
+		return pb.PositionEventType_POSITION_EVENT_TYPE_WITHDRAW
+	default:
+		return pb.PositionEventType_POSITION_EVENT_TYPE_UNSPECIFIED
+	}
+}
+
+// notifyPositionEventSQL is the trigger function + triggers that make
+// StreamPositionEvents possible. newNotifier executes it against the
+// analytics database every time the query API starts (it is idempotent:
+// CREATE OR REPLACE plus DROP TRIGGER IF EXISTS). Kept here rather than in
+// internal/database so that package doesn't need to know about a gRPC
+// consumer of its schema.
+const notifyPositionEventSQL = `
+CREATE OR REPLACE FUNCTION mercon_notify_position_event() RETURNS trigger AS $$
+DECLARE
+  event_type text;
+  position_addr text;
+  wallet_addr text;
+  ts bigint;
+BEGIN
+  IF TG_TABLE_NAME = 'meteora_fee_claims' THEN
+    event_type := 'claim_fee';
+    ts := extract(epoch from NEW.claim_time)::bigint;
+  ELSIF TG_TABLE_NAME = 'meteora_liquidity_additions' THEN
+    event_type := 'deposit';
+    ts := extract(epoch from NEW.add_time)::bigint;
+  ELSIF TG_TABLE_NAME = 'meteora_liquidity_removals' THEN
+    event_type := 'withdraw';
+    ts := extract(epoch from NEW.remove_time)::bigint;
+  END IF;
+
+  SELECT address INTO position_addr FROM meteora_positions WHERE id = NEW.position_id;
+  SELECT address INTO wallet_addr FROM wallets WHERE id = NEW.wallet_id;
+
+  PERFORM pg_notify('mercon_position_events', json_build_object(
+    'type', event_type,
+    'position_address', position_addr,
+    'wallet_address', wallet_addr,
+    'record_id', NEW.id,
+    'onchain_timestamp_unix', ts
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS mercon_notify_fee_claim ON meteora_fee_claims;
+CREATE TRIGGER mercon_notify_fee_claim AFTER INSERT ON meteora_fee_claims
+  FOR EACH ROW EXECUTE FUNCTION mercon_notify_position_event();
+
+DROP TRIGGER IF EXISTS mercon_notify_deposit ON meteora_liquidity_additions;
+CREATE TRIGGER mercon_notify_deposit AFTER INSERT ON meteora_liquidity_additions
+  FOR EACH ROW EXECUTE FUNCTION mercon_notify_position_event();
+
+DROP TRIGGER IF EXISTS mercon_notify_withdraw ON meteora_liquidity_removals;
+CREATE TRIGGER mercon_notify_withdraw AFTER INSERT ON meteora_liquidity_removals
+  FOR EACH ROW EXECUTE FUNCTION mercon_notify_position_event();
+`
@@ -0,0 +1,458 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	pb "github.com/wnt/mercon/internal/api/grpc/pb"
+	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/rpc"
+	"github.com/wnt/mercon/internal/worker"
+)
+
+// defaultPageLimit and maxPageLimit bound every List* RPC's page size - a
+// client that asks for 0 gets the default, a client that asks for too many
+// gets capped rather than rejected.
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// Config configures the read-only gRPC query API.
+type Config struct {
+	// Addr is the address to bind the gRPC listener to, e.g. ":9091".
+	Addr string
+}
+
+// Server implements WalletService, PositionService, and AdminService
+// against the analytics database, the RPC pool, and the worker manager.
+// Unlike internal/grpcapi.Server it never mutates scrape state except for
+// the two explicitly operator-facing AdminService RPCs (DrainEndpoint,
+// PauseWorkers).
+type Server struct {
+	pb.UnimplementedWalletServiceServer
+	pb.UnimplementedPositionServiceServer
+	pb.UnimplementedAdminServiceServer
+
+	config     Config
+	db         *gorm.DB
+	rpcPool    *rpc.Pool
+	manager    *worker.Manager
+	logger     zerolog.Logger
+	grpcServer *grpc.Server
+	notifier   *notifier
+}
+
+// NewServer builds a Server. Call Start to begin serving.
+func NewServer(cfg Config, db *gorm.DB, rpcPool *rpc.Pool, manager *worker.Manager, logger zerolog.Logger) *Server {
+	return &Server{
+		config:  cfg,
+		db:      db,
+		rpcPool: rpcPool,
+		manager: manager,
+		logger:  logger.With().Str("component", "query_grpc").Logger(),
+	}
+}
+
+// Start begins serving the query API on Config.Addr. It blocks until the
+// listener fails or Stop is called, the same convention internal/grpcapi
+// and cmd/mercon's other servers use.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+
+	s.notifier = newNotifier(s.db, s.logger)
+
+	s.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.recoveryUnaryInterceptor, s.loggingMetricsUnaryInterceptor),
+	)
+	pb.RegisterWalletServiceServer(s.grpcServer, s)
+	pb.RegisterPositionServiceServer(s.grpcServer, s)
+	pb.RegisterAdminServiceServer(s.grpcServer, s)
+
+	s.logger.Info().Str("addr", s.config.Addr).Msg("Starting gRPC query API")
+	if err := s.grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("grpc server failed: %w", err)
+	}
+	return nil
+}
+
+// Stop gracefully shuts down the server, letting in-flight calls finish.
+func (s *Server) Stop() {
+	if s.notifier != nil {
+		s.notifier.Close()
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+}
+
+// GetWallet looks up a wallet by address.
+func (s *Server) GetWallet(ctx context.Context, req *pb.GetWalletRequest) (*pb.GetWalletResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+
+	var wallet models.Wallet
+	if err := s.db.WithContext(ctx).Where("address = ?", req.Address).First(&wallet).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, status.Errorf(codes.NotFound, "no wallet %q", req.Address)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to look up wallet: %v", err)
+	}
+
+	return &pb.GetWalletResponse{
+		Address:                wallet.Address,
+		FirstTransactionAtUnix: wallet.FirstTransactionAt.Unix(),
+		LastTransactionAtUnix:  wallet.LastTransactionAt.Unix(),
+		TransactionCount:       int32(wallet.TransactionCount),
+		SolBalance:             wallet.SOLBalance,
+		LastScrapedUnix:        wallet.LastScraped.Unix(),
+	}, nil
+}
+
+// ListPositions lists the positions a wallet holds, oldest first (by row ID).
+func (s *Server) ListPositions(ctx context.Context, req *pb.ListPositionsRequest) (*pb.ListPositionsResponse, error) {
+	if req.WalletAddress == "" {
+		return nil, status.Error(codes.InvalidArgument, "wallet_address is required")
+	}
+
+	var wallet models.Wallet
+	if err := s.db.WithContext(ctx).Where("address = ?", req.WalletAddress).First(&wallet).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, status.Errorf(codes.NotFound, "no wallet %q", req.WalletAddress)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to look up wallet: %v", err)
+	}
+
+	cursor, err := parseCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+	}
+	limit := pageLimit(req.Limit)
+
+	var rows []models.MeteoraPosition
+	q := s.db.WithContext(ctx).Where("wallet_id = ?", wallet.ID).Order("id ASC").Limit(limit + 1)
+	if cursor > 0 {
+		q = q.Where("id > ?", cursor)
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list positions: %v", err)
+	}
+
+	resp := &pb.ListPositionsResponse{}
+	rows, resp.NextCursor = paginate(rows, limit, func(p models.MeteoraPosition) uint { return p.ID })
+	for _, p := range rows {
+		resp.Positions = append(resp.Positions, &pb.Position{
+			Address:            p.Address,
+			Owner:              p.Owner,
+			TotalValueUsd:      p.TotalValueUSD,
+			TotalFeeUsdClaimed: p.TotalFeeUSDClaimed,
+			LastDataUpdateUnix: p.LastDataUpdate.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// StreamPositionEvents streams position activity (claims, deposits,
+// withdraws) as it's written to the analytics database, until the client
+// disconnects. See notify.go for how rows landing in Postgres become
+// events here.
+func (s *Server) StreamPositionEvents(req *pb.StreamPositionEventsRequest, stream pb.WalletService_StreamPositionEventsServer) error {
+	events, unsubscribe, err := s.notifier.subscribe(stream.Context(), req.WalletAddress)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to subscribe to position events: %v", err)
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetPosition looks up a single position by its on-chain address.
+func (s *Server) GetPosition(ctx context.Context, req *pb.GetPositionRequest) (*pb.GetPositionResponse, error) {
+	if req.PositionAddress == "" {
+		return nil, status.Error(codes.InvalidArgument, "position_address is required")
+	}
+
+	var p models.MeteoraPosition
+	if err := s.db.WithContext(ctx).Where("address = ?", req.PositionAddress).First(&p).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, status.Errorf(codes.NotFound, "no position %q", req.PositionAddress)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to look up position: %v", err)
+	}
+
+	return &pb.GetPositionResponse{Position: &pb.Position{
+		Address:            p.Address,
+		Owner:              p.Owner,
+		TotalValueUsd:      p.TotalValueUSD,
+		TotalFeeUsdClaimed: p.TotalFeeUSDClaimed,
+		LastDataUpdateUnix: p.LastDataUpdate.Unix(),
+	}}, nil
+}
+
+// ListClaims lists a position's fee claims oldest-to-newest.
+func (s *Server) ListClaims(ctx context.Context, req *pb.ListClaimsRequest) (*pb.ListClaimsResponse, error) {
+	positionID, err := s.positionIDByAddress(ctx, req.PositionAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := parseCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+	}
+	limit := pageLimit(req.Limit)
+
+	var rows []models.MeteoraFeeClaim
+	q := s.db.WithContext(ctx).Where("position_id = ?", positionID).Order("id ASC").Limit(limit + 1)
+	if cursor > 0 {
+		q = q.Where("id > ?", cursor)
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list claims: %v", err)
+	}
+
+	resp := &pb.ListClaimsResponse{}
+	rows, resp.NextCursor = paginate(rows, limit, func(c models.MeteoraFeeClaim) uint { return c.ID })
+	for _, c := range rows {
+		resp.Claims = append(resp.Claims, &pb.Claim{
+			Id:            int64(c.ID),
+			AmountX:       c.AmountX,
+			AmountY:       c.AmountY,
+			TotalValueUsd: c.TotalValueUSD,
+			ClaimTimeUnix: c.ClaimTime.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// ListDeposits lists a position's liquidity additions oldest-to-newest.
+func (s *Server) ListDeposits(ctx context.Context, req *pb.ListDepositsRequest) (*pb.ListDepositsResponse, error) {
+	positionID, err := s.positionIDByAddress(ctx, req.PositionAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := parseCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+	}
+	limit := pageLimit(req.Limit)
+
+	var rows []models.MeteoraLiquidityAddition
+	q := s.db.WithContext(ctx).Where("position_id = ?", positionID).Order("id ASC").Limit(limit + 1)
+	if cursor > 0 {
+		q = q.Where("id > ?", cursor)
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list deposits: %v", err)
+	}
+
+	resp := &pb.ListDepositsResponse{}
+	rows, resp.NextCursor = paginate(rows, limit, func(d models.MeteoraLiquidityAddition) uint { return d.ID })
+	for _, d := range rows {
+		resp.Deposits = append(resp.Deposits, &pb.Deposit{
+			Id:            int64(d.ID),
+			ActiveId:      d.ActiveID,
+			AmountX:       d.AmountX,
+			AmountY:       d.AmountY,
+			TotalValueUsd: d.TotalValueUSD,
+			AddTimeUnix:   d.AddTime.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// ListWithdraws lists a position's liquidity removals oldest-to-newest.
+func (s *Server) ListWithdraws(ctx context.Context, req *pb.ListWithdrawsRequest) (*pb.ListWithdrawsResponse, error) {
+	positionID, err := s.positionIDByAddress(ctx, req.PositionAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := parseCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+	}
+	limit := pageLimit(req.Limit)
+
+	var rows []models.MeteoraLiquidityRemoval
+	q := s.db.WithContext(ctx).Where("position_id = ?", positionID).Order("id ASC").Limit(limit + 1)
+	if cursor > 0 {
+		q = q.Where("id > ?", cursor)
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list withdraws: %v", err)
+	}
+
+	resp := &pb.ListWithdrawsResponse{}
+	rows, resp.NextCursor = paginate(rows, limit, func(w models.MeteoraLiquidityRemoval) uint { return w.ID })
+	for _, w := range rows {
+		resp.Withdraws = append(resp.Withdraws, &pb.Withdraw{
+			Id:             int64(w.ID),
+			AmountXRemoved: w.AmountXRemoved,
+			AmountYRemoved: w.AmountYRemoved,
+			TotalValueUsd:  w.TotalValueUSD,
+			RemoveTimeUnix: w.RemoveTime.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// positionIDByAddress resolves a position's on-chain address to its
+// primary key, the shared first step of every PositionService List* RPC.
+func (s *Server) positionIDByAddress(ctx context.Context, address string) (uint, error) {
+	if address == "" {
+		return 0, status.Error(codes.InvalidArgument, "position_address is required")
+	}
+	var p models.MeteoraPosition
+	if err := s.db.WithContext(ctx).Select("id").Where("address = ?", address).First(&p).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, status.Errorf(codes.NotFound, "no position %q", address)
+		}
+		return 0, status.Errorf(codes.Internal, "failed to look up position: %v", err)
+	}
+	return p.ID, nil
+}
+
+// GetStats reports the same figures as worker.Manager.GetStats, typed
+// instead of a map[string]interface{} over JSON.
+func (s *Server) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	stats := s.manager.GetStats()
+	return &pb.GetStatsResponse{
+		ActiveWorkers:              int32(statsInt(stats, "active_workers")),
+		QueueLength:                int64(statsInt(stats, "queue_length")),
+		InFlightWallets:            int32(statsInt(stats, "in_flight_wallets")),
+		HealthyEndpoints:           int32(statsInt(stats, "healthy_endpoints")),
+		MinWorkers:                 int32(statsInt(stats, "min_workers")),
+		MaxWorkers:                 int32(statsInt(stats, "max_workers")),
+		AvgWalletProcessingSeconds: statsFloat(stats, "avg_wallet_processing_seconds"),
+		RpcErrorRate:               statsFloat(stats, "rpc_error_rate"),
+	}, nil
+}
+
+// ListEndpoints reports every configured RPC endpoint's current health.
+func (s *Server) ListEndpoints(ctx context.Context, req *pb.ListEndpointsRequest) (*pb.ListEndpointsResponse, error) {
+	stats := s.rpcPool.GetStats()
+	endpoints, _ := stats["endpoints"].([]map[string]interface{})
+
+	resp := &pb.ListEndpointsResponse{Endpoints: make([]*pb.Endpoint, 0, len(endpoints))}
+	for _, e := range endpoints {
+		url, _ := e["url"].(string)
+		healthy, _ := e["healthy"].(bool)
+		errCount := statsInt(e, "consecutive_errors")
+		resp.Endpoints = append(resp.Endpoints, &pb.Endpoint{
+			Url:        url,
+			Healthy:    healthy,
+			ErrorCount: int64(errCount),
+		})
+	}
+	return resp, nil
+}
+
+// DrainEndpoint takes an endpoint out of rotation for DrainSeconds. Pool
+// has no notion of a stateless HTTP client "finishing" in-flight requests
+// before stopping, so this is implemented as Pool.SetCooldown rather than
+// a true connection drain - an honest narrower version of what the proto
+// field's name implies.
+func (s *Server) DrainEndpoint(ctx context.Context, req *pb.DrainEndpointRequest) (*pb.DrainEndpointResponse, error) {
+	if req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+	if req.DrainSeconds <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "drain_seconds must be positive")
+	}
+	s.rpcPool.SetCooldown(req.Url, time.Duration(req.DrainSeconds)*time.Second)
+	return &pb.DrainEndpointResponse{}, nil
+}
+
+// PauseWorkers pauses every currently running worker.
+func (s *Server) PauseWorkers(ctx context.Context, req *pb.PauseWorkersRequest) (*pb.PauseWorkersResponse, error) {
+	return &pb.PauseWorkersResponse{Paused: int32(s.manager.PauseAllWorkers())}, nil
+}
+
+func pageLimit(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageLimit
+	case requested > maxPageLimit:
+		return maxPageLimit
+	default:
+		return int(requested)
+	}
+}
+
+// parseCursor decodes a List* RPC's opaque cursor (the decimal row ID of
+// the last item seen on the previous page) back into an ID, treating ""
+// as "start from the beginning".
+func parseCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cursor %q is not a row ID", cursor)
+	}
+	return uint(id), nil
+}
+
+// paginate drops the lookahead row a List* RPC fetched (limit+1 rows) back
+// down to limit, and derives next_cursor - the decimal ID of the last row
+// kept - from whether that lookahead row existed.
+func paginate[T any](rows []T, limit int, idOf func(T) uint) ([]T, string) {
+	if len(rows) <= limit {
+		return rows, ""
+	}
+	rows = rows[:limit]
+	return rows, strconv.FormatUint(uint64(idOf(rows[limit-1])), 10)
+}
+
+func statsInt(m map[string]interface{}, key string) int {
+	switch v := m[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+func statsFloat(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,46 @@
+// Package assets provides a read-only asset-inventory view over a wallet's
+// parsed Meteora activity: which token mints a wallet has ever touched, and
+// its current exposure to each one, aggregated entirely in SQL so a call
+// doesn't cost a full transaction scan.
+package assets
+
+import "context"
+
+// TokenMetadata is what TokenMetadataResolver resolves a mint to.
+type TokenMetadata struct {
+	Symbol   string
+	Decimals uint8
+}
+
+// TokenMetadataResolver resolves a token mint's symbol and decimals, e.g.
+// from Metaplex on-chain metadata or a user-maintained registry. ListAssets
+// calls it once per distinct mint in the aggregated result, not once per
+// row, so a slow implementation doesn't scale with transaction count.
+type TokenMetadataResolver interface {
+	Resolve(ctx context.Context, mint string) (TokenMetadata, error)
+}
+
+// AssetSummary aggregates one wallet's Meteora-derived exposure to a single
+// token mint.
+type AssetSummary struct {
+	Mint     string
+	Symbol   string
+	Decimals uint8
+
+	// TotalIn and TotalOut are lifetime swap volume into and out of this
+	// mint, summed across every MeteoraSwap where it was the input or
+	// output side.
+	TotalIn  uint64
+	TotalOut uint64
+
+	// LiquidityProvided is this wallet's current contribution of this mint
+	// across positions that are still open: total added minus total
+	// removed, on whichever side (X or Y) of each pair the mint sits.
+	LiquidityProvided int64
+
+	// FeesClaimed and RewardsClaimed are lifetime totals pulled from
+	// MeteoraFeeClaim/MeteoraRewardClaim rows. These are claimed amounts,
+	// not unclaimed balances - see ListAssets' doc comment for why.
+	FeesClaimed    uint64
+	RewardsClaimed uint64
+}
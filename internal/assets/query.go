@@ -0,0 +1,207 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// ListAssets returns, for every token mint walletAddress has ever touched
+// through Meteora, the aggregate exposure described by AssetSummary,
+// sorted by mint. Every aggregate is computed with a GROUP BY in the
+// database rather than by loading and summing rows in Go, so the cost of a
+// call doesn't grow with the wallet's transaction count.
+//
+// FeesClaimed and RewardsClaimed report what MeteoraFeeClaim/
+// MeteoraRewardClaim rows say this wallet has already claimed - they are
+// not a "how much is there to claim right now" balance. Answering that
+// would require reading each open position's live on-chain accrued-fee and
+// accrued-reward state, which isn't something this ingestion pipeline
+// tracks; only claims that have actually happened show up as parsed rows.
+func ListAssets(ctx context.Context, db *gorm.DB, walletAddress string, resolver TokenMetadataResolver) ([]AssetSummary, error) {
+	tx := db.WithContext(ctx)
+
+	var walletID uint
+	err := tx.Model(&models.Wallet{}).Select("id").Where("address = ?", walletAddress).Take(&walletID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolving wallet address: %w", err)
+	}
+
+	totals := make(map[string]*AssetSummary)
+	summaryFor := func(mint string) *AssetSummary {
+		s, ok := totals[mint]
+		if !ok {
+			s = &AssetSummary{Mint: mint}
+			totals[mint] = s
+		}
+		return s
+	}
+
+	for _, agg := range mintAggregates(walletID) {
+		var rows []mintSum
+		if err := agg.query(tx).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", agg.label, err)
+		}
+		for _, r := range rows {
+			if r.Mint == "" {
+				continue
+			}
+			agg.assign(summaryFor(r.Mint), r.Sum)
+		}
+	}
+
+	summaries := make([]AssetSummary, 0, len(totals))
+	for _, s := range totals {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Mint < summaries[j].Mint })
+
+	for i := range summaries {
+		meta, err := resolver.Resolve(ctx, summaries[i].Mint)
+		if err != nil {
+			return nil, fmt.Errorf("resolving metadata for mint %s: %w", summaries[i].Mint, err)
+		}
+		summaries[i].Symbol = meta.Symbol
+		summaries[i].Decimals = meta.Decimals
+	}
+
+	return summaries, nil
+}
+
+// mintSum is the shape every per-mint GROUP BY aggregate scans into.
+type mintSum struct {
+	Mint string
+	Sum  int64
+}
+
+// mintAggregate is one GROUP BY query contributing to a mint's
+// AssetSummary, plus how to fold its result into that summary.
+type mintAggregate struct {
+	label  string
+	query  func(tx *gorm.DB) *gorm.DB
+	assign func(s *AssetSummary, v int64)
+}
+
+// mintAggregates lists every query ListAssets runs to build up per-mint
+// totals for walletID.
+func mintAggregates(walletID uint) []mintAggregate {
+	// openPosition restricts a liquidity addition/removal query to
+	// positions that are still open, so closed positions don't count
+	// towards currently-provided liquidity.
+	openPosition := func(table string) string {
+		return fmt.Sprintf(
+			"JOIN meteora_positions ON meteora_positions.id = %s.position_id AND meteora_positions.closed_at IS NULL",
+			table,
+		)
+	}
+
+	return []mintAggregate{
+		{
+			label: "aggregating swap inflows",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_swaps").
+					Select("token_in_mint AS mint, SUM(amount_in) AS sum").
+					Where("wallet_id = ?", walletID).
+					Group("token_in_mint")
+			},
+			assign: func(s *AssetSummary, v int64) { s.TotalIn += uint64(v) },
+		},
+		{
+			label: "aggregating swap outflows",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_swaps").
+					Select("token_out_mint AS mint, SUM(amount_out) AS sum").
+					Where("wallet_id = ?", walletID).
+					Group("token_out_mint")
+			},
+			assign: func(s *AssetSummary, v int64) { s.TotalOut += uint64(v) },
+		},
+		{
+			label: "aggregating liquidity added (X side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_liquidity_additions").
+					Select("meteora_pairs.token_mint_x AS mint, SUM(meteora_liquidity_additions.amount_x) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_liquidity_additions.pair_id").
+					Joins(openPosition("meteora_liquidity_additions")).
+					Where("meteora_liquidity_additions.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_x")
+			},
+			assign: func(s *AssetSummary, v int64) { s.LiquidityProvided += v },
+		},
+		{
+			label: "aggregating liquidity added (Y side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_liquidity_additions").
+					Select("meteora_pairs.token_mint_y AS mint, SUM(meteora_liquidity_additions.amount_y) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_liquidity_additions.pair_id").
+					Joins(openPosition("meteora_liquidity_additions")).
+					Where("meteora_liquidity_additions.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_y")
+			},
+			assign: func(s *AssetSummary, v int64) { s.LiquidityProvided += v },
+		},
+		{
+			label: "aggregating liquidity removed (X side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_liquidity_removals").
+					Select("meteora_pairs.token_mint_x AS mint, SUM(meteora_liquidity_removals.amount_x_removed) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_liquidity_removals.pair_id").
+					Joins(openPosition("meteora_liquidity_removals")).
+					Where("meteora_liquidity_removals.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_x")
+			},
+			assign: func(s *AssetSummary, v int64) { s.LiquidityProvided -= v },
+		},
+		{
+			label: "aggregating liquidity removed (Y side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_liquidity_removals").
+					Select("meteora_pairs.token_mint_y AS mint, SUM(meteora_liquidity_removals.amount_y_removed) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_liquidity_removals.pair_id").
+					Joins(openPosition("meteora_liquidity_removals")).
+					Where("meteora_liquidity_removals.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_y")
+			},
+			assign: func(s *AssetSummary, v int64) { s.LiquidityProvided -= v },
+		},
+		{
+			label: "aggregating fee claims (X side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_fee_claims").
+					Select("meteora_pairs.token_mint_x AS mint, SUM(meteora_fee_claims.amount_x) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_fee_claims.pair_id").
+					Where("meteora_fee_claims.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_x")
+			},
+			assign: func(s *AssetSummary, v int64) { s.FeesClaimed += uint64(v) },
+		},
+		{
+			label: "aggregating fee claims (Y side)",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_fee_claims").
+					Select("meteora_pairs.token_mint_y AS mint, SUM(meteora_fee_claims.amount_y) AS sum").
+					Joins("JOIN meteora_pairs ON meteora_pairs.id = meteora_fee_claims.pair_id").
+					Where("meteora_fee_claims.wallet_id = ?", walletID).
+					Group("meteora_pairs.token_mint_y")
+			},
+			assign: func(s *AssetSummary, v int64) { s.FeesClaimed += uint64(v) },
+		},
+		{
+			label: "aggregating reward claims",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meteora_reward_claims").
+					Select("meteora_rewards.reward_mint AS mint, SUM(meteora_reward_claims.amount) AS sum").
+					Joins("JOIN meteora_rewards ON meteora_rewards.id = meteora_reward_claims.reward_id").
+					Where("meteora_reward_claims.wallet_id = ?", walletID).
+					Group("meteora_rewards.reward_mint")
+			},
+			assign: func(s *AssetSummary, v int64) { s.RewardsClaimed += uint64(v) },
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+)
+
+// MapTokenMetadataResolver is a TokenMetadataResolver backed by a
+// caller-supplied mint-to-metadata registry, e.g. a curated list of known
+// tokens loaded from config.
+type MapTokenMetadataResolver map[string]TokenMetadata
+
+// Resolve looks mint up in the map. An unknown mint is not an error: it
+// resolves to the zero TokenMetadata, leaving Symbol/Decimals blank rather
+// than failing the whole ListAssets call over one untracked token.
+func (r MapTokenMetadataResolver) Resolve(ctx context.Context, mint string) (TokenMetadata, error) {
+	return r[mint], nil
+}
+
+// MintAccountReader fetches the raw bytes of an SPL mint account and a
+// Metaplex token-metadata PDA, so MetaplexTokenMetadataResolver stays
+// testable without a live RPC client - the same shape as price_oracle.go's
+// PythAccountReader.
+type MintAccountReader interface {
+	ReadMintAccount(ctx context.Context, mint string) ([]byte, error)
+	ReadMetadataAccount(ctx context.Context, mint string) ([]byte, error)
+}
+
+// MetaplexTokenMetadataResolver resolves decimals from a token's SPL mint
+// account and its symbol from the corresponding Metaplex token-metadata
+// PDA, via reader.
+type MetaplexTokenMetadataResolver struct {
+	reader MintAccountReader
+}
+
+// NewMetaplexTokenMetadataResolver builds a TokenMetadataResolver that
+// reads decimals/symbol straight out of on-chain accounts via reader.
+func NewMetaplexTokenMetadataResolver(reader MintAccountReader) *MetaplexTokenMetadataResolver {
+	return &MetaplexTokenMetadataResolver{reader: reader}
+}
+
+// SPL mint account layout (spl-token Mint struct): mint_authority (36:
+// option tag + pubkey), supply (8), decimals (1), ... - decimals sits at a
+// fixed offset.
+const (
+	splMintOffsetDecimals = 44
+	splMintMinAccountLen  = 45
+)
+
+// Metaplex token-metadata account layout: key (1) + update_authority (32)
+// + mint (32), followed by the borsh-encoded Data struct, whose name and
+// symbol fields are each a 4-byte little-endian length prefix followed by
+// that many bytes (not padded to their max length in the stored account).
+const metaplexNameOffset = 1 + 32 + 32
+
+func (r *MetaplexTokenMetadataResolver) Resolve(ctx context.Context, mint string) (TokenMetadata, error) {
+	mintData, err := r.reader.ReadMintAccount(ctx, mint)
+	if err != nil {
+		return TokenMetadata{}, fmt.Errorf("reading mint account: %w", err)
+	}
+	if len(mintData) < splMintMinAccountLen {
+		return TokenMetadata{}, fmt.Errorf("mint account too short (%d bytes)", len(mintData))
+	}
+	decimals := mintData[splMintOffsetDecimals]
+
+	metadataData, err := r.reader.ReadMetadataAccount(ctx, mint)
+	if err != nil {
+		return TokenMetadata{}, fmt.Errorf("reading metadata account: %w", err)
+	}
+	symbol, err := parseMetaplexSymbol(metadataData)
+	if err != nil {
+		return TokenMetadata{}, fmt.Errorf("parsing metadata account: %w", err)
+	}
+
+	return TokenMetadata{Symbol: symbol, Decimals: decimals}, nil
+}
+
+func parseMetaplexSymbol(data []byte) (string, error) {
+	if len(data) < metaplexNameOffset+4 {
+		return "", fmt.Errorf("account too short for name length (%d bytes)", len(data))
+	}
+	nameLen := int(leUint32(data[metaplexNameOffset:]))
+	symbolLenOffset := metaplexNameOffset + 4 + nameLen
+	if len(data) < symbolLenOffset+4 {
+		return "", fmt.Errorf("account too short for symbol length (%d bytes)", len(data))
+	}
+	symbolLen := int(leUint32(data[symbolLenOffset:]))
+	symbolOffset := symbolLenOffset + 4
+	if len(data) < symbolOffset+symbolLen {
+		return "", fmt.Errorf("account too short for symbol bytes (%d bytes)", len(data))
+	}
+	return string(data[symbolOffset : symbolOffset+symbolLen]), nil
+}
+
+func leUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
@@ -0,0 +1,88 @@
+package assets
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMapTokenMetadataResolver(t *testing.T) {
+	registry := MapTokenMetadataResolver{
+		"MintA": {Symbol: "AAA", Decimals: 6},
+	}
+
+	meta, err := registry.Resolve(context.Background(), "MintA")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if meta.Symbol != "AAA" || meta.Decimals != 6 {
+		t.Errorf("Resolve(MintA) = %+v, want {AAA 6}", meta)
+	}
+
+	meta, err = registry.Resolve(context.Background(), "Unknown")
+	if err != nil {
+		t.Fatalf("Resolve returned error for unknown mint: %v", err)
+	}
+	if meta != (TokenMetadata{}) {
+		t.Errorf("Resolve(Unknown) = %+v, want zero value", meta)
+	}
+}
+
+type fakeMintAccountReader struct {
+	mint     []byte
+	metadata []byte
+}
+
+func (r fakeMintAccountReader) ReadMintAccount(ctx context.Context, mint string) ([]byte, error) {
+	return r.mint, nil
+}
+
+func (r fakeMintAccountReader) ReadMetadataAccount(ctx context.Context, mint string) ([]byte, error) {
+	return r.metadata, nil
+}
+
+// buildMetaplexAccount assembles a minimal Metaplex token-metadata account
+// with the given name/symbol, enough for parseMetaplexSymbol to exercise.
+func buildMetaplexAccount(name, symbol string) []byte {
+	data := make([]byte, metaplexNameOffset)
+
+	putLen := func(n int) {
+		data = append(data, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+
+	putLen(len(name))
+	data = append(data, []byte(name)...)
+	putLen(len(symbol))
+	data = append(data, []byte(symbol)...)
+
+	return data
+}
+
+func TestMetaplexTokenMetadataResolver(t *testing.T) {
+	mintAccount := make([]byte, splMintMinAccountLen)
+	mintAccount[splMintOffsetDecimals] = 9
+
+	reader := fakeMintAccountReader{
+		mint:     mintAccount,
+		metadata: buildMetaplexAccount("Wrapped SOL", "wSOL"),
+	}
+	resolver := NewMetaplexTokenMetadataResolver(reader)
+
+	meta, err := resolver.Resolve(context.Background(), "So11111111111111111111111111111111111111112")
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if meta.Symbol != "wSOL" || meta.Decimals != 9 {
+		t.Errorf("Resolve() = %+v, want {wSOL 9}", meta)
+	}
+}
+
+func TestMetaplexTokenMetadataResolver_ShortAccounts(t *testing.T) {
+	resolver := NewMetaplexTokenMetadataResolver(fakeMintAccountReader{
+		mint:     []byte{1, 2, 3},
+		metadata: buildMetaplexAccount("X", "X"),
+	})
+
+	if _, err := resolver.Resolve(context.Background(), "mint"); err == nil {
+		t.Error("Resolve() with a too-short mint account should return an error")
+	}
+}
@@ -0,0 +1,120 @@
+// Package backoff provides cancellation-aware, jittered retry delays for
+// the worker loop. Each failure class (RPC rate limit, RPC 5xx, queue
+// empty, DB write failure, parse failure) gets its own Tracker so that
+// repeated failures of one kind grow that kind's delay independently,
+// instead of every failure path sharing a single hardcoded sleep.
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Strategy computes the delay before the next attempt. consecutiveFailures
+// is the number of failures seen in a row since the last success (0 for the
+// first failure); lastDelay is the delay Strategy itself returned last time,
+// which DecorrelatedJitter needs and the others ignore.
+type Strategy interface {
+	Next(consecutiveFailures int, lastDelay time.Duration) time.Duration
+}
+
+// Constant always waits the same delay, regardless of how many failures
+// have happened in a row. Used for the "queue is empty" pause, which isn't
+// really a failure and shouldn't grow over time.
+type Constant struct {
+	Delay time.Duration
+}
+
+func (c Constant) Next(consecutiveFailures int, lastDelay time.Duration) time.Duration {
+	return c.Delay
+}
+
+// ExponentialWithJitter doubles the delay with each consecutive failure,
+// capped at Max, and returns a random value in (0, delay] so that many
+// workers failing at the same moment don't all retry in lockstep.
+type ExponentialWithJitter struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (e ExponentialWithJitter) Next(consecutiveFailures int, lastDelay time.Duration) time.Duration {
+	shift := consecutiveFailures
+	if shift > 32 {
+		shift = 32
+	}
+	delay := e.Base * time.Duration(uint64(1)<<uint(shift))
+	if delay <= 0 || delay > e.Max {
+		delay = e.Max
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// DecorrelatedJitter implements the "decorrelated jitter" backoff from
+// AWS's retry guidance: each delay is a random value between Base and three
+// times the previous delay, capped at Max. It spreads retries out more than
+// plain exponential backoff does once several workers are already jittered.
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (d DecorrelatedJitter) Next(consecutiveFailures int, lastDelay time.Duration) time.Duration {
+	prev := lastDelay
+	if prev < d.Base {
+		prev = d.Base
+	}
+
+	upper := prev * 3
+	if upper > d.Max {
+		upper = d.Max
+	}
+	if upper <= d.Base {
+		return d.Base
+	}
+
+	return d.Base + time.Duration(rand.Int63n(int64(upper-d.Base)))
+}
+
+// Tracker applies a Strategy to a single failure class, remembering how
+// many times in a row it has failed and the delay it last returned. Success
+// resets both, so the next failure starts the strategy over from scratch.
+// Safe for concurrent use is not required here - each Tracker belongs to a
+// single worker goroutine.
+type Tracker struct {
+	strategy    Strategy
+	consecutive int
+	lastDelay   time.Duration
+}
+
+// NewTracker creates a Tracker driven by strategy.
+func NewTracker(strategy Strategy) *Tracker {
+	return &Tracker{strategy: strategy}
+}
+
+// Next records a failure and returns how long to wait before the next
+// attempt.
+func (t *Tracker) Next() time.Duration {
+	delay := t.strategy.Next(t.consecutive, t.lastDelay)
+	t.consecutive++
+	t.lastDelay = delay
+	return delay
+}
+
+// Succeed resets the tracker after a successful attempt.
+func (t *Tracker) Succeed() {
+	t.consecutive = 0
+	t.lastDelay = 0
+}
+
+// Sleep waits for d or until ctx is cancelled, returning ctx.Err() in the
+// latter case. Every pause in the worker loop goes through this so none of
+// them can block shutdown.
+func Sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
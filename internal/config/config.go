@@ -5,35 +5,86 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/wnt/mercon/internal/rpc"
 )
 
 // Config holds all configuration for Mercon
 type Config struct {
 	// Redis configuration
 	RedisURL string
+	// QueueBackend selects internal/worker.Manager's wallet queue
+	// implementation: "zset" (default) is internal/queue.Client's
+	// sorted-set queue; "streams" hands workers an
+	// internal/queue.StreamClient backed by Redis Streams and consumer
+	// groups instead. Manager's admin/DLQ/scaling operations stay on the
+	// ZSET client regardless - see queue.StreamClient's doc comment.
+	QueueBackend string
 
 	// Database configuration
 	ChainDBName     string
-	ChainDBHost       string
-	ChainDBUser       string
-	ChainDBPassword   string
-	ChainDBPort       string
-	ChainDBSSLMode    string
+	ChainDBHost     string
+	ChainDBUser     string
+	ChainDBPassword string
+	ChainDBPort     string
+	ChainDBSSLMode  string
 
 	AnalyticsDBName     string
-	AnalyticsDBHost       string
-	AnalyticsDBUser       string
-	AnalyticsDBPassword   string
-	AnalyticsDBPort       string
-	AnalyticsDBSSLMode    string
+	AnalyticsDBHost     string
+	AnalyticsDBUser     string
+	AnalyticsDBPassword string
+	AnalyticsDBPort     string
+	AnalyticsDBSSLMode  string
 
 	// RPC configuration
-	RPCEndpoints []string
+	RPCEndpoints     []string
+	RPCSchedulerMode string
+	RPCStickyWallets bool
+	// RPCVerifyQuorum, e.g. "2/3", enables quorum verification of fetched
+	// transactions (see internal/rpc.WithVerifyQuorum). Empty disables it.
+	RPCVerifyQuorum string
+	// RPCWSIngestionEnabled turns on websocket-based signature discovery
+	// (see internal/rpc/ws.Subscriber) as a lower-latency supplement to
+	// polling-based FetchSignatures. Endpoints that refuse the websocket
+	// upgrade are transparently left on the polling path.
+	RPCWSIngestionEnabled bool
+	// RPCMaxRetries and RPCQuarantineDuration configure
+	// internal/solana.EndpointPool, the health-scored pool *solana.Client
+	// picks endpoints from for its own raw RPC calls (GetSlot, GetBlocks,
+	// ReadAccounts) - a separate pool from the one RPCSchedulerMode/
+	// RPCStickyWallets tune above, which serves internal/worker.Manager's
+	// fetch path instead. See internal/solana.loadConfigFromEnv for why
+	// *Client reads RPC_ENDPOINTS/RPC_MAX_RETRIES/RPC_QUARANTINE_DURATION
+	// from the environment directly rather than through this Config.
+	RPCMaxRetries         int
+	RPCQuarantineDuration time.Duration
+
+	// MeteoraMetricsEnabled registers internal/meteorametrics.Collector,
+	// which republishes live Meteora pair/position/reward figures as
+	// Prometheus gauges, with the default registry.
+	MeteoraMetricsEnabled bool
 
 	// Worker configuration
 	MinWorkers int
 	MaxWorkers int
 
+	// MaxWalletRequeues caps how many times RequeueStuckWallets will put a
+	// stuck wallet back on the main queue before routing it to the DLQ
+	// instead, so a consistently-failing wallet can't cycle forever and
+	// starve healthy work.
+	MaxWalletRequeues int
+
+	// Autoscaler configuration: calculateDesiredWorkers uses a PID
+	// controller with two setpoints (queue drain time and RPC error
+	// rate) to decide how many workers to run.
+	ScalerKp                     float64
+	ScalerKi                     float64
+	ScalerKd                     float64
+	ScalerTargetQueueDrainTime   time.Duration
+	ScalerTargetRPCErrorRate     float64
+	ScalerConcurrencyPerEndpoint int
+
 	// Logging configuration
 	LogLevel string
 
@@ -42,27 +93,52 @@ type Config struct {
 
 	// Metrics configuration
 	MetricsPort string
+
+	// HTTP JSON-RPC admin/control API configuration
+	HTTPRPCAddr        string
+	HTTPRPCAPI         []string
+	HTTPRPCBearerToken string
+
+	// gRPC control plane configuration
+	GRPCAddr   string
+	GRPCTokens map[string][]string
+
+	// gRPC query API configuration (internal/api/grpc) - read-only
+	// WalletService/PositionService/AdminService, disabled unless set.
+	QueryGRPCAddr string
 }
 
 // Load reads configuration from environment variables and validates it
 func Load() (Config, error) {
 	cfg := Config{
-		RedisURL:       getEnv("REDIS_URL", "redis://localhost:6379"),
-		ChainDBName:     getEnv("CHAIN_DB_NAME", ""),
-		ChainDBHost:     getEnv("CHAIN_DB_HOST", ""),
-		ChainDBUser:     getEnv("CHAIN_DB_USER", ""),
-		ChainDBPassword: getEnv("CHAIN_DB_PASSWORD", ""),
-		ChainDBPort:     getEnv("CHAIN_DB_PORT", ""),
-		ChainDBSSLMode:  getEnv("CHAIN_DB_SSL_MODE", ""),
-		AnalyticsDBName: getEnv("ANALYTICS_DB_NAME", ""),
-		AnalyticsDBHost: getEnv("ANALYTICS_DB_HOST", ""),
-		AnalyticsDBUser: getEnv("ANALYTICS_DB_USER", ""),
+		RedisURL:            getEnv("REDIS_URL", "redis://localhost:6379"),
+		QueueBackend:        getEnv("QUEUE_BACKEND", "zset"),
+		ChainDBName:         getEnv("CHAIN_DB_NAME", ""),
+		ChainDBHost:         getEnv("CHAIN_DB_HOST", ""),
+		ChainDBUser:         getEnv("CHAIN_DB_USER", ""),
+		ChainDBPassword:     getEnv("CHAIN_DB_PASSWORD", ""),
+		ChainDBPort:         getEnv("CHAIN_DB_PORT", ""),
+		ChainDBSSLMode:      getEnv("CHAIN_DB_SSL_MODE", ""),
+		AnalyticsDBName:     getEnv("ANALYTICS_DB_NAME", ""),
+		AnalyticsDBHost:     getEnv("ANALYTICS_DB_HOST", ""),
+		AnalyticsDBUser:     getEnv("ANALYTICS_DB_USER", ""),
 		AnalyticsDBPassword: getEnv("ANALYTICS_DB_PASSWORD", ""),
-		AnalyticsDBPort: getEnv("ANALYTICS_DB_PORT", ""),
-		AnalyticsDBSSLMode: getEnv("ANALYTICS_DB_SSL_MODE", ""),
-		LogLevel:       getEnv("LOG_LEVEL", "info"),
-		PosthogKey:     getEnv("POSTHOG_KEY", ""),
-		MetricsPort:    getEnv("METRICS_PORT", "9100"),
+		AnalyticsDBPort:     getEnv("ANALYTICS_DB_PORT", ""),
+		AnalyticsDBSSLMode:  getEnv("ANALYTICS_DB_SSL_MODE", ""),
+		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		PosthogKey:          getEnv("POSTHOG_KEY", ""),
+		MetricsPort:         getEnv("METRICS_PORT", "9100"),
+		HTTPRPCAddr:         getEnv("HTTP_RPC_ADDR", ":8090"),
+		HTTPRPCBearerToken:  getEnv("HTTP_RPC_BEARER_TOKEN", ""),
+	}
+
+	// Parse HTTP RPC admin API module list (empty means the admin API is
+	// disabled entirely, same convention as RPC_ENDPOINTS being required)
+	if httpRPCAPIStr := getEnv("HTTP_RPC_API", ""); httpRPCAPIStr != "" {
+		cfg.HTTPRPCAPI = strings.Split(httpRPCAPIStr, ",")
+		for i, module := range cfg.HTTPRPCAPI {
+			cfg.HTTPRPCAPI[i] = strings.TrimSpace(module)
+		}
 	}
 
 	// Parse RPC endpoints
@@ -75,8 +151,51 @@ func Load() (Config, error) {
 		cfg.RPCEndpoints[i] = strings.TrimSpace(endpoint)
 	}
 
-	// Parse worker configuration
+	cfg.RPCSchedulerMode = getEnv("RPC_SCHEDULER_MODE", "round_robin")
+
 	var err error
+	cfg.RPCStickyWallets, err = parseBoolEnv("RPC_STICKY_WALLETS", false)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid RPC_STICKY_WALLETS: %w", err)
+	}
+
+	cfg.RPCVerifyQuorum = getEnv("RPC_VERIFY_QUORUM", "")
+
+	cfg.RPCWSIngestionEnabled, err = parseBoolEnv("RPC_WS_INGESTION_ENABLED", false)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid RPC_WS_INGESTION_ENABLED: %w", err)
+	}
+
+	cfg.RPCMaxRetries, err = parseIntEnv("RPC_MAX_RETRIES", 3)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid RPC_MAX_RETRIES: %w", err)
+	}
+
+	cfg.RPCQuarantineDuration = 30 * time.Second
+	if quarantineStr := getEnv("RPC_QUARANTINE_DURATION", ""); quarantineStr != "" {
+		val, parseErr := time.ParseDuration(quarantineStr)
+		if parseErr != nil || val <= 0 {
+			return cfg, fmt.Errorf("invalid RPC_QUARANTINE_DURATION: %s", quarantineStr)
+		}
+		cfg.RPCQuarantineDuration = val
+	}
+
+	cfg.MeteoraMetricsEnabled, err = parseBoolEnv("METEORA_METRICS_ENABLED", false)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid METEORA_METRICS_ENABLED: %w", err)
+	}
+
+	// gRPC control plane: disabled unless GRPC_ADDR is set
+	cfg.GRPCAddr = getEnv("GRPC_ADDR", "")
+	cfg.GRPCTokens, err = parseGRPCTokens(getEnv("GRPC_AUTH_TOKENS", ""))
+	if err != nil {
+		return cfg, fmt.Errorf("invalid GRPC_AUTH_TOKENS: %w", err)
+	}
+
+	// gRPC query API: disabled unless QUERY_GRPC_ADDR is set
+	cfg.QueryGRPCAddr = getEnv("QUERY_GRPC_ADDR", "")
+
+	// Parse worker configuration
 	cfg.MinWorkers, err = parseIntEnv("MIN_WORKERS", 4)
 	if err != nil {
 		return cfg, fmt.Errorf("invalid MIN_WORKERS: %w", err)
@@ -87,6 +206,43 @@ func Load() (Config, error) {
 		return cfg, fmt.Errorf("invalid MAX_WORKERS: %w", err)
 	}
 
+	cfg.MaxWalletRequeues, err = parseIntEnv("MAX_WALLET_REQUEUES", 5)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid MAX_WALLET_REQUEUES: %w", err)
+	}
+
+	// Parse autoscaler configuration
+	cfg.ScalerKp, err = parseFloatEnv("SCALER_KP", 2.0)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_KP: %w", err)
+	}
+
+	cfg.ScalerKi, err = parseFloatEnv("SCALER_KI", 0.1)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_KI: %w", err)
+	}
+
+	cfg.ScalerKd, err = parseFloatEnv("SCALER_KD", 0.05)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_KD: %w", err)
+	}
+
+	targetQueueDrainSeconds, err := parseFloatEnv("SCALER_TARGET_QUEUE_DRAIN_SECONDS", 60)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_TARGET_QUEUE_DRAIN_SECONDS: %w", err)
+	}
+	cfg.ScalerTargetQueueDrainTime = time.Duration(targetQueueDrainSeconds * float64(time.Second))
+
+	cfg.ScalerTargetRPCErrorRate, err = parseFloatEnv("SCALER_TARGET_RPC_ERROR_RATE", 0.05)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_TARGET_RPC_ERROR_RATE: %w", err)
+	}
+
+	cfg.ScalerConcurrencyPerEndpoint, err = parseIntEnv("SCALER_CONCURRENCY_PER_ENDPOINT", 5)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid SCALER_CONCURRENCY_PER_ENDPOINT: %w", err)
+	}
+
 	// Validate configuration
 	if err := cfg.validate(); err != nil {
 		return cfg, fmt.Errorf("configuration validation failed: %w", err)
@@ -121,6 +277,26 @@ func (c Config) validate() error {
 		return fmt.Errorf("MAX_WORKERS must be greater than or equal to MIN_WORKERS")
 	}
 
+	if c.MaxWalletRequeues < 1 {
+		return fmt.Errorf("MAX_WALLET_REQUEUES must be at least 1")
+	}
+
+	if c.ScalerKp < 0 || c.ScalerKi < 0 || c.ScalerKd < 0 {
+		return fmt.Errorf("SCALER_KP, SCALER_KI and SCALER_KD must be non-negative")
+	}
+
+	if c.ScalerTargetQueueDrainTime <= 0 {
+		return fmt.Errorf("SCALER_TARGET_QUEUE_DRAIN_SECONDS must be positive")
+	}
+
+	if c.ScalerTargetRPCErrorRate <= 0 || c.ScalerTargetRPCErrorRate > 1 {
+		return fmt.Errorf("SCALER_TARGET_RPC_ERROR_RATE must be in (0, 1]")
+	}
+
+	if c.ScalerConcurrencyPerEndpoint < 1 {
+		return fmt.Errorf("SCALER_CONCURRENCY_PER_ENDPOINT must be at least 1")
+	}
+
 	validLogLevels := map[string]bool{
 		"trace": true,
 		"debug": true,
@@ -135,6 +311,39 @@ func (c Config) validate() error {
 		return fmt.Errorf("invalid LOG_LEVEL: %s (must be one of: trace, debug, info, warn, error, fatal, panic)", c.LogLevel)
 	}
 
+	if len(c.HTTPRPCAPI) > 0 && c.HTTPRPCBearerToken == "" {
+		return fmt.Errorf("HTTP_RPC_BEARER_TOKEN is required when HTTP_RPC_API is set")
+	}
+
+	validSchedulerModes := map[string]bool{
+		"round_robin":   true,
+		"first_healthy": true,
+		"least_latency": true,
+		"scored":        true,
+	}
+
+	if !validSchedulerModes[c.RPCSchedulerMode] {
+		return fmt.Errorf("invalid RPC_SCHEDULER_MODE: %s (must be one of: round_robin, first_healthy, least_latency, scored)", c.RPCSchedulerMode)
+	}
+
+	if c.QueueBackend != "zset" && c.QueueBackend != "streams" {
+		return fmt.Errorf("invalid QUEUE_BACKEND: %s (must be one of: zset, streams)", c.QueueBackend)
+	}
+
+	if c.RPCVerifyQuorum != "" {
+		spec, err := rpc.ParseQuorumSpec(c.RPCVerifyQuorum)
+		if err != nil {
+			return fmt.Errorf("invalid RPC_VERIFY_QUORUM: %w", err)
+		}
+		if spec.N > len(c.RPCEndpoints) {
+			return fmt.Errorf("invalid RPC_VERIFY_QUORUM: %s needs %d endpoints but only %d are configured", c.RPCVerifyQuorum, spec.N, len(c.RPCEndpoints))
+		}
+	}
+
+	if c.GRPCAddr != "" && len(c.GRPCTokens) == 0 {
+		return fmt.Errorf("GRPC_AUTH_TOKENS is required when GRPC_ADDR is set")
+	}
+
 	return nil
 }
 
@@ -153,4 +362,51 @@ func parseIntEnv(key string, defaultValue int) (int, error) {
 		return defaultValue, nil
 	}
 	return strconv.Atoi(str)
-} 
\ No newline at end of file
+}
+
+// parseBoolEnv parses a boolean environment variable with a default value
+func parseBoolEnv(key string, defaultValue bool) (bool, error) {
+	str := os.Getenv(key)
+	if str == "" {
+		return defaultValue, nil
+	}
+	return strconv.ParseBool(str)
+}
+
+// parseFloatEnv parses a floating-point environment variable with a default value
+func parseFloatEnv(key string, defaultValue float64) (float64, error) {
+	str := os.Getenv(key)
+	if str == "" {
+		return defaultValue, nil
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// parseGRPCTokens parses GRPC_AUTH_TOKENS, a ";"-separated list of
+// "token=perm1,perm2" entries, into a token -> granted permissions map.
+func parseGRPCTokens(raw string) (map[string][]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	tokens := make(map[string][]string)
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		token, permsStr, ok := strings.Cut(entry, "=")
+		if !ok || token == "" || permsStr == "" {
+			return nil, fmt.Errorf("malformed entry %q (want token=perm1,perm2)", entry)
+		}
+
+		perms := strings.Split(permsStr, ",")
+		for i, perm := range perms {
+			perms[i] = strings.TrimSpace(perm)
+		}
+		tokens[token] = perms
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,14 @@
+package conformance
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	vectorsDir := os.Getenv("MERCON_METEORA_VECTORS_DIR")
+	if vectorsDir == "" {
+		vectorsDir = DefaultVectorsDir
+	}
+	Run(t, vectorsDir)
+}
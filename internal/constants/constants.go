@@ -0,0 +1,10 @@
+// Package constants holds program IDs and API endpoints shared across the
+// internal packages.
+package constants
+
+// MeteoraDLMM is the program ID of the Meteora DLMM (Dynamic Liquidity
+// Market Maker) program on Solana mainnet.
+const MeteoraDLMM = "LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo"
+
+// HeliusBaseURL is the base URL for the Helius enhanced transactions API.
+const HeliusBaseURL = "https://api.helius.xyz"
@@ -1,17 +1,59 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/database/migrations"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
+const (
+	defaultMaxIdleConns    = 10
+	defaultMaxOpenConns    = 100
+	defaultConnMaxLifetime = time.Hour
+	defaultConnMaxIdleTime = 10 * time.Minute
+)
+
+// Connect opens the database connection and brings the schema up to date by
+// applying any pending migrations. This is the entry point the server
+// bootstrap uses; callers that need the connection without triggering
+// migrations (the mercon migrate CLI) should use ConnectRaw instead.
 func Connect() (*gorm.DB, error) {
+	db, err := ConnectRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateSchema(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// ConnectRaw opens the database connection and configures its pool, but
+// does not run migrations - it's split out from Connect so the mercon
+// migrate CLI can connect and inspect or roll back schema state without
+// Up running first.
+//
+// Pool sizing (DB_MAX_IDLE_CONNS, DB_MAX_OPEN_CONNS, DB_CONN_MAX_LIFETIME,
+// DB_CONN_MAX_IDLE_TIME) is configurable via env vars, falling back to the
+// previous hardcoded defaults. If DB_REPLICA_DSNS is set (a comma-separated
+// list of Postgres DSNs), each one is pinged and, if reachable, registered
+// as a read replica via gorm's dbresolver plugin: SELECT queries are routed
+// to a replica at random, everything else goes to the primary. Unreachable
+// replicas are skipped rather than registered, so the pool starts with only
+// endpoints known to be up - see CheckReplicaHealth for checking their
+// health again later.
+func ConnectRaw() (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
 		os.Getenv("DB_HOST"),
@@ -35,6 +77,11 @@ func Connect() (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	maxIdleConns := getEnvInt("DB_MAX_IDLE_CONNS", defaultMaxIdleConns)
+	maxOpenConns := getEnvInt("DB_MAX_OPEN_CONNS", defaultMaxOpenConns)
+	connMaxLifetime := getEnvDuration("DB_CONN_MAX_LIFETIME", defaultConnMaxLifetime)
+	connMaxIdleTime := getEnvDuration("DB_CONN_MAX_IDLE_TIME", defaultConnMaxIdleTime)
+
 	// Set connection pool settings
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -42,50 +89,121 @@ func Connect() (*gorm.DB, error) {
 	}
 
 	// Set connection pool limits
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(maxIdleConns)
+	sqlDB.SetMaxOpenConns(maxOpenConns)
+	sqlDB.SetConnMaxLifetime(connMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
 
-	// Migrate database schema
-	if err := migrateSchema(db); err != nil {
-		return nil, err
+	if replicaDSNs := parseReplicaDSNs(); len(replicaDSNs) > 0 {
+		var replicas []gorm.Dialector
+		for _, replicaDSN := range replicaDSNs {
+			if err := pingDSN(context.Background(), replicaDSN); err != nil {
+				continue
+			}
+			replicas = append(replicas, postgres.Open(replicaDSN))
+		}
+
+		if len(replicas) > 0 {
+			resolver := dbresolver.Register(dbresolver.Config{
+				Replicas: replicas,
+				Policy:   dbresolver.RandomPolicy{},
+			}).
+				SetMaxIdleConns(maxIdleConns).
+				SetMaxOpenConns(maxOpenConns).
+				SetConnMaxLifetime(connMaxLifetime).
+				SetConnMaxIdleTime(connMaxIdleTime)
+
+			if err := db.Use(resolver); err != nil {
+				return nil, fmt.Errorf("failed to register read replicas: %w", err)
+			}
+		}
 	}
 
 	return db, nil
 }
 
-func migrateSchema(db *gorm.DB) error {
-	// Migrate models
-	if err := db.AutoMigrate(
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.TransactionInstruction{},
-		&models.TransactionAccount{},
-		&models.MeteoraPair{},
-		&models.MeteoraPosition{},
-		&models.MeteoraSwap{},
-		&models.MeteoraLiquidityAddition{},
-		&models.MeteoraLiquidityRemoval{},
-		&models.MeteoraFeeClaim{},
-		&models.MeteoraReward{},
-		&models.MeteoraRewardFunding{},
-		&models.MeteoraRewardClaim{},
-	); err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
+// CheckReplicaHealth pings every DSN in dsns (as produced by parsing
+// DB_REPLICA_DSNS) and returns the subset that failed to respond, keyed by
+// DSN. dbresolver's replica set is fixed at registration time - there's no
+// supported way to pull a replica out of an already-running resolver - so
+// this doesn't mutate anything. It's meant for an operator or a health-check
+// endpoint to call periodically: a non-empty result means DB_REPLICA_DSNS
+// should be trimmed and the process restarted to pick up the change, the
+// same way ConnectRaw already skips unreachable replicas at startup.
+func CheckReplicaHealth(ctx context.Context, dsns []string) map[string]error {
+	unhealthy := make(map[string]error)
+	for _, dsn := range dsns {
+		if err := pingDSN(ctx, dsn); err != nil {
+			unhealthy[dsn] = err
+		}
 	}
+	return unhealthy
+}
 
-	// Add composite indexes for common query patterns
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_wallet_blocktime ON transactions(wallet_id, block_time)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_positions_wallet_pair ON meteora_positions(wallet_id, pair_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_transaction_accounts_pubkey_signer ON transaction_accounts(pubkey, signer) WHERE signer = true")
+// pingDSN opens a short-lived connection to dsn and pings it, closing it
+// before returning - used to probe replica reachability without holding a
+// pooled connection open for it.
+func pingDSN(ctx context.Context, dsn string) error {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", dsn, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get connection for %s: %w", dsn, err)
+	}
+	defer sqlDB.Close()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping %s: %w", dsn, err)
+	}
+	return nil
+}
+
+// parseReplicaDSNs splits DB_REPLICA_DSNS, a comma-separated list of
+// Postgres DSNs, into individual entries, skipping blanks.
+func parseReplicaDSNs() []string {
+	raw := os.Getenv("DB_REPLICA_DSNS")
+	if raw == "" {
+		return nil
+	}
+
+	var dsns []string
+	for _, dsn := range strings.Split(raw, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	return dsns
+}
 
-	// Add indexes for USD value searches
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_swaps_amount_in_usd ON meteora_swaps(amount_in_usd)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_fee_claims_total_value_usd ON meteora_fee_claims(total_value_usd)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_liquidity_additions_total_value_usd ON meteora_liquidity_additions(total_value_usd)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_liquidity_removals_total_value_usd ON meteora_liquidity_removals(total_value_usd)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_pairs_tvl ON meteora_pairs(tvl)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_meteora_positions_total_value_usd ON meteora_positions(total_value_usd)")
+// getEnvInt parses an integer environment variable, falling back to
+// defaultValue if it's unset or invalid.
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
 
+// getEnvDuration parses a time.Duration environment variable (e.g. "90s",
+// "1h"), falling back to defaultValue if it's unset or invalid.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// migrateSchema brings db up to date by applying every migration in
+// migrations.All that hasn't already been applied. This replaces the old
+// unconditional AutoMigrate + CREATE INDEX IF NOT EXISTS block - see
+// internal/database/migrations for why.
+func migrateSchema(db *gorm.DB) error {
+	if err := migrations.NewRunner(db).Up(context.Background()); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
 	return nil
 }
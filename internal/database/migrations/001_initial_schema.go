@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration001InitialSchema creates every Meteora/transaction table this
+// repo has ever shipped, via gorm AutoMigrate against the current model
+// structs. This is the one place AutoMigrate still runs - bootstrapping a
+// brand new, empty database - and Runner only ever applies it once, the
+// first time schema_migrations doesn't have a row for it. Every schema
+// change after this one is its own numbered migration with an explicit,
+// reviewed Up/Down, instead of AutoMigrate silently reflecting the live
+// struct tags against a database that may already have production data.
+var migration001InitialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&models.Wallet{},
+			&models.Transaction{},
+			&models.TransactionInstruction{},
+			&models.TransactionAccount{},
+			&models.MeteoraPair{},
+			&models.MeteoraPosition{},
+			&models.MeteoraSwap{},
+			&models.MeteoraLiquidityAddition{},
+			&models.MeteoraLiquidityRemoval{},
+			&models.MeteoraFeeClaim{},
+			&models.MeteoraReward{},
+			&models.MeteoraRewardFunding{},
+			&models.MeteoraRewardClaim{},
+			&models.MeteoraPairOracle{},
+			&models.MeteoraBinDelta{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&models.MeteoraBinDelta{},
+			&models.MeteoraPairOracle{},
+			&models.MeteoraRewardClaim{},
+			&models.MeteoraRewardFunding{},
+			&models.MeteoraReward{},
+			&models.MeteoraFeeClaim{},
+			&models.MeteoraLiquidityRemoval{},
+			&models.MeteoraLiquidityAddition{},
+			&models.MeteoraSwap{},
+			&models.MeteoraPosition{},
+			&models.MeteoraPair{},
+			&models.TransactionAccount{},
+			&models.TransactionInstruction{},
+			&models.Transaction{},
+			&models.Wallet{},
+		)
+	},
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// migration002CompositeIndexes adds the composite and USD-value indexes
+// that used to be a block of unconditional "CREATE INDEX IF NOT EXISTS"
+// calls run on every boot in migrateSchema. Moved here so they're applied
+// exactly once and tracked, with a real Down, instead of just re-running
+// "IF NOT EXISTS" on every connect and hoping it never had anything to do.
+var migration002CompositeIndexes = Migration{
+	Version: 2,
+	Name:    "composite_indexes",
+	Up: func(tx *gorm.DB) error {
+		for _, stmt := range []string{
+			"CREATE INDEX IF NOT EXISTS idx_transactions_wallet_blocktime ON transactions(wallet_id, block_time)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_positions_wallet_pair ON meteora_positions(wallet_id, pair_id)",
+			"CREATE INDEX IF NOT EXISTS idx_transaction_accounts_pubkey_signer ON transaction_accounts(pubkey, signer) WHERE signer = true",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_swaps_amount_in_usd ON meteora_swaps(amount_in_usd)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_fee_claims_total_value_usd ON meteora_fee_claims(total_value_usd)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_liquidity_additions_total_value_usd ON meteora_liquidity_additions(total_value_usd)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_liquidity_removals_total_value_usd ON meteora_liquidity_removals(total_value_usd)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_pairs_tvl ON meteora_pairs(tvl)",
+			"CREATE INDEX IF NOT EXISTS idx_meteora_positions_total_value_usd ON meteora_positions(total_value_usd)",
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, idx := range []string{
+			"idx_transactions_wallet_blocktime",
+			"idx_meteora_positions_wallet_pair",
+			"idx_transaction_accounts_pubkey_signer",
+			"idx_meteora_swaps_amount_in_usd",
+			"idx_meteora_fee_claims_total_value_usd",
+			"idx_meteora_liquidity_additions_total_value_usd",
+			"idx_meteora_liquidity_removals_total_value_usd",
+			"idx_meteora_pairs_tvl",
+			"idx_meteora_positions_total_value_usd",
+		} {
+			if err := tx.Exec("DROP INDEX IF EXISTS " + idx).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/wnt/mercon/internal/events"
+	"gorm.io/gorm"
+)
+
+// migration003WebhookSubscriptions adds the webhook_subscriptions and
+// webhook_dead_letters tables events.HTTPPublisher reads and writes. Both
+// are brand new tables, so this uses Migrator().CreateTable rather than
+// AutoMigrate - it only ever creates, never alters or drops a column on an
+// existing table, which is the part of AutoMigrate migration001's doc
+// comment warns against.
+var migration003WebhookSubscriptions = Migration{
+	Version: 3,
+	Name:    "webhook_subscriptions",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&events.Subscription{}, &events.DeadLetter{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&events.DeadLetter{}, &events.Subscription{})
+	},
+}
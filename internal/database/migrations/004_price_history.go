@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/wnt/mercon/internal/services"
+	"gorm.io/gorm"
+)
+
+// migration004PriceHistory adds the price_history table
+// services.PriceHistoryStore reads and writes. It's a brand new table, so
+// this uses Migrator().CreateTable like migration003WebhookSubscriptions -
+// but PriceHistoryStore.PriceAt/nearestSample finds the closest sample to t
+// via two one-sided range queries ("unix_second <= t ORDER BY unix_second
+// DESC LIMIT 1" and "unix_second >= t ORDER BY unix_second ASC LIMIT 1")
+// rather than a single ORDER BY ABS(...) scan, specifically so a b-tree
+// index can serve both: a (mint, unix_second DESC) index can be walked
+// backwards for the ASC query just as well as forwards for the DESC one.
+// CreateTable's struct-tag-derived composite index serves it ascending
+// only, so a second raw CREATE INDEX statement adds the DESC-ordered one on
+// top, the same way migration002CompositeIndexes adds indexes
+// CreateTable/AutoMigrate can't express from struct tags alone.
+var migration004PriceHistory = Migration{
+	Version: 4,
+	Name:    "price_history",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&services.PriceHistorySample{}); err != nil {
+			return err
+		}
+		return tx.Exec("CREATE INDEX IF NOT EXISTS idx_price_history_mint_time_desc ON price_history(mint, unix_second DESC)").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_price_history_mint_time_desc").Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&services.PriceHistorySample{})
+	},
+}
@@ -0,0 +1,15 @@
+// Package migrations is mercon's versioned schema migration subsystem,
+// replacing the ad-hoc db.AutoMigrate + CREATE INDEX IF NOT EXISTS block
+// that used to run unconditionally in database.migrateSchema on every
+// boot. Each migration is a numbered Go file (001_initial_schema.go,
+// 002_composite_indexes.go, ...) registered in All, applied in order
+// inside its own transaction, tracked in a schema_migrations table, and
+// reversible via Down - AutoMigrate silently drops indexes it doesn't see
+// in the live struct tags and can't safely alter a column once a table has
+// production data, which reapplying it unconditionally on every boot can't
+// protect against.
+//
+// Adding a schema change means appending a new Migration - never editing an
+// already-released one, the same rule any migration framework enforces -
+// in its own numbered file.
+package migrations
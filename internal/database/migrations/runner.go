@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one numbered, reversible schema change. Up and Down both run
+// inside a transaction - a failing Up leaves the schema exactly as it was
+// before the migration started, rather than half-applied.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// All is every migration this repo knows about, in the order they're
+// applied. Adding a schema change means appending a new Migration here (in
+// its own 00N_description.go file) - see the package doc comment.
+var All = []Migration{
+	migration001InitialSchema,
+	migration002CompositeIndexes,
+	migration003WebhookSubscriptions,
+	migration004PriceHistory,
+}
+
+// schemaMigration is the schema_migrations table Runner uses to track which
+// migrations have already been applied, so Up only runs the ones a given
+// database hasn't seen yet.
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// Runner applies and rolls back All against one database connection.
+type Runner struct {
+	db *gorm.DB
+}
+
+// NewRunner wraps db for migration purposes. It doesn't touch the database
+// until Up, Down, or Status is called.
+func NewRunner(db *gorm.DB) *Runner {
+	return &Runner{db: db}
+}
+
+// ensureTable creates the schema_migrations bookkeeping table itself, via
+// plain AutoMigrate - it's the one piece of schema this package still
+// manages outside of All, since nothing can track applied migrations
+// before it exists.
+func (r *Runner) ensureTable(ctx context.Context) error {
+	return r.db.WithContext(ctx).AutoMigrate(&schemaMigration{})
+}
+
+// appliedVersions returns the set of migration versions already recorded
+// as applied.
+func (r *Runner) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	var rows []schemaMigration
+	if err := r.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = true
+	}
+	return applied, nil
+}
+
+// Up applies every migration in All that isn't already recorded in
+// schema_migrations, in version order, each inside its own transaction.
+func (r *Runner) Up(ctx context.Context) error {
+	if err := r.ensureTable(ctx); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range All {
+		if applied[m.Version] {
+			continue
+		}
+		if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return fmt.Errorf("migration %03d_%s: %w", m.Version, m.Name, err)
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down rolls back the single most recently applied migration. Repeated
+// calls walk backward one migration at a time, the same as golang-migrate's
+// "down 1" - there's no "roll back everything" here, since that's rarely
+// what an operator actually wants against a database with real data.
+func (r *Runner) Down(ctx context.Context) error {
+	if err := r.ensureTable(ctx); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var last *Migration
+	for i := range All {
+		m := &All[i]
+		if applied[m.Version] && (last == nil || m.Version > last.Version) {
+			last = m
+		}
+	}
+	if last == nil {
+		return fmt.Errorf("no applied migrations to roll back")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := last.Down(tx); err != nil {
+			return fmt.Errorf("migration %03d_%s down: %w", last.Version, last.Name, err)
+		}
+		return tx.Where("version = ?", last.Version).Delete(&schemaMigration{}).Error
+	})
+}
+
+// Status is one migration's applied/pending state, as reported by
+// Runner.Status.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Status reports every migration in All alongside whether (and when) it's
+// been applied to this database.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var rows []schemaMigration
+	if err := r.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	appliedAt := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+
+	statuses := make([]Status, len(All))
+	for i, m := range All {
+		statuses[i] = Status{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			statuses[i].Applied = true
+			appliedAtCopy := at
+			statuses[i].AppliedAt = &appliedAtCopy
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,16 @@
+// Package events notifies external subscribers whenever the transaction
+// persistence path (internal/solana.SaveTransactions) commits a new
+// Transaction, MeteoraSwap, or MeteoraFeeClaim, so downstream services can
+// react to on-chain activity without polling Postgres.
+//
+// Publisher is the delivery-agnostic interface. HTTPPublisher - HMAC-signed
+// webhooks, delivered at-least-once with exponential retry and a
+// dead-letter table for exhausted deliveries - is the one fully working
+// backend. NATSPublisher and KafkaPublisher satisfy the same interface for
+// a message-bus deployment, but are deliberate stubs: this repo has no
+// existing NATS/Kafka client dependency, connection config, or topic
+// convention to build against (see internal/queue for the Redis client it
+// does use), so wiring one up would mean fabricating a broker topology
+// nobody has specified. They return a clear "not implemented" error rather
+// than silently no-op-ing or guessing - see their doc comments.
+package events
@@ -0,0 +1,22 @@
+package events
+
+import "time"
+
+// Type identifies what happened, e.g. TypeTransactionCreated. Subscriptions
+// filter on this.
+type Type string
+
+const (
+	TypeTransactionCreated     Type = "transaction.created"
+	TypeMeteoraSwapCreated     Type = "meteora_swap.created"
+	TypeMeteoraFeeClaimCreated Type = "meteora_fee_claim.created"
+)
+
+// Event is one notification of persisted on-chain activity, handed to a
+// Publisher for delivery to every subscription whose filter matches Type.
+type Event struct {
+	Type       Type        `json:"type"`
+	ID         string      `json:"id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Payload    interface{} `json:"payload"`
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// KafkaPublisher is a Publisher backend for a Kafka event bus. It's a
+// deliberate stub - see the package doc comment for why - rather than a
+// fabricated client against a broker this repo has no connection config,
+// topic convention, or dependency for.
+type KafkaPublisher struct{}
+
+// NewKafkaPublisher returns a KafkaPublisher. Publish on it always fails;
+// wire up a real producer and topic here once the Kafka deployment this is
+// meant to talk to is decided.
+func NewKafkaPublisher() *KafkaPublisher {
+	return &KafkaPublisher{}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	return fmt.Errorf("events: Kafka publisher is not implemented yet")
+}
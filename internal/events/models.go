@@ -0,0 +1,31 @@
+package events
+
+import "time"
+
+// Subscription is one webhook_subscriptions row: a URL to deliver Events
+// to, the HMAC secret used to sign each delivery, and which Type it wants
+// to receive (or "*" for all of them).
+type Subscription struct {
+	ID        uint   `gorm:"primaryKey"`
+	URL       string `gorm:"size:2048;not null"`
+	Secret    string `gorm:"size:128;not null"`
+	EventType string `gorm:"size:64;index;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Subscription) TableName() string { return "webhook_subscriptions" }
+
+// DeadLetter is one delivery HTTPPublisher gave up on after exhausting its
+// retries, kept so an operator can inspect and manually replay it.
+type DeadLetter struct {
+	ID             uint   `gorm:"primaryKey"`
+	SubscriptionID uint   `gorm:"index;not null"`
+	EventType      string `gorm:"size:64;index"`
+	Payload        string `gorm:"type:text"`
+	LastError      string `gorm:"type:text"`
+	Attempts       int
+	CreatedAt      time.Time
+}
+
+func (DeadLetter) TableName() string { return "webhook_dead_letters" }
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// NATSPublisher is a Publisher backend for a NATS event bus. It's a
+// deliberate stub - see the package doc comment for why - rather than a
+// fabricated client against a broker this repo has no connection config,
+// subject convention, or dependency for.
+type NATSPublisher struct{}
+
+// NewNATSPublisher returns a NATSPublisher. Publish on it always fails;
+// wire up a real *nats.Conn and subject here once the NATS deployment this
+// is meant to talk to is decided.
+func NewNATSPublisher() *NATSPublisher {
+	return &NATSPublisher{}
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	return fmt.Errorf("events: NATS publisher is not implemented yet")
+}
@@ -0,0 +1,11 @@
+package events
+
+import "context"
+
+// Publisher delivers an Event to whatever transport it wraps (HTTP
+// webhook, NATS, Kafka, ...). Publish should not make the caller wait on
+// full subscriber delivery - see HTTPPublisher for how the webhook backend
+// handles that with background retries.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
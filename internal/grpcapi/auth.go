@@ -0,0 +1,96 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// permission levels form a hierarchy, same as Lotus's wallet interface
+// annotations: "admin" covers "write", which covers "read".
+const (
+	permRead  = "read"
+	permWrite = "write"
+	permAdmin = "admin"
+)
+
+var permissionRank = map[string]int{
+	permRead:  1,
+	permWrite: 2,
+	permAdmin: 3,
+}
+
+// methodPermissions maps each RPC's full method name to the permission it
+// requires, mirroring the "permission: ..." comments in mercon.proto.
+var methodPermissions = map[string]string{
+	"/mercon.grpcapi.v1.Mercon/EnqueueWallet":      permWrite,
+	"/mercon.grpcapi.v1.Mercon/DequeueWallet":      permWrite,
+	"/mercon.grpcapi.v1.Mercon/GetWalletProgress":  permRead,
+	"/mercon.grpcapi.v1.Mercon/ListInFlight":       permRead,
+	"/mercon.grpcapi.v1.Mercon/StreamWorkerEvents": permRead,
+	"/mercon.grpcapi.v1.Mercon/PauseWorker":        permAdmin,
+	"/mercon.grpcapi.v1.Mercon/ResumeWorker":       permAdmin,
+}
+
+// authorize checks the bearer token on an incoming call against the
+// permission required by fullMethod, returning a gRPC status error if the
+// token is missing, unknown, or doesn't grant a high enough permission.
+func (s *Server) authorize(ctx context.Context, fullMethod string) error {
+	required, ok := methodPermissions[fullMethod]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	tokens := md.Get("authorization")
+	if len(tokens) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	granted, ok := s.config.Tokens[tokens[0]]
+	if !ok {
+		return status.Error(codes.Unauthenticated, "unknown token")
+	}
+
+	if !hasPermission(granted, required) {
+		return status.Errorf(codes.PermissionDenied, "token does not grant %s permission", required)
+	}
+
+	return nil
+}
+
+// hasPermission reports whether granted contains a permission at or above
+// required's rank in the read < write < admin hierarchy.
+func hasPermission(granted []string, required string) bool {
+	requiredRank := permissionRank[required]
+	for _, perm := range granted {
+		if permissionRank[perm] >= requiredRank {
+			return true
+		}
+	}
+	return false
+}
+
+// unaryAuthInterceptor enforces per-method permissions on unary RPCs.
+func (s *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor enforces per-method permissions on streaming RPCs
+// (StreamWorkerEvents).
+func (s *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,11 @@
+// Package grpcapi is the gRPC control plane for the wallet queue and worker
+// fleet - the typed, streaming counterpart to internal/adminapi's JSON-RPC
+// surface. The service is defined in mercon.proto; generated bindings live
+// in the sibling pb package, produced by:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	       mercon.proto
+//
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative mercon.proto
+package grpcapi
@@ -0,0 +1,210 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/wnt/mercon/internal/grpcapi/pb"
+	"github.com/wnt/mercon/internal/queue"
+	"github.com/wnt/mercon/internal/worker"
+)
+
+// Config configures the gRPC control plane server.
+type Config struct {
+	// Addr is the address to bind the gRPC listener to, e.g. ":9090".
+	Addr string
+	// Tokens maps a bearer token to the permissions it grants ("read",
+	// "write", "admin"). A request presenting a missing or unknown token is
+	// rejected before reaching the handler.
+	Tokens map[string][]string
+}
+
+// Server implements the generated MerconServer interface against
+// queue.Client and worker.Manager.
+type Server struct {
+	pb.UnimplementedMerconServer
+
+	config     Config
+	queue      *queue.Client
+	manager    *worker.Manager
+	logger     zerolog.Logger
+	grpcServer *grpc.Server
+}
+
+// NewServer builds a Server. Call Start to begin serving.
+func NewServer(cfg Config, queueClient *queue.Client, manager *worker.Manager, logger zerolog.Logger) *Server {
+	return &Server{
+		config:  cfg,
+		queue:   queueClient,
+		manager: manager,
+		logger:  logger.With().Str("component", "grpcapi").Logger(),
+	}
+}
+
+// Start begins serving the gRPC control plane on Config.Addr. It blocks
+// until the listener fails or Stop is called, the same convention
+// cmd/mercon uses for the metrics and admin API servers.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+
+	s.grpcServer = grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.StreamInterceptor(s.streamAuthInterceptor),
+	)
+	pb.RegisterMerconServer(s.grpcServer, s)
+
+	s.logger.Info().Str("addr", s.config.Addr).Msg("Starting gRPC control plane")
+	if err := s.grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("grpc server failed: %w", err)
+	}
+	return nil
+}
+
+// Stop gracefully shuts down the server, letting in-flight calls finish.
+func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+}
+
+// EnqueueWallet adds a wallet to the scrape queue with the given priority.
+func (s *Server) EnqueueWallet(ctx context.Context, req *pb.EnqueueWalletRequest) (*pb.EnqueueWalletResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	if err := s.queue.PushWallet(ctx, req.Address, req.Priority); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enqueue wallet: %v", err)
+	}
+	return &pb.EnqueueWalletResponse{}, nil
+}
+
+// DequeueWallet removes a wallet from the scrape queue before it's picked up.
+func (s *Server) DequeueWallet(ctx context.Context, req *pb.DequeueWalletRequest) (*pb.DequeueWalletResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	if err := s.queue.RemoveFromQueue(ctx, req.Address); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to dequeue wallet: %v", err)
+	}
+	return &pb.DequeueWalletResponse{}, nil
+}
+
+// GetWalletProgress reports a wallet's last processed signature and, if it's
+// currently in flight, which worker has it and since when.
+func (s *Server) GetWalletProgress(ctx context.Context, req *pb.GetWalletProgressRequest) (*pb.GetWalletProgressResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+
+	lastSig, err := s.queue.GetProgress(ctx, req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get wallet progress: %v", err)
+	}
+
+	resp := &pb.GetWalletProgressResponse{LastSignature: lastSig}
+
+	workerID, since, inFlight, err := s.queue.GetInFlightInfo(ctx, req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get in-flight info: %v", err)
+	}
+	if inFlight {
+		resp.InFlightBy = workerID
+		resp.EnqueuedAtUnix = since.Unix()
+	}
+
+	return resp, nil
+}
+
+// ListInFlight lists every wallet currently being processed by a worker.
+func (s *Server) ListInFlight(ctx context.Context, req *pb.ListInFlightRequest) (*pb.ListInFlightResponse, error) {
+	inFlight, err := s.queue.GetInFlightWallets(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list in-flight wallets: %v", err)
+	}
+
+	resp := &pb.ListInFlightResponse{Wallets: make([]*pb.InFlightWallet, 0, len(inFlight))}
+	for addr := range inFlight {
+		workerID, _, ok, err := s.queue.GetInFlightInfo(ctx, addr)
+		if err != nil || !ok {
+			continue
+		}
+		resp.Wallets = append(resp.Wallets, &pb.InFlightWallet{Address: addr, WorkerId: workerID})
+	}
+
+	return resp, nil
+}
+
+// StreamWorkerEvents streams started/finished/failed events as workers
+// process wallets, until the client disconnects.
+func (s *Server) StreamWorkerEvents(req *pb.StreamWorkerEventsRequest, stream pb.Mercon_StreamWorkerEventsServer) error {
+	events, unsubscribe := s.manager.SubscribeEvents()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// PauseWorker stops a worker from picking up new wallets.
+func (s *Server) PauseWorker(ctx context.Context, req *pb.PauseWorkerRequest) (*pb.PauseWorkerResponse, error) {
+	if req.WorkerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker_id is required")
+	}
+	if err := s.manager.PauseWorker(req.WorkerId); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return &pb.PauseWorkerResponse{}, nil
+}
+
+// ResumeWorker clears a pause set by PauseWorker.
+func (s *Server) ResumeWorker(ctx context.Context, req *pb.ResumeWorkerRequest) (*pb.ResumeWorkerResponse, error) {
+	if req.WorkerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker_id is required")
+	}
+	if err := s.manager.ResumeWorker(req.WorkerId); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return &pb.ResumeWorkerResponse{}, nil
+}
+
+func eventToProto(e worker.Event) *pb.WorkerEvent {
+	return &pb.WorkerEvent{
+		WorkerId:      e.WorkerID,
+		Wallet:        e.Wallet,
+		Type:          eventTypeToProto(e.Type),
+		Error:         e.Err,
+		TimestampUnix: e.Timestamp.Unix(),
+	}
+}
+
+func eventTypeToProto(t worker.EventType) pb.WorkerEventType {
+	switch t {
+	case worker.EventStarted:
+		return pb.WorkerEventType_WORKER_EVENT_TYPE_STARTED
+	case worker.EventFinished:
+		return pb.WorkerEventType_WORKER_EVENT_TYPE_FINISHED
+	case worker.EventFailed:
+		return pb.WorkerEventType_WORKER_EVENT_TYPE_FAILED
+	default:
+		return pb.WorkerEventType_WORKER_EVENT_TYPE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,85 @@
+// Package history turns the rows internal/solana's parser writes into a
+// first-class read API: a paginated, filterable view over a wallet's
+// parsed Meteora activity, similar in spirit to dcrdex/status-go's
+// wallet-transaction-history endpoints.
+package history
+
+import (
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/solana"
+)
+
+// DefaultLimit is the page size History and HistoryStream use when
+// HistoryFilter.Limit is zero.
+const DefaultLimit = 100
+
+// HistoryFilter narrows which parsed Meteora activity History and
+// HistoryStream return. A zero-valued field is unconstrained; an empty
+// Types matches every MeteoraTxType the five derived tables can represent.
+type HistoryFilter struct {
+	WalletAddress   string
+	PairAddress     string
+	PositionAddress string
+	TokenMints      []string
+	Types           []solana.MeteoraTxType
+	After           time.Time
+	Before          time.Time
+
+	// Limit caps the number of entries a single History call returns.
+	// Zero uses DefaultLimit.
+	Limit int
+	// Cursor resumes from the page after the one that produced it; the
+	// zero Cursor starts from the beginning.
+	Cursor Cursor
+}
+
+// Cursor identifies a position in the history stream by (slot, signature,
+// row ID), so pagination stays stable even as later transactions are
+// ingested out of slot order.
+//
+// The request asks for a (slot, signature, ix_index) cursor, but the
+// derived-row tables (MeteoraSwap and friends) don't record which
+// instruction within the transaction produced them - only their own
+// primary key. RowID substitutes for ix_index here: it's assigned in the
+// same order the parser appended rows within a transaction, so it orders
+// entries the same way ix_index would, just at row rather than
+// instruction granularity.
+type Cursor struct {
+	Slot      int64
+	Signature string
+	RowID     uint
+}
+
+// IsZero reports whether c is the starting cursor.
+func (c Cursor) IsZero() bool {
+	return c == Cursor{}
+}
+
+// HistoryEntry is one row of parsed Meteora activity, tagged by Type so
+// callers know which of the model fields is populated - this package's
+// take on the discriminated union the request asks for. Exactly one of
+// Swap, LiquidityAdd, LiquidityRemove, FeeClaim, or RewardClaim is
+// non-nil, matching Type.
+type HistoryEntry struct {
+	Type      solana.MeteoraTxType
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+
+	// USDValue is the entry's USD valuation at BlockTime. It's read
+	// straight off whichever TotalValueUSD-equivalent field the enricher
+	// has already populated on the underlying model, so it stays zero
+	// until internal/services.MeteoraDataEnricher has run for this
+	// transaction.
+	USDValue float64
+
+	Swap            *models.MeteoraSwap
+	LiquidityAdd    *models.MeteoraLiquidityAddition
+	LiquidityRemove *models.MeteoraLiquidityRemoval
+	FeeClaim        *models.MeteoraFeeClaim
+	RewardClaim     *models.MeteoraRewardClaim
+
+	cursor Cursor
+}
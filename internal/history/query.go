@@ -0,0 +1,330 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/solana"
+	"gorm.io/gorm"
+)
+
+// allTypes is the set of MeteoraTxType values History queries when
+// filter.Types is empty.
+var allTypes = []solana.MeteoraTxType{
+	solana.MeteoraTxSwap,
+	solana.MeteoraTxAddLiquidity,
+	solana.MeteoraTxRemoveLiquidity,
+	solana.MeteoraTxClaimFee,
+	solana.MeteoraTxClaimReward,
+}
+
+// less orders cursors (and the entries they're attached to) oldest-first.
+func (c Cursor) less(o Cursor) bool {
+	if c.Slot != o.Slot {
+		return c.Slot < o.Slot
+	}
+	if c.Signature != o.Signature {
+		return c.Signature < o.Signature
+	}
+	return c.RowID < o.RowID
+}
+
+// History queries db for parsed Meteora activity matching filter, returning
+// up to filter.Limit entries ordered oldest-first by (slot, signature, row
+// ID) and the cursor to pass back as filter.Cursor for the next page. A
+// returned slice shorter than the requested limit means there is no more
+// data to page through.
+func History(ctx context.Context, db *gorm.DB, filter HistoryFilter) ([]HistoryEntry, Cursor, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	s, err := newScope(ctx, db, filter)
+	if err != nil {
+		return nil, Cursor{}, err
+	}
+	if s.empty {
+		return nil, Cursor{}, nil
+	}
+
+	types := filter.Types
+	if len(types) == 0 {
+		types = allTypes
+	}
+
+	// Each type is fetched independently, sorted and capped at limit. That
+	// wastes a little work when only a few types are requested, but it
+	// guarantees the union's true oldest `limit` entries are present among
+	// the candidates: any entry among the global oldest `limit` that comes
+	// from a given type is, by definition, among that type's own oldest
+	// `limit` entries.
+	var candidates []HistoryEntry
+	for _, t := range types {
+		fetched, err := s.fetch(t, limit)
+		if err != nil {
+			return nil, Cursor{}, fmt.Errorf("fetching %v history: %w", t, err)
+		}
+		candidates = append(candidates, fetched...)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].cursor.less(candidates[j].cursor) })
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	next := Cursor{}
+	if len(candidates) > 0 {
+		next = candidates[len(candidates)-1].cursor
+	}
+	return candidates, next, nil
+}
+
+// scope resolves a HistoryFilter's address fields to IDs once, so every
+// per-type fetch reuses them instead of re-resolving per query.
+type scope struct {
+	db     *gorm.DB
+	filter HistoryFilter
+
+	pairID   *uint
+	walletID *uint
+	posID    *uint
+
+	// empty is true once an address filter resolved to no matching row,
+	// meaning the overall query can short-circuit to no results.
+	empty bool
+}
+
+func newScope(ctx context.Context, db *gorm.DB, filter HistoryFilter) (*scope, error) {
+	s := &scope{db: db.WithContext(ctx), filter: filter}
+
+	var err error
+	if s.pairID, err = s.resolveID(&models.MeteoraPair{}, filter.PairAddress); err != nil {
+		return nil, fmt.Errorf("resolving pair address: %w", err)
+	}
+	if s.walletID, err = s.resolveID(&models.Wallet{}, filter.WalletAddress); err != nil {
+		return nil, fmt.Errorf("resolving wallet address: %w", err)
+	}
+	if s.posID, err = s.resolveID(&models.MeteoraPosition{}, filter.PositionAddress); err != nil {
+		return nil, fmt.Errorf("resolving position address: %w", err)
+	}
+	return s, nil
+}
+
+func (s *scope) resolveID(model interface{}, address string) (*uint, error) {
+	if address == "" {
+		return nil, nil
+	}
+	var id uint
+	err := s.db.Model(model).Select("id").Where("address = ?", address).Take(&id).Error
+	if err == gorm.ErrRecordNotFound {
+		s.empty = true
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// base builds the shared joins/filters every per-type query needs: the join
+// back to transactions for slot/signature/block_time, the resolved
+// pair/wallet/position IDs, the time range, the token mint filter (via
+// meteora_pairs), and the keyset cursor predicate. hasPosition says whether
+// table carries a position_id column at all; if the filter asked for a
+// position but table has none, the query is made to return nothing rather
+// than silently ignoring the filter.
+func (s *scope) base(table string, hasPosition bool, limit int) *gorm.DB {
+	tx := s.db.Table(table).
+		Select(table + ".*, transactions.slot AS slot, transactions.signature AS signature, transactions.block_time AS block_time").
+		Joins("JOIN transactions ON transactions.id = " + table + ".transaction_id")
+
+	if s.pairID != nil {
+		tx = tx.Where(table+".pair_id = ?", *s.pairID)
+	}
+	if s.walletID != nil {
+		tx = tx.Where(table+".wallet_id = ?", *s.walletID)
+	}
+	if s.posID != nil {
+		if !hasPosition {
+			return tx.Where("1 = 0")
+		}
+		tx = tx.Where(table+".position_id = ?", *s.posID)
+	}
+	if !s.filter.After.IsZero() {
+		tx = tx.Where("transactions.block_time >= ?", s.filter.After)
+	}
+	if !s.filter.Before.IsZero() {
+		tx = tx.Where("transactions.block_time <= ?", s.filter.Before)
+	}
+	if len(s.filter.TokenMints) > 0 {
+		tx = tx.Joins("JOIN meteora_pairs ON meteora_pairs.id = "+table+".pair_id").
+			Where("meteora_pairs.token_mint_x IN ? OR meteora_pairs.token_mint_y IN ?", s.filter.TokenMints, s.filter.TokenMints)
+	}
+	if !s.filter.Cursor.IsZero() {
+		c := s.filter.Cursor
+		tx = tx.Where("(transactions.slot, transactions.signature, "+table+".id) > (?, ?, ?)", c.Slot, c.Signature, c.RowID)
+	}
+
+	return tx.Order("transactions.slot ASC, transactions.signature ASC, " + table + ".id ASC").Limit(limit)
+}
+
+func (s *scope) fetch(t solana.MeteoraTxType, limit int) ([]HistoryEntry, error) {
+	switch t {
+	case solana.MeteoraTxSwap:
+		return s.fetchSwaps(limit)
+	case solana.MeteoraTxAddLiquidity:
+		return s.fetchLiquidityAdditions(limit)
+	case solana.MeteoraTxRemoveLiquidity:
+		return s.fetchLiquidityRemovals(limit)
+	case solana.MeteoraTxClaimFee:
+		return s.fetchFeeClaims(limit)
+	case solana.MeteoraTxClaimReward:
+		return s.fetchRewardClaims(limit)
+	default:
+		return nil, nil
+	}
+}
+
+type swapRow struct {
+	models.MeteoraSwap
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+}
+
+func (s *scope) fetchSwaps(limit int) ([]HistoryEntry, error) {
+	var rows []swapRow
+	if err := s.base("meteora_swaps", false, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(rows))
+	for i, r := range rows {
+		swap := r.MeteoraSwap
+		entries[i] = HistoryEntry{
+			Type:      solana.MeteoraTxSwap,
+			Slot:      r.Slot,
+			Signature: r.Signature,
+			BlockTime: r.BlockTime,
+			USDValue:  swap.AmountInUSD,
+			Swap:      &swap,
+			cursor:    Cursor{Slot: r.Slot, Signature: r.Signature, RowID: swap.ID},
+		}
+	}
+	return entries, nil
+}
+
+type liquidityAdditionRow struct {
+	models.MeteoraLiquidityAddition
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+}
+
+func (s *scope) fetchLiquidityAdditions(limit int) ([]HistoryEntry, error) {
+	var rows []liquidityAdditionRow
+	if err := s.base("meteora_liquidity_additions", true, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(rows))
+	for i, r := range rows {
+		addition := r.MeteoraLiquidityAddition
+		entries[i] = HistoryEntry{
+			Type:         solana.MeteoraTxAddLiquidity,
+			Slot:         r.Slot,
+			Signature:    r.Signature,
+			BlockTime:    r.BlockTime,
+			USDValue:     addition.TotalValueUSD,
+			LiquidityAdd: &addition,
+			cursor:       Cursor{Slot: r.Slot, Signature: r.Signature, RowID: addition.ID},
+		}
+	}
+	return entries, nil
+}
+
+type liquidityRemovalRow struct {
+	models.MeteoraLiquidityRemoval
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+}
+
+func (s *scope) fetchLiquidityRemovals(limit int) ([]HistoryEntry, error) {
+	var rows []liquidityRemovalRow
+	if err := s.base("meteora_liquidity_removals", true, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(rows))
+	for i, r := range rows {
+		removal := r.MeteoraLiquidityRemoval
+		entries[i] = HistoryEntry{
+			Type:            solana.MeteoraTxRemoveLiquidity,
+			Slot:            r.Slot,
+			Signature:       r.Signature,
+			BlockTime:       r.BlockTime,
+			USDValue:        removal.TotalValueUSD,
+			LiquidityRemove: &removal,
+			cursor:          Cursor{Slot: r.Slot, Signature: r.Signature, RowID: removal.ID},
+		}
+	}
+	return entries, nil
+}
+
+type feeClaimRow struct {
+	models.MeteoraFeeClaim
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+}
+
+func (s *scope) fetchFeeClaims(limit int) ([]HistoryEntry, error) {
+	var rows []feeClaimRow
+	if err := s.base("meteora_fee_claims", true, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(rows))
+	for i, r := range rows {
+		claim := r.MeteoraFeeClaim
+		entries[i] = HistoryEntry{
+			Type:      solana.MeteoraTxClaimFee,
+			Slot:      r.Slot,
+			Signature: r.Signature,
+			BlockTime: r.BlockTime,
+			USDValue:  claim.TotalValueUSD,
+			FeeClaim:  &claim,
+			cursor:    Cursor{Slot: r.Slot, Signature: r.Signature, RowID: claim.ID},
+		}
+	}
+	return entries, nil
+}
+
+type rewardClaimRow struct {
+	models.MeteoraRewardClaim
+	Slot      int64
+	Signature string
+	BlockTime time.Time
+}
+
+func (s *scope) fetchRewardClaims(limit int) ([]HistoryEntry, error) {
+	var rows []rewardClaimRow
+	if err := s.base("meteora_reward_claims", true, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(rows))
+	for i, r := range rows {
+		claim := r.MeteoraRewardClaim
+		entries[i] = HistoryEntry{
+			Type:        solana.MeteoraTxClaimReward,
+			Slot:        r.Slot,
+			Signature:   r.Signature,
+			BlockTime:   r.BlockTime,
+			USDValue:    claim.AmountUSD,
+			RewardClaim: &claim,
+			cursor:      Cursor{Slot: r.Slot, Signature: r.Signature, RowID: claim.ID},
+		}
+	}
+	return entries, nil
+}
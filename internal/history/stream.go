@@ -0,0 +1,50 @@
+package history
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HistoryStream pages through History starting at filter.Cursor (in
+// filter.Limit-sized pages, DefaultLimit when it is zero) and pushes every entry
+// onto the returned channel in order, closing it once the data is
+// exhausted, ctx is canceled, or a query fails. It exists for callers (e.g.
+// UIs) that want to consume activity incrementally rather than paging
+// through History themselves.
+func HistoryStream(ctx context.Context, db *gorm.DB, filter HistoryFilter) <-chan HistoryEntry {
+	out := make(chan HistoryEntry)
+
+	go func() {
+		defer close(out)
+
+		for {
+			page, next, err := History(ctx, db, filter)
+			if err != nil {
+				return
+			}
+
+			for _, entry := range page {
+				select {
+				case out <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if len(page) < pageSize(filter) {
+				return
+			}
+			filter.Cursor = next
+		}
+	}()
+
+	return out
+}
+
+func pageSize(filter HistoryFilter) int {
+	if filter.Limit <= 0 {
+		return DefaultLimit
+	}
+	return filter.Limit
+}
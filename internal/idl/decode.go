@@ -0,0 +1,186 @@
+package idl
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// borshReader decodes Borsh-encoded values from a byte slice, the
+// encoding Anchor uses for instruction args: fixed-width little-endian
+// ints, a u32 length prefix for vecs/strings/bytes, and a single 0/1 tag
+// byte for options.
+type borshReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *borshReader) take(n int) ([]byte, error) {
+	if r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("unexpected end of data: need %d bytes at offset %d, have %d", n, r.pos, len(r.data))
+	}
+	out := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return out, nil
+}
+
+func (r *borshReader) readValue(t IdlType, types map[string]*IdlTypeDef) (interface{}, error) {
+	switch t.Kind {
+	case "bool":
+		b, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "u8":
+		b, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return uint64(b[0]), nil
+	case "i8":
+		b, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int8(b[0])), nil
+	case "u16":
+		b, err := r.take(2)
+		if err != nil {
+			return nil, err
+		}
+		return uint64(binary.LittleEndian.Uint16(b)), nil
+	case "i16":
+		b, err := r.take(2)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int16(binary.LittleEndian.Uint16(b))), nil
+	case "u32":
+		b, err := r.take(4)
+		if err != nil {
+			return nil, err
+		}
+		return uint64(binary.LittleEndian.Uint32(b)), nil
+	case "i32":
+		b, err := r.take(4)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.LittleEndian.Uint32(b))), nil
+	case "u64":
+		b, err := r.take(8)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.Uint64(b), nil
+	case "i64":
+		b, err := r.take(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case "publicKey":
+		b, err := r.take(32)
+		if err != nil {
+			return nil, err
+		}
+		var key solana.PublicKey
+		copy(key[:], b)
+		return key.String(), nil
+	case "string":
+		length, err := r.readU32Len()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.take(int(length))
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "bytes":
+		length, err := r.readU32Len()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.take(int(length))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, len(b))
+		copy(out, b)
+		return out, nil
+	case "option":
+		tag, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		if tag[0] == 0 {
+			return nil, nil
+		}
+		return r.readValue(*t.Elem, types)
+	case "vec":
+		length, err := r.readU32Len()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, 0, length)
+		for i := uint32(0); i < length; i++ {
+			v, err := r.readValue(*t.Elem, types)
+			if err != nil {
+				return nil, fmt.Errorf("decoding vec element %d: %w", i, err)
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case "array":
+		out := make([]interface{}, 0, t.ArrayLen)
+		for i := 0; i < t.ArrayLen; i++ {
+			v, err := r.readValue(*t.Elem, types)
+			if err != nil {
+				return nil, fmt.Errorf("decoding array element %d: %w", i, err)
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case "defined":
+		def, ok := types[t.Defined]
+		if !ok {
+			return nil, fmt.Errorf("no type definition for %q", t.Defined)
+		}
+		if def.Type.Kind != "struct" {
+			return nil, fmt.Errorf("type %q: only struct definitions are supported, got %q", t.Defined, def.Type.Kind)
+		}
+		return r.readFields(def.Type.Fields, types)
+	default:
+		return nil, fmt.Errorf("unsupported IDL type %q", t.Kind)
+	}
+}
+
+func (r *borshReader) readU32Len() (uint32, error) {
+	b, err := r.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *borshReader) readFields(fields []IdlField, types map[string]*IdlTypeDef) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		v, err := r.readValue(f.Type, types)
+		if err != nil {
+			return nil, fmt.Errorf("decoding field %q: %w", f.Name, err)
+		}
+		out[f.Name] = v
+	}
+	return out, nil
+}
+
+// decodeArgs decodes the Borsh-encoded remainder of an instruction's data
+// (after the 8-byte discriminator) per the IDL's declared arg fields.
+func decodeArgs(fields []IdlField, data []byte, types map[string]*IdlTypeDef) (map[string]interface{}, error) {
+	r := &borshReader{data: data}
+	return r.readFields(fields, types)
+}
@@ -0,0 +1,196 @@
+// Package idl decodes Solana instructions and CPI event logs generically
+// from an Anchor IDL JSON file, the same way go-ethereum's accounts/abi
+// package turns a contract ABI into a decoder without hand-rolled
+// per-method code. A Parser loads an IDL once, precomputes each
+// instruction's 8-byte discriminator (sha256("global:<name>")[:8]) and each
+// event's (sha256("event:<name>")[:8]), and Parse/ParseEvent dispatch on
+// the leading 8 bytes of instruction or emit_cpi! data at runtime.
+//
+// internal/solana.InstructionRegistry wraps a Parser to decode the Meteora
+// DLMM program's CPI event logs (see its doc comment for why top-level
+// instruction dispatch there still uses its own single-byte scheme instead
+// of Parse). damm/dlmm remain out of scope - they live in the unpublished
+// github.com/metfin/core module, outside this repo's control. Parser is
+// otherwise meant for any Meteora/Jupiter program that follows the Anchor
+// IDL convention, loaded by program ID from a config directory via LoadDir.
+package idl
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// Idl is the subset of Anchor's IDL JSON schema (see
+// https://www.anchor-lang.com/docs/idl) this package understands: enough to
+// decode instruction args, map accounts, and decode CPI event logs, not the
+// full IDL (errors and constants are still ignored).
+type Idl struct {
+	Version      string           `json:"version"`
+	Name         string           `json:"name"`
+	Instructions []IdlInstruction `json:"instructions"`
+	Events       []IdlEvent       `json:"events"`
+	Types        []IdlTypeDef     `json:"types"`
+	Metadata     *IdlMetadata     `json:"metadata"`
+
+	byDiscriminator      map[[8]byte]*IdlInstruction
+	byEventDiscriminator map[[8]byte]*IdlEvent
+	byName               map[string]*IdlTypeDef
+}
+
+// IdlMetadata carries the program's on-chain address, used to key Parser's
+// registry so a raw instruction's programId can find its IDL.
+type IdlMetadata struct {
+	Address string `json:"address"`
+}
+
+// IdlInstruction is one entry in the IDL's "instructions" array.
+type IdlInstruction struct {
+	Name     string       `json:"name"`
+	Accounts []IdlAccount `json:"accounts"`
+	Args     []IdlField   `json:"args"`
+
+	discriminator [8]byte
+}
+
+// IdlAccount is one entry in an instruction's "accounts" array. Nested
+// (composite) account groups aren't supported - Meteora's own IDLs use a
+// flat list.
+type IdlAccount struct {
+	Name string `json:"name"`
+}
+
+// IdlEvent is one entry in the IDL's "events" array: a struct emitted via
+// Anchor's emit_cpi!, encoded exactly like an instruction (an 8-byte
+// discriminator followed by its Borsh-encoded fields in order) but hashed
+// from "event:<name>" instead of "global:<name>". Each field's "index" flag
+// (whether it's part of the program's log-level event ABI) doesn't affect
+// decoding, so it's ignored here.
+type IdlEvent struct {
+	Name   string     `json:"name"`
+	Fields []IdlField `json:"fields"`
+
+	discriminator [8]byte
+}
+
+// IdlField is one entry in an instruction's "args" array, or a struct
+// type's "fields" array.
+type IdlField struct {
+	Name string  `json:"name"`
+	Type IdlType `json:"type"`
+}
+
+// IdlTypeDef is one entry in the IDL's top-level "types" array: a named
+// struct that IdlType's "defined" variant refers to. Enum type defs aren't
+// supported yet.
+type IdlTypeDef struct {
+	Name string `json:"name"`
+	Type struct {
+		Kind   string     `json:"kind"`
+		Fields []IdlField `json:"fields"`
+	} `json:"type"`
+}
+
+// IdlType is an Anchor IDL type: either a bare string ("u64", "publicKey",
+// ...) or an object describing a compound type ({"vec": T}, {"option": T},
+// {"array": [T, N]}, {"defined": "Name"}).
+type IdlType struct {
+	Kind     string
+	Elem     *IdlType
+	ArrayLen int
+	Defined  string
+}
+
+func (t *IdlType) UnmarshalJSON(b []byte) error {
+	var bare string
+	if err := json.Unmarshal(b, &bare); err == nil {
+		t.Kind = bare
+		return nil
+	}
+
+	var obj struct {
+		Vec     *IdlType          `json:"vec"`
+		Option  *IdlType          `json:"option"`
+		Defined string            `json:"defined"`
+		Array   []json.RawMessage `json:"array"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return fmt.Errorf("decoding IDL type: %w", err)
+	}
+
+	switch {
+	case obj.Vec != nil:
+		t.Kind = "vec"
+		t.Elem = obj.Vec
+	case obj.Option != nil:
+		t.Kind = "option"
+		t.Elem = obj.Option
+	case obj.Defined != "":
+		t.Kind = "defined"
+		t.Defined = obj.Defined
+	case len(obj.Array) == 2:
+		var elem IdlType
+		if err := json.Unmarshal(obj.Array[0], &elem); err != nil {
+			return fmt.Errorf("decoding array element type: %w", err)
+		}
+		var length int
+		if err := json.Unmarshal(obj.Array[1], &length); err != nil {
+			return fmt.Errorf("decoding array length: %w", err)
+		}
+		t.Kind = "array"
+		t.Elem = &elem
+		t.ArrayLen = length
+	default:
+		return fmt.Errorf("unrecognized IDL type: %s", string(b))
+	}
+	return nil
+}
+
+// discriminator computes the 8-byte Anchor global instruction discriminator
+// for name: sha256("global:<name>")[:8].
+func discriminator(name string) [8]byte {
+	return sighash("global", name)
+}
+
+// eventDiscriminator computes the 8-byte discriminator Anchor's emit_cpi!
+// prefixes an event's encoded fields with: sha256("event:<name>")[:8].
+func eventDiscriminator(name string) [8]byte {
+	return sighash("event", name)
+}
+
+func sighash(namespace, name string) [8]byte {
+	sum := sha256.Sum256([]byte(namespace + ":" + name))
+	var out [8]byte
+	copy(out[:], sum[:8])
+	return out
+}
+
+// Parse decodes raw into an Idl and precomputes every instruction's and
+// event's discriminator and every named type's lookup entry.
+func Parse(raw []byte) (*Idl, error) {
+	var doc Idl
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("decoding IDL: %w", err)
+	}
+
+	doc.byDiscriminator = make(map[[8]byte]*IdlInstruction, len(doc.Instructions))
+	for i := range doc.Instructions {
+		inst := &doc.Instructions[i]
+		inst.discriminator = discriminator(inst.Name)
+		doc.byDiscriminator[inst.discriminator] = inst
+	}
+
+	doc.byEventDiscriminator = make(map[[8]byte]*IdlEvent, len(doc.Events))
+	for i := range doc.Events {
+		ev := &doc.Events[i]
+		ev.discriminator = eventDiscriminator(ev.Name)
+		doc.byEventDiscriminator[ev.discriminator] = ev
+	}
+
+	doc.byName = make(map[string]*IdlTypeDef, len(doc.Types))
+	for i := range doc.Types {
+		doc.byName[doc.Types[i].Name] = &doc.Types[i]
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,159 @@
+package idl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ParsedInstruction is what Parser.Parse produces for one instruction: its
+// decoded args, and its accounts mapped from IDL-declared names to the
+// pubkeys instruction.Accounts supplied them in.
+//
+// Unknown is set instead of returning an error when the instruction's
+// discriminator doesn't match anything in the loaded IDL - an upgraded
+// program version with new variants shouldn't break parsing of the
+// variants this IDL still knows about.
+type ParsedInstruction struct {
+	ProgramID string
+	Name      string
+	Args      map[string]interface{}
+	Accounts  map[string]string
+	Unknown   bool
+}
+
+// Parser dispatches raw instruction bytes to the right IDL by program ID
+// and decodes them. It's safe for concurrent reads once loading is done;
+// Register/LoadDir are not meant to run concurrently with Parse.
+type Parser struct {
+	programs map[string]*Idl
+}
+
+// NewParser returns an empty Parser. Register or LoadDir programs into it
+// before calling Parse.
+func NewParser() *Parser {
+	return &Parser{programs: make(map[string]*Idl)}
+}
+
+// Register adds doc to the parser, keyed by programID. It overrides
+// whatever doc.Metadata.Address says, so callers can register the same IDL
+// under more than one deployed program ID (e.g. devnet vs mainnet).
+func (p *Parser) Register(programID string, doc *Idl) {
+	p.programs[programID] = doc
+}
+
+// LoadDir parses every *.json file in dir as an IDL and registers it under
+// its own Metadata.Address, so adding a new program to the parser is just
+// dropping its IDL file into dir - no code change. A file without a
+// Metadata.Address stops the load and LoadDir returns an error, since
+// there'd be no program ID to register it under.
+func (p *Parser) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("globbing IDL dir %s: %w", dir, err)
+	}
+
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading IDL %s: %w", path, err)
+		}
+
+		doc, err := Parse(raw)
+		if err != nil {
+			return fmt.Errorf("parsing IDL %s: %w", path, err)
+		}
+
+		if doc.Metadata == nil || doc.Metadata.Address == "" {
+			return fmt.Errorf("IDL %s has no metadata.address to register it under", path)
+		}
+
+		p.Register(doc.Metadata.Address, doc)
+	}
+
+	return nil
+}
+
+// Parse decodes one instruction for programID. accounts is the
+// instruction's account list in IDL order (e.g. internal/solana's
+// Instruction.Accounts, already resolved to pubkey strings).
+func (p *Parser) Parse(programID string, data []byte, accounts []string) (*ParsedInstruction, error) {
+	doc, ok := p.programs[programID]
+	if !ok {
+		return nil, fmt.Errorf("no IDL registered for program %s", programID)
+	}
+
+	if len(data) < 8 {
+		return &ParsedInstruction{ProgramID: programID, Unknown: true}, nil
+	}
+
+	var disc [8]byte
+	copy(disc[:], data[:8])
+
+	inst, ok := doc.byDiscriminator[disc]
+	if !ok {
+		return &ParsedInstruction{ProgramID: programID, Unknown: true}, nil
+	}
+
+	args, err := decodeArgs(inst.Args, data[8:], doc.byName)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s.%s args: %w", programID, inst.Name, err)
+	}
+
+	namedAccounts := make(map[string]string, len(inst.Accounts))
+	for i, acc := range inst.Accounts {
+		if i < len(accounts) {
+			namedAccounts[acc.Name] = accounts[i]
+		}
+	}
+
+	return &ParsedInstruction{
+		ProgramID: programID,
+		Name:      inst.Name,
+		Args:      args,
+		Accounts:  namedAccounts,
+	}, nil
+}
+
+// ParsedEvent is what Parser.ParseEvent produces for one decoded Anchor CPI
+// event log.
+//
+// Unknown is set instead of returning an error when the data's
+// discriminator doesn't match any event in the loaded IDL, for the same
+// forward-compatibility reason as ParsedInstruction.Unknown.
+type ParsedEvent struct {
+	ProgramID string
+	Name      string
+	Fields    map[string]interface{}
+	Unknown   bool
+}
+
+// ParseEvent decodes one Anchor CPI event emitted by programID via
+// emit_cpi!. data is the self-invoked inner instruction's data: an 8-byte
+// "event:<name>" discriminator followed by the event's Borsh-encoded
+// fields, the same layout Parse decodes instruction args from.
+func (p *Parser) ParseEvent(programID string, data []byte) (*ParsedEvent, error) {
+	doc, ok := p.programs[programID]
+	if !ok {
+		return nil, fmt.Errorf("no IDL registered for program %s", programID)
+	}
+
+	if len(data) < 8 {
+		return &ParsedEvent{ProgramID: programID, Unknown: true}, nil
+	}
+
+	var disc [8]byte
+	copy(disc[:], data[:8])
+
+	ev, ok := doc.byEventDiscriminator[disc]
+	if !ok {
+		return &ParsedEvent{ProgramID: programID, Unknown: true}, nil
+	}
+
+	fields, err := decodeArgs(ev.Fields, data[8:], doc.byName)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s event %s: %w", programID, ev.Name, err)
+	}
+
+	return &ParsedEvent{ProgramID: programID, Name: ev.Name, Fields: fields}, nil
+}
@@ -0,0 +1,196 @@
+package idl
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIdlJSON = `{
+  "version": "0.1.0",
+  "name": "test_program",
+  "metadata": {"address": "Test111111111111111111111111111111111111111"},
+  "instructions": [
+    {
+      "name": "swap",
+      "accounts": [
+        {"name": "pair"},
+        {"name": "user"}
+      ],
+      "args": [
+        {"name": "amountIn", "type": "u64"},
+        {"name": "minAmountOut", "type": "u64"},
+        {"name": "memo", "type": {"option": "string"}}
+      ]
+    },
+    {
+      "name": "initializePosition",
+      "accounts": [
+        {"name": "position"}
+      ],
+      "args": [
+        {"name": "bins", "type": {"vec": "i32"}},
+        {"name": "config", "type": {"defined": "PositionConfig"}}
+      ]
+    }
+  ],
+  "events": [
+    {
+      "name": "SwapEvent",
+      "fields": [
+        {"name": "amountIn", "type": "u64", "index": false},
+        {"name": "amountOut", "type": "u64", "index": false}
+      ]
+    }
+  ],
+  "types": [
+    {
+      "name": "PositionConfig",
+      "type": {
+        "kind": "struct",
+        "fields": [
+          {"name": "width", "type": "u16"},
+          {"name": "active", "type": "bool"}
+        ]
+      }
+    }
+  ]
+}`
+
+func mustDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("global:" + name))
+	return sum[:8]
+}
+
+func TestParser_Parse(t *testing.T) {
+	doc, err := Parse([]byte(testIdlJSON))
+	require.NoError(t, err)
+
+	p := NewParser()
+	p.Register(doc.Metadata.Address, doc)
+
+	t.Run("scalar args and option present", func(t *testing.T) {
+		data := mustDiscriminator("swap")
+		data = appendU64(data, 1_000_000)
+		data = appendU64(data, 990_000)
+		data = append(data, 1) // option tag: Some
+		data = appendU32(data, 4)
+		data = append(data, "gm!!"...)
+
+		got, err := p.Parse(doc.Metadata.Address, data, []string{"PairAddr", "UserAddr"})
+		require.NoError(t, err)
+
+		assert.False(t, got.Unknown)
+		assert.Equal(t, "swap", got.Name)
+		assert.Equal(t, uint64(1_000_000), got.Args["amountIn"])
+		assert.Equal(t, uint64(990_000), got.Args["minAmountOut"])
+		assert.Equal(t, "gm!!", got.Args["memo"])
+		assert.Equal(t, "PairAddr", got.Accounts["pair"])
+		assert.Equal(t, "UserAddr", got.Accounts["user"])
+	})
+
+	t.Run("option absent", func(t *testing.T) {
+		data := mustDiscriminator("swap")
+		data = appendU64(data, 1)
+		data = appendU64(data, 2)
+		data = append(data, 0) // option tag: None
+
+		got, err := p.Parse(doc.Metadata.Address, data, []string{"PairAddr", "UserAddr"})
+		require.NoError(t, err)
+		assert.Nil(t, got.Args["memo"])
+	})
+
+	t.Run("vec and defined struct args", func(t *testing.T) {
+		data := mustDiscriminator("initializePosition")
+		data = appendU32(data, 2)
+		data = appendI32(data, -10)
+		data = appendI32(data, 10)
+		data = appendU16(data, 64)
+		data = append(data, 1) // active: true
+
+		got, err := p.Parse(doc.Metadata.Address, data, []string{"PositionAddr"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "initializePosition", got.Name)
+		assert.Equal(t, []interface{}{int64(-10), int64(10)}, got.Args["bins"])
+		config, ok := got.Args["config"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, uint64(64), config["width"])
+		assert.Equal(t, true, config["active"])
+	})
+
+	t.Run("unknown discriminator", func(t *testing.T) {
+		data := mustDiscriminator("doesNotExist")
+		got, err := p.Parse(doc.Metadata.Address, data, nil)
+		require.NoError(t, err)
+		assert.True(t, got.Unknown)
+	})
+
+	t.Run("unregistered program", func(t *testing.T) {
+		_, err := p.Parse("SomeOtherProgram1111111111111111111111111", mustDiscriminator("swap"), nil)
+		assert.Error(t, err)
+	})
+}
+
+func mustEventDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("event:" + name))
+	return sum[:8]
+}
+
+func TestParser_ParseEvent(t *testing.T) {
+	doc, err := Parse([]byte(testIdlJSON))
+	require.NoError(t, err)
+
+	p := NewParser()
+	p.Register(doc.Metadata.Address, doc)
+
+	t.Run("known event", func(t *testing.T) {
+		data := mustEventDiscriminator("SwapEvent")
+		data = appendU64(data, 1_000_000)
+		data = appendU64(data, 990_000)
+
+		got, err := p.ParseEvent(doc.Metadata.Address, data)
+		require.NoError(t, err)
+
+		assert.False(t, got.Unknown)
+		assert.Equal(t, "SwapEvent", got.Name)
+		assert.Equal(t, uint64(1_000_000), got.Fields["amountIn"])
+		assert.Equal(t, uint64(990_000), got.Fields["amountOut"])
+	})
+
+	t.Run("unknown discriminator", func(t *testing.T) {
+		got, err := p.ParseEvent(doc.Metadata.Address, mustEventDiscriminator("doesNotExist"))
+		require.NoError(t, err)
+		assert.True(t, got.Unknown)
+	})
+
+	t.Run("unregistered program", func(t *testing.T) {
+		_, err := p.ParseEvent("SomeOtherProgram1111111111111111111111111", mustEventDiscriminator("SwapEvent"))
+		assert.Error(t, err)
+	})
+}
+
+func appendU64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendU16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendI32(b []byte, v int32) []byte {
+	return appendU32(b, uint32(v))
+}
@@ -0,0 +1,209 @@
+// Package leader provides Redis-lease-backed leader election so exactly
+// one process among several identical pods runs a given periodic job -
+// the stuck-wallet reaper and the pair/position enrichment sweep both
+// race today if run from every pod, so internal/worker.Manager gates them
+// behind a Campaign for "reaper" and "enricher" respectively.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/wnt/mercon/internal/metrics"
+)
+
+// defaultLeaseTTL is how long a held lease survives without renewal -
+// and therefore, worst case, how long a job goes un-run after its leader
+// pod disappears without a clean shutdown.
+const defaultLeaseTTL = 15 * time.Second
+
+// defaultAcquireRetryInterval is how often a non-leader retries SET NX PX
+// while campaigning for a lease it doesn't yet hold.
+const defaultAcquireRetryInterval = 2 * time.Second
+
+// leaseKeyPrefix namespaces leadership keys from the rest of Mercon's
+// Redis keyspace (queue ZSETs/hashes, Streams consumer groups, ...).
+const leaseKeyPrefix = "mercon:leader:"
+
+// renewScript extends the caller's lease only if it's still the recorded
+// holder - if the key expired and someone else already won it, PEXPIRE
+// would otherwise silently extend a lease this process no longer holds.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// releaseScript deletes the lease key only if it's still the caller's,
+// same compare-and-delete rationale as renewScript.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`)
+
+// Elector campaigns for named leadership leases backed by a Redis `SET NX
+// PX` key per job. Like queue.NewStreamClient, it opens its own Redis
+// connection rather than sharing queue.Client's, so it doesn't need that
+// package's unexported *redis.Client.
+type Elector struct {
+	client   *redis.Client
+	logger   zerolog.Logger
+	holderID string
+	leaseTTL time.Duration
+	retry    time.Duration
+}
+
+// ElectorOption customizes an Elector at construction time.
+type ElectorOption func(*Elector)
+
+// WithLeaseTTL overrides how long an acquired lease survives without
+// renewal. The default is defaultLeaseTTL; renewal happens at half this
+// interval.
+func WithLeaseTTL(ttl time.Duration) ElectorOption {
+	return func(e *Elector) { e.leaseTTL = ttl }
+}
+
+// NewElector opens a Redis connection for leader election against
+// redisURL. The elector's holder ID, surfaced in logs and implicitly via
+// which pod's mercon_leader_held metric reads 1, is always
+// "<hostname>-<random>".
+func NewElector(redisURL string, logger zerolog.Logger, opts ...ElectorOption) (*Elector, error) {
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	e := &Elector{
+		client:   client,
+		logger:   logger.With().Str("component", "leader_elector").Logger(),
+		holderID: fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+		leaseTTL: defaultLeaseTTL,
+		retry:    defaultAcquireRetryInterval,
+	}
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e, nil
+}
+
+// LeadershipHandle is returned by a won Campaign. Its Done channel closes
+// once this process's lease on the campaigned-for job is lost, either
+// because a renewal failed or because ctx was canceled (in which case the
+// lease is also released, so another pod doesn't have to wait out the
+// full TTL before taking over).
+type LeadershipHandle struct {
+	jobName string
+	done    chan struct{}
+}
+
+// JobName is the job this handle holds leadership for.
+func (h *LeadershipHandle) JobName() string { return h.jobName }
+
+// Done returns a channel that's closed when this process's leadership of
+// JobName ends.
+func (h *LeadershipHandle) Done() <-chan struct{} { return h.done }
+
+// Campaign blocks, retrying SET NX PX every e.retry, until this process
+// acquires jobName's lease or ctx is canceled. Once acquired, it starts a
+// background goroutine that renews the lease at half its TTL and closes
+// the returned handle's Done channel (releasing the lease) when renewal
+// fails or ctx is canceled. Callers that want to keep contesting jobName
+// for the life of the process should loop: call Campaign again once
+// Done() fires.
+func (e *Elector) Campaign(ctx context.Context, jobName string) (*LeadershipHandle, error) {
+	key := leaseKeyPrefix + jobName
+
+	ticker := time.NewTicker(e.retry)
+	defer ticker.Stop()
+
+	for {
+		ok, err := e.client.SetNX(ctx, key, e.holderID, e.leaseTTL).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			e.logger.Warn().Err(err).Str("job", jobName).Msg("Failed to attempt leadership acquisition, retrying")
+		} else if ok {
+			e.logger.Info().Str("job", jobName).Str("holder", e.holderID).Msg("Acquired leadership lease")
+			metrics.SetLeaderHeld(jobName, true)
+			handle := &LeadershipHandle{jobName: jobName, done: make(chan struct{})}
+			go e.holdLease(ctx, key, handle)
+			return handle, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// holdLease renews key at half e.leaseTTL until ctx is canceled or a
+// renewal fails, then releases the lease and closes handle.Done.
+func (e *Elector) holdLease(ctx context.Context, key string, handle *LeadershipHandle) {
+	defer func() {
+		metrics.SetLeaderHeld(handle.jobName, false)
+		close(handle.done)
+	}()
+
+	ticker := time.NewTicker(e.leaseTTL / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.release(key)
+			return
+		case <-ticker.C:
+			renewCtx, cancel := context.WithTimeout(context.Background(), e.leaseTTL/4)
+			renewed, err := renewScript.Run(renewCtx, e.client, []string{key}, e.holderID, e.leaseTTL.Milliseconds()).Int()
+			cancel()
+			if err != nil || renewed == 0 {
+				e.logger.Warn().Err(err).Str("job", handle.jobName).Msg("Lost leadership lease")
+				return
+			}
+		}
+	}
+}
+
+// release best-effort deletes key if e.holderID is still its value, using
+// a fresh short-lived context since ctx (the one Campaign's caller
+// passed in) is already canceled by the time this runs.
+func (e *Elector) release(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := releaseScript.Run(ctx, e.client, []string{key}, e.holderID).Err(); err != nil {
+		e.logger.Warn().Err(err).Str("key", key).Msg("Failed to release leadership lease on shutdown")
+	}
+}
+
+// Close closes the Elector's Redis connection.
+func (e *Elector) Close() error {
+	return e.client.Close()
+}
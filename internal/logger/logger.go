@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -50,4 +51,20 @@ func WithWallet(logger zerolog.Logger, wallet string) zerolog.Logger {
 // WithRPCEndpoint adds RPC endpoint to logger context
 func WithRPCEndpoint(logger zerolog.Logger, endpoint string) zerolog.Logger {
 	return logger.With().Str("rpc_endpoint", endpoint).Logger()
-} 
\ No newline at end of file
+}
+
+// WithComponent adds a component name to logger context
+func WithComponent(logger zerolog.Logger, component string) zerolog.Logger {
+	return logger.With().Str("component", component).Logger()
+}
+
+// SetLevel updates the global zerolog level at runtime, e.g. from the admin
+// JSON-RPC API's admin_logLevel method, without restarting the process.
+func SetLevel(level string) error {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	zerolog.SetGlobalLevel(parsed)
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package meteorametrics republishes live Meteora pair, position, and
+// reward figures as Prometheus gauges. It's distinct from internal/metrics
+// (which the rest of the app updates inline as package-level counters/
+// gauges while it does work): Collector is a pull-based prometheus.Collector
+// that does its own fetching on every scrape, using the analytics
+// database's MeteoraPair/MeteoraPosition tables to know which pairs and
+// positions exist and services.MeteoraPubClient to get their current
+// USD/APR figures.
+package meteorametrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"github.com/wnt/mercon/internal/services"
+)
+
+// defaultScrapeWorkers caps how many pairs/positions Collector fetches from
+// MeteoraPubClient concurrently during one refresh, so a slow or hanging
+// upstream call can't stall the whole scrape.
+const defaultScrapeWorkers = 8
+
+type pairSample struct {
+	tvlUSD       float64
+	volume24hUSD float64
+	apr          float64
+	mintX        string
+	mintY        string
+}
+
+type positionSample struct {
+	valueUSD    float64
+	feeAPR24h   float64
+	pairAddress string
+	wallet      string
+}
+
+type rewardSample struct {
+	amountUSD   float64
+	pairAddress string
+	rewardMint  string
+}
+
+// Collector is a prometheus.Collector that republishes live Meteora pair,
+// position, and reward data as gauges, labelled by pair address, token
+// mints, and wallet. Collect never blocks on the network: it kicks off a
+// background refresh (unless one is already in flight) and immediately
+// emits whatever the last successful refresh cached, so a slow upstream
+// never stalls a Prometheus scrape.
+type Collector struct {
+	db      *gorm.DB
+	client  *services.MeteoraPubClient
+	logger  zerolog.Logger
+	workers int
+
+	pairTVLDesc        *prometheus.Desc
+	pairVolumeDesc     *prometheus.Desc
+	pairAPRDesc        *prometheus.Desc
+	positionValueDesc  *prometheus.Desc
+	positionFeeAPRDesc *prometheus.Desc
+	rewardAmountDesc   *prometheus.Desc
+
+	mu         sync.Mutex
+	refreshing bool
+	pairs      map[string]pairSample
+	positions  map[string]positionSample
+	rewards    map[string]rewardSample
+}
+
+// CollectorOption configures a Collector at construction time.
+type CollectorOption func(*Collector)
+
+// WithScrapeWorkers overrides how many pairs/positions Collector fetches
+// from MeteoraPubClient concurrently during one refresh. The default is
+// defaultScrapeWorkers.
+func WithScrapeWorkers(n int) CollectorOption {
+	return func(c *Collector) { c.workers = n }
+}
+
+// NewCollector creates a Collector that republishes db's MeteoraPair and
+// MeteoraPosition rows as live Prometheus gauges, refreshing each row's
+// USD/APR figures from client on every scrape.
+func NewCollector(db *gorm.DB, client *services.MeteoraPubClient, logger zerolog.Logger, opts ...CollectorOption) *Collector {
+	c := &Collector{
+		db:      db,
+		client:  client,
+		logger:  logger.With().Str("component", "meteorametrics").Logger(),
+		workers: defaultScrapeWorkers,
+
+		pairs:     make(map[string]pairSample),
+		positions: make(map[string]positionSample),
+		rewards:   make(map[string]rewardSample),
+
+		pairTVLDesc: prometheus.NewDesc(
+			"mercon_meteora_pair_tvl_usd",
+			"Total value locked of a Meteora pair in USD",
+			[]string{"pair_address", "token_mint_x", "token_mint_y"}, nil,
+		),
+		pairVolumeDesc: prometheus.NewDesc(
+			"mercon_meteora_pair_volume24h_usd",
+			"24h trade volume of a Meteora pair in USD",
+			[]string{"pair_address", "token_mint_x", "token_mint_y"}, nil,
+		),
+		pairAPRDesc: prometheus.NewDesc(
+			"mercon_meteora_pair_apr",
+			"Annual percentage rate of a Meteora pair",
+			[]string{"pair_address", "token_mint_x", "token_mint_y"}, nil,
+		),
+		positionValueDesc: prometheus.NewDesc(
+			"mercon_meteora_position_value_usd",
+			"Total USD value of a Meteora position",
+			[]string{"position_address", "pair_address", "wallet"}, nil,
+		),
+		positionFeeAPRDesc: prometheus.NewDesc(
+			"mercon_meteora_position_fee_apr_24h",
+			"24h fee APR of a Meteora position",
+			[]string{"position_address", "pair_address", "wallet"}, nil,
+		),
+		rewardAmountDesc: prometheus.NewDesc(
+			"mercon_meteora_reward_amount_usd",
+			"USD value of a Meteora pair's reward emission",
+			[]string{"pair_address", "reward_mint"}, nil,
+		),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pairTVLDesc
+	ch <- c.pairVolumeDesc
+	ch <- c.pairAPRDesc
+	ch <- c.positionValueDesc
+	ch <- c.positionFeeAPRDesc
+	ch <- c.rewardAmountDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.triggerRefresh()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for addr, p := range c.pairs {
+		ch <- prometheus.MustNewConstMetric(c.pairTVLDesc, prometheus.GaugeValue, p.tvlUSD, addr, p.mintX, p.mintY)
+		ch <- prometheus.MustNewConstMetric(c.pairVolumeDesc, prometheus.GaugeValue, p.volume24hUSD, addr, p.mintX, p.mintY)
+		ch <- prometheus.MustNewConstMetric(c.pairAPRDesc, prometheus.GaugeValue, p.apr, addr, p.mintX, p.mintY)
+	}
+	for addr, p := range c.positions {
+		ch <- prometheus.MustNewConstMetric(c.positionValueDesc, prometheus.GaugeValue, p.valueUSD, addr, p.pairAddress, p.wallet)
+		ch <- prometheus.MustNewConstMetric(c.positionFeeAPRDesc, prometheus.GaugeValue, p.feeAPR24h, addr, p.pairAddress, p.wallet)
+	}
+	for _, r := range c.rewards {
+		ch <- prometheus.MustNewConstMetric(c.rewardAmountDesc, prometheus.GaugeValue, r.amountUSD, r.pairAddress, r.rewardMint)
+	}
+}
+
+// triggerRefresh starts a background refresh unless one is already running.
+func (c *Collector) triggerRefresh() {
+	c.mu.Lock()
+	if c.refreshing {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing = true
+	c.mu.Unlock()
+
+	go c.refresh()
+}
@@ -0,0 +1,68 @@
+package meteorametrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/wnt/mercon/internal/services"
+	"github.com/wnt/mercon/internal/utils"
+)
+
+// httpRequestsTotal tracks every attempt MeteoraPubClient's utils.HTTPClient
+// makes, by response status (or "error" if the attempt never got one).
+var httpRequestsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mercon_meteora_http_requests_total",
+		Help: "The total number of HTTP requests made to the Meteora public API",
+	},
+	[]string{"method", "status"},
+)
+
+// httpRetriesTotal tracks attempts beyond the first for one logical
+// request.
+var httpRetriesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mercon_meteora_http_retries_total",
+		Help: "The total number of retried HTTP requests made to the Meteora public API",
+	},
+	[]string{"method"},
+)
+
+// httpRequestDurationSeconds is a per-status latency histogram for requests
+// to the Meteora public API.
+var httpRequestDurationSeconds = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "mercon_meteora_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests made to the Meteora public API, by response status",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"method", "status"},
+)
+
+// httpObserver is a utils.RequestObserver that records httpRequestsTotal,
+// httpRetriesTotal, and httpRequestDurationSeconds for every attempt
+// MeteoraPubClient's HTTP client makes. Pass it to
+// services.WithHTTPClientOptions(utils.WithObserver(httpObserver)) when
+// constructing the client Collector scrapes.
+func httpObserver(method, _ string, statusCode, attempt int, duration time.Duration, err error) {
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(statusCode)
+	}
+
+	httpRequestsTotal.WithLabelValues(method, status).Inc()
+	httpRequestDurationSeconds.WithLabelValues(method, status).Observe(duration.Seconds())
+	if attempt > 0 {
+		httpRetriesTotal.WithLabelValues(method).Inc()
+	}
+}
+
+// NewInstrumentedMeteoraPubClient creates a services.MeteoraPubClient whose
+// every request/retry/status is recorded via httpObserver, for use with a
+// Collector.
+func NewInstrumentedMeteoraPubClient() *services.MeteoraPubClient {
+	return services.NewMeteoraPubClient(services.WithHTTPClientOptions(utils.WithObserver(httpObserver)))
+}
@@ -0,0 +1,164 @@
+package meteorametrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/wnt/mercon/internal/models"
+)
+
+// refresh lists every Meteora pair and active position from the database,
+// then fans a MeteoraPubClient fetch out per pair/position across a
+// bounded worker pool coordinated by a sync.WaitGroup, so one slow upstream
+// request can't stall the rest. A pair or position whose fetch fails keeps
+// whatever sample the last successful refresh cached for it rather than
+// being cleared, so a transient upstream error doesn't blank out its
+// metric.
+func (c *Collector) refresh() {
+	defer func() {
+		c.mu.Lock()
+		c.refreshing = false
+		c.mu.Unlock()
+	}()
+
+	var pairs []models.MeteoraPair
+	if err := c.db.Find(&pairs).Error; err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to load Meteora pairs for metrics refresh")
+		return
+	}
+
+	var positions []models.MeteoraPosition
+	if err := c.db.Preload("Wallet").Where("status = ?", "active").Find(&positions).Error; err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to load Meteora positions for metrics refresh")
+		return
+	}
+
+	pairByID := make(map[uint]models.MeteoraPair, len(pairs))
+	for _, pair := range pairs {
+		pairByID[pair.ID] = pair
+	}
+
+	sem := make(chan struct{}, c.workers)
+	var wg sync.WaitGroup
+
+	for _, pair := range pairs {
+		pair := pair
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			c.refreshPair(pair)
+		}()
+	}
+
+	for _, position := range positions {
+		position := position
+		pair, ok := pairByID[position.PairID]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			c.refreshPosition(position, pair)
+		}()
+	}
+
+	wg.Wait()
+
+	// Reward amounts are already computed and stored by
+	// services.MeteoraDataEnricher (MeteoraReward.RewardAmountUSD) -
+	// MeteoraPubClient has no live endpoint for a pair's reward emission,
+	// so there's nothing to fan out here; this is a single cheap query.
+	c.refreshRewards()
+}
+
+// refreshPair fetches pair's current TVL/volume/APR from MeteoraPubClient
+// and caches it for the next Collect call.
+func (c *Collector) refreshPair(pair models.MeteoraPair) {
+	info, err := c.client.GetPair(pair.Address)
+	if err != nil {
+		c.logger.Debug().Err(err).Str("pair", pair.Address).Msg("Failed to fetch pair for metrics refresh, keeping last known sample")
+		return
+	}
+
+	tvlUSD, err := strconv.ParseFloat(info.Liquidity, 64)
+	if err != nil {
+		c.logger.Debug().Err(err).Str("pair", pair.Address).Str("liquidity", info.Liquidity).Msg("Failed to parse pair liquidity, keeping last known sample")
+		return
+	}
+
+	sample := pairSample{
+		tvlUSD:       tvlUSD,
+		volume24hUSD: info.TradeVolume24h,
+		apr:          info.Apr,
+		mintX:        pair.TokenMintX,
+		mintY:        pair.TokenMintY,
+	}
+
+	c.mu.Lock()
+	c.pairs[pair.Address] = sample
+	c.mu.Unlock()
+}
+
+// refreshPosition fetches position's current fee APR from MeteoraPubClient
+// and caches it alongside its last-enriched TotalValueUSD (MeteoraPubClient
+// has no live endpoint that returns a position's current USD value).
+func (c *Collector) refreshPosition(position models.MeteoraPosition, pair models.MeteoraPair) {
+	info, err := c.client.GetPosition(position.Address)
+	if err != nil {
+		c.logger.Debug().Err(err).Str("position", position.Address).Msg("Failed to fetch position for metrics refresh, keeping last known sample")
+		return
+	}
+
+	sample := positionSample{
+		valueUSD:    position.TotalValueUSD,
+		feeAPR24h:   info.FeeApr24h,
+		pairAddress: pair.Address,
+		wallet:      position.Wallet.Address,
+	}
+
+	c.mu.Lock()
+	c.positions[position.Address] = sample
+	c.mu.Unlock()
+}
+
+// refreshRewards reads every active MeteoraReward row directly - see the
+// comment in refresh for why this skips the worker pool.
+func (c *Collector) refreshRewards() {
+	var rewards []models.MeteoraReward
+	if err := c.db.Where("status = ?", "active").Find(&rewards).Error; err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to load Meteora rewards for metrics refresh")
+		return
+	}
+
+	pairAddresses := make(map[uint]string)
+	for _, reward := range rewards {
+		if _, ok := pairAddresses[reward.PairID]; ok {
+			continue
+		}
+		var pair models.MeteoraPair
+		if err := c.db.First(&pair, reward.PairID).Error; err != nil {
+			continue
+		}
+		pairAddresses[reward.PairID] = pair.Address
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, reward := range rewards {
+		pairAddress, ok := pairAddresses[reward.PairID]
+		if !ok {
+			continue
+		}
+		key := pairAddress + ":" + reward.RewardMint
+		c.rewards[key] = rewardSample{
+			amountUSD:   reward.RewardAmountUSD,
+			pairAddress: pairAddress,
+			rewardMint:  reward.RewardMint,
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -18,15 +20,59 @@ var (
 		Help: "The number of workers currently active",
 	})
 
-	// RPCRequestsTotal tracks RPC requests by status
+	// RPCRequestsTotal tracks RPC requests by endpoint, method, and status
 	RPCRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "mercon_rpc_requests_total",
 			Help: "The total number of RPC requests",
 		},
-		[]string{"status"},
+		[]string{"endpoint", "method", "status"},
+	)
+
+	// RPCLatencySeconds tracks RPC request latency by endpoint and method
+	RPCLatencySeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mercon_rpc_latency_seconds",
+			Help:    "Latency of RPC requests by endpoint and method",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 13), // 10ms to ~41s
+		},
+		[]string{"endpoint", "method"},
+	)
+
+	// RPCRetriesTotal tracks RPC retries by endpoint, method, and reason
+	// (429, 5xx, timeout, parse)
+	RPCRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_rpc_retries_total",
+			Help: "The total number of RPC retries",
+		},
+		[]string{"endpoint", "method", "reason"},
+	)
+
+	// RPCInFlight tracks the number of in-flight RPC requests by endpoint and method
+	RPCInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_rpc_inflight",
+			Help: "The number of RPC requests currently in flight",
+		},
+		[]string{"endpoint", "method"},
+	)
+
+	// QueueDepth tracks the depth of Mercon's Redis-backed queues
+	QueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_queue_depth",
+			Help: "The number of items waiting in a queue",
+		},
+		[]string{"queue"},
 	)
 
+	// InFlightWallets tracks the number of wallets currently being processed by a worker
+	InFlightWallets = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mercon_inflight_wallets",
+		Help: "The number of wallets currently being processed by a worker",
+	})
+
 	// WalletScrapeSeconds tracks time taken to scrape wallets
 	WalletScrapeSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
 		Name:    "mercon_wallet_scrape_seconds",
@@ -61,6 +107,40 @@ var (
 		[]string{"endpoint"},
 	)
 
+	// RPCEndpointScore tracks the scored scheduler's current selection
+	// weight per endpoint (success ratio over latency EWMA and rate-limit
+	// penalty, each raised to its weight - see rpc.Endpoint.score), for comparing
+	// endpoints side by side regardless of which scheduler mode is active.
+	RPCEndpointScore = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_rpc_endpoint_score",
+			Help: "Scored scheduler selection weight of RPC endpoints (success_ratio / (latency_ewma_seconds * rate_limit_penalty), weighted)",
+		},
+		[]string{"endpoint"},
+	)
+
+	// RPCEndpointSuccessRatio tracks the fraction of an endpoint's last
+	// requests (see rpc.scoreRingSize) that succeeded.
+	RPCEndpointSuccessRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_rpc_endpoint_success_ratio",
+			Help: "Fraction of an RPC endpoint's recent requests that succeeded",
+		},
+		[]string{"endpoint"},
+	)
+
+	// RPCEndpointRateLimitEWMA tracks the scored scheduler's time-decayed
+	// count of recent 429/503 responses per endpoint (see
+	// rpc.Pool.RecordRateLimit), so operators can see a provider starting to
+	// rate limit before it degrades enough to show up in success ratio.
+	RPCEndpointRateLimitEWMA = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_rpc_endpoint_rate_limit_ewma",
+			Help: "Time-decayed count of recent 429/503 responses from an RPC endpoint",
+		},
+		[]string{"endpoint"},
+	)
+
 	// WorkerTaskDuration tracks how long workers spend on tasks
 	WorkerTaskDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -70,11 +150,198 @@ var (
 		},
 		[]string{"task_type", "worker_id"},
 	)
+
+	// PairMetadataCacheRequestsTotal tracks PairMetadataResolver's cache
+	// hit/miss rate
+	PairMetadataCacheRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_pair_metadata_cache_requests_total",
+			Help: "The total number of PairMetadataResolver cache lookups",
+		},
+		[]string{"result"}, // hit, miss
+	)
+
+	// PairMetadataRPCErrorsTotal tracks PairMetadataResolver RPC fetch
+	// failures
+	PairMetadataRPCErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_pair_metadata_rpc_errors_total",
+		Help: "The total number of PairMetadataResolver RPC fetch failures",
+	})
+
+	// RPCErrorsTotal tracks RPC failures by endpoint, method, and error
+	// class (rate_limited, timeout, http_status, parse, rpc_error, not_found,
+	// other),
+	// for per-endpoint alerting on top of RPCRequestsTotal's coarser
+	// success/error status label.
+	RPCErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_rpc_errors_total",
+			Help: "The total number of RPC errors by endpoint, method, and error class",
+		},
+		[]string{"endpoint", "method", "error_class"},
+	)
+
+	// PriceCacheSize tracks the number of entries currently held in
+	// MeteoraDataEnricher's pair price cache.
+	PriceCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mercon_price_cache_size",
+		Help: "The number of entries currently in the pair price cache",
+	})
+
+	// PriceCacheHitsTotal tracks pair price cache lookups that found a
+	// live entry.
+	PriceCacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_price_cache_hits_total",
+		Help: "The total number of pair price cache lookups that hit",
+	})
+
+	// PriceCacheMissesTotal tracks pair price cache lookups that found
+	// nothing (absent or expired).
+	PriceCacheMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_price_cache_misses_total",
+		Help: "The total number of pair price cache lookups that missed",
+	})
+
+	// PriceCacheEvictionsTotal tracks entries dropped from the pair price
+	// cache, whether by TTL expiry or LRU pressure.
+	PriceCacheEvictionsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_price_cache_evictions_total",
+		Help: "The total number of entries evicted from the pair price cache",
+	})
+
+	// WalletsDeadLettered tracks wallets routed to the DLQ after exceeding
+	// MaxWalletRequeues.
+	WalletsDeadLettered = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_wallets_dead_lettered_total",
+		Help: "The total number of wallets routed to the dead-letter queue",
+	})
+
+	// RPCCoalescedHitsTotal tracks Fetcher calls that were coalesced onto an
+	// already in-flight request for the same signature or
+	// wallet/before/limit key, instead of issuing a duplicate RPC call.
+	RPCCoalescedHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mercon_rpc_coalesced_hits_total",
+		Help: "The total number of Fetcher calls coalesced onto an already in-flight RPC request",
+	})
+
+	// WSSubscriptionsActive tracks how many wallets internal/rpc/ws.Subscriber
+	// currently has an active logsSubscribe subscription for.
+	WSSubscriptionsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mercon_ws_subscriptions_active",
+		Help: "The number of wallets currently subscribed to via websocket log notifications",
+	})
+
+	// WSReconnectsTotal tracks how many times Subscriber has had to
+	// reconnect a dropped endpoint websocket connection.
+	WSReconnectsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_ws_reconnects_total",
+			Help: "The total number of websocket reconnects to an RPC endpoint",
+		},
+		[]string{"endpoint"},
+	)
+
+	// WSNotificationsTotal tracks log notifications received over active
+	// websocket subscriptions.
+	WSNotificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_ws_notifications_total",
+			Help: "The total number of websocket log notifications received",
+		},
+		[]string{"endpoint"},
+	)
+
+	// QueryGRPCRequestsTotal tracks internal/api/grpc query API calls by
+	// method and final status ("ok" or "error").
+	QueryGRPCRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_query_grpc_requests_total",
+			Help: "The total number of internal/api/grpc query API requests",
+		},
+		[]string{"method", "status"},
+	)
+
+	// QueryGRPCLatencySeconds tracks internal/api/grpc query API call
+	// latency by method.
+	QueryGRPCLatencySeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mercon_query_grpc_latency_seconds",
+			Help:    "The latency of internal/api/grpc query API requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+
+	// QueryGRPCPanicsTotal tracks handler panics recovered by the query
+	// API's unary panic-recovery interceptor.
+	QueryGRPCPanicsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mercon_query_grpc_panics_total",
+			Help: "The total number of panics recovered in internal/api/grpc handlers",
+		},
+		[]string{"method"},
+	)
+
+	// LeaderHeld tracks whether this process currently holds
+	// internal/leader.Elector's Redis lease for job_name (1 = holds it, 0 =
+	// does not). Since every pod exports its own view under the scrape's
+	// own "instance" label, operators can tell which pod holds which lease
+	// by finding the one reporting 1 for a given job_name.
+	LeaderHeld = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mercon_leader_held",
+			Help: "Whether this process currently holds the leadership lease for job_name (1 = held, 0 = not held)",
+		},
+		[]string{"job_name"},
+	)
 )
 
-// RecordRPCRequest records an RPC request with the given status
-func RecordRPCRequest(status string) {
-	RPCRequestsTotal.WithLabelValues(status).Inc()
+// RecordRPCRequest records an RPC request to endpoint/method with the given status
+func RecordRPCRequest(endpoint, method, status string) {
+	RPCRequestsTotal.WithLabelValues(endpoint, method, status).Inc()
+}
+
+// RecordRPCError records an RPC failure against endpoint/method, tagged
+// with errorClass (see rpc.ErrorClass).
+func RecordRPCError(endpoint, method, errorClass string) {
+	RPCErrorsTotal.WithLabelValues(endpoint, method, errorClass).Inc()
+}
+
+// RecordRPCRetry records a retry of an RPC call to endpoint/method, tagged
+// with the reason the previous attempt failed (e.g. "429", "5xx", "timeout",
+// "parse").
+func RecordRPCRetry(endpoint, method, reason string) {
+	RPCRetriesTotal.WithLabelValues(endpoint, method, reason).Inc()
+}
+
+// Observe starts timing an RPC call to endpoint/method and bumps its
+// in-flight gauge. The returned func must be called with the call's error
+// (nil on success) when the call finishes; it records the latency, final
+// status, and drops the in-flight gauge back down.
+func Observe(endpoint, method string) func(err error) {
+	start := time.Now()
+	RPCInFlight.WithLabelValues(endpoint, method).Inc()
+
+	return func(err error) {
+		RPCInFlight.WithLabelValues(endpoint, method).Dec()
+		RPCLatencySeconds.WithLabelValues(endpoint, method).Observe(time.Since(start).Seconds())
+
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		RecordRPCRequest(endpoint, method, status)
+	}
+}
+
+// SetQueueDepth sets the current depth of the named queue
+func SetQueueDepth(queue string, depth int) {
+	QueueDepth.WithLabelValues(queue).Set(float64(depth))
+}
+
+// SetInFlightWallets sets the current number of wallets being processed by a worker
+func SetInFlightWallets(count int) {
+	InFlightWallets.Set(float64(count))
 }
 
 // RecordWalletScrape records the time taken to scrape a wallet
@@ -101,7 +368,91 @@ func SetRPCEndpointHealth(endpoint string, healthy bool) {
 	RPCEndpointHealth.WithLabelValues(endpoint).Set(value)
 }
 
+// SetRPCEndpointScore records the scored scheduler's current selection
+// weight and success ratio for an endpoint.
+func SetRPCEndpointScore(endpoint string, score, successRatio float64) {
+	RPCEndpointScore.WithLabelValues(endpoint).Set(score)
+	RPCEndpointSuccessRatio.WithLabelValues(endpoint).Set(successRatio)
+}
+
+// SetRPCEndpointRateLimitEWMA records an endpoint's current time-decayed
+// 429/503 count.
+func SetRPCEndpointRateLimitEWMA(endpoint string, rateLimitEWMA float64) {
+	RPCEndpointRateLimitEWMA.WithLabelValues(endpoint).Set(rateLimitEWMA)
+}
+
 // RecordWorkerTaskDuration records the time taken by a worker to complete a task
 func RecordWorkerTaskDuration(taskType, workerID string, duration float64) {
 	WorkerTaskDuration.WithLabelValues(taskType, workerID).Observe(duration)
-} 
\ No newline at end of file
+}
+
+// SetPriceCacheSize sets the current number of entries in the pair price cache
+func SetPriceCacheSize(size int) {
+	PriceCacheSize.Set(float64(size))
+}
+
+// RecordPriceCacheHit records a pair price cache lookup that found a live entry
+func RecordPriceCacheHit() {
+	PriceCacheHitsTotal.Inc()
+}
+
+// RecordPriceCacheMiss records a pair price cache lookup that found nothing
+func RecordPriceCacheMiss() {
+	PriceCacheMissesTotal.Inc()
+}
+
+// RecordPriceCacheEviction records an entry dropped from the pair price cache
+func RecordPriceCacheEviction() {
+	PriceCacheEvictionsTotal.Inc()
+}
+
+// RecordWalletDeadLettered records a wallet being routed to the DLQ
+func RecordWalletDeadLettered() {
+	WalletsDeadLettered.Inc()
+}
+
+// RecordRPCCoalescedHit records a Fetcher call that was coalesced onto an
+// already in-flight request instead of issuing its own.
+func RecordRPCCoalescedHit() {
+	RPCCoalescedHitsTotal.Inc()
+}
+
+// SetWSSubscriptionsActive sets the current number of wallets with an
+// active websocket subscription.
+func SetWSSubscriptionsActive(count int) {
+	WSSubscriptionsActive.Set(float64(count))
+}
+
+// RecordWSReconnect records a websocket reconnect attempt against endpoint.
+func RecordWSReconnect(endpoint string) {
+	WSReconnectsTotal.WithLabelValues(endpoint).Inc()
+}
+
+// RecordWSNotification records a log notification received over endpoint's
+// websocket subscription.
+func RecordWSNotification(endpoint string) {
+	WSNotificationsTotal.WithLabelValues(endpoint).Inc()
+}
+
+// RecordQueryGRPCRequest records an internal/api/grpc query API call to
+// method with the given duration and final status ("ok" or "error").
+func RecordQueryGRPCRequest(method, status string, duration float64) {
+	QueryGRPCRequestsTotal.WithLabelValues(method, status).Inc()
+	QueryGRPCLatencySeconds.WithLabelValues(method).Observe(duration)
+}
+
+// RecordQueryGRPCPanic records a handler panic recovered by the query API's
+// panic-recovery interceptor for method.
+func RecordQueryGRPCPanic(method string) {
+	QueryGRPCPanicsTotal.WithLabelValues(method).Inc()
+}
+
+// SetLeaderHeld records whether this process currently holds jobName's
+// leadership lease.
+func SetLeaderHeld(jobName string, held bool) {
+	value := 0.0
+	if held {
+		value = 1.0
+	}
+	LeaderHeld.WithLabelValues(jobName).Set(value)
+}
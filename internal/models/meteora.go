@@ -7,8 +7,17 @@ import (
 )
 
 // MeteoraPair represents a Meteora DLMM liquidity pair
+//
+// Protocol discriminates which DEX a row came from. It's forward-compat
+// prep for services.DEXClient covering more than Meteora (Raydium, Orca,
+// ...): unifying MeteoraPair/Position/Swap into single DEXPair/Position/Swap
+// tables would mean rewriting every query in internal/services and
+// internal/database that assumes a Meteora-only schema, a much larger
+// migration than this column justifies on its own - so for now every row
+// is tagged "meteora" and the per-protocol tables stay as they are.
 type MeteoraPair struct {
 	gorm.Model
+	Protocol   string `gorm:"size:20;index;default:'meteora'"`
 	Address    string `gorm:"size:44;uniqueIndex;not null"`
 	TokenMintX string `gorm:"size:44;index"`
 	TokenMintY string `gorm:"size:44;index"`
@@ -36,9 +45,27 @@ type MeteoraPair struct {
 	Rewards   []MeteoraReward   `gorm:"foreignKey:PairID"`
 }
 
-// MeteoraPosition represents a liquidity position in a Meteora DLMM pair
+// MeteoraPairOracle records per-pair price oracle overrides, letting
+// operators exclude a feed that's known-bad for that pool (Enabled=false)
+// without disabling it globally. A pair with no row for an oracle, or a
+// row with Enabled=true, still uses that oracle - this is a blacklist, not
+// a whitelist.
+type MeteoraPairOracle struct {
+	gorm.Model
+	PairID   uint   `gorm:"index;not null"`
+	OracleID string `gorm:"size:32;index;not null"`
+	Enabled  bool   `gorm:"default:true"`
+
+	// Relationships
+	Pair MeteoraPair `gorm:"foreignKey:PairID"`
+}
+
+// MeteoraPosition represents a liquidity position in a Meteora DLMM pair.
+// See MeteoraPair's doc comment for why Protocol exists without a full
+// DEXPosition table unification.
 type MeteoraPosition struct {
 	gorm.Model
+	Protocol   string `gorm:"size:20;index;default:'meteora'"`
 	Address    string `gorm:"size:44;uniqueIndex;not null"`
 	PairID     uint   `gorm:"index;not null"`
 	WalletID   uint   `gorm:"index;not null"`
@@ -62,12 +89,16 @@ type MeteoraPosition struct {
 	LiquidityRemovals  []MeteoraLiquidityRemoval  `gorm:"foreignKey:PositionID"`
 	FeeClaims          []MeteoraFeeClaim          `gorm:"foreignKey:PositionID"`
 	RewardClaims       []MeteoraRewardClaim       `gorm:"foreignKey:PositionID"`
+	BinDeltas          []MeteoraBinDelta          `gorm:"foreignKey:PositionID"`
 	Wallet             Wallet                     `gorm:"foreignKey:WalletID"`
 }
 
-// MeteoraSwap represents a token swap in a Meteora DLMM pair
+// MeteoraSwap represents a token swap in a Meteora DLMM pair. See
+// MeteoraPair's doc comment for why Protocol exists without a full DEXSwap
+// table unification.
 type MeteoraSwap struct {
 	gorm.Model
+	Protocol      string `gorm:"size:20;index;default:'meteora'"`
 	TransactionID uint   `gorm:"index;not null"`
 	PairID        uint   `gorm:"index;not null"`
 	WalletID      uint   `gorm:"index;not null"`
@@ -93,6 +124,22 @@ type MeteoraSwap struct {
 	TokenPrice     float64
 	PriceImpact    float64
 
+	// Token registry enrichment, populated when the parser has a
+	// tokenregistry.TokenRegistry (see TransactionParser.WithTokenRegistry);
+	// left blank/zero otherwise, same as the USD fields above before a
+	// PriceOracle is wired in. AmountInDecimal/AmountOutDecimal are
+	// AmountIn/AmountOut normalized by TokenInDecimals/TokenOutDecimals -
+	// plain float64 rather than decimal.Decimal, matching
+	// TokenTransfer.AmountDecimal's existing raw+human-decimal pairing
+	// convention rather than introducing a dependency the rest of the repo
+	// doesn't use.
+	TokenInSymbol    string `gorm:"size:32"`
+	TokenOutSymbol   string `gorm:"size:32"`
+	TokenInDecimals  uint8
+	TokenOutDecimals uint8
+	AmountInDecimal  float64 `gorm:"type:decimal(30,15)"`
+	AmountOutDecimal float64 `gorm:"type:decimal(30,15)"`
+
 	// Relationships
 	Transaction Transaction `gorm:"foreignKey:TransactionID"`
 	Wallet      Wallet      `gorm:"foreignKey:WalletID"`
@@ -248,3 +295,34 @@ type MeteoraRewardClaim struct {
 	Reward      MeteoraReward   `gorm:"foreignKey:RewardID"`
 	Pair        MeteoraPair     `gorm:"foreignKey:PairID"`
 }
+
+// MeteoraBinDelta represents one bin's liquidity change from a single
+// addLiquidity/addLiquidityByStrategy(OneSide)/removeLiquidity operation,
+// reconstructed from that instruction's bin-distribution argument data
+// rather than the single aggregate AmountX/AmountY on
+// MeteoraLiquidityAddition/MeteoraLiquidityRemoval. DeltaX/DeltaY are
+// signed: positive for liquidity added to the bin, negative for liquidity
+// removed from it. LiquidityShare is this bin's fraction (0-1) of the
+// operation's total liquidity, for weighting PnL/impermanent-loss
+// calculations that need per-bin rather than per-position granularity.
+// Approximated is true when DeltaX/DeltaY/LiquidityShare come from
+// strategyWeights's heuristic curve shape rather than the instruction's own
+// bps distribution - addLiquidityByStrategy(OneSide) doesn't encode a
+// per-bin breakdown on-chain, so the parser reconstructs one; downstream
+// PnL/IL consumers should treat these rows as an estimate, not ground truth.
+type MeteoraBinDelta struct {
+	gorm.Model
+	TransactionID  uint  `gorm:"index;not null"`
+	PositionID     uint  `gorm:"index;not null"`
+	PairID         uint  `gorm:"index;not null"`
+	BinID          int32 `gorm:"index"`
+	DeltaX         int64
+	DeltaY         int64
+	LiquidityShare float64
+	Approximated   bool
+
+	// Relationships
+	Transaction Transaction     `gorm:"foreignKey:TransactionID"`
+	Position    MeteoraPosition `gorm:"foreignKey:PositionID"`
+	Pair        MeteoraPair     `gorm:"foreignKey:PairID"`
+}
@@ -9,10 +9,15 @@ import (
 // Transaction represents a Solana blockchain transaction
 type Transaction struct {
 	gorm.Model
-	Signature   string    `gorm:"size:88;uniqueIndex;not null"`
-	WalletID    uint      `gorm:"index;not null"`
-	BlockTime   time.Time `gorm:"index"`
-	Slot        int64     `gorm:"index"`
+	Signature string    `gorm:"size:88;uniqueIndex;not null"`
+	WalletID  uint      `gorm:"index;not null"`
+	BlockTime time.Time `gorm:"index"`
+	Slot      int64     `gorm:"index"`
+	// BlockHash is the hash of the block at Slot, for data sources that
+	// surface it. The Helius feed internal/solana.Client ingests from
+	// today doesn't, so this stays empty there - see internal/reorg's
+	// package doc comment for how fork detection copes without it.
+	BlockHash   string `gorm:"size:88;index"`
 	Description string
 	Type        string `gorm:"size:50;index"`
 	Source      string `gorm:"size:50;index"`
@@ -25,6 +30,12 @@ type Transaction struct {
 	HasTokenTransfers  bool
 	HasInstructions    bool
 
+	// Enrichment tracks the outcome of the last PostProcessTransaction
+	// attempt: "", "enriched", or "enrichment_failed". EnrichmentStage
+	// records which stage failed (e.g. "swap", "fee_claim") for retries.
+	EnrichmentStatus string `gorm:"size:20;index"`
+	EnrichmentStage  string `gorm:"size:30"`
+
 	// Relationships
 	Wallet          Wallet                   `gorm:"foreignKey:WalletID"`
 	Instructions    []TransactionInstruction `gorm:"foreignKey:TransactionID"`
@@ -38,6 +49,7 @@ type Transaction struct {
 	FeeClaims          []MeteoraFeeClaim          `gorm:"foreignKey:TransactionID"`
 	RewardClaims       []MeteoraRewardClaim       `gorm:"foreignKey:TransactionID"`
 	RewardFundings     []MeteoraRewardFunding     `gorm:"foreignKey:TransactionID"`
+	BinDeltas          []MeteoraBinDelta          `gorm:"foreignKey:TransactionID"`
 }
 
 // TransactionInstruction represents an instruction in a transaction
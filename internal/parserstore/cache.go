@@ -0,0 +1,143 @@
+package parserstore
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheSize is how many entries each of CachedStore's and
+// CachedResolver's LRUs hold by default.
+const DefaultCacheSize = 4096
+
+// keyedCache is a fixed-size LRU keyed by string, with a singleflight.Group
+// so a burst of concurrent lookups for the same key collapses into a single
+// call to fetch instead of stampeding whatever's behind it. Zero value is
+// not usable; construct with newKeyedCache.
+type keyedCache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+	group    singleflight.Group
+}
+
+type cacheEntry[V any] struct {
+	key   string
+	value V
+}
+
+// newKeyedCache returns an empty cache holding at most capacity entries.
+// capacity <= 0 means unbounded.
+func newKeyedCache[V any](capacity int) *keyedCache[V] {
+	return &keyedCache[V]{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns key's cached value, calling fetch on a miss. Concurrent
+// callers asking for the same key at the same time share a single call to
+// fetch via singleflight.
+func (c *keyedCache[V]) get(key string, fetch func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		value := elem.Value.(*cacheEntry[V]).value
+		c.mu.Unlock()
+		return value, nil
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	value := v.(V)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		return elem.Value.(*cacheEntry[V]).value, nil
+	}
+	elem := c.order.PushFront(&cacheEntry[V]{key: key, value: value})
+	c.entries[key] = elem
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry[V]).key)
+		}
+	}
+	return value, nil
+}
+
+// CachedStore wraps a Store with a bounded LRU plus singleflight per entity
+// kind, so a burst of parallel transactions touching the same pair,
+// position, wallet, or reward collapses into one underlying lookup instead
+// of stampeding the database.
+type CachedStore struct {
+	store     Store
+	pairs     *keyedCache[uint]
+	positions *keyedCache[uint]
+	wallets   *keyedCache[uint]
+	rewards   *keyedCache[uint]
+}
+
+// NewCachedStore wraps store with LRUs of the given size per entity kind. A
+// size <= 0 means unbounded.
+func NewCachedStore(store Store, size int) *CachedStore {
+	return &CachedStore{
+		store:     store,
+		pairs:     newKeyedCache[uint](size),
+		positions: newKeyedCache[uint](size),
+		wallets:   newKeyedCache[uint](size),
+		rewards:   newKeyedCache[uint](size),
+	}
+}
+
+func (c *CachedStore) GetOrCreatePair(ctx context.Context, address string) (uint, error) {
+	return c.pairs.get(address, func() (uint, error) { return c.store.GetOrCreatePair(ctx, address) })
+}
+
+func (c *CachedStore) GetOrCreatePosition(ctx context.Context, address string) (uint, error) {
+	return c.positions.get(address, func() (uint, error) { return c.store.GetOrCreatePosition(ctx, address) })
+}
+
+func (c *CachedStore) GetOrCreateWallet(ctx context.Context, address string) (uint, error) {
+	return c.wallets.get(address, func() (uint, error) { return c.store.GetOrCreateWallet(ctx, address) })
+}
+
+func (c *CachedStore) GetOrCreateReward(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error) {
+	key := fmt.Sprintf("%s:%d", pairAddress, rewardIndex)
+	return c.rewards.get(key, func() (uint, error) { return c.store.GetOrCreateReward(ctx, pairAddress, rewardIndex) })
+}
+
+var _ Store = (*CachedStore)(nil)
+
+// CachedResolver wraps a TokenAccountResolver with a bounded LRU plus
+// singleflight, so repeated lookups of the same token account - e.g. across
+// many swaps through the same pool - don't repeatedly hit the RPC endpoint
+// behind it.
+type CachedResolver struct {
+	resolver TokenAccountResolver
+	mints    *keyedCache[string]
+}
+
+// NewCachedResolver wraps resolver with an LRU of the given size. A size <=
+// 0 means unbounded.
+func NewCachedResolver(resolver TokenAccountResolver, size int) *CachedResolver {
+	return &CachedResolver{resolver: resolver, mints: newKeyedCache[string](size)}
+}
+
+func (c *CachedResolver) MintOf(ctx context.Context, tokenAccount string) (string, error) {
+	return c.mints.get(tokenAccount, func() (string, error) { return c.resolver.MintOf(ctx, tokenAccount) })
+}
+
+var _ TokenAccountResolver = (*CachedResolver)(nil)
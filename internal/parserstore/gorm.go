@@ -0,0 +1,68 @@
+package parserstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormStore is the production Store, backed by the same Postgres database
+// the rest of mercon writes to (see internal/database). It follows the same
+// FirstOrCreate pattern as scraper.getOrCreateWallet.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a Store backed by db.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+func (s *GormStore) GetOrCreatePair(ctx context.Context, address string) (uint, error) {
+	var pair models.MeteoraPair
+	result := s.db.WithContext(ctx).Where("address = ?", address).FirstOrCreate(&pair, models.MeteoraPair{Address: address})
+	if result.Error != nil {
+		return 0, fmt.Errorf("get or create pair %s: %w", address, result.Error)
+	}
+	return pair.ID, nil
+}
+
+func (s *GormStore) GetOrCreatePosition(ctx context.Context, address string) (uint, error) {
+	var position models.MeteoraPosition
+	result := s.db.WithContext(ctx).Where("address = ?", address).FirstOrCreate(&position, models.MeteoraPosition{Address: address})
+	if result.Error != nil {
+		return 0, fmt.Errorf("get or create position %s: %w", address, result.Error)
+	}
+	return position.ID, nil
+}
+
+func (s *GormStore) GetOrCreateWallet(ctx context.Context, address string) (uint, error) {
+	var wallet models.Wallet
+	result := s.db.WithContext(ctx).Where("address = ?", address).FirstOrCreate(&wallet, models.Wallet{Address: address})
+	if result.Error != nil {
+		return 0, fmt.Errorf("get or create wallet %s: %w", address, result.Error)
+	}
+	return wallet.ID, nil
+}
+
+// GetOrCreateReward resolves pairAddress to its pair row first, since
+// MeteoraReward has no address of its own - it's keyed by pair ID and
+// reward index.
+func (s *GormStore) GetOrCreateReward(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error) {
+	pairID, err := s.GetOrCreatePair(ctx, pairAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	var reward models.MeteoraReward
+	where := models.MeteoraReward{PairID: pairID, RewardIndex: rewardIndex}
+	result := s.db.WithContext(ctx).Where(&where).FirstOrCreate(&reward, where)
+	if result.Error != nil {
+		return 0, fmt.Errorf("get or create reward %d for pair %s: %w", rewardIndex, pairAddress, result.Error)
+	}
+	return reward.ID, nil
+}
+
+var _ Store = (*GormStore)(nil)
@@ -0,0 +1,143 @@
+package parserstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// rewardKey identifies a reward by the pair it belongs to and its index,
+// since rewards have no address of their own on-chain.
+type rewardKey struct {
+	pairAddress string
+	rewardIndex uint64
+}
+
+// MemoryStore is an in-memory Store and TokenAccountResolver for tests. It
+// assigns each newly-seen address the next sequential ID within its own
+// entity kind, so the first pair, position, wallet, and reward it sees each
+// get ID 1, independent of each other. Token account -> mint lookups must be
+// seeded with SetMint before use; MemoryStore has no RPC to fall back on.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	nextPairID     uint
+	nextPositionID uint
+	nextWalletID   uint
+	nextRewardID   uint
+
+	pairs     map[string]uint
+	positions map[string]uint
+	wallets   map[string]uint
+	rewards   map[rewardKey]uint
+	mints     map[string]string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		pairs:     make(map[string]uint),
+		positions: make(map[string]uint),
+		wallets:   make(map[string]uint),
+		rewards:   make(map[rewardKey]uint),
+		mints:     make(map[string]string),
+	}
+}
+
+func (s *MemoryStore) GetOrCreatePair(ctx context.Context, address string) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.pairs[address]; ok {
+		return id, nil
+	}
+	s.nextPairID++
+	s.pairs[address] = s.nextPairID
+	return s.nextPairID, nil
+}
+
+func (s *MemoryStore) GetOrCreatePosition(ctx context.Context, address string) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.positions[address]; ok {
+		return id, nil
+	}
+	s.nextPositionID++
+	s.positions[address] = s.nextPositionID
+	return s.nextPositionID, nil
+}
+
+func (s *MemoryStore) GetOrCreateWallet(ctx context.Context, address string) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.wallets[address]; ok {
+		return id, nil
+	}
+	s.nextWalletID++
+	s.wallets[address] = s.nextWalletID
+	return s.nextWalletID, nil
+}
+
+// GetOrCreateReward assigns IDs keyed by (pairAddress, rewardIndex) only; it
+// does not register pairAddress as a pair, so Pairs lists only addresses
+// passed to GetOrCreatePair.
+func (s *MemoryStore) GetOrCreateReward(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := rewardKey{pairAddress: pairAddress, rewardIndex: rewardIndex}
+	if id, ok := s.rewards[key]; ok {
+		return id, nil
+	}
+	s.nextRewardID++
+	s.rewards[key] = s.nextRewardID
+	return s.nextRewardID, nil
+}
+
+// Pairs returns a snapshot of every pair address this store has assigned an
+// ID to, for tests that want to assert on the ID assignment itself (e.g.
+// internal/solana's conformance corpus, which has no richer pair state to
+// diff against - MemoryStore only ever hands out sequential IDs).
+func (s *MemoryStore) Pairs() map[string]uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]uint, len(s.pairs))
+	for k, v := range s.pairs {
+		out[k] = v
+	}
+	return out
+}
+
+// Positions returns a snapshot of every position address this store has
+// assigned an ID to. See Pairs.
+func (s *MemoryStore) Positions() map[string]uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]uint, len(s.positions))
+	for k, v := range s.positions {
+		out[k] = v
+	}
+	return out
+}
+
+// SetMint seeds tokenAccount's mint for MintOf to return. Tests call this to
+// set up fixtures instead of going through a real RPC lookup.
+func (s *MemoryStore) SetMint(tokenAccount, mint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mints[tokenAccount] = mint
+}
+
+// MintOf returns the mint seeded for tokenAccount via SetMint, or an error
+// if none was seeded.
+func (s *MemoryStore) MintOf(ctx context.Context, tokenAccount string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mint, ok := s.mints[tokenAccount]; ok {
+		return mint, nil
+	}
+	return "", fmt.Errorf("no mint seeded for token account %s", tokenAccount)
+}
+
+var (
+	_ Store                = (*MemoryStore)(nil)
+	_ TokenAccountResolver = (*MemoryStore)(nil)
+)
@@ -0,0 +1,44 @@
+// Package parserstore persists and resolves the entities
+// internal/solana.TransactionParser discovers while decoding Meteora DLMM
+// instructions: pairs, positions, wallets, and rewards, plus SPL token
+// account -> mint lookups used to tell a swap's X side from its Y side.
+//
+// Store mirrors the wallet-store interface pattern used by bytom/vapor: a
+// narrow, storage-agnostic interface the parser depends on, so production
+// code can wire up GormStore while tests use MemoryStore. Neither this
+// package nor the interfaces it defines import internal/solana - an
+// RPC-backed TokenAccountResolver belongs on *solana.Client instead, to
+// avoid a parserstore <-> solana import cycle.
+package parserstore
+
+import "context"
+
+// Store resolves the on-chain addresses TransactionParser encounters to
+// their database row IDs, creating the row the first time an address is
+// seen.
+type Store interface {
+	// GetOrCreatePair returns the row ID for the Meteora pair at address,
+	// creating it if this is the first time it's been seen.
+	GetOrCreatePair(ctx context.Context, address string) (uint, error)
+
+	// GetOrCreatePosition returns the row ID for the position at address,
+	// creating it if this is the first time it's been seen.
+	GetOrCreatePosition(ctx context.Context, address string) (uint, error)
+
+	// GetOrCreateWallet returns the row ID for the wallet at address,
+	// creating it if this is the first time it's been seen.
+	GetOrCreateWallet(ctx context.Context, address string) (uint, error)
+
+	// GetOrCreateReward returns the row ID for the reward at rewardIndex on
+	// the pair at pairAddress, creating both the pair and the reward if
+	// either is being seen for the first time. Rewards have no address of
+	// their own on-chain - they're identified by their pair and index.
+	GetOrCreateReward(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error)
+}
+
+// TokenAccountResolver resolves an SPL token account to the mint it holds.
+// TransactionParser uses it to tell a swap's input token account apart
+// from a pair's X and Y mints (isTokenXToY, isXToken).
+type TokenAccountResolver interface {
+	MintOf(ctx context.Context, tokenAccount string) (string, error)
+}
@@ -0,0 +1,146 @@
+package parserstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemoryStore_GetOrCreate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	t.Run("same address returns same ID", func(t *testing.T) {
+		first, err := store.GetOrCreatePair(ctx, "pair-1")
+		if err != nil {
+			t.Fatalf("GetOrCreatePair: %v", err)
+		}
+		second, err := store.GetOrCreatePair(ctx, "pair-1")
+		if err != nil {
+			t.Fatalf("GetOrCreatePair: %v", err)
+		}
+		if first != second {
+			t.Fatalf("expected repeated lookup to return the same ID, got %d and %d", first, second)
+		}
+	})
+
+	t.Run("different addresses get different IDs", func(t *testing.T) {
+		first, _ := store.GetOrCreatePair(ctx, "pair-2")
+		second, _ := store.GetOrCreatePair(ctx, "pair-3")
+		if first == second {
+			t.Fatalf("expected distinct addresses to get distinct IDs, both got %d", first)
+		}
+	})
+
+	t.Run("entity kinds have independent ID sequences", func(t *testing.T) {
+		// This is the first position and the first wallet this store has
+		// seen, even though it's already assigned several pair IDs above -
+		// each entity kind keeps its own sequence.
+		positionID, _ := store.GetOrCreatePosition(ctx, "solo-position")
+		walletID, _ := store.GetOrCreateWallet(ctx, "solo-wallet")
+		if positionID != 1 {
+			t.Fatalf("expected first position to get ID 1, got %d", positionID)
+		}
+		if walletID != 1 {
+			t.Fatalf("expected first wallet to get ID 1, got %d", walletID)
+		}
+	})
+
+	t.Run("reward is keyed by pair and index", func(t *testing.T) {
+		first, err := store.GetOrCreateReward(ctx, "reward-pair", 0)
+		if err != nil {
+			t.Fatalf("GetOrCreateReward: %v", err)
+		}
+		second, err := store.GetOrCreateReward(ctx, "reward-pair", 1)
+		if err != nil {
+			t.Fatalf("GetOrCreateReward: %v", err)
+		}
+		if first == second {
+			t.Fatalf("expected distinct reward indices on the same pair to get distinct IDs, both got %d", first)
+		}
+		again, err := store.GetOrCreateReward(ctx, "reward-pair", 0)
+		if err != nil {
+			t.Fatalf("GetOrCreateReward: %v", err)
+		}
+		if again != first {
+			t.Fatalf("expected repeated (pair, index) lookup to return the same ID, got %d and %d", first, again)
+		}
+	})
+}
+
+func TestMemoryStore_MintOf(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if _, err := store.MintOf(ctx, "unseeded-account"); err == nil {
+		t.Fatal("expected an error for an unseeded token account, got none")
+	}
+
+	store.SetMint("token-account-1", "mint-1")
+	mint, err := store.MintOf(ctx, "token-account-1")
+	if err != nil {
+		t.Fatalf("MintOf: %v", err)
+	}
+	if mint != "mint-1" {
+		t.Fatalf("expected mint-1, got %s", mint)
+	}
+}
+
+// countingStore wraps a Store and counts how many times GetOrCreatePair's
+// underlying lookup actually runs, so tests can assert the cache is doing
+// its job rather than just passing through. It counts no other method.
+type countingStore struct {
+	calls int32
+}
+
+func (s *countingStore) GetOrCreatePair(ctx context.Context, address string) (uint, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return 42, nil
+}
+
+func (s *countingStore) GetOrCreatePosition(ctx context.Context, address string) (uint, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *countingStore) GetOrCreateWallet(ctx context.Context, address string) (uint, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *countingStore) GetOrCreateReward(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error) {
+	return 0, errors.New("not implemented")
+}
+
+func TestCachedStore_DeduplicatesConcurrentLookups(t *testing.T) {
+	inner := &countingStore{}
+	cached := NewCachedStore(inner, DefaultCacheSize)
+
+	const concurrency = 20
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			id, err := cached.GetOrCreatePair(context.Background(), "same-pair")
+			if err != nil {
+				t.Errorf("GetOrCreatePair: %v", err)
+			}
+			if id != 42 {
+				t.Errorf("expected ID 42, got %d", id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&inner.calls); got != 1 {
+		t.Fatalf("expected the underlying store to be called exactly once for a burst of lookups on the same address, got %d calls", got)
+	}
+
+	if _, err := cached.GetOrCreatePair(context.Background(), "same-pair"); err != nil {
+		t.Fatalf("GetOrCreatePair: %v", err)
+	}
+	if got := atomic.LoadInt32(&inner.calls); got != 1 {
+		t.Fatalf("expected a cached lookup to not call the underlying store again, got %d calls", got)
+	}
+}
@@ -2,14 +2,22 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
+
+	"github.com/wnt/mercon/internal/metrics"
 )
 
+// maxWalletErrorsKept bounds how many recent error messages
+// RecordWalletError keeps per wallet, so a wallet that fails forever
+// doesn't grow its error list without limit.
+const maxWalletErrorsKept = 10
+
 // Client wraps Redis operations for Mercon queue management
 type Client struct {
 	client *redis.Client
@@ -75,7 +83,7 @@ func (c *Client) PushWallet(ctx context.Context, addr string, priority float64)
 		Str("wallet", addr).
 		Float64("priority", priority).
 		Msg("Pushed wallet to queue")
-	
+
 	return nil
 }
 
@@ -83,7 +91,7 @@ func (c *Client) PushWallet(ctx context.Context, addr string, priority float64)
 func (c *Client) SetInFlight(ctx context.Context, addr, worker string) error {
 	value := fmt.Sprintf("%s,%d", worker, time.Now().Unix())
 	err := c.client.HSet(ctx, "wallet_inflight", addr, value).Err()
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to set wallet in-flight: %w", err)
 	}
@@ -92,14 +100,14 @@ func (c *Client) SetInFlight(ctx context.Context, addr, worker string) error {
 		Str("wallet", addr).
 		Str("worker", worker).
 		Msg("Marked wallet as in-flight")
-	
+
 	return nil
 }
 
 // RemoveInFlight removes a wallet from the in-flight tracking
 func (c *Client) RemoveInFlight(ctx context.Context, addr string) error {
 	err := c.client.HDel(ctx, "wallet_inflight", addr).Err()
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to remove wallet from in-flight: %w", err)
 	}
@@ -124,7 +132,7 @@ func (c *Client) GetProgress(ctx context.Context, addr string) (string, error) {
 // SetProgress updates the last processed signature for a wallet
 func (c *Client) SetProgress(ctx context.Context, addr, sig string) error {
 	err := c.client.HSet(ctx, "wallet_progress", addr, sig).Err()
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to set wallet progress: %w", err)
 	}
@@ -133,7 +141,7 @@ func (c *Client) SetProgress(ctx context.Context, addr, sig string) error {
 		Str("wallet", addr).
 		Str("signature", sig).
 		Msg("Updated wallet progress")
-	
+
 	return nil
 }
 
@@ -146,6 +154,15 @@ func (c *Client) GetQueueLength(ctx context.Context) (int64, error) {
 	return length, nil
 }
 
+// GetBackfillQueueLength returns the number of pending backfill jobs
+func (c *Client) GetBackfillQueueLength(ctx context.Context) (int64, error) {
+	length, err := c.client.LLen(ctx, "backfill_queue").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get backfill queue length: %w", err)
+	}
+	return length, nil
+}
+
 // GetInFlightWallets returns all wallets currently being processed
 func (c *Client) GetInFlightWallets(ctx context.Context) (map[string]string, error) {
 	result, err := c.client.HGetAll(ctx, "wallet_inflight").Result()
@@ -155,8 +172,14 @@ func (c *Client) GetInFlightWallets(ctx context.Context) (map[string]string, err
 	return result, nil
 }
 
-// RequeueStuckWallets moves wallets that have been in-flight too long back to the queue
-func (c *Client) RequeueStuckWallets(ctx context.Context, timeoutMinutes int) error {
+// RequeueStuckWallets moves wallets that have been in-flight too long back
+// to the queue, unless a wallet has already been requeued maxRequeues times
+// (the count is not reset when the wallet later completes; only
+// dead-lettering or a DLQ retry clears it), in which case it's routed to
+// the DLQ instead so a consistently
+// failing wallet (bad address, RPC always errors on it) can't cycle forever
+// and starve healthy work.
+func (c *Client) RequeueStuckWallets(ctx context.Context, timeoutMinutes int, maxRequeues int) error {
 	inFlight, err := c.GetInFlightWallets(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get in-flight wallets: %w", err)
@@ -178,24 +201,40 @@ func (c *Client) RequeueStuckWallets(ctx context.Context, timeoutMinutes int) er
 			continue
 		}
 
-		if startTime < cutoff {
-			// Wallet has been stuck too long, requeue it
-			if err := c.PushWallet(ctx, wallet, 0); err != nil {
-				c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to requeue stuck wallet")
-				continue
-			}
+		if startTime >= cutoff {
+			continue
+		}
+
+		// Wallet has been stuck too long.
+		if err := c.RemoveInFlight(ctx, wallet); err != nil {
+			c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to remove requeued wallet from in-flight")
+		}
+
+		requeueCount, err := c.client.HIncrBy(ctx, "wallet_requeue_count", wallet, 1).Result()
+		if err != nil {
+			c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to increment wallet requeue count")
+			continue
+		}
 
-			if err := c.RemoveInFlight(ctx, wallet); err != nil {
-				c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to remove requeued wallet from in-flight")
+		if int(requeueCount) > maxRequeues {
+			if err := c.deadLetterWallet(ctx, wallet, int(requeueCount)); err != nil {
+				c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to dead-letter wallet")
 			}
+			continue
+		}
 
-			requeuedCount++
-			c.logger.Info().
-				Str("wallet", wallet).
-				Str("worker", parts[0]).
-				Int64("stuck_minutes", (time.Now().Unix()-startTime)/60).
-				Msg("Requeued stuck wallet")
+		if err := c.PushWallet(ctx, wallet, 0); err != nil {
+			c.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to requeue stuck wallet")
+			continue
 		}
+
+		requeuedCount++
+		c.logger.Info().
+			Str("wallet", wallet).
+			Str("worker", parts[0]).
+			Int64("stuck_minutes", (time.Now().Unix()-startTime)/60).
+			Int64("requeue_count", requeueCount).
+			Msg("Requeued stuck wallet")
 	}
 
 	if requeuedCount > 0 {
@@ -205,6 +244,301 @@ func (c *Client) RequeueStuckWallets(ctx context.Context, timeoutMinutes int) er
 	return nil
 }
 
+// walletErrorsKey is the Redis list RecordWalletError appends to and
+// deadLetterWallet reads from: the last few error messages a worker hit
+// while processing addr, newest first.
+func walletErrorsKey(addr string) string {
+	return fmt.Sprintf("wallet_errors:%s", addr)
+}
+
+// RecordWalletError appends an error message to the wallet's recent-error
+// log, trimmed to the most recent maxWalletErrorsKept, so a DLQ entry can
+// show root cause without database spelunking. Called by the worker after
+// a failed processWallet attempt.
+func (c *Client) RecordWalletError(ctx context.Context, addr, errMsg string) error {
+	if err := c.client.LPush(ctx, walletErrorsKey(addr), errMsg).Err(); err != nil {
+		return fmt.Errorf("failed to record wallet error: %w", err)
+	}
+	if err := c.client.LTrim(ctx, walletErrorsKey(addr), 0, maxWalletErrorsKept-1).Err(); err != nil {
+		return fmt.Errorf("failed to trim wallet error log: %w", err)
+	}
+	return nil
+}
+
+// GetWalletErrors returns the wallet's recent error log, newest first.
+func (c *Client) GetWalletErrors(ctx context.Context, addr string) ([]string, error) {
+	errs, err := c.client.LRange(ctx, walletErrorsKey(addr), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet error log: %w", err)
+	}
+	return errs, nil
+}
+
+// DLQEntry is a wallet that RequeueStuckWallets gave up on, recorded in the
+// wallet_dlq hash for an operator to inspect via the admin API.
+type DLQEntry struct {
+	Wallet         string    `json:"wallet"`
+	RequeueCount   int       `json:"requeueCount"`
+	DeadLetteredAt time.Time `json:"deadLetteredAt"`
+	RecentErrors   []string  `json:"recentErrors"`
+}
+
+// deadLetterWallet moves a wallet that exceeded its requeue budget into the
+// DLQ, carrying its recent error log along so the reason it kept getting
+// stuck is visible without a DB lookup.
+func (c *Client) deadLetterWallet(ctx context.Context, wallet string, requeueCount int) error {
+	recentErrors, err := c.GetWalletErrors(ctx, wallet)
+	if err != nil {
+		c.logger.Warn().Err(err).Str("wallet", wallet).Msg("Failed to fetch wallet error log for DLQ entry")
+	}
+
+	entry := DLQEntry{
+		Wallet:         wallet,
+		RequeueCount:   requeueCount,
+		DeadLetteredAt: time.Now(),
+		RecentErrors:   recentErrors,
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode DLQ entry: %w", err)
+	}
+
+	if err := c.client.HSet(ctx, "wallet_dlq", wallet, encoded).Err(); err != nil {
+		return fmt.Errorf("failed to write DLQ entry: %w", err)
+	}
+
+	if err := c.client.HDel(ctx, "wallet_requeue_count", wallet).Err(); err != nil {
+		c.logger.Warn().Err(err).Str("wallet", wallet).Msg("Failed to clear wallet requeue count after dead-lettering")
+	}
+
+	metrics.RecordWalletDeadLettered()
+	c.logger.Warn().
+		Str("wallet", wallet).
+		Int("requeue_count", requeueCount).
+		Strs("recent_errors", recentErrors).
+		Msg("Wallet exceeded max requeues, moved to DLQ")
+
+	return nil
+}
+
+// ListDLQ returns every wallet currently parked in the DLQ, for the admin
+// API's queue_listDLQ method.
+func (c *Client) ListDLQ(ctx context.Context) ([]DLQEntry, error) {
+	raw, err := c.client.HGetAll(ctx, "wallet_dlq").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DLQ: %w", err)
+	}
+
+	entries := make([]DLQEntry, 0, len(raw))
+	for wallet, encoded := range raw {
+		var entry DLQEntry
+		if err := json.Unmarshal([]byte(encoded), &entry); err != nil {
+			c.logger.Warn().Err(err).Str("wallet", wallet).Msg("Failed to decode DLQ entry")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// RetryDLQWallet pulls a wallet out of the DLQ and back onto the main
+// queue with a clean requeue count, for the admin API's queue_retryDLQ
+// method (an operator manually reviving a wallet after fixing the root
+// cause).
+func (c *Client) RetryDLQWallet(ctx context.Context, addr string) error {
+	removed, err := c.client.HDel(ctx, "wallet_dlq", addr).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove wallet from DLQ: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("wallet %s is not in the DLQ", addr)
+	}
+
+	if err := c.client.HDel(ctx, "wallet_requeue_count", addr).Err(); err != nil {
+		c.logger.Warn().Err(err).Str("wallet", addr).Msg("Failed to clear wallet requeue count on DLQ retry")
+	}
+	if err := c.client.Del(ctx, walletErrorsKey(addr)).Err(); err != nil {
+		c.logger.Warn().Err(err).Str("wallet", addr).Msg("Failed to clear wallet error log on DLQ retry")
+	}
+
+	if err := c.PushWallet(ctx, addr, 0); err != nil {
+		return fmt.Errorf("failed to requeue wallet from DLQ: %w", err)
+	}
+
+	c.logger.Info().Str("wallet", addr).Msg("Retried DLQ wallet")
+	return nil
+}
+
+// PurgeDLQ clears every wallet out of the DLQ without requeuing them, for
+// the admin API's queue_purgeDLQ method (an operator deciding a batch of
+// dead-lettered wallets genuinely aren't worth retrying).
+func (c *Client) PurgeDLQ(ctx context.Context) error {
+	wallets, err := c.client.HKeys(ctx, "wallet_dlq").Result()
+	if err != nil {
+		return fmt.Errorf("failed to list DLQ for purge: %w", err)
+	}
+	if len(wallets) == 0 {
+		return nil
+	}
+
+	if err := c.client.Del(ctx, "wallet_dlq").Err(); err != nil {
+		return fmt.Errorf("failed to purge DLQ: %w", err)
+	}
+
+	c.logger.Info().Int("count", len(wallets)).Msg("Purged DLQ")
+	return nil
+}
+
+// PauseWallet marks a wallet as paused, for the admin API's
+// scraper_pauseWallet method. Paused wallets are left in the queue/in-flight
+// tracking untouched; it's up to callers (the worker loop) to check
+// IsWalletPaused before picking one up.
+func (c *Client) PauseWallet(ctx context.Context, addr string) error {
+	if err := c.client.SAdd(ctx, "wallet_paused", addr).Err(); err != nil {
+		return fmt.Errorf("failed to pause wallet: %w", err)
+	}
+
+	c.logger.Info().Str("wallet", addr).Msg("Paused wallet")
+	return nil
+}
+
+// ResumeWallet clears a wallet's paused status, for the admin API's
+// scraper_resumeWallet method.
+func (c *Client) ResumeWallet(ctx context.Context, addr string) error {
+	if err := c.client.SRem(ctx, "wallet_paused", addr).Err(); err != nil {
+		return fmt.Errorf("failed to resume wallet: %w", err)
+	}
+
+	c.logger.Info().Str("wallet", addr).Msg("Resumed wallet")
+	return nil
+}
+
+// IsWalletPaused reports whether a wallet is currently paused.
+func (c *Client) IsWalletPaused(ctx context.Context, addr string) (bool, error) {
+	paused, err := c.client.SIsMember(ctx, "wallet_paused", addr).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check wallet paused status: %w", err)
+	}
+	return paused, nil
+}
+
+// BackfillJob describes a request to re-walk a wallet's history within a
+// specific slot range, queued by the admin API's scraper_backfill method.
+type BackfillJob struct {
+	Wallet   string `json:"wallet"`
+	FromSlot uint64 `json:"fromSlot"`
+	ToSlot   uint64 `json:"toSlot"`
+}
+
+// PushBackfillJob queues a backfill job for a worker to pick up.
+func (c *Client) PushBackfillJob(ctx context.Context, job BackfillJob) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode backfill job: %w", err)
+	}
+
+	if err := c.client.RPush(ctx, "backfill_queue", encoded).Err(); err != nil {
+		return fmt.Errorf("failed to push backfill job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("wallet", job.Wallet).
+		Uint64("from_slot", job.FromSlot).
+		Uint64("to_slot", job.ToSlot).
+		Msg("Queued backfill job")
+
+	return nil
+}
+
+// PopBackfillJob removes and returns the oldest queued backfill job, or a
+// nil job if the queue is empty.
+func (c *Client) PopBackfillJob(ctx context.Context) (*BackfillJob, error) {
+	result, err := c.client.LPop(ctx, "backfill_queue").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // No backfill jobs queued
+		}
+		return nil, fmt.Errorf("failed to pop backfill job: %w", err)
+	}
+
+	var job BackfillJob
+	if err := json.Unmarshal([]byte(result), &job); err != nil {
+		return nil, fmt.Errorf("failed to decode backfill job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// RemoveFromQueue removes a wallet from the pending queue without marking it
+// in-flight, for the gRPC control plane's DequeueWallet method (an operator
+// pulling a wallet out before a worker picks it up).
+func (c *Client) RemoveFromQueue(ctx context.Context, addr string) error {
+	if err := c.client.ZRem(ctx, "wallet_queue", addr).Err(); err != nil {
+		return fmt.Errorf("failed to remove wallet from queue: %w", err)
+	}
+
+	c.logger.Info().Str("wallet", addr).Msg("Removed wallet from queue")
+	return nil
+}
+
+// PeekQueue returns up to limit wallet addresses currently waiting in the
+// queue, highest-priority first, without removing them. Used by the admin
+// API's queue_peek method so an operator can inspect backlog without
+// affecting it.
+func (c *Client) PeekQueue(ctx context.Context, limit int) ([]string, error) {
+	result, err := c.client.ZRange(ctx, "wallet_queue", 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to peek queue: %w", err)
+	}
+	return result, nil
+}
+
+// PurgeWallet removes a wallet from every piece of queue state: the pending
+// queue, in-flight tracking, and the paused set. Used by the admin API's
+// queue_purge method to fully drop a wallet the operator no longer wants
+// scraped, as opposed to RemoveFromQueue which only pulls it off the
+// pending queue.
+func (c *Client) PurgeWallet(ctx context.Context, addr string) error {
+	if err := c.client.ZRem(ctx, "wallet_queue", addr).Err(); err != nil {
+		return fmt.Errorf("failed to purge wallet from queue: %w", err)
+	}
+	if err := c.client.HDel(ctx, "wallet_inflight", addr).Err(); err != nil {
+		return fmt.Errorf("failed to purge wallet from in-flight: %w", err)
+	}
+	if err := c.client.SRem(ctx, "wallet_paused", addr).Err(); err != nil {
+		return fmt.Errorf("failed to purge wallet from paused set: %w", err)
+	}
+
+	c.logger.Info().Str("wallet", addr).Msg("Purged wallet from queue")
+	return nil
+}
+
+// GetInFlightInfo returns the worker currently processing addr and when it
+// picked the wallet up, for the gRPC control plane's GetWalletProgress
+// method. ok is false if the wallet isn't currently in flight.
+func (c *Client) GetInFlightInfo(ctx context.Context, addr string) (workerID string, since time.Time, ok bool, err error) {
+	value, err := c.client.HGet(ctx, "wallet_inflight", addr).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", time.Time{}, false, nil
+		}
+		return "", time.Time{}, false, fmt.Errorf("failed to get in-flight info: %w", err)
+	}
+
+	parts := splitValue(value)
+	if len(parts) != 2 {
+		return "", time.Time{}, false, fmt.Errorf("invalid in-flight value format for %s", addr)
+	}
+
+	ts, parseErr := strconv.ParseInt(parts[1], 10, 64)
+	if parseErr != nil {
+		return "", time.Time{}, false, fmt.Errorf("invalid in-flight timestamp for %s: %w", addr, parseErr)
+	}
+
+	return parts[0], time.Unix(ts, 0), true, nil
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	return c.client.Close()
@@ -214,19 +548,19 @@ func (c *Client) Close() error {
 func splitValue(value string) []string {
 	parts := make([]string, 0, 2)
 	commaIndex := -1
-	
+
 	for i, char := range value {
 		if char == ',' {
 			commaIndex = i
 			break
 		}
 	}
-	
+
 	if commaIndex == -1 {
 		return []string{value}
 	}
-	
+
 	parts = append(parts, value[:commaIndex])
 	parts = append(parts, value[commaIndex+1:])
 	return parts
-} 
\ No newline at end of file
+}
@@ -0,0 +1,30 @@
+package queue
+
+import "context"
+
+// Queue is the subset of Client's surface that internal/worker.Worker needs
+// to pull wallets off the queue and report back on them. It exists so
+// Worker can run against either backend QUEUE_BACKEND selects - the
+// ZSET-based Client, or the Redis Streams-based StreamClient - without
+// caring which one it was handed.
+//
+// The admin-facing surface (DLQ inspection, queue peek/purge, in-flight
+// introspection) is deliberately not part of this interface: it's specific
+// to Client's ZSET/hash layout and is only exposed through
+// internal/worker.Manager.Queue(), which still returns a concrete *Client.
+// See StreamClient's doc comment for what that means for QUEUE_BACKEND=streams.
+type Queue interface {
+	PushWallet(ctx context.Context, addr string, priority float64) error
+	PopWallet(ctx context.Context) (string, error)
+	SetInFlight(ctx context.Context, addr, worker string) error
+	RemoveInFlight(ctx context.Context, addr string) error
+	GetProgress(ctx context.Context, addr string) (string, error)
+	SetProgress(ctx context.Context, addr, sig string) error
+	IsWalletPaused(ctx context.Context, addr string) (bool, error)
+	RecordWalletError(ctx context.Context, addr, errMsg string) error
+	PopBackfillJob(ctx context.Context) (*BackfillJob, error)
+	Close() error
+}
+
+var _ Queue = (*Client)(nil)
+var _ Queue = (*StreamClient)(nil)
@@ -0,0 +1,428 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/wnt/mercon/internal/metrics"
+)
+
+// walletStreamKey is the Redis Stream StreamClient pushes wallets onto and
+// reads them back from, in place of Client's wallet_queue ZSET.
+const walletStreamKey = "wallet_stream"
+
+// walletConsumerGroup is the single consumer group every StreamClient in a
+// process joins. All workers in that process read through one shared
+// *StreamClient (see NewManager), so they share one consumer identity too -
+// see the consumer field below.
+const walletConsumerGroup = "wallet_workers"
+
+// walletDeadLetterStreamKey is where entries that exceeded maxDeliveries
+// are moved, mirroring Client's wallet_dlq hash.
+const walletDeadLetterStreamKey = "wallet_deadletter"
+
+const (
+	defaultMaxDeliveries   = 5
+	defaultReapInterval    = time.Minute
+	defaultReapIdleTimeout = 15 * time.Minute
+)
+
+// StreamClient is a Queue backend built on Redis Streams instead of
+// Client's ZSET/hash layout: PushWallet is an XADD, PopWallet is an
+// XREADGROUP against a shared consumer group, and a background goroutine
+// calls XAUTOCLAIM to reclaim entries a worker picked up and never
+// acknowledged, consulting XPENDING's per-entry delivery count to decide
+// whether to hand the entry back out again or move it to
+// walletDeadLetterStreamKey.
+//
+// It satisfies Queue, the subset of Client's surface internal/worker.Worker
+// needs - GetProgress/SetProgress/IsWalletPaused/RecordWalletError/
+// PopBackfillJob delegate to the exact same Redis keys Client uses, since
+// none of those are ZSET-specific. The admin surface Client exposes beyond
+// Queue (GetQueueLength, RequeueStuckWallets, ListDLQ/RetryDLQWallet/
+// PurgeDLQ, PeekQueue, ...) has no StreamClient equivalent here: those
+// read/write the ZSET and hashes directly, and building a
+// streams-native admin API (XLEN, an XPENDING summary, browsing
+// walletDeadLetterStreamKey) is follow-up work, not part of this backend
+// swap. See internal/worker.Manager, which keeps its admin-facing queue
+// field pointed at a ZSET Client regardless of QUEUE_BACKEND and only
+// hands workers a StreamClient when it's configured.
+type StreamClient struct {
+	client        *redis.Client
+	logger        zerolog.Logger
+	consumer      string
+	maxDeliveries int64
+	reapInterval  time.Duration
+	reapIdle      time.Duration
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+
+	pendingMu sync.Mutex
+	pending   map[string]string // wallet addr -> stream message ID not yet acked
+
+	reclaimedMu sync.Mutex
+	reclaimed   []string // addrs XAUTOCLAIM reclaimed, drained by PopWallet before a fresh XREADGROUP
+}
+
+// StreamClientOption configures optional StreamClient behavior.
+type StreamClientOption func(*StreamClient)
+
+// WithMaxDeliveries overrides how many times a wallet can be delivered
+// (first read + reclaims) before the reaper dead-letters it. Default 5,
+// matching Client's MAX_WALLET_REQUEUES default.
+func WithMaxDeliveries(n int64) StreamClientOption {
+	return func(s *StreamClient) { s.maxDeliveries = n }
+}
+
+// WithReapInterval overrides how often the background reaper scans for
+// stuck entries. Default one minute.
+func WithReapInterval(d time.Duration) StreamClientOption {
+	return func(s *StreamClient) { s.reapInterval = d }
+}
+
+// WithReapIdleTimeout overrides how long an entry must sit unacknowledged
+// before the reaper reclaims it. Default 15 minutes, matching the timeout
+// Manager.runStuckWalletRecovery passes to Client.RequeueStuckWallets.
+func WithReapIdleTimeout(d time.Duration) StreamClientOption {
+	return func(s *StreamClient) { s.reapIdle = d }
+}
+
+// NewStreamClient connects to Redis and ensures walletConsumerGroup exists
+// on walletStreamKey, creating both if this is the first connection to see
+// them. It then starts the background XAUTOCLAIM reaper; callers must call
+// Close to stop it.
+func NewStreamClient(redisURL string, logger zerolog.Logger, opts ...StreamClientOption) (*StreamClient, error) {
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	if err := client.XGroupCreateMkStream(ctx, walletStreamKey, walletConsumerGroup, "0").Err(); err != nil &&
+		!strings.Contains(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create %s consumer group: %w", walletConsumerGroup, err)
+	}
+
+	s := &StreamClient{
+		client:        client,
+		logger:        logger.With().Str("component", "stream_queue").Logger(),
+		consumer:      fmt.Sprintf("consumer-%d", os.Getpid()),
+		maxDeliveries: defaultMaxDeliveries,
+		reapInterval:  defaultReapInterval,
+		reapIdle:      defaultReapIdleTimeout,
+		stopCh:        make(chan struct{}),
+		pending:       make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	logger.Info().Str("redis_url", redisURL).Str("consumer", s.consumer).Msg("Connected to Redis Streams queue successfully")
+
+	go s.runReaper()
+
+	return s, nil
+}
+
+// PushWallet adds a wallet to the stream. Unlike Client's ZSET, a stream is
+// strictly FIFO by arrival order - priority is carried along on the entry
+// for observability, but (unlike ZAdd) it can't move a requeued wallet
+// ahead of ones already waiting. Callers that depend on priority-based
+// reordering (e.g. RetryDLQWallet's "requeue at the front") get
+// best-effort FIFO instead under QUEUE_BACKEND=streams.
+func (s *StreamClient) PushWallet(ctx context.Context, addr string, priority float64) error {
+	err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: walletStreamKey,
+		Values: map[string]interface{}{"addr": addr, "priority": priority},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to push wallet to stream: %w", err)
+	}
+
+	s.logger.Debug().Str("wallet", addr).Float64("priority", priority).Msg("Pushed wallet to stream")
+	return nil
+}
+
+// PopWallet returns a reclaimed entry if the reaper has one waiting,
+// otherwise reads one new entry from walletStreamKey under
+// walletConsumerGroup. Returns "", nil if nothing is available, matching
+// Client.PopWallet's contract.
+func (s *StreamClient) PopWallet(ctx context.Context) (string, error) {
+	if addr, ok := s.popReclaimed(); ok {
+		return addr, nil
+	}
+
+	res, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    walletConsumerGroup,
+		Consumer: s.consumer,
+		Streams:  []string{walletStreamKey, ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read wallet from stream: %w", err)
+	}
+	if len(res) == 0 || len(res[0].Messages) == 0 {
+		return "", nil
+	}
+
+	msg := res[0].Messages[0]
+	addr, _ := msg.Values["addr"].(string)
+	s.recordPending(addr, msg.ID)
+
+	s.logger.Debug().Str("wallet", addr).Str("message_id", msg.ID).Msg("Popped wallet from stream")
+	return addr, nil
+}
+
+// SetInFlight mirrors the entry into the same wallet_inflight hash Client
+// uses, purely for operator visibility (e.g. a health check pointed at the
+// ZSET Client's GetInFlightWallets). Ownership itself is already tracked by
+// the consumer group's pending entries list - this call doesn't affect
+// delivery or acking.
+func (s *StreamClient) SetInFlight(ctx context.Context, addr, worker string) error {
+	value := fmt.Sprintf("%s,%d", worker, time.Now().Unix())
+	if err := s.client.HSet(ctx, "wallet_inflight", addr, value).Err(); err != nil {
+		return fmt.Errorf("failed to set wallet in-flight: %w", err)
+	}
+	return nil
+}
+
+// RemoveInFlight acknowledges and deletes the wallet's pending stream
+// entry, and clears the wallet_inflight visibility hash SetInFlight wrote.
+func (s *StreamClient) RemoveInFlight(ctx context.Context, addr string) error {
+	if id, ok := s.forgetPending(addr); ok {
+		if err := s.client.XAck(ctx, walletStreamKey, walletConsumerGroup, id).Err(); err != nil {
+			return fmt.Errorf("failed to ack wallet stream entry: %w", err)
+		}
+		if err := s.client.XDel(ctx, walletStreamKey, id).Err(); err != nil {
+			return fmt.Errorf("failed to delete wallet stream entry: %w", err)
+		}
+	}
+
+	if err := s.client.HDel(ctx, "wallet_inflight", addr).Err(); err != nil {
+		return fmt.Errorf("failed to remove wallet from in-flight: %w", err)
+	}
+	return nil
+}
+
+// GetProgress reads the same wallet_progress hash Client uses - progress
+// tracking isn't ZSET-specific, so both backends share it.
+func (s *StreamClient) GetProgress(ctx context.Context, addr string) (string, error) {
+	result, err := s.client.HGet(ctx, "wallet_progress", addr).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get wallet progress: %w", err)
+	}
+	return result, nil
+}
+
+// SetProgress writes the same wallet_progress hash Client uses. See GetProgress.
+func (s *StreamClient) SetProgress(ctx context.Context, addr, sig string) error {
+	if err := s.client.HSet(ctx, "wallet_progress", addr, sig).Err(); err != nil {
+		return fmt.Errorf("failed to set wallet progress: %w", err)
+	}
+	return nil
+}
+
+// IsWalletPaused reads the same wallet_paused set Client uses. See GetProgress.
+func (s *StreamClient) IsWalletPaused(ctx context.Context, addr string) (bool, error) {
+	paused, err := s.client.SIsMember(ctx, "wallet_paused", addr).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check wallet paused status: %w", err)
+	}
+	return paused, nil
+}
+
+// RecordWalletError appends to the same wallet_errors:<addr> list Client
+// uses. See GetProgress.
+func (s *StreamClient) RecordWalletError(ctx context.Context, addr, errMsg string) error {
+	if err := s.client.LPush(ctx, walletErrorsKey(addr), errMsg).Err(); err != nil {
+		return fmt.Errorf("failed to record wallet error: %w", err)
+	}
+	if err := s.client.LTrim(ctx, walletErrorsKey(addr), 0, maxWalletErrorsKept-1).Err(); err != nil {
+		return fmt.Errorf("failed to trim wallet error log: %w", err)
+	}
+	return nil
+}
+
+// PopBackfillJob pops from the same backfill_queue list Client uses.
+// Backfill jobs are one-shot range requests, not the steady wallet stream
+// this migration targets, so there's no streams-based equivalent here.
+func (s *StreamClient) PopBackfillJob(ctx context.Context) (*BackfillJob, error) {
+	result, err := s.client.LPop(ctx, "backfill_queue").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to pop backfill job: %w", err)
+	}
+
+	var job BackfillJob
+	if err := json.Unmarshal([]byte(result), &job); err != nil {
+		return nil, fmt.Errorf("failed to decode backfill job: %w", err)
+	}
+	return &job, nil
+}
+
+// Close stops the background reaper and closes the Redis connection.
+func (s *StreamClient) Close() error {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	return s.client.Close()
+}
+
+// recordPending remembers that addr was delivered as message id, so
+// RemoveInFlight knows what to XAck.
+func (s *StreamClient) recordPending(addr, id string) {
+	s.pendingMu.Lock()
+	s.pending[addr] = id
+	s.pendingMu.Unlock()
+}
+
+// forgetPending removes and returns the pending message id recorded for
+// addr, if any.
+func (s *StreamClient) forgetPending(addr string) (string, bool) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	id, ok := s.pending[addr]
+	if ok {
+		delete(s.pending, addr)
+	}
+	return id, ok
+}
+
+func (s *StreamClient) pushReclaimed(addr string) {
+	s.reclaimedMu.Lock()
+	s.reclaimed = append(s.reclaimed, addr)
+	s.reclaimedMu.Unlock()
+}
+
+func (s *StreamClient) popReclaimed() (string, bool) {
+	s.reclaimedMu.Lock()
+	defer s.reclaimedMu.Unlock()
+	if len(s.reclaimed) == 0 {
+		return "", false
+	}
+	addr := s.reclaimed[0]
+	s.reclaimed = s.reclaimed[1:]
+	return addr, true
+}
+
+// runReaper periodically reclaims stream entries that have been pending
+// (delivered but never acked - a worker died or hung mid-processWallet)
+// for longer than reapIdle, until Close stops it.
+func (s *StreamClient) runReaper() {
+	ticker := time.NewTicker(s.reapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := s.reapOnce(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Failed to reap stuck wallet stream entries")
+			}
+			cancel()
+		}
+	}
+}
+
+// reapOnce claims every entry idle longer than reapIdle via XAUTOCLAIM and,
+// for each one, consults XPENDING's delivery count: entries that have
+// exceeded maxDeliveries are moved to walletDeadLetterStreamKey, the rest
+// are claimed under this process's shared consumer and handed to the next
+// PopWallet caller via the reclaimed buffer.
+func (s *StreamClient) reapOnce(ctx context.Context) error {
+	start := "0-0"
+	for {
+		claimed, nextStart, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   walletStreamKey,
+			Group:    walletConsumerGroup,
+			Consumer: s.consumer,
+			MinIdle:  s.reapIdle,
+			Start:    start,
+			Count:    50,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("xautoclaim: %w", err)
+		}
+
+		for _, msg := range claimed {
+			addr, _ := msg.Values["addr"].(string)
+
+			deliveries := int64(1)
+			if ext, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: walletStreamKey, Group: walletConsumerGroup, Start: msg.ID, End: msg.ID, Count: 1,
+			}).Result(); err == nil && len(ext) > 0 {
+				deliveries = ext[0].RetryCount
+			}
+
+			if deliveries > s.maxDeliveries {
+				if err := s.deadLetter(ctx, msg.ID, addr, deliveries); err != nil {
+					s.logger.Error().Err(err).Str("wallet", addr).Msg("Failed to dead-letter wallet stream entry")
+				}
+				continue
+			}
+
+			s.recordPending(addr, msg.ID)
+			s.pushReclaimed(addr)
+			s.logger.Info().Str("wallet", addr).Int64("deliveries", deliveries).Msg("Reclaimed stuck wallet stream entry")
+		}
+
+		if nextStart == "0-0" || len(claimed) == 0 {
+			return nil
+		}
+		start = nextStart
+	}
+}
+
+// deadLetter moves a wallet's stuck entry into walletDeadLetterStreamKey
+// and acks/deletes it from walletStreamKey, mirroring Client's
+// deadLetterWallet for the ZSET backend.
+func (s *StreamClient) deadLetter(ctx context.Context, id, addr string, deliveries int64) error {
+	if err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: walletDeadLetterStreamKey,
+		Values: map[string]interface{}{
+			"addr":             addr,
+			"deliveries":       deliveries,
+			"dead_lettered_at": time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to write dead letter entry: %w", err)
+	}
+
+	if err := s.client.XAck(ctx, walletStreamKey, walletConsumerGroup, id).Err(); err != nil {
+		return fmt.Errorf("failed to ack dead-lettered entry: %w", err)
+	}
+	if err := s.client.XDel(ctx, walletStreamKey, id).Err(); err != nil {
+		return fmt.Errorf("failed to delete dead-lettered entry: %w", err)
+	}
+	s.forgetPending(addr)
+
+	metrics.RecordWalletDeadLettered()
+	s.logger.Warn().Str("wallet", addr).Int64("deliveries", deliveries).Msg("Wallet exceeded max deliveries, moved to dead-letter stream")
+	return nil
+}
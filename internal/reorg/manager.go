@@ -0,0 +1,152 @@
+// Package reorg detects and recovers from Solana forks: when the chain
+// reorganizes and a slot mercon already processed transactions from is
+// replaced, every derived Meteora row that came from that slot is now
+// invalid and must be deleted so the canonical chain can be re-parsed.
+//
+// Manager is modeled after a wallet reorg tracker: MarkFinalized advances
+// the safe root as the chain confirms blocks, and Rewind records and
+// reports a detected fork, with a mutex guarding both against a concurrent
+// poller. Detection itself (Poller) compares the slots mercon has observed
+// against the canonical chain's getBlocks output rather than diffing block
+// hashes: the Helius transaction feed internal/solana.Client ingests from
+// doesn't surface a transaction's block hash, only its slot (see
+// models.Transaction.BlockHash), and mercon's only Solana RPC dependency,
+// gagliardetto/solana-go, doesn't vendor a slotsUpdates/rootSubscribe
+// websocket client. Slot membership in getBlocks is the fork signal that's
+// actually available, and is what Poller polls for.
+package reorg
+
+import "sync"
+
+// ReorgEvent describes a detected fork: every processed transaction whose
+// slot falls in [FromSlot, ToSlot] has been orphaned and must be rolled
+// back (see DeleteOrphanedRows) and re-parsed.
+type ReorgEvent struct {
+	FromSlot int64
+	ToSlot   int64
+}
+
+// DefaultUnconfirmedDepth is how many slots behind the tip Manager tracks
+// for fork detection before MarkFinalized retires them as settled.
+// Solana's optimistic confirmation typically lands well within this.
+const DefaultUnconfirmedDepth = 32
+
+// Manager tracks which slots mercon has processed transactions from and
+// detects when one of them falls out of the canonical chain.
+type Manager struct {
+	mu sync.Mutex
+
+	unconfirmedDepth int64
+	finalizedRoot    int64
+	tip              int64
+	observed         map[int64]string // slot -> block hash, when known
+}
+
+// ManagerOption configures optional Manager behavior.
+type ManagerOption func(*Manager)
+
+// WithUnconfirmedDepth overrides DefaultUnconfirmedDepth.
+func WithUnconfirmedDepth(depth int64) ManagerOption {
+	return func(m *Manager) { m.unconfirmedDepth = depth }
+}
+
+// NewManager returns a Manager with no slots observed yet.
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{
+		unconfirmedDepth: DefaultUnconfirmedDepth,
+		observed:         make(map[int64]string),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Observe records that mercon has processed a transaction from slot,
+// optionally with its block hash (empty when the data source doesn't
+// supply one - see the package doc comment). It also advances the tracked
+// tip. Slots at or below the finalized root are ignored: Solana's
+// finalized commitment is irreversible, so there's nothing left to track.
+func (m *Manager) Observe(slot int64, blockHash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slot <= m.finalizedRoot {
+		return
+	}
+	m.observed[slot] = blockHash
+	if slot > m.tip {
+		m.tip = slot
+	}
+	m.trimUnconfirmedLocked()
+}
+
+// trimUnconfirmedLocked retires any observed slot more than unconfirmedDepth
+// behind the current tip, the same way MarkFinalized retires slots at or
+// below an explicitly confirmed root. This bounds Manager's tracked window
+// even if the finalized-commitment poll that drives MarkFinalized falls
+// behind or stalls: a slot this far behind the tip has almost certainly
+// reached Solana's optimistic confirmation (see DefaultUnconfirmedDepth),
+// so there's nothing left worth tracking it for. Callers must hold mu.
+func (m *Manager) trimUnconfirmedLocked() {
+	cutoff := m.tip - m.unconfirmedDepth
+	if cutoff <= m.finalizedRoot {
+		return
+	}
+	for s := range m.observed {
+		if s <= cutoff {
+			delete(m.observed, s)
+		}
+	}
+	m.finalizedRoot = cutoff
+}
+
+// MarkFinalized advances the finalized root to slot and stops tracking any
+// observed slot at or below it.
+func (m *Manager) MarkFinalized(slot int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slot <= m.finalizedRoot {
+		return
+	}
+	m.finalizedRoot = slot
+	for s := range m.observed {
+		if s <= slot {
+			delete(m.observed, s)
+		}
+	}
+}
+
+// UnconfirmedSlots returns the slots Manager is still tracking as
+// unconfirmed - the candidates a poller should re-check against the
+// canonical chain on its next pass.
+func (m *Manager) UnconfirmedSlots() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slots := make([]int64, 0, len(m.observed))
+	for s := range m.observed {
+		slots = append(slots, s)
+	}
+	return slots
+}
+
+// Rewind records a detected fork at slot - an observed slot that's no
+// longer part of the canonical chain - and returns the ReorgEvent callers
+// should act on. It drops every tracked slot from slot through the current
+// tip, since they're all being rolled back and will be re-observed from
+// scratch as the canonical chain is re-parsed.
+func (m *Manager) Rewind(slot int64) ReorgEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event := ReorgEvent{FromSlot: slot, ToSlot: m.tip}
+	for s := range m.observed {
+		if s >= slot {
+			delete(m.observed, s)
+		}
+	}
+	m.tip = slot - 1
+	if m.tip < m.finalizedRoot {
+		m.tip = m.finalizedRoot
+	}
+	return event
+}
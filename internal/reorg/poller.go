@@ -0,0 +1,83 @@
+package reorg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// BlockLister is the subset of *rpc.Client Poller needs: the finalized tip
+// and the canonical slots the chain actually contains. Scoped narrowly so
+// tests can fake it without standing up a real RPC client.
+type BlockLister interface {
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+	GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error)
+}
+
+// Poller periodically checks a Manager's unconfirmed slots against the
+// canonical chain. See the package doc comment for why this polls
+// getBlocks rather than subscribing to slotsUpdates/rootSubscribe.
+type Poller struct {
+	client  BlockLister
+	manager *Manager
+}
+
+// NewPoller returns a Poller that checks manager's tracked slots against
+// client's canonical chain.
+func NewPoller(client BlockLister, manager *Manager) *Poller {
+	return &Poller{client: client, manager: manager}
+}
+
+// Poll advances manager's finalized root to the chain's current finalized
+// slot, then checks every slot manager still considers unconfirmed against
+// the canonical getBlocks list. The lowest tracked slot missing from that
+// list has been forked out; Poll rewinds from there and returns the
+// resulting ReorgEvent, or nil if nothing forked.
+func (p *Poller) Poll(ctx context.Context) (*ReorgEvent, error) {
+	finalized, err := p.client.GetSlot(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("getting finalized slot: %w", err)
+	}
+	p.manager.MarkFinalized(int64(finalized))
+
+	unconfirmed := p.manager.UnconfirmedSlots()
+	if len(unconfirmed) == 0 {
+		return nil, nil
+	}
+
+	minSlot, maxSlot := unconfirmed[0], unconfirmed[0]
+	for _, s := range unconfirmed {
+		if s < minSlot {
+			minSlot = s
+		}
+		if s > maxSlot {
+			maxSlot = s
+		}
+	}
+
+	end := uint64(maxSlot)
+	canonical, err := p.client.GetBlocks(ctx, uint64(minSlot), &end, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("getting canonical blocks [%d,%d]: %w", minSlot, maxSlot, err)
+	}
+
+	canonicalSlots := make(map[int64]bool, len(canonical))
+	for _, s := range canonical {
+		canonicalSlots[int64(s)] = true
+	}
+
+	forkedAt, found := int64(0), false
+	for _, s := range unconfirmed {
+		if !canonicalSlots[s] && (!found || s < forkedAt) {
+			forkedAt = s
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	event := p.manager.Rewind(forkedAt)
+	return &event, nil
+}
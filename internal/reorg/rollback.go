@@ -0,0 +1,52 @@
+package reorg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeleteOrphanedRows deletes every MeteoraSwap, MeteoraLiquidityAddition,
+// MeteoraLiquidityRemoval, MeteoraFeeClaim, MeteoraRewardClaim,
+// MeteoraRewardFunding, and MeteoraBinDelta row whose parent transaction's
+// slot falls in
+// [event.FromSlot, event.ToSlot], then deletes those transactions
+// themselves so the canonical chain re-parses them from scratch. Runs in a
+// single database transaction so a caller never observes a partially
+// rolled-back slot range.
+func DeleteOrphanedRows(ctx context.Context, db *gorm.DB, event ReorgEvent) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var txIDs []uint
+		if err := tx.Model(&models.Transaction{}).
+			Where("slot >= ? AND slot <= ?", event.FromSlot, event.ToSlot).
+			Pluck("id", &txIDs).Error; err != nil {
+			return fmt.Errorf("finding orphaned transactions in [%d,%d]: %w", event.FromSlot, event.ToSlot, err)
+		}
+		if len(txIDs) == 0 {
+			return nil
+		}
+
+		derived := []interface{}{
+			&models.MeteoraSwap{},
+			&models.MeteoraLiquidityAddition{},
+			&models.MeteoraLiquidityRemoval{},
+			&models.MeteoraFeeClaim{},
+			&models.MeteoraRewardClaim{},
+			&models.MeteoraRewardFunding{},
+			&models.MeteoraBinDelta{},
+		}
+		for _, model := range derived {
+			if err := tx.Where("transaction_id IN ?", txIDs).Delete(model).Error; err != nil {
+				return fmt.Errorf("deleting orphaned %T rows: %w", model, err)
+			}
+		}
+
+		if err := tx.Where("id IN ?", txIDs).Delete(&models.Transaction{}).Error; err != nil {
+			return fmt.Errorf("deleting orphaned transactions: %w", err)
+		}
+
+		return nil
+	})
+}
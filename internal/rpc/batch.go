@@ -0,0 +1,227 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxBatchSize caps how many requests DoBatch sends in a single POST -
+// providers commonly reject or silently truncate oversized JSON-RPC
+// batches, so a larger body is split into sequential sub-batches instead.
+const maxBatchSize = 100
+
+// rpcBatchEntry is the minimal shape DoBatch needs to read back out of a
+// caller-supplied batch body (e.g. one built by solana.NewBatchRpcBody):
+// just enough to re-issue one entry as a standalone call if its sub-batch
+// never answers it.
+type rpcBatchEntry struct {
+	ID     string        `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// DoBatch posts a JSON-RPC 2.0 batch request body and returns the raw batch
+// response body, correlated back to the caller's original request order by
+// id - batch responses aren't guaranteed to come back in request order, and
+// a caller like solana.ParseBatchResponse relies on that ordering. If body
+// encodes more than maxBatchSize requests, it's split into sequential
+// sub-batches sent as separate POSTs. Any individual request whose sub-batch
+// didn't return a clean result for it - missing entirely, or answered with
+// an RPC error - is retried once, alone, against the pool's normal
+// scheduler, so one bad request in a batch doesn't cost every other request
+// that happened to share its POST.
+func (p *Pool) DoBatch(ctx context.Context, body []byte) ([]byte, error) {
+	var entries []rpcBatchEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch request body: %w", err)
+	}
+	if len(entries) == 0 {
+		return json.Marshal([]RpcResponse{})
+	}
+
+	responses := make(map[string]RpcResponse, len(entries))
+	for start := 0; start < len(entries); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		if err := p.doBatchChunk(ctx, entries[start:end], responses); err != nil {
+			return nil, err
+		}
+	}
+
+	ordered := make([]RpcResponse, len(entries))
+	for i, entry := range entries {
+		if resp, ok := responses[entry.ID]; ok {
+			ordered[i] = resp
+			continue
+		}
+		ordered[i] = RpcResponse{
+			Jsonrpc: "2.0",
+			ID:      entry.ID,
+			Error:   &RpcError{Message: "no response received for this request"},
+		}
+	}
+	return json.Marshal(ordered)
+}
+
+// doBatchChunk sends one sub-batch POST, folds its responses into responses
+// by id, and then retries - alone, via the pool's normal scheduler - any
+// entry that still doesn't have a clean result. It only returns an error
+// when the pool couldn't hand out a client at all (every endpoint down);
+// an individual request's entry is replaced by whatever doBatchEntryRetry
+// produced, or left as the batch's own response if that retry failed too.
+func (p *Pool) doBatchChunk(ctx context.Context, entries []rpcBatchEntry, responses map[string]RpcResponse) error {
+	client, endpoint, err := p.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get RPC client for batch: %w", err)
+	}
+
+	if batch, ok := p.postBatch(ctx, client, endpoint, entries); ok {
+		for _, resp := range batch {
+			responses[resp.ID] = resp
+		}
+	}
+
+	for _, entry := range entries {
+		if resp, ok := responses[entry.ID]; ok && resp.Error == nil {
+			continue
+		}
+		// A non-empty ID means the retry got a real RPC response (even an
+		// RPC-level error is worth keeping over the generic "no response
+		// received" placeholder); a zero value means the retry's transport
+		// itself failed, so the entry is left as-is.
+		if retried, _ := p.doBatchEntryRetry(ctx, entry); retried.ID != "" {
+			responses[entry.ID] = retried
+		}
+	}
+	return nil
+}
+
+// postBatch does the actual HTTP round trip for one sub-batch against
+// endpoint, folding the outcome into the pool's health/cooldown bookkeeping
+// the same way fetchTransactionBatchOnce does. ok is false whenever the
+// sub-batch couldn't be completed at all (as opposed to individual entries
+// within it coming back with an RPC error, which is a normal part of
+// batch) - doBatchChunk's per-entry retry pass covers both cases.
+func (p *Pool) postBatch(ctx context.Context, client *http.Client, endpoint string, entries []rpcBatchEntry) ([]RpcResponse, bool) {
+	requestBody, err := json.Marshal(entries)
+	if err != nil {
+		return nil, false
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, false
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		p.RecordFailure(endpoint)
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		p.SetCooldown(endpoint, parseRetryAfter(resp.Header.Get("Retry-After")))
+		p.RecordRateLimit(endpoint)
+		return nil, false
+	}
+	if resp.StatusCode != http.StatusOK {
+		p.RecordFailure(endpoint)
+		return nil, false
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		p.RecordFailure(endpoint)
+		return nil, false
+	}
+
+	var batch []RpcResponse
+	if err := json.Unmarshal(respBody, &batch); err != nil {
+		p.RecordFailure(endpoint)
+		return nil, false
+	}
+
+	p.RecordLatency(endpoint, time.Since(start))
+	p.RecordSuccess(endpoint)
+	return batch, true
+}
+
+// doBatchEntryRetry re-issues one batch entry as a standalone JSON-RPC call
+// via the pool's normal scheduler. That may or may not land on a different
+// endpoint than the one its batch POST used - Pool has no "exclude this
+// endpoint" selection mode today - but it's still worth doing: the
+// scheduler usually does pick a different endpoint, and even retrying
+// against the same one recovers a simple transient failure.
+func (p *Pool) doBatchEntryRetry(ctx context.Context, entry rpcBatchEntry) (RpcResponse, error) {
+	client, endpoint, err := p.GetClient(ctx)
+	if err != nil {
+		return RpcResponse{}, err
+	}
+
+	request := RpcRequest{Jsonrpc: "2.0", ID: entry.ID, Method: entry.Method, Params: entry.Params}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return RpcResponse{}, fmt.Errorf("failed to marshal retry request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return RpcResponse{}, fmt.Errorf("failed to create retry request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		p.RecordFailure(endpoint)
+		return RpcResponse{}, fmt.Errorf("retry request to %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		p.SetCooldown(endpoint, retryAfter)
+		p.RecordRateLimit(endpoint)
+		return RpcResponse{}, &RateLimitedError{Endpoint: endpoint, RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		p.RecordFailure(endpoint)
+		return RpcResponse{}, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		p.RecordFailure(endpoint)
+		return RpcResponse{}, fmt.Errorf("failed to read retry response body: %w", err)
+	}
+
+	var rpcResponse RpcResponse
+	if err := json.Unmarshal(respBody, &rpcResponse); err != nil {
+		p.RecordFailure(endpoint)
+		return RpcResponse{}, fmt.Errorf("failed to unmarshal retry response: %w", err)
+	}
+	rpcResponse.ID = entry.ID
+
+	if rpcResponse.Error != nil {
+		if rpcResponse.Error.Code == solanaNodeBehindErrorCode {
+			p.RecordRateLimit(endpoint)
+		} else {
+			p.RecordFailure(endpoint)
+		}
+		return rpcResponse, fmt.Errorf("RPC error from %s: code %d, message: %s", endpoint, rpcResponse.Error.Code, rpcResponse.Error.Message)
+	}
+
+	p.RecordLatency(endpoint, time.Since(start))
+	p.RecordSuccess(endpoint)
+	return rpcResponse, nil
+}
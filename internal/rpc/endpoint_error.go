@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EndpointError wraps an error returned by a specific RPC endpoint with the
+// context needed to tell endpoints apart in logs and metrics: which
+// endpoint answered, which RPC method was called, and how long the call
+// took before failing. Modeled on the "RPCClient returned error (name)"
+// convention some chain-indexer projects use to make a wall of retry logs
+// searchable by endpoint.
+type EndpointError struct {
+	Endpoint string
+	Method   string
+	Latency  time.Duration
+	Err      error
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("RPCClient returned error (%s): %s: %v", e.Endpoint, e.Method, e.Err)
+}
+
+func (e *EndpointError) Unwrap() error { return e.Err }
+
+// wrapEndpointError wraps err (if non-nil) as an *EndpointError attributing
+// it to endpoint/method, with latency measured from start.
+func wrapEndpointError(endpoint, method string, start time.Time, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &EndpointError{Endpoint: endpoint, Method: method, Latency: time.Since(start), Err: err}
+}
+
+// ErrorClass buckets err into the error_class label mercon_rpc_errors_total
+// distinguishes. It understands *EndpointError (unwrapping to classify the
+// underlying cause) as well as raw errors, so callers that haven't gone
+// through wrapEndpointError still get a sensible class.
+func ErrorClass(err error) string {
+	if err == nil {
+		return "none"
+	}
+
+	var rateLimited *RateLimitedError
+	var endpointErr *EndpointError
+	switch {
+	case errors.As(err, &rateLimited):
+		return "rate_limited"
+	case errors.As(err, &endpointErr):
+		return ErrorClass(endpointErr.Err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "timeout"
+	case strings.Contains(err.Error(), "unexpected status code"):
+		return "http_status"
+	case strings.Contains(err.Error(), "not found"):
+		return "not_found"
+	case strings.Contains(err.Error(), "unmarshal"), strings.Contains(err.Error(), "marshal"):
+		return "parse"
+	case strings.Contains(err.Error(), "RPC error from"):
+		return "rpc_error"
+	default:
+		return "other"
+	}
+}
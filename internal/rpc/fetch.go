@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -43,76 +47,575 @@ type RpcError struct {
 	Message string `json:"message"`
 }
 
+// defaultRateLimitRetry is how long a caller should back off when an
+// endpoint returns 429/503 without a usable Retry-After header.
+const defaultRateLimitRetry = 30 * time.Second
+
+// solanaNodeBehindErrorCode is the JSON-RPC error code a Solana validator
+// returns when it hasn't caught up to the rest of the cluster yet. Like a
+// 429/503, it's a signal to back this endpoint off rather than a genuine
+// failure of the request itself, so it feeds the same Pool.RecordRateLimit
+// path instead of counting toward the endpoint's consecutive-error streak.
+const solanaNodeBehindErrorCode = -32005
+
+// RateLimitedError is returned when an endpoint responds with 429 or 503.
+// RetryAfter carries the endpoint's requested cooldown - parsed from the
+// Retry-After header when present, or defaultRateLimitRetry otherwise - so
+// the worker's backoff can honor it verbatim instead of guessing its own.
+type RateLimitedError struct {
+	Endpoint   string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited by endpoint %s, retry after %s", e.Endpoint, e.RetryAfter)
+}
+
+// parseRetryAfter parses a Retry-After header value, which per RFC 9110 is
+// either a number of seconds or an HTTP date. Falls back to
+// defaultRateLimitRetry if header is empty or doesn't parse as either.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return defaultRateLimitRetry
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return defaultRateLimitRetry
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay
+		}
+	}
+
+	return defaultRateLimitRetry
+}
+
 // Fetcher handles RPC transaction fetching with retries and backoff
 type Fetcher struct {
-	pool   *Pool
-	logger zerolog.Logger
+	pool             *Pool
+	logger           zerolog.Logger
+	quorum           *QuorumSpec
+	transactionBatch int
+
+	errorRateMutex sync.Mutex
+	errorRateEWMA  float64
+
+	sfMutex sync.Mutex
+	sfCalls map[string]*sfCall
+}
+
+// sfCall is an in-flight or just-completed singleflight-coalesced fetch.
+// done is closed once val/err are set, so every waiter can select on it
+// instead of needing a sync.Cond or repeated polling.
+type sfCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// singleflightLeaderTimeout bounds how long a singleflight-coalesced fn is
+// allowed to run once detached from its leader's ctx. It's sized well above
+// doFetchTransaction's worst-case retry budget (5 retries, delays doubling
+// from 250ms) so a normal fetch never trips it, while still guaranteeing a
+// leader whose own ctx was canceled doesn't leave followers waiting forever
+// on a truly hung RPC call.
+const singleflightLeaderTimeout = 60 * time.Second
+
+// singleflight runs fn, coalescing concurrent callers that share the same
+// key onto a single execution: whichever caller arrives first runs fn and
+// every other caller waits on its result instead of issuing a duplicate RPC
+// call. Modeled on golang.org/x/sync/singleflight's shape, kept as a small
+// local type since Fetcher is the only caller and doesn't need the rest of
+// that package's surface (forgotten/shared groups, panic handling, etc).
+//
+// fn is handed a context derived from context.Background (bounded by
+// singleflightLeaderTimeout), not from the leader's own ctx: the leader is
+// just whichever caller happened to arrive first, and its cancellation must
+// not poison the result every coalesced follower is waiting on. Each
+// caller's ctx - leader included - only governs how long that caller is
+// willing to wait for fn's result; if ctx is canceled, this call returns
+// ctx.Err() without affecting fn's execution or any other waiter sharing
+// the key.
+func (f *Fetcher) singleflight(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	f.sfMutex.Lock()
+	c, ok := f.sfCalls[key]
+	if ok {
+		f.sfMutex.Unlock()
+		metrics.RecordRPCCoalescedHit()
+		select {
+		case <-c.done:
+			return c.val, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c = &sfCall{done: make(chan struct{})}
+	f.sfCalls[key] = c
+	f.sfMutex.Unlock()
+
+	go func() {
+		leaderCtx, cancel := context.WithTimeout(context.Background(), singleflightLeaderTimeout)
+		defer cancel()
+
+		c.val, c.err = fn(leaderCtx)
+		close(c.done)
+
+		f.sfMutex.Lock()
+		delete(f.sfCalls, key)
+		f.sfMutex.Unlock()
+	}()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// errorRateEWMAAlpha weights how much a single RPC round trip's outcome
+// moves the Fetcher's rolling error rate, the same smoothing Pool's
+// latencyEWMA uses for endpoint latency.
+const errorRateEWMAAlpha = 0.1
+
+// FetcherOption customizes a Fetcher at construction time.
+type FetcherOption func(*Fetcher)
+
+// WithVerifyQuorum enables quorum verification for every FetchTransaction
+// call: the transaction is fetched from spec.N distinct healthy endpoints
+// and only returned once at least spec.K of them agree on signature, Meta
+// error, slot, and a hash of the compiled message. Disagreement is logged
+// as a DivergenceReport instead of silently trusting whichever endpoint
+// answered first, so wallets scanned through untrusted public RPCs get a
+// tamper-evident pipeline without running a full validator. Without this
+// option, FetchTransaction uses the pool's normal single-endpoint retry
+// path.
+func WithVerifyQuorum(spec QuorumSpec) FetcherOption {
+	return func(f *Fetcher) { f.quorum = &spec }
+}
+
+// WithTransactionBatchSize overrides how many signatures
+// FetchTransactionsBatch packs into a single JSON-RPC batch POST. The
+// default is defaultTransactionBatchSize.
+func WithTransactionBatchSize(n int) FetcherOption {
+	return func(f *Fetcher) { f.transactionBatch = n }
+}
+
+// recordOutcome updates the Fetcher's rolling RPC error rate EWMA: 1.0 for
+// a failed round trip, 0.0 for a successful one.
+func (f *Fetcher) recordOutcome(success bool) {
+	outcome := 0.0
+	if !success {
+		outcome = 1.0
+	}
+
+	f.errorRateMutex.Lock()
+	f.errorRateEWMA = errorRateEWMAAlpha*outcome + (1-errorRateEWMAAlpha)*f.errorRateEWMA
+	f.errorRateMutex.Unlock()
+}
+
+// ErrorRateEWMA returns the fetcher's rolling RPC error rate (0..1), for
+// Manager's autoscaler to react to upstream trouble before it degrades far
+// enough to drop endpoints from GetHealthyEndpointCount entirely.
+func (f *Fetcher) ErrorRateEWMA() float64 {
+	f.errorRateMutex.Lock()
+	defer f.errorRateMutex.Unlock()
+	return f.errorRateEWMA
 }
 
+// defaultTransactionBatchSize is how many signatures
+// FetchTransactionsBatch packs into one JSON-RPC batch POST when the
+// Fetcher isn't built with WithTransactionBatchSize.
+const defaultTransactionBatchSize = 50
+
 // NewFetcher creates a new transaction fetcher
-func NewFetcher(pool *Pool, logger zerolog.Logger) *Fetcher {
-	return &Fetcher{
-		pool:   pool,
-		logger: logger.With().Str("component", "rpc_fetcher").Logger(),
+func NewFetcher(pool *Pool, logger zerolog.Logger, opts ...FetcherOption) *Fetcher {
+	f := &Fetcher{
+		pool:             pool,
+		logger:           logger.With().Str("component", "rpc_fetcher").Logger(),
+		transactionBatch: defaultTransactionBatchSize,
+		sfCalls:          make(map[string]*sfCall),
+	}
+	for _, opt := range opts {
+		opt(f)
 	}
+	return f
 }
 
-// FetchTransaction fetches a transaction by signature with retry logic
+const methodGetTransaction = "getTransaction"
+const methodGetTransactionBatch = "getTransactionBatch"
+const methodGetSignaturesForAddress = "getSignaturesForAddress"
+
+// FetchTransaction fetches a transaction by signature with retry logic. If
+// the Fetcher was built with WithVerifyQuorum, it instead cross-checks the
+// transaction against a quorum of endpoints - see fetchTransactionQuorum.
+// Concurrent calls for the same signature are coalesced via singleflight,
+// keyed "tx:<signature>".
 func (f *Fetcher) FetchTransaction(ctx context.Context, signature string) (*RpcTransaction, error) {
+	val, err := f.singleflight(ctx, "tx:"+signature, func(leaderCtx context.Context) (interface{}, error) {
+		return f.doFetchTransaction(leaderCtx, signature)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx, _ := val.(*RpcTransaction)
+	return tx, nil
+}
+
+// doFetchTransaction is FetchTransaction's actual fetch logic, run at most
+// once per in-flight signature by singleflight.
+func (f *Fetcher) doFetchTransaction(ctx context.Context, signature string) (*RpcTransaction, error) {
+	if f.quorum != nil {
+		return f.fetchTransactionQuorum(ctx, signature)
+	}
+
 	const maxRetries = 5
 	baseDelay := 250 * time.Millisecond
-	
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		tx, err := f.fetchTransactionOnce(ctx, signature)
+		tx, endpoint, err := f.fetchTransactionOnce(ctx, signature)
 		if err == nil {
-			metrics.RecordRPCRequest("success")
 			return tx, nil
 		}
-		
+
 		// Log the error
 		f.logger.Warn().
 			Err(err).
 			Str("signature", signature).
+			Str("endpoint", endpoint).
 			Int("attempt", attempt+1).
 			Int("max_retries", maxRetries).
 			Msg("Failed to fetch transaction")
-		
+
 		// Check if we should retry
 		if attempt == maxRetries {
-			metrics.RecordRPCRequest("failed")
 			return nil, fmt.Errorf("failed to fetch transaction after %d attempts: %w", maxRetries+1, err)
 		}
-		
+
+		metrics.RecordRPCRetry(endpoint, methodGetTransaction, retryReason(err))
+
 		// Exponential backoff with jitter
 		delay := baseDelay * time.Duration(1<<attempt)
 		if delay > 30*time.Second {
 			delay = 30 * time.Second
 		}
-		
+
 		f.logger.Debug().
 			Str("signature", signature).
 			Dur("delay", delay).
 			Msg("Retrying transaction fetch after delay")
-		
+
 		select {
 		case <-time.After(delay):
 			// Continue to next attempt
 		case <-ctx.Done():
-			metrics.RecordRPCRequest("cancelled")
 			return nil, ctx.Err()
 		}
 	}
-	
+
 	return nil, fmt.Errorf("unreachable code")
 }
 
-// fetchTransactionOnce performs a single transaction fetch attempt
-func (f *Fetcher) fetchTransactionOnce(ctx context.Context, signature string) (*RpcTransaction, error) {
+// FetchTransactionsBatch fetches many transactions in one or more JSON-RPC
+// batch POSTs instead of one HTTP round trip per signature, for callers
+// like Worker.scrapeWallet that otherwise dominate RPC pool pressure
+// fetching thousands of transactions per wallet. signatures is split into
+// chunks of the Fetcher's transaction batch size (see
+// WithTransactionBatchSize); each chunk gets its own retry/backoff pass and
+// is attributed to whichever endpoint served it for health/cooldown
+// purposes, same as FetchTransaction.
+//
+// The returned map only contains signatures that were found and decoded
+// successfully - a signature missing from it either wasn't found on chain
+// or failed to decode, and is logged individually rather than failing the
+// whole batch. An error is only returned when a whole chunk's HTTP round
+// trip fails after exhausting retries.
+//
+// WithVerifyQuorum isn't supported here: quorum verification needs
+// responses from multiple distinct endpoints per transaction, which a
+// single-endpoint batch POST can't provide.
+func (f *Fetcher) FetchTransactionsBatch(ctx context.Context, signatures []string) (map[string]*RpcTransaction, error) {
+	if f.quorum != nil {
+		return nil, fmt.Errorf("FetchTransactionsBatch does not support quorum verification")
+	}
+
+	results := make(map[string]*RpcTransaction, len(signatures))
+
+	chunkSize := f.transactionBatch
+	if chunkSize <= 0 {
+		chunkSize = defaultTransactionBatchSize
+	}
+
+	for start := 0; start < len(signatures); start += chunkSize {
+		end := start + chunkSize
+		if end > len(signatures) {
+			end = len(signatures)
+		}
+
+		chunk, err := f.fetchTransactionBatchChunk(ctx, signatures[start:end])
+		if err != nil {
+			return results, err
+		}
+		for sig, tx := range chunk {
+			results[sig] = tx
+		}
+	}
+
+	return results, nil
+}
+
+// fetchTransactionBatchChunk sends one JSON-RPC batch POST for up to
+// f.transactionBatch signatures, retrying the whole chunk on transport
+// failure the same way doFetchTransaction retries a single
+// getTransaction call.
+func (f *Fetcher) fetchTransactionBatchChunk(ctx context.Context, sigs []string) (map[string]*RpcTransaction, error) {
+	const maxRetries = 5
+	baseDelay := 250 * time.Millisecond
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		result, endpoint, err := f.fetchTransactionBatchOnce(ctx, sigs)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		f.logger.Warn().
+			Err(err).
+			Int("signatures", len(sigs)).
+			Str("endpoint", endpoint).
+			Int("attempt", attempt+1).
+			Int("max_retries", maxRetries).
+			Msg("Failed to fetch transaction batch")
+
+		if attempt == maxRetries {
+			break
+		}
+
+		metrics.RecordRPCRetry(endpoint, methodGetTransactionBatch, retryReason(err))
+
+		delay := baseDelay * time.Duration(1<<attempt)
+		if delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("failed to fetch transaction batch of %d after %d attempts: %w", len(sigs), maxRetries+1, lastErr)
+}
+
+// fetchTransactionBatchOnce performs a single JSON-RPC batch POST for sigs
+// against whichever endpoint the pool's scheduler picks, decodes the
+// response array, and correlates each element back to its signature by the
+// index-derived request ID. A per-element RPC error or a missing result
+// is logged and the signature is simply left out of the returned map,
+// rather than failing the whole chunk.
+func (f *Fetcher) fetchTransactionBatchOnce(ctx context.Context, sigs []string) (map[string]*RpcTransaction, string, error) {
 	client, endpoint, err := f.pool.GetClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get RPC client: %w", err)
+		return nil, "", fmt.Errorf("failed to get RPC client: %w", err)
 	}
-	
+
+	start := time.Now()
+	done := metrics.Observe(endpoint, methodGetTransactionBatch)
+	var opErr error
+	defer func() {
+		f.recordOutcome(opErr == nil)
+		if opErr != nil {
+			metrics.RecordRPCError(endpoint, methodGetTransactionBatch, ErrorClass(opErr))
+		}
+		done(opErr)
+	}()
+
+	batch := make([]RpcRequest, len(sigs))
+	for i, sig := range sigs {
+		batch[i] = RpcRequest{
+			Jsonrpc: "2.0",
+			ID:      strconv.Itoa(i),
+			Method:  "getTransaction",
+			Params: []interface{}{
+				sig,
+				map[string]interface{}{
+					"encoding":                       "json",
+					"commitment":                     "confirmed",
+					"maxSupportedTransactionVersion": 0,
+				},
+			},
+		}
+	}
+
+	requestBody, err := json.Marshal(batch)
+	if err != nil {
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("failed to marshal RPC batch request: %w", err))
+		return nil, endpoint, opErr
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("failed to create HTTP request: %w", err))
+		return nil, endpoint, opErr
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	startTime := time.Now()
+	resp, err := client.Do(httpReq)
+	duration := time.Since(startTime)
+
+	if err != nil {
+		f.handleError(endpoint, err, duration)
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("HTTP request failed: %w", err))
+		return nil, endpoint, opErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		f.handleRateLimit(endpoint)
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, &RateLimitedError{Endpoint: endpoint, RetryAfter: retryAfter})
+		return nil, endpoint, opErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		f.pool.RecordFailure(endpoint)
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode))
+		return nil, endpoint, opErr
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("failed to read response body: %w", err))
+		return nil, endpoint, opErr
+	}
+
+	var rpcResponses []RpcResponse
+	if err := json.Unmarshal(body, &rpcResponses); err != nil {
+		opErr = wrapEndpointError(endpoint, methodGetTransactionBatch, start, fmt.Errorf("failed to unmarshal RPC batch response: %w", err))
+		return nil, endpoint, opErr
+	}
+
+	result := make(map[string]*RpcTransaction, len(sigs))
+	for _, rpcResponse := range rpcResponses {
+		idx, err := strconv.Atoi(rpcResponse.ID)
+		if err != nil || idx < 0 || idx >= len(sigs) {
+			f.logger.Warn().Str("id", rpcResponse.ID).Msg("Batch response ID didn't correlate to a requested signature, skipping")
+			continue
+		}
+		sig := sigs[idx]
+
+		if rpcResponse.Error != nil {
+			if rpcResponse.Error.Code == solanaNodeBehindErrorCode {
+				f.pool.RecordRateLimit(endpoint)
+			}
+			f.logger.Warn().
+				Str("signature", sig).
+				Int("code", rpcResponse.Error.Code).
+				Str("message", rpcResponse.Error.Message).
+				Msg("RPC error fetching transaction in batch, skipping")
+			continue
+		}
+		if rpcResponse.Result == nil {
+			f.logger.Debug().Str("signature", sig).Msg("Transaction not found in batch response")
+			continue
+		}
+
+		resultBytes, err := json.Marshal(rpcResponse.Result)
+		if err != nil {
+			f.logger.Warn().Err(err).Str("signature", sig).Msg("Failed to marshal transaction result from batch, skipping")
+			continue
+		}
+		var transaction RpcTransaction
+		if err := json.Unmarshal(resultBytes, &transaction); err != nil {
+			f.logger.Warn().Err(err).Str("signature", sig).Msg("Failed to unmarshal transaction from batch, skipping")
+			continue
+		}
+		result[sig] = &transaction
+	}
+
+	f.logger.Debug().
+		Int("requested", len(sigs)).
+		Int("found", len(result)).
+		Str("endpoint", endpoint).
+		Dur("duration", duration).
+		Msg("Successfully fetched transaction batch")
+
+	f.pool.RecordLatency(endpoint, duration)
+	f.pool.RecordSuccess(endpoint)
+
+	return result, endpoint, nil
+}
+
+// retryReason classifies an error from a failed attempt into the reason
+// labels mercon_rpc_retries_total distinguishes: "429" (rate limited), "5xx"
+// (unexpected HTTP status), "timeout", "parse" (malformed response), or
+// "other" for anything else.
+func retryReason(err error) string {
+	var rateLimited *RateLimitedError
+	switch {
+	case errors.As(err, &rateLimited):
+		return "429"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case strings.Contains(err.Error(), "unexpected status code"):
+		return "5xx"
+	case strings.Contains(err.Error(), "unmarshal"):
+		return "parse"
+	default:
+		return "other"
+	}
+}
+
+// fetchTransactionOnce performs a single transaction fetch attempt against
+// whichever endpoint the pool's scheduler picks. It returns the endpoint it
+// tried, even on failure, so the caller can label the resulting retry
+// metric.
+func (f *Fetcher) fetchTransactionOnce(ctx context.Context, signature string) (*RpcTransaction, string, error) {
+	client, endpoint, err := f.pool.GetClient(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get RPC client: %w", err)
+	}
+
+	tx, err := f.fetchTransactionWith(ctx, client, endpoint, signature)
+	return tx, endpoint, err
+}
+
+// fetchTransactionFromEndpoint fetches a transaction from one specific
+// endpoint, bypassing the pool's scheduler entirely - used by
+// fetchTransactionQuorum, which needs responses from specific distinct
+// endpoints rather than whichever one the scheduler would pick.
+func (f *Fetcher) fetchTransactionFromEndpoint(ctx context.Context, endpoint, signature string) (*RpcTransaction, error) {
+	client, err := f.pool.clientForEndpoint(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RPC client for %s: %w", endpoint, err)
+	}
+	return f.fetchTransactionWith(ctx, client, endpoint, signature)
+}
+
+// fetchTransactionWith performs the getTransaction RPC call against a
+// specific client/endpoint pair and records metrics, health, and latency
+// against endpoint regardless of how it was selected.
+func (f *Fetcher) fetchTransactionWith(ctx context.Context, client *http.Client, endpoint, signature string) (*RpcTransaction, error) {
+	start := time.Now()
+	done := metrics.Observe(endpoint, methodGetTransaction)
+	var opErr error
+	defer func() {
+		f.recordOutcome(opErr == nil)
+		if opErr != nil {
+			metrics.RecordRPCError(endpoint, methodGetTransaction, ErrorClass(opErr))
+		}
+		done(opErr)
+	}()
+
 	// Create RPC request
 	request := RpcRequest{
 		Jsonrpc: "2.0",
@@ -127,95 +630,140 @@ func (f *Fetcher) fetchTransactionOnce(ctx context.Context, signature string) (*
 			},
 		},
 	}
-	
+
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal RPC request: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to marshal RPC request: %w", err))
+		return nil, opErr
 	}
-	
+
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(requestBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to create HTTP request: %w", err))
+		return nil, opErr
 	}
-	
+
 	httpReq.Header.Set("Content-Type", "application/json")
-	
+
 	// Make the request
 	startTime := time.Now()
 	resp, err := client.Do(httpReq)
 	duration := time.Since(startTime)
-	
+
 	if err != nil {
 		f.handleError(endpoint, err, duration)
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("HTTP request failed: %w", err))
+		return nil, opErr
 	}
 	defer resp.Body.Close()
-	
+
 	// Handle HTTP status codes
 	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
 		f.handleRateLimit(endpoint)
-		return nil, fmt.Errorf("rate limited by endpoint %s: status %d", endpoint, resp.StatusCode)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, &RateLimitedError{Endpoint: endpoint, RetryAfter: retryAfter})
+		return nil, opErr
 	}
-	
+
 	if resp.StatusCode != http.StatusOK {
-		f.pool.MarkUnhealthy(endpoint)
-		return nil, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode)
+		f.pool.RecordFailure(endpoint)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode))
+		return nil, opErr
 	}
-	
+
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to read response body: %w", err))
+		return nil, opErr
 	}
-	
+
 	// Parse RPC response
 	var rpcResponse RpcResponse
 	if err := json.Unmarshal(body, &rpcResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal RPC response: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to unmarshal RPC response: %w", err))
+		return nil, opErr
 	}
-	
+
 	// Check for RPC errors
 	if rpcResponse.Error != nil {
-		return nil, fmt.Errorf("RPC error from %s: code %d, message: %s", 
-			endpoint, rpcResponse.Error.Code, rpcResponse.Error.Message)
+		if rpcResponse.Error.Code == solanaNodeBehindErrorCode {
+			f.pool.RecordRateLimit(endpoint)
+		} else {
+			f.pool.RecordFailure(endpoint)
+		}
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("RPC error from %s: code %d, message: %s",
+			endpoint, rpcResponse.Error.Code, rpcResponse.Error.Message))
+		return nil, opErr
 	}
-	
+
 	// Check if transaction was found
 	if rpcResponse.Result == nil {
-		return nil, fmt.Errorf("transaction not found: %s", signature)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("transaction not found: %s", signature))
+		return nil, opErr
 	}
-	
+
 	// Parse the transaction result
 	resultBytes, err := json.Marshal(rpcResponse.Result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal transaction result: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to marshal transaction result: %w", err))
+		return nil, opErr
 	}
-	
+
 	var transaction RpcTransaction
 	if err := json.Unmarshal(resultBytes, &transaction); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetTransaction, start, fmt.Errorf("failed to unmarshal transaction: %w", err))
+		return nil, opErr
 	}
-	
+
 	f.logger.Debug().
 		Str("signature", signature).
 		Str("endpoint", endpoint).
 		Dur("duration", duration).
 		Msg("Successfully fetched transaction")
-	
-	// Mark endpoint as healthy since request succeeded
-	f.pool.MarkHealthy(endpoint)
-	
+
+	// Record the successful round trip for health and latency tracking
+	f.pool.RecordLatency(endpoint, duration)
+	f.pool.RecordSuccess(endpoint)
+
 	return &transaction, nil
 }
 
-// FetchSignatures fetches transaction signatures for a wallet
+// FetchSignatures fetches transaction signatures for a wallet. Concurrent
+// calls sharing the same wallet/before/limit are coalesced via
+// singleflight, keyed "sigs:<wallet>:<before>:<limit>".
 func (f *Fetcher) FetchSignatures(ctx context.Context, wallet string, before string, limit int) ([]string, error) {
-	client, endpoint, err := f.pool.GetClient(ctx)
+	key := fmt.Sprintf("sigs:%s:%s:%d", wallet, before, limit)
+	val, err := f.singleflight(ctx, key, func(leaderCtx context.Context) (interface{}, error) {
+		return f.doFetchSignatures(leaderCtx, wallet, before, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sigs, _ := val.([]string)
+	return sigs, nil
+}
+
+// doFetchSignatures is FetchSignatures's actual fetch logic, run at most
+// once per in-flight (wallet, before, limit) key by singleflight.
+func (f *Fetcher) doFetchSignatures(ctx context.Context, wallet string, before string, limit int) ([]string, error) {
+	client, endpoint, err := f.pool.GetClientForWallet(ctx, wallet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get RPC client: %w", err)
 	}
-	
+
+	start := time.Now()
+	done := metrics.Observe(endpoint, methodGetSignaturesForAddress)
+	var opErr error
+	defer func() {
+		f.recordOutcome(opErr == nil)
+		if opErr != nil {
+			metrics.RecordRPCError(endpoint, methodGetSignaturesForAddress, ErrorClass(opErr))
+		}
+		done(opErr)
+	}()
+
 	params := []interface{}{
 		wallet,
 		map[string]interface{}{
@@ -223,65 +771,79 @@ func (f *Fetcher) FetchSignatures(ctx context.Context, wallet string, before str
 			"commitment": "confirmed",
 		},
 	}
-	
+
 	if before != "" {
 		params[1].(map[string]interface{})["before"] = before
 	}
-	
+
 	request := RpcRequest{
 		Jsonrpc: "2.0",
 		ID:      "1",
 		Method:  "getSignaturesForAddress",
 		Params:  params,
 	}
-	
+
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal RPC request: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("failed to marshal RPC request: %w", err))
+		return nil, opErr
 	}
-	
+
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(requestBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("failed to create HTTP request: %w", err))
+		return nil, opErr
 	}
-	
+
 	httpReq.Header.Set("Content-Type", "application/json")
-	
+
 	startTime := time.Now()
 	resp, err := client.Do(httpReq)
 	duration := time.Since(startTime)
-	
+
 	if err != nil {
 		f.handleError(endpoint, err, duration)
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("HTTP request failed: %w", err))
+		return nil, opErr
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
 		f.handleRateLimit(endpoint)
-		return nil, fmt.Errorf("rate limited by endpoint %s: status %d", endpoint, resp.StatusCode)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, &RateLimitedError{Endpoint: endpoint, RetryAfter: retryAfter})
+		return nil, opErr
 	}
-	
+
 	if resp.StatusCode != http.StatusOK {
-		f.pool.MarkUnhealthy(endpoint)
-		return nil, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode)
+		f.pool.RecordFailure(endpoint)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("unexpected status code from %s: %d", endpoint, resp.StatusCode))
+		return nil, opErr
 	}
-	
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("failed to read response body: %w", err))
+		return nil, opErr
 	}
-	
+
 	var rpcResponse RpcResponse
 	if err := json.Unmarshal(body, &rpcResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal RPC response: %w", err)
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("failed to unmarshal RPC response: %w", err))
+		return nil, opErr
 	}
-	
+
 	if rpcResponse.Error != nil {
-		return nil, fmt.Errorf("RPC error from %s: code %d, message: %s", 
-			endpoint, rpcResponse.Error.Code, rpcResponse.Error.Message)
+		if rpcResponse.Error.Code == solanaNodeBehindErrorCode {
+			f.pool.RecordRateLimit(endpoint)
+		} else {
+			f.pool.RecordFailure(endpoint)
+		}
+		opErr = wrapEndpointError(endpoint, methodGetSignaturesForAddress, start, fmt.Errorf("RPC error from %s: code %d, message: %s",
+			endpoint, rpcResponse.Error.Code, rpcResponse.Error.Message))
+		return nil, opErr
 	}
-	
+
 	// Parse signature results
 	signatures := make([]string, 0)
 	if resultSlice, ok := rpcResponse.Result.([]interface{}); ok {
@@ -293,17 +855,17 @@ func (f *Fetcher) FetchSignatures(ctx context.Context, wallet string, before str
 			}
 		}
 	}
-	
+
 	f.logger.Debug().
 		Str("wallet", wallet).
 		Str("endpoint", endpoint).
 		Int("signatures", len(signatures)).
 		Dur("duration", duration).
 		Msg("Successfully fetched signatures")
-	
-	metrics.RecordRPCRequest("success")
-	f.pool.MarkHealthy(endpoint)
-	
+
+	f.pool.RecordLatency(endpoint, duration)
+	f.pool.RecordSuccess(endpoint)
+
 	return signatures, nil
 }
 
@@ -314,10 +876,10 @@ func (f *Fetcher) handleError(endpoint string, err error, duration time.Duration
 		Str("endpoint", endpoint).
 		Dur("duration", duration).
 		Msg("RPC request failed")
-	
-	// Mark endpoint as unhealthy on network errors
-	f.pool.MarkUnhealthy(endpoint)
-	metrics.RecordRPCRequest("error")
+
+	// Count this toward the endpoint's consecutive-error streak; it's only
+	// marked unhealthy once that streak crosses the pool's threshold.
+	f.pool.RecordFailure(endpoint)
 }
 
 // handleRateLimit handles rate limiting by setting cooldown
@@ -325,8 +887,8 @@ func (f *Fetcher) handleRateLimit(endpoint string) {
 	f.logger.Warn().
 		Str("endpoint", endpoint).
 		Msg("Rate limited by endpoint")
-	
+
 	// Set 5-minute cooldown for rate limited endpoints
 	f.pool.SetCooldown(endpoint, 5*time.Minute)
-	metrics.RecordRPCRequest("rate_limited")
-} 
\ No newline at end of file
+	f.pool.RecordRateLimit(endpoint)
+}
@@ -0,0 +1,46 @@
+// Package geyser is scaffolding for an rpc.Transport backed by a
+// Yellowstone Geyser gRPC stream. Like services.RaydiumClient/OrcaClient,
+// mercon has no Geyser integration today: there is no vendored
+// yellowstone-grpc proto client in this repo and this sandbox has no
+// protoc toolchain to generate one, so every method here returns
+// ErrNotImplemented instead of guessing at a wire format that has never
+// been verified against a real Geyser endpoint. Replace Transport with a
+// real grpc.ClientConn-backed implementation once that dependency and its
+// generated pb package exist, following httpTransport/ws.transport's
+// Call/Subscribe/Close shape.
+package geyser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/wnt/mercon/internal/rpc"
+)
+
+// ErrNotImplemented is returned by every Transport method. See the package
+// doc comment for why.
+var ErrNotImplemented = errors.New("geyser: Yellowstone Geyser gRPC transport is not implemented in this repo")
+
+// Transport is an unimplemented rpc.Transport stub for Yellowstone Geyser.
+// rpc.RouteTransportKind never selects rpc.TransportGRPC automatically -
+// this is only reachable if a caller explicitly constructs one.
+type Transport struct{}
+
+// NewTransport returns a Transport. See the package doc comment: it is not
+// yet backed by a real Geyser gRPC client.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+func (t *Transport) Call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func (t *Transport) Subscribe(ctx context.Context, method string, params []interface{}) (<-chan json.RawMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func (t *Transport) Close() error { return nil }
+
+var _ rpc.Transport = (*Transport)(nil)
@@ -1,10 +1,14 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,28 +17,273 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// SchedulerMode selects how Pool.GetClient picks among healthy endpoints.
+type SchedulerMode string
+
+const (
+	// SchedulerRoundRobin cycles through endpoints in order, same as the
+	// pool's original behavior. This is the default.
+	SchedulerRoundRobin SchedulerMode = "round_robin"
+	// SchedulerFirstHealthy always prefers the earliest endpoint in the list
+	// that's currently healthy and not rate limited, falling back to the
+	// next one only when it isn't available. Useful when one endpoint is a
+	// known-good primary and the rest are failover-only.
+	SchedulerFirstHealthy SchedulerMode = "first_healthy"
+	// SchedulerLeastLatency prefers the healthy endpoint with the lowest
+	// recent average latency.
+	SchedulerLeastLatency SchedulerMode = "least_latency"
+	// SchedulerScored picks weighted-randomly among healthy endpoints in
+	// proportion to a score combining recent success rate and latency
+	// (see Endpoint.score), using power-of-two-choices so a single
+	// standout endpoint doesn't monopolize traffic. Endpoints whose
+	// success ratio drops below the pool's probation threshold are
+	// offered as candidates only 1-in-probationProbeRate calls.
+	SchedulerScored SchedulerMode = "scored"
+)
+
+// defaultMaxConsecutiveErrors is how many consecutive failures an endpoint
+// tolerates before Pool marks it unhealthy and schedules a re-probe.
+const defaultMaxConsecutiveErrors = 3
+
+// latencyEWMAAlpha weights how much a single observation moves an endpoint's
+// rolling average latency. Lower values smooth out noise from one-off slow
+// requests; higher values react faster to a real degradation.
+const latencyEWMAAlpha = 0.2
+
+// scoreRingSize is how many of an endpoint's most recent requests feed its
+// success ratio for SchedulerScored.
+const scoreRingSize = 256
+
+// scoreLatencyEpsilonSeconds keeps an endpoint with a near-zero latency
+// EWMA (or none yet) from producing an unbounded score.
+const scoreLatencyEpsilonSeconds = 0.001
+
+// defaultProbationThreshold is the success ratio below which an endpoint is
+// considered "in probation" by the scored scheduler.
+const defaultProbationThreshold = 0.5
+
+// defaultProbationProbeRate is how often (1-in-K calls) a probationary
+// endpoint is still offered as a selection candidate, so it gets a trickle
+// of real traffic to detect recovery without dragging down the pool's
+// overall success rate while it's still failing.
+const defaultProbationProbeRate = 8
+
+// defaultLatencyWeight, defaultErrorWeight, and defaultRateLimitWeight are
+// the exponents SchedulerScored raises each of an endpoint's three score
+// components to (see Endpoint.score). All three default to 1, reproducing
+// the scheduler's original success_ratio/latency_ewma formula except for
+// the added rate-limit penalty, which a weight of 1 applies at its
+// unscaled strength.
+const (
+	defaultLatencyWeight   = 1.0
+	defaultErrorWeight     = 1.0
+	defaultRateLimitWeight = 1.0
+)
+
+// rateLimitHalfLife is how long it takes a 429/503 response's contribution
+// to an endpoint's rate-limit score penalty to decay by half. Modeled as a
+// time-weighted EWMA rather than a ticker-driven counter so it decays
+// correctly with however often (or rarely) the endpoint actually gets rate
+// limited, without Pool needing a background goroutine or shutdown path of
+// its own.
+const rateLimitHalfLife = 2 * time.Minute
+
+// defaultEndpointRateLimit is each endpoint's starting rate limit, in
+// requests/second, and the ceiling maybeRampUpLocked climbs back toward
+// after an AIMD halving - the same ~2 req/s free-tier-safe limit this pool
+// has always used, just no longer fixed for the endpoint's whole lifetime.
+const defaultEndpointRateLimit = rate.Limit(2.0)
+
+// minAIMDRateLimit is the floor applyAIMDHalveLocked won't halve an
+// endpoint's rate limit below, so a provider that keeps rate limiting us
+// still gets an occasional request rather than starving entirely.
+const minAIMDRateLimit = rate.Limit(0.1)
+
+// aimdRampStep and aimdRampEvery control AIMD's additive-increase half:
+// every aimdRampEvery consecutive successes, an endpoint's rate limit climbs
+// by aimdRampStep, up to its defaultEndpointRateLimit ceiling. Additive
+// (rather than re-doubling) so a flaky endpoint that alternates between a
+// handful of successes and another rate limit doesn't get its limit
+// restored in one jump right before getting halved again.
+const (
+	aimdRampStep  = rate.Limit(0.25)
+	aimdRampEvery = 10
+)
+
+// reprobeBaseDelay and reprobeMaxDelay bound the exponential backoff reprobe
+// uses between re-probe attempts while an endpoint's circuit stays open: 1s,
+// 2s, 4s, ... capped at reprobeMaxDelay.
+const (
+	reprobeBaseDelay = 1 * time.Second
+	reprobeMaxDelay  = 5 * time.Minute
+)
+
+// CircuitState is an explicit name for where Pool's health tracking
+// currently sits for one endpoint, mirroring the classic circuit breaker
+// states. Unlike a textbook breaker, CircuitHalfOpen here only admits
+// reprobe's own getBlockHeight call, not live application traffic - routing
+// real requests to an endpoint we aren't sure has recovered risks feeding
+// callers bad data, not just failing a health check.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
 // Pool manages a pool of RPC endpoints with load balancing and rate limiting
 type Pool struct {
-	endpoints []*Endpoint
-	current   int
-	mutex     sync.RWMutex
-	logger    zerolog.Logger
+	endpoints            []*Endpoint
+	current              int
+	mutex                sync.RWMutex
+	logger               zerolog.Logger
+	schedulerMode        SchedulerMode
+	maxConsecutiveErrors int
+	probationThreshold   float64
+	probationProbeRate   int
+	latencyWeight        float64
+	errorWeight          float64
+	rateLimitWeight      float64
+
+	stickyWallets  bool
+	stickyMutex    sync.Mutex
+	stickyByWallet map[string]string
 }
 
 // Endpoint represents a single RPC endpoint with its own rate limiter
 type Endpoint struct {
-	URL        string
-	client     *http.Client
-	limiter    *rate.Limiter
-	healthy    bool
-	cooldownUntil time.Time
-	mutex      sync.RWMutex
+	URL               string
+	client            *http.Client
+	limiter           *rate.Limiter
+	healthy           bool
+	cooldownUntil     time.Time
+	consecutiveErrors int
+	latencyEWMA       time.Duration
+
+	// successRing is a ring buffer of the endpoint's last scoreRingSize
+	// outcomes (true = success), used by SchedulerScored to compute a
+	// success ratio independent of consecutiveErrors (which resets to
+	// zero on one success and doesn't capture an endpoint that's merely
+	// flaky rather than fully down).
+	successRing    [scoreRingSize]bool
+	successRingLen int
+	successRingPos int
+	probationHits  int
+
+	// rateLimitEWMA is a time-decayed count of recent 429/503 responses
+	// (see rateLimitHalfLife), read by score as a selection penalty
+	// independent of consecutiveErrors/successRing - an endpoint that's
+	// merely rate limiting us, not erroring, shouldn't look as unhealthy
+	// as one that's actually failing, but should still be disfavored by
+	// SchedulerScored in proportion to how often it's happening.
+	rateLimitEWMA      float64
+	lastRateLimitEvent time.Time
+
+	// circuitState is an explicit circuit breaker state alongside
+	// healthy/cooldownUntil, so callers like GetStats can tell "open,
+	// waiting for its next reprobe" apart from "half-open, a reprobe is in
+	// flight right now".
+	circuitState CircuitState
+
+	// baseRateLimit is this endpoint's configured rate limit ceiling -
+	// limiter's rate before any AIMD halving (see RecordRateLimit), and the
+	// ceiling maybeRampUpLocked won't climb back above.
+	baseRateLimit rate.Limit
+	// rampCounter counts consecutive successes since the last AIMD halving,
+	// toward the aimdRampEvery needed before maybeRampUpLocked climbs the
+	// rate limit back up a step.
+	rampCounter int
+
+	// reprobing is true while a reprobe goroutine is running for this
+	// endpoint. RecordFailure can be called concurrently by multiple
+	// in-flight requests that all cross maxConsecutiveErrors before
+	// MarkUnhealthy takes effect; without this guard each of them would
+	// spawn its own infinite reprobe loop.
+	reprobing bool
+
+	mutex sync.RWMutex
+}
+
+// PoolOption customizes a Pool at construction time. Most deployments only
+// need the defaults (round-robin, no stickiness); options exist for the
+// operators who want health-aware scheduling or wallet affinity.
+type PoolOption func(*Pool)
+
+// WithSchedulerMode selects how the pool picks among healthy endpoints.
+func WithSchedulerMode(mode SchedulerMode) PoolOption {
+	return func(p *Pool) { p.schedulerMode = mode }
+}
+
+// WithMaxConsecutiveErrors overrides how many consecutive failures an
+// endpoint tolerates before being marked unhealthy and scheduled for
+// re-probing. The default is defaultMaxConsecutiveErrors.
+func WithMaxConsecutiveErrors(n int) PoolOption {
+	return func(p *Pool) { p.maxConsecutiveErrors = n }
+}
+
+// WithStickyWallets makes FetchSignatures prefer routing all of one wallet's
+// requests to the same endpoint, as long as that endpoint stays healthy.
+// This helps with providers that cache or rate-limit per-client-per-address.
+func WithStickyWallets() PoolOption {
+	return func(p *Pool) { p.stickyWallets = true }
+}
+
+// WithProbationThreshold overrides the success ratio below which
+// SchedulerScored puts an endpoint into probation. The default is
+// defaultProbationThreshold.
+func WithProbationThreshold(threshold float64) PoolOption {
+	return func(p *Pool) { p.probationThreshold = threshold }
+}
+
+// WithProbationProbeRate overrides how often (1-in-K calls) SchedulerScored
+// still offers a probationary endpoint as a candidate. The default is
+// defaultProbationProbeRate.
+func WithProbationProbeRate(k int) PoolOption {
+	return func(p *Pool) { p.probationProbeRate = k }
+}
+
+// WithScoreWeights overrides the exponents SchedulerScored applies to an
+// endpoint's success ratio, latency EWMA, and rate-limit EWMA when
+// combining them into one composite score (see Endpoint.score). Raising
+// rateLimitWeight above the default of 1, for example, makes the scheduler
+// move traffic away from a provider that's returning 429s faster than it
+// otherwise would, even before that provider's success ratio or latency
+// has visibly degraded.
+func WithScoreWeights(latencyWeight, errorWeight, rateLimitWeight float64) PoolOption {
+	return func(p *Pool) {
+		p.latencyWeight = latencyWeight
+		p.errorWeight = errorWeight
+		p.rateLimitWeight = rateLimitWeight
+	}
 }
 
 // NewPool creates a new RPC pool with the given endpoints
-func NewPool(urls []string, logger zerolog.Logger) *Pool {
+func NewPool(urls []string, logger zerolog.Logger, opts ...PoolOption) *Pool {
+	p := &Pool{
+		endpoints:            newEndpoints(urls),
+		current:              rand.Intn(len(urls)),
+		logger:               logger.With().Str("component", "rpc_pool").Logger(),
+		schedulerMode:        SchedulerRoundRobin,
+		maxConsecutiveErrors: defaultMaxConsecutiveErrors,
+		probationThreshold:   defaultProbationThreshold,
+		probationProbeRate:   defaultProbationProbeRate,
+		latencyWeight:        defaultLatencyWeight,
+		errorWeight:          defaultErrorWeight,
+		rateLimitWeight:      defaultRateLimitWeight,
+		stickyByWallet:       make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func newEndpoints(urls []string) []*Endpoint {
 	endpoints := make([]*Endpoint, len(urls))
-	
+
 	for i, url := range urls {
 		endpoints[i] = &Endpoint{
 			URL: url,
@@ -42,65 +291,150 @@ func NewPool(urls []string, logger zerolog.Logger) *Pool {
 				Timeout: 30 * time.Second,
 			},
 			// Rate limit to ~2 req/s per endpoint to stay under free tier limits
-			limiter: rate.NewLimiter(rate.Limit(2.0), 5),
-			healthy: true,
+			limiter:       rate.NewLimiter(defaultEndpointRateLimit, 5),
+			healthy:       true,
+			circuitState:  CircuitClosed,
+			baseRateLimit: defaultEndpointRateLimit,
 		}
-		
+
 		// Set initial health status in metrics
 		metrics.SetRPCEndpointHealth(url, true)
 	}
-	
-	return &Pool{
-		endpoints: endpoints,
-		current:   rand.Intn(len(endpoints)),
-		logger:    logger.With().Str("component", "rpc_pool").Logger(),
-	}
+
+	return endpoints
 }
 
-// GetClient returns the next available RPC client using round-robin
+// GetClient returns the next available RPC client according to the pool's
+// scheduler mode.
 func (p *Pool) GetClient(ctx context.Context) (*http.Client, string, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	
+
+	switch p.schedulerMode {
+	case SchedulerFirstHealthy:
+		return p.getClientFirstHealthy(ctx)
+	case SchedulerLeastLatency:
+		return p.getClientLeastLatency(ctx)
+	case SchedulerScored:
+		return p.getClientScored(ctx)
+	default:
+		return p.getClientRoundRobin(ctx)
+	}
+}
+
+// GetClientForWallet is like GetClient, but when the pool was built with
+// WithStickyWallets it prefers the endpoint previously used for this wallet,
+// so long as that endpoint is still healthy. FetchSignatures uses this;
+// FetchTransaction has no wallet context and always uses GetClient.
+func (p *Pool) GetClientForWallet(ctx context.Context, wallet string) (*http.Client, string, error) {
+	if !p.stickyWallets {
+		return p.GetClient(ctx)
+	}
+
+	p.stickyMutex.Lock()
+	stuckTo, ok := p.stickyByWallet[wallet]
+	p.stickyMutex.Unlock()
+
+	if ok {
+		if endpoint := p.endpointByURL(stuckTo); endpoint != nil && endpoint.isUsable() {
+			return endpoint.client, endpoint.URL, nil
+		}
+	}
+
+	client, url, err := p.GetClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	p.stickyMutex.Lock()
+	p.stickyByWallet[wallet] = url
+	p.stickyMutex.Unlock()
+
+	return client, url, nil
+}
+
+// clientForEndpoint returns the HTTP client for one specific endpoint URL,
+// bypassing the scheduler - used by the quorum verifier, which needs
+// responses from specific distinct endpoints rather than whichever one the
+// scheduler would pick for a single fetch.
+func (p *Pool) clientForEndpoint(url string) (*http.Client, error) {
+	endpoint := p.endpointByURL(url)
+	if endpoint == nil {
+		return nil, fmt.Errorf("unknown endpoint %s", url)
+	}
+	return endpoint.client, nil
+}
+
+// HealthyEndpointURLs returns the URLs of endpoints that are currently
+// healthy and not in cooldown, in pool order. The quorum verifier uses this
+// to pick distinct endpoints to cross-check a transaction against.
+func (p *Pool) HealthyEndpointURLs() []string {
+	urls := make([]string, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		if endpoint.isUsable() {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	return urls
+}
+
+func (p *Pool) endpointByURL(url string) *Endpoint {
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL == url {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+func (e *Endpoint) isUsable() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.healthy && time.Now().After(e.cooldownUntil)
+}
+
+// getClientRoundRobin is the pool's original selection algorithm. Callers
+// must hold p.mutex.
+func (p *Pool) getClientRoundRobin(ctx context.Context) (*http.Client, string, error) {
 	attempts := 0
 	startIndex := p.current
-	
+
 	for {
 		endpoint := p.endpoints[p.current]
 		p.current = (p.current + 1) % len(p.endpoints)
 		attempts++
-		
+
 		// Check if endpoint is in cooldown
 		endpoint.mutex.RLock()
 		inCooldown := time.Now().Before(endpoint.cooldownUntil)
 		healthy := endpoint.healthy
 		endpoint.mutex.RUnlock()
-		
+
 		if inCooldown {
 			p.logger.Debug().
 				Str("endpoint", endpoint.URL).
 				Time("cooldown_until", endpoint.cooldownUntil).
 				Msg("Endpoint in cooldown, skipping")
-			
+
 			// If we've tried all endpoints, continue to rate limiting check
 			if attempts >= len(p.endpoints) {
 				break
 			}
 			continue
 		}
-		
+
 		if !healthy {
 			p.logger.Debug().
 				Str("endpoint", endpoint.URL).
 				Msg("Endpoint unhealthy, skipping")
-			
+
 			// If we've tried all endpoints, continue to rate limiting check
 			if attempts >= len(p.endpoints) {
 				break
 			}
 			continue
 		}
-		
+
 		// Check rate limit
 		if endpoint.limiter.Allow() {
 			p.logger.Debug().
@@ -108,30 +442,30 @@ func (p *Pool) GetClient(ctx context.Context) (*http.Client, string, error) {
 				Msg("Selected RPC endpoint")
 			return endpoint.client, endpoint.URL, nil
 		}
-		
+
 		p.logger.Debug().
 			Str("endpoint", endpoint.URL).
 			Msg("Endpoint rate limited, trying next")
-		
+
 		// If we've tried all endpoints, break
 		if attempts >= len(p.endpoints) {
 			break
 		}
 	}
-	
+
 	// All endpoints are rate limited or unhealthy, wait for the first available one
 	endpoint := p.endpoints[startIndex]
-	
+
 	p.logger.Debug().
 		Str("endpoint", endpoint.URL).
 		Msg("All endpoints rate limited, waiting for availability")
-	
+
 	// Wait for rate limit to reset with context cancellation
 	reservation := endpoint.limiter.Reserve()
 	if !reservation.OK() {
 		return nil, "", fmt.Errorf("rate limiter failed to make reservation")
 	}
-	
+
 	delay := reservation.Delay()
 	if delay > 0 {
 		select {
@@ -142,18 +476,401 @@ func (p *Pool) GetClient(ctx context.Context) (*http.Client, string, error) {
 			return nil, "", ctx.Err()
 		}
 	}
-	
+
 	return endpoint.client, endpoint.URL, nil
 }
 
+// getClientFirstHealthy always prefers the earliest usable endpoint in
+// list order, falling back to round robin if none are usable right now.
+// Callers must hold p.mutex.
+func (p *Pool) getClientFirstHealthy(ctx context.Context) (*http.Client, string, error) {
+	for _, endpoint := range p.endpoints {
+		if !endpoint.isUsable() {
+			continue
+		}
+		if endpoint.limiter.Allow() {
+			p.logger.Debug().Str("endpoint", endpoint.URL).Msg("Selected RPC endpoint (first healthy)")
+			return endpoint.client, endpoint.URL, nil
+		}
+	}
+	return p.getClientRoundRobin(ctx)
+}
+
+// getClientLeastLatency prefers the usable endpoint with the lowest rolling
+// average latency, falling back to round robin if none are usable right
+// now. A zero EWMA (never observed) sorts first, since an untested endpoint
+// deserves a chance to establish a baseline. Callers must hold p.mutex.
+func (p *Pool) getClientLeastLatency(ctx context.Context) (*http.Client, string, error) {
+	var best *Endpoint
+	for _, endpoint := range p.endpoints {
+		if !endpoint.isUsable() || !endpoint.limiter.Allow() {
+			continue
+		}
+
+		if best == nil {
+			best = endpoint
+			continue
+		}
+
+		endpoint.mutex.RLock()
+		latency := endpoint.latencyEWMA
+		endpoint.mutex.RUnlock()
+
+		best.mutex.RLock()
+		bestLatency := best.latencyEWMA
+		best.mutex.RUnlock()
+
+		if latency < bestLatency {
+			best = endpoint
+		}
+	}
+
+	if best == nil {
+		return p.getClientRoundRobin(ctx)
+	}
+
+	p.logger.Debug().Str("endpoint", best.URL).Dur("latency_ewma", best.latencyEWMA).Msg("Selected RPC endpoint (least latency)")
+	return best.client, best.URL, nil
+}
+
+// getClientScored picks among usable endpoints weighted by Endpoint.score,
+// using power-of-two-choices: it samples two weighted picks and keeps
+// whichever scores higher, which avoids a single far-ahead endpoint
+// permanently starving the rest of the pool the way pure weighted-random
+// selection would. Callers must hold p.mutex.
+func (p *Pool) getClientScored(ctx context.Context) (*http.Client, string, error) {
+	var candidates []*Endpoint
+	for _, endpoint := range p.endpoints {
+		if !endpoint.isUsable() || !endpoint.limiter.Allow() {
+			continue
+		}
+		if !p.admitProbation(endpoint) {
+			continue
+		}
+		candidates = append(candidates, endpoint)
+	}
+
+	if len(candidates) == 0 {
+		return p.getClientRoundRobin(ctx)
+	}
+
+	best := weightedPick(candidates, p.latencyWeight, p.errorWeight, p.rateLimitWeight)
+	if len(candidates) > 1 {
+		if other := weightedPick(candidates, p.latencyWeight, p.errorWeight, p.rateLimitWeight); other.score(p.latencyWeight, p.errorWeight, p.rateLimitWeight) > best.score(p.latencyWeight, p.errorWeight, p.rateLimitWeight) {
+			best = other
+		}
+	}
+
+	p.logger.Debug().Str("endpoint", best.URL).Float64("score", best.score(p.latencyWeight, p.errorWeight, p.rateLimitWeight)).Msg("Selected RPC endpoint (scored)")
+	return best.client, best.URL, nil
+}
+
+// admitProbation gates how often an endpoint whose success ratio has
+// dropped below the pool's probationThreshold is offered to getClientScored
+// as a candidate at all: only every probationProbeRate'th call lets it
+// through, so it keeps receiving a trickle of traffic to detect recovery
+// without its low score (which weightedPick already accounts for) being
+// the only thing holding it back.
+func (p *Pool) admitProbation(e *Endpoint) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.successRatioLocked() >= p.probationThreshold {
+		e.probationHits = 0
+		return true
+	}
+
+	e.probationHits++
+	return e.probationHits%p.probationProbeRate == 0
+}
+
+// weightedPick samples one endpoint from candidates with probability
+// proportional to its score.
+func weightedPick(candidates []*Endpoint, latencyWeight, errorWeight, rateLimitWeight float64) *Endpoint {
+	scores := make([]float64, len(candidates))
+	total := 0.0
+	for i, e := range candidates {
+		scores[i] = e.score(latencyWeight, errorWeight, rateLimitWeight)
+		total += scores[i]
+	}
+
+	if total <= 0 {
+		return candidates[rand.Intn(len(candidates))]
+	}
+
+	r := rand.Float64() * total
+	for i, s := range scores {
+		r -= s
+		if r <= 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// recordOutcome pushes a success/failure sample into the endpoint's ring
+// buffer of its last scoreRingSize requests. Callers must hold e.mutex.
+func (e *Endpoint) recordOutcome(success bool) {
+	if e.successRingLen < scoreRingSize {
+		e.successRing[e.successRingLen] = success
+		e.successRingLen++
+	} else {
+		e.successRing[e.successRingPos] = success
+	}
+	e.successRingPos = (e.successRingPos + 1) % scoreRingSize
+}
+
+// successRatioLocked returns the fraction of the endpoint's last
+// scoreRingSize requests that succeeded. An endpoint with no history yet
+// returns 1.0 - optimistic, same rationale as a zero latencyEWMA sorting
+// first in getClientLeastLatency: an untested endpoint deserves a chance to
+// establish a baseline. Callers must hold e.mutex (or e.mutex.RLock).
+func (e *Endpoint) successRatioLocked() float64 {
+	if e.successRingLen == 0 {
+		return 1.0
+	}
+	successes := 0
+	for i := 0; i < e.successRingLen; i++ {
+		if e.successRing[i] {
+			successes++
+		}
+	}
+	return float64(successes) / float64(e.successRingLen)
+}
+
+// score returns this endpoint's SchedulerScored selection weight: its
+// success ratio raised to errorWeight, divided by its latency EWMA (in
+// seconds) raised to latencyWeight and its decayed rate-limit penalty
+// raised to rateLimitWeight. Weights of 1 (the default - see
+// WithScoreWeights) apply each component at its unscaled strength; a
+// weight of 0 removes that component from the score entirely.
+func (e *Endpoint) score(latencyWeight, errorWeight, rateLimitWeight float64) float64 {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	latencySeconds := e.latencyEWMA.Seconds()
+	rateLimitPenalty := 1 + e.decayedRateLimitLocked()
+	return math.Pow(e.successRatioLocked(), errorWeight) /
+		(math.Pow(latencySeconds+scoreLatencyEpsilonSeconds, latencyWeight) * math.Pow(rateLimitPenalty, rateLimitWeight))
+}
+
+// decayedRateLimitLocked returns the endpoint's current rate-limit EWMA,
+// decayed for however long it's been since the last 429/503 (without
+// mutating state - only recordRateLimitLocked writes back a decayed
+// value). Callers must hold e.mutex (or e.mutex.RLock).
+func (e *Endpoint) decayedRateLimitLocked() float64 {
+	if e.rateLimitEWMA == 0 || e.lastRateLimitEvent.IsZero() {
+		return 0
+	}
+	elapsed := time.Since(e.lastRateLimitEvent)
+	halfLives := elapsed.Seconds() / rateLimitHalfLife.Seconds()
+	return e.rateLimitEWMA * math.Pow(0.5, halfLives)
+}
+
+// recordRateLimitLocked folds a new 429/503 response into the endpoint's
+// decayed rate-limit count. Callers must hold e.mutex.
+func (e *Endpoint) recordRateLimitLocked() {
+	e.rateLimitEWMA = e.decayedRateLimitLocked() + 1
+	e.lastRateLimitEvent = time.Now()
+}
+
+// applyAIMDHalveLocked is AIMD's multiplicative-decrease half: it halves the
+// endpoint's current rate limit, flooring at minAIMDRateLimit, and resets
+// rampCounter so a run of successes has to accumulate again before
+// maybeRampUpLocked starts climbing the limit back up. Callers must hold
+// e.mutex.
+func (e *Endpoint) applyAIMDHalveLocked() {
+	newLimit := e.limiter.Limit() / 2
+	if newLimit < minAIMDRateLimit {
+		newLimit = minAIMDRateLimit
+	}
+	e.limiter.SetLimit(newLimit)
+	e.rampCounter = 0
+}
+
+// maybeRampUpLocked is AIMD's additive-increase half: every aimdRampEvery
+// consecutive successes, it nudges the endpoint's rate limit up by
+// aimdRampStep, capped at baseRateLimit. Callers must hold e.mutex.
+func (e *Endpoint) maybeRampUpLocked() {
+	if e.limiter.Limit() >= e.baseRateLimit {
+		return
+	}
+	e.rampCounter++
+	if e.rampCounter < aimdRampEvery {
+		return
+	}
+	e.rampCounter = 0
+	newLimit := e.limiter.Limit() + aimdRampStep
+	if newLimit > e.baseRateLimit {
+		newLimit = e.baseRateLimit
+	}
+	e.limiter.SetLimit(newLimit)
+}
+
+// RecordLatency updates an endpoint's rolling average latency from a
+// completed request, for use by the least-latency scheduler.
+func (p *Pool) RecordLatency(url string, duration time.Duration) {
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL != url {
+			continue
+		}
+		endpoint.mutex.Lock()
+		if endpoint.latencyEWMA == 0 {
+			endpoint.latencyEWMA = duration
+		} else {
+			endpoint.latencyEWMA = time.Duration(latencyEWMAAlpha*float64(duration) + (1-latencyEWMAAlpha)*float64(endpoint.latencyEWMA))
+		}
+		endpoint.mutex.Unlock()
+		break
+	}
+}
+
+// RecordSuccess clears an endpoint's consecutive-error count. Unlike
+// MarkHealthy it doesn't force the endpoint healthy or clear a cooldown set
+// for an unrelated reason (e.g. rate limiting) - it just resets the failure
+// streak that RecordFailure tracks.
+func (p *Pool) RecordSuccess(url string) {
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL != url {
+			continue
+		}
+		endpoint.mutex.Lock()
+		endpoint.consecutiveErrors = 0
+		endpoint.recordOutcome(true)
+		endpoint.maybeRampUpLocked()
+		endpoint.mutex.Unlock()
+		break
+	}
+}
+
+// RecordFailure increments an endpoint's consecutive-error count and, once
+// it reaches the pool's threshold, marks the endpoint unhealthy and starts
+// re-probing it in the background with a lightweight getBlockHeight call.
+// At most one reprobe loop runs per endpoint at a time - see the
+// endpoint.reprobing guard below.
+func (p *Pool) RecordFailure(url string) {
+	var endpoint *Endpoint
+	for _, e := range p.endpoints {
+		if e.URL == url {
+			endpoint = e
+			break
+		}
+	}
+	if endpoint == nil {
+		return
+	}
+
+	endpoint.mutex.Lock()
+	endpoint.consecutiveErrors++
+	count := endpoint.consecutiveErrors
+	endpoint.recordOutcome(false)
+
+	shouldReprobe := false
+	if count >= p.maxConsecutiveErrors && !endpoint.reprobing {
+		endpoint.reprobing = true
+		shouldReprobe = true
+	}
+	endpoint.mutex.Unlock()
+
+	if count < p.maxConsecutiveErrors {
+		return
+	}
+
+	p.MarkUnhealthy(url)
+	if shouldReprobe {
+		go p.reprobe(endpoint, count)
+	}
+}
+
+// reprobe repeatedly re-checks an unhealthy (circuit open) endpoint with a
+// lightweight getBlockHeight call, waiting an exponential backoff - 1s, 2s,
+// 4s, ... capped at reprobeMaxDelay - between attempts. It keeps retrying
+// until the endpoint recovers, marking the circuit half-open for the
+// duration of each attempt: a single failed probe used to leave the
+// endpoint unhealthy forever, since only RecordFailure scheduled the next
+// one, and an unhealthy endpoint receives no further traffic to trigger
+// RecordFailure again. Callers must only invoke this after winning the
+// endpoint.reprobing compare-and-set in RecordFailure, and it always clears
+// that flag on the way out so a later failure streak can start a new loop.
+func (p *Pool) reprobe(endpoint *Endpoint, failureCount int) {
+	defer func() {
+		endpoint.mutex.Lock()
+		endpoint.reprobing = false
+		endpoint.mutex.Unlock()
+	}()
+
+	p.logger.Warn().Str("endpoint", endpoint.URL).Int("consecutive_errors", failureCount).Msg("Circuit open, scheduling re-probes")
+
+	for attempt := 0; ; attempt++ {
+		delay := reprobeBaseDelay * time.Duration(1<<uint(attempt))
+		if delay <= 0 || delay > reprobeMaxDelay {
+			delay = reprobeMaxDelay
+		}
+		time.Sleep(delay)
+
+		endpoint.mutex.Lock()
+		endpoint.circuitState = CircuitHalfOpen
+		endpoint.mutex.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := probeBlockHeight(ctx, endpoint)
+		cancel()
+
+		if err == nil {
+			p.logger.Info().Str("endpoint", endpoint.URL).Msg("Re-probe succeeded, marking endpoint healthy")
+			p.MarkHealthy(endpoint.URL)
+			return
+		}
+
+		p.logger.Debug().Err(err).Str("endpoint", endpoint.URL).Int("attempt", attempt+1).Msg("Re-probe failed, endpoint stays unhealthy")
+		endpoint.mutex.Lock()
+		endpoint.circuitState = CircuitOpen
+		endpoint.mutex.Unlock()
+	}
+}
+
+// probeBlockHeight issues a minimal getBlockHeight RPC call to check whether
+// an unhealthy endpoint has recovered, without the overhead of a full
+// transaction or signature fetch.
+func probeBlockHeight(ctx context.Context, endpoint *Endpoint) error {
+	request := RpcRequest{
+		Jsonrpc: "2.0",
+		ID:      "1",
+		Method:  "getBlockHeight",
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal probe request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint.URL, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to create probe request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := endpoint.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("probe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("probe returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // MarkUnhealthy marks an endpoint as unhealthy
 func (p *Pool) MarkUnhealthy(url string) {
 	for _, endpoint := range p.endpoints {
 		if endpoint.URL == url {
 			endpoint.mutex.Lock()
 			endpoint.healthy = false
+			endpoint.circuitState = CircuitOpen
 			endpoint.mutex.Unlock()
-			
+
 			metrics.SetRPCEndpointHealth(url, false)
 			p.logger.Warn().Str("endpoint", url).Msg("Marked endpoint as unhealthy")
 			break
@@ -168,8 +885,10 @@ func (p *Pool) MarkHealthy(url string) {
 			endpoint.mutex.Lock()
 			endpoint.healthy = true
 			endpoint.cooldownUntil = time.Time{} // Clear cooldown
+			endpoint.consecutiveErrors = 0       // MarkHealthy always resets the failure streak, same as a fresh endpoint
+			endpoint.circuitState = CircuitClosed
 			endpoint.mutex.Unlock()
-			
+
 			metrics.SetRPCEndpointHealth(url, true)
 			p.logger.Info().Str("endpoint", url).Msg("Marked endpoint as healthy")
 			break
@@ -177,14 +896,45 @@ func (p *Pool) MarkHealthy(url string) {
 	}
 }
 
-// SetCooldown puts an endpoint in cooldown for the specified duration
+// RecordRateLimit folds a 429/503 (or Solana's -32005 "node is behind")
+// response into an endpoint's decayed rate-limit score penalty (see
+// Endpoint.score, WithScoreWeights) and halves its rate limiter - AIMD's
+// multiplicative-decrease half, applyAIMDHalveLocked; RecordSuccess's
+// maybeRampUpLocked is the additive-increase half that climbs it back up.
+// It's separate from SetCooldown because SetCooldown is also used for the
+// admin API's intentional endpoint drains, which aren't the provider rate
+// limiting us and shouldn't be scored or throttled as if they were.
+func (p *Pool) RecordRateLimit(url string) {
+	for _, endpoint := range p.endpoints {
+		if endpoint.URL == url {
+			endpoint.mutex.Lock()
+			endpoint.recordRateLimitLocked()
+			endpoint.applyAIMDHalveLocked()
+			endpoint.mutex.Unlock()
+			break
+		}
+	}
+}
+
+// SetCooldown puts an endpoint in cooldown for the specified duration.
+//
+// This stays duration-based rather than computing its own exponential
+// backoff from consecutive failures: the admin API's drain endpoint calls
+// it with an operator-chosen DrainSeconds, and the 429/503 paths in
+// batch.go/transport.go call it with a duration parsed from the endpoint's
+// own Retry-After header (fetch.go's handleRateLimit uses a fixed five
+// minutes), each chosen by its caller rather than locally guessed from
+// failures, and SetCooldown has no way to tell which case it's being called
+// for. reprobe's exponential backoff (1s, 2s, ... capped at reprobeMaxDelay)
+// is what governs how long an open-circuit endpoint actually waits between
+// re-probes.
 func (p *Pool) SetCooldown(url string, duration time.Duration) {
 	for _, endpoint := range p.endpoints {
 		if endpoint.URL == url {
 			endpoint.mutex.Lock()
 			endpoint.cooldownUntil = time.Now().Add(duration)
 			endpoint.mutex.Unlock()
-			
+
 			p.logger.Warn().
 				Str("endpoint", url).
 				Dur("duration", duration).
@@ -207,6 +957,57 @@ func (p *Pool) GetHealthyEndpointCount() int {
 	return count
 }
 
+// EndpointErrorCount is one entry in TopErrorEndpoints' rollup: an
+// endpoint's URL and its current consecutive-error streak.
+type EndpointErrorCount struct {
+	URL               string
+	ConsecutiveErrors int
+}
+
+// TopErrorEndpoints returns up to n endpoints with the highest consecutive
+// error streaks, worst first, for runQueueMonitoring's per-minute error
+// rollup log line. Endpoints with no errors are omitted entirely.
+func (p *Pool) TopErrorEndpoints(n int) []EndpointErrorCount {
+	counts := make([]EndpointErrorCount, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		endpoint.mutex.RLock()
+		errs := endpoint.consecutiveErrors
+		endpoint.mutex.RUnlock()
+		if errs > 0 {
+			counts = append(counts, EndpointErrorCount{URL: endpoint.URL, ConsecutiveErrors: errs})
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].ConsecutiveErrors > counts[j].ConsecutiveErrors
+	})
+
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// SetEndpoints replaces the pool's endpoints wholesale, for the admin API's
+// scraper_setRPCEndpoints method (rotating in a fresh provider without
+// restarting the process). Every endpoint starts healthy with a fresh rate
+// limiter, same as NewPool. Sticky wallet assignments are cleared, since
+// they may point at an endpoint that no longer exists.
+func (p *Pool) SetEndpoints(urls []string) {
+	endpoints := newEndpoints(urls)
+
+	p.mutex.Lock()
+	p.endpoints = endpoints
+	p.current = 0
+	p.mutex.Unlock()
+
+	p.stickyMutex.Lock()
+	p.stickyByWallet = make(map[string]string)
+	p.stickyMutex.Unlock()
+
+	p.logger.Info().Int("endpoint_count", len(urls)).Msg("Replaced RPC endpoints")
+}
+
 // GetStats returns pool statistics
 func (p *Pool) GetStats() map[string]interface{} {
 	stats := map[string]interface{}{
@@ -214,19 +1015,39 @@ func (p *Pool) GetStats() map[string]interface{} {
 		"healthy_endpoints": p.GetHealthyEndpointCount(),
 		"endpoints":         make([]map[string]interface{}, len(p.endpoints)),
 	}
-	
+
 	for i, endpoint := range p.endpoints {
 		endpoint.mutex.RLock()
+		healthy := endpoint.healthy
+		cooldownUntil := endpoint.cooldownUntil
+		consecutiveErrors := endpoint.consecutiveErrors
+		latencyEWMA := endpoint.latencyEWMA
+		successRatio := endpoint.successRatioLocked()
+		rateLimitEWMA := endpoint.decayedRateLimitLocked()
+		circuitState := endpoint.circuitState
+		currentRateLimit := float64(endpoint.limiter.Limit())
+		score := math.Pow(successRatio, p.errorWeight) /
+			(math.Pow(latencyEWMA.Seconds()+scoreLatencyEpsilonSeconds, p.latencyWeight) * math.Pow(1+rateLimitEWMA, p.rateLimitWeight))
+		endpoint.mutex.RUnlock()
+
 		endpointStats := map[string]interface{}{
-			"url":             endpoint.URL,
-			"healthy":         endpoint.healthy,
-			"in_cooldown":     time.Now().Before(endpoint.cooldownUntil),
-			"cooldown_until":  endpoint.cooldownUntil,
+			"url":                endpoint.URL,
+			"healthy":            healthy,
+			"in_cooldown":        time.Now().Before(cooldownUntil),
+			"cooldown_until":     cooldownUntil,
+			"consecutive_errors": consecutiveErrors,
+			"latency_ewma_ms":    latencyEWMA.Milliseconds(),
+			"success_ratio":      successRatio,
+			"rate_limit_ewma":    rateLimitEWMA,
+			"circuit_state":      circuitState,
+			"rate_limit":         currentRateLimit,
+			"score":              score,
 		}
-		endpoint.mutex.RUnlock()
-		
+
+		metrics.SetRPCEndpointScore(endpoint.URL, score, successRatio)
+		metrics.SetRPCEndpointRateLimitEWMA(endpoint.URL, rateLimitEWMA)
 		stats["endpoints"].([]map[string]interface{})[i] = endpointStats
 	}
-	
+
 	return stats
-} 
\ No newline at end of file
+}
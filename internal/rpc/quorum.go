@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// QuorumSpec configures quorum verification: a transaction is sampled from
+// N distinct healthy endpoints and accepted only once at least K of them
+// agree on its fingerprint.
+type QuorumSpec struct {
+	K int
+	N int
+}
+
+// ParseQuorumSpec parses a "K/N" or "quorum:K/N" spec, as accepted by the
+// RPC_VERIFY_QUORUM config value (see internal/config). K must be between 1
+// and N inclusive.
+func ParseQuorumSpec(spec string) (QuorumSpec, error) {
+	spec = strings.TrimPrefix(spec, "quorum:")
+	parts := strings.SplitN(spec, "/", 2)
+	if len(parts) != 2 {
+		return QuorumSpec{}, fmt.Errorf("invalid quorum spec %q (want K/N, e.g. 2/3)", spec)
+	}
+
+	k, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return QuorumSpec{}, fmt.Errorf("invalid quorum spec %q: K is not an integer", spec)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return QuorumSpec{}, fmt.Errorf("invalid quorum spec %q: N is not an integer", spec)
+	}
+	if n <= 0 || k <= 0 || k > n {
+		return QuorumSpec{}, fmt.Errorf("invalid quorum spec %q: need 1 <= K <= N", spec)
+	}
+
+	return QuorumSpec{K: k, N: n}, nil
+}
+
+// EndpointDiff is one endpoint's observed fingerprint for a transaction a
+// DivergenceReport is about, or the error it returned instead.
+type EndpointDiff struct {
+	Endpoint    string
+	Err         string
+	Signature   string
+	Slot        uint64
+	MetaErr     string
+	MessageHash string
+}
+
+// DivergenceReport is returned (and logged) when fewer than Spec.K of
+// Spec.N sampled endpoints agreed on a transaction's fingerprint. It
+// implements error so callers can propagate it like any other fetch
+// failure while still inspecting Diffs for the per-endpoint detail.
+type DivergenceReport struct {
+	Signature string
+	Spec      QuorumSpec
+	Diffs     []EndpointDiff
+}
+
+func (r *DivergenceReport) Error() string {
+	return fmt.Sprintf("quorum %d/%d not reached for transaction %s across %d sampled endpoints",
+		r.Spec.K, r.Spec.N, r.Signature, len(r.Diffs))
+}
+
+// fingerprint extracts the fields a quorum compares: the transaction's own
+// first signature, its Meta.err, its slot, and a hash of its compiled
+// message. json.Marshal sorts map keys, so hashing the re-marshaled message
+// is stable across endpoints that returned the same transaction with
+// differently-ordered JSON.
+func fingerprint(tx *RpcTransaction) (EndpointDiff, error) {
+	diff := EndpointDiff{Slot: tx.Slot}
+
+	if sigs, ok := tx.Transaction["signatures"].([]interface{}); ok && len(sigs) > 0 {
+		if sig, ok := sigs[0].(string); ok {
+			diff.Signature = sig
+		}
+	}
+
+	if metaErr, ok := tx.Meta["err"]; ok && metaErr != nil {
+		b, err := json.Marshal(metaErr)
+		if err != nil {
+			return diff, fmt.Errorf("encoding meta.err: %w", err)
+		}
+		diff.MetaErr = string(b)
+	}
+
+	if message, ok := tx.Transaction["message"]; ok {
+		b, err := json.Marshal(message)
+		if err != nil {
+			return diff, fmt.Errorf("encoding message: %w", err)
+		}
+		sum := sha256.Sum256(b)
+		diff.MessageHash = hex.EncodeToString(sum[:])
+	}
+
+	return diff, nil
+}
+
+// fingerprintKey groups EndpointDiffs that agree on every compared field.
+// Endpoints that errored never share a key with each other or with a
+// successful fetch, since diff.Err is part of the key.
+func fingerprintKey(d EndpointDiff) string {
+	return strings.Join([]string{d.Err, d.Signature, strconv.FormatUint(d.Slot, 10), d.MetaErr, d.MessageHash}, "|")
+}
+
+// fetchTransactionQuorum fetches signature from f.quorum.N distinct healthy
+// endpoints in parallel and returns the transaction only if at least
+// f.quorum.K of them produced an identical fingerprint. On disagreement it
+// returns a *DivergenceReport describing every endpoint's answer instead of
+// silently trusting whichever one responded.
+func (f *Fetcher) fetchTransactionQuorum(ctx context.Context, signature string) (*RpcTransaction, error) {
+	spec := *f.quorum
+
+	endpoints := f.pool.HealthyEndpointURLs()
+	if len(endpoints) < spec.N {
+		return nil, fmt.Errorf("quorum %d/%d requires %d healthy endpoints, only %d available", spec.K, spec.N, spec.N, len(endpoints))
+	}
+	endpoints = endpoints[:spec.N]
+
+	type sample struct {
+		tx  *RpcTransaction
+		err error
+	}
+	samples := make([]sample, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			tx, err := f.fetchTransactionFromEndpoint(ctx, endpoint, signature)
+			samples[i] = sample{tx: tx, err: err}
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	diffs := make([]EndpointDiff, len(endpoints))
+	groups := make(map[string][]int)
+
+	for i, s := range samples {
+		var diff EndpointDiff
+		diff.Endpoint = endpoints[i]
+
+		switch {
+		case s.err != nil:
+			diff.Err = s.err.Error()
+		default:
+			fp, err := fingerprint(s.tx)
+			if err != nil {
+				diff.Err = err.Error()
+			} else {
+				diff.Signature = fp.Signature
+				diff.Slot = fp.Slot
+				diff.MetaErr = fp.MetaErr
+				diff.MessageHash = fp.MessageHash
+			}
+		}
+
+		diffs[i] = diff
+		key := fingerprintKey(diff)
+		groups[key] = append(groups[key], i)
+	}
+
+	var majorityKey string
+	var majority []int
+	for key, indices := range groups {
+		if len(indices) > len(majority) {
+			majorityKey = key
+			majority = indices
+		}
+	}
+	_ = majorityKey
+
+	if len(majority) < spec.K || diffs[majority[0]].Err != "" {
+		f.logger.Warn().
+			Str("signature", signature).
+			Int("k", spec.K).
+			Int("n", spec.N).
+			Interface("diffs", diffs).
+			Msg("Quorum not reached for transaction")
+		return nil, &DivergenceReport{Signature: signature, Spec: spec, Diffs: diffs}
+	}
+
+	return samples[majority[0]].tx, nil
+}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TransportKind identifies which wire protocol backs a Transport: plain
+// HTTP JSON-RPC, a websocket PubSub connection, or a Yellowstone Geyser
+// gRPC stream.
+type TransportKind string
+
+const (
+	TransportHTTP      TransportKind = "http"
+	TransportWebSocket TransportKind = "websocket"
+	TransportGRPC      TransportKind = "grpc"
+)
+
+// subscriptionMethods is every Solana JSON-RPC method that only exists as a
+// PubSub subscription - never as a plain HTTP call - and therefore must be
+// routed to a websocket (or Geyser) transport instead of HTTP.
+var subscriptionMethods = map[string]bool{
+	"logsSubscribe":      true,
+	"accountSubscribe":   true,
+	"signatureSubscribe": true,
+	"programSubscribe":   true,
+	"slotSubscribe":      true,
+	"rootSubscribe":      true,
+}
+
+// RouteTransportKind decides which TransportKind Call/Subscribe for method
+// should use: subscriptionMethods go to TransportWebSocket, everything else
+// stays on TransportHTTP. TransportGRPC (Yellowstone Geyser) is never
+// chosen automatically - a caller that wants it has to ask for it
+// explicitly, since this repo has no Geyser client to route to yet.
+func RouteTransportKind(method string) TransportKind {
+	if subscriptionMethods[method] {
+		return TransportWebSocket
+	}
+	return TransportHTTP
+}
+
+// Transport abstracts how a caller actually talks to one RPC endpoint -
+// HTTP, websocket PubSub, or (eventually) a Geyser gRPC stream - behind one
+// Call/Subscribe/Close surface, so callers like internal/solana.Client
+// don't need to know which wire protocol backs a given method.
+type Transport interface {
+	// Call issues a synchronous JSON-RPC request and returns its raw
+	// "result" field.
+	Call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error)
+	// Subscribe issues a PubSub-style subscription request and returns a
+	// channel carrying each notification's raw "result" field. The channel
+	// is closed when the transport is closed or the underlying connection
+	// drops.
+	Subscribe(ctx context.Context, method string, params []interface{}) (<-chan json.RawMessage, error)
+	Close() error
+}
+
+// httpTransport implements Transport over one Pool-tracked HTTP(S)
+// endpoint, reusing the same request/response shapes and health bookkeeping
+// as Fetcher's fetchTransactionFromEndpoint.
+type httpTransport struct {
+	pool   *Pool
+	url    string
+	client *http.Client
+}
+
+// NewHTTPTransport wraps one Pool-tracked endpoint (as returned by
+// Pool.GetClient) as a Transport. Its Subscribe always fails - plain HTTP
+// has no subscription method - so callers should route subscription
+// methods (see RouteTransportKind) to a websocket or Geyser transport
+// instead.
+func NewHTTPTransport(pool *Pool, url string, client *http.Client) Transport {
+	return &httpTransport{pool: pool, url: url, client: client}
+}
+
+func (t *httpTransport) Call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	request := RpcRequest{Jsonrpc: "2.0", ID: "1", Method: method, Params: params}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		t.pool.RecordFailure(t.url)
+		return nil, fmt.Errorf("%s request to %s failed: %w", method, t.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		t.pool.SetCooldown(t.url, retryAfter)
+		t.pool.RecordRateLimit(t.url)
+		return nil, &RateLimitedError{Endpoint: t.url, RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.pool.RecordFailure(t.url)
+		return nil, fmt.Errorf("unexpected status code from %s: %d", t.url, resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.pool.RecordFailure(t.url)
+		return nil, fmt.Errorf("failed to read %s response body: %w", method, err)
+	}
+
+	var rpcResponse RpcResponse
+	if err := json.Unmarshal(respBody, &rpcResponse); err != nil {
+		t.pool.RecordFailure(t.url)
+		return nil, fmt.Errorf("failed to unmarshal %s response: %w", method, err)
+	}
+	if rpcResponse.Error != nil {
+		if rpcResponse.Error.Code == solanaNodeBehindErrorCode {
+			t.pool.RecordRateLimit(t.url)
+		} else {
+			t.pool.RecordFailure(t.url)
+		}
+		return nil, fmt.Errorf("RPC error from %s: code %d, message: %s", t.url, rpcResponse.Error.Code, rpcResponse.Error.Message)
+	}
+
+	t.pool.RecordLatency(t.url, time.Since(start))
+	t.pool.RecordSuccess(t.url)
+
+	if rpcResponse.Result == nil {
+		return nil, nil
+	}
+	return json.Marshal(rpcResponse.Result)
+}
+
+func (t *httpTransport) Subscribe(ctx context.Context, method string, params []interface{}) (<-chan json.RawMessage, error) {
+	return nil, fmt.Errorf("method %q requires a websocket or gRPC subscription, not supported over HTTP transport", method)
+}
+
+func (t *httpTransport) Close() error { return nil }
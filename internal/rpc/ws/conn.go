@@ -0,0 +1,318 @@
+// Package ws implements just enough of RFC 6455 to drive Solana's JSON-RPC
+// pubsub protocol (logsSubscribe/accountSubscribe/signatureSubscribe)
+// without pulling in a third-party websocket dependency - this module has
+// no go.mod and can't vendor one. It only supports what that protocol
+// needs: single-frame text messages in each direction, server-initiated
+// ping/close handled transparently, no permessage-deflate, no continuation
+// frames. A provider that fragments its pubsub notifications across
+// multiple frames isn't handled; ReadMessage returns an error instead of
+// silently truncating.
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is the fixed GUID RFC 6455 section 1.3 uses to derive
+// Sec-WebSocket-Accept from the client's Sec-WebSocket-Key.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// opcode identifies a websocket frame's payload type, per RFC 6455 section
+// 5.2. Only the ones Dial/ReadMessage/WriteMessage need are named.
+type opcode byte
+
+const (
+	opContinuation opcode = 0x0
+	opText         opcode = 0x1
+	opClose        opcode = 0x8
+	opPing         opcode = 0x9
+	opPong         opcode = 0xA
+)
+
+// Conn is a single client websocket connection good for one reader and one
+// writer goroutine at a time - ReadMessage is not safe to call
+// concurrently with itself, nor is WriteMessage, though one of each may run
+// concurrently with the other.
+type Conn struct {
+	nc net.Conn
+	br *bufio.Reader
+
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+}
+
+// Dial opens a TCP (or TLS, for wss://) connection to rawURL and performs
+// the websocket opening handshake. ctx only governs the handshake itself;
+// it has no effect on the connection's lifetime afterward.
+func Dial(ctx context.Context, rawURL string) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket URL %q: %w", rawURL, err)
+	}
+
+	var useTLS bool
+	switch u.Scheme {
+	case "wss":
+		useTLS = true
+	case "ws":
+		useTLS = false
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme %q (want ws or wss)", u.Scheme)
+	}
+
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		if useTLS {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	dialer := net.Dialer{}
+	nc, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", host, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = nc.SetDeadline(deadline)
+	}
+
+	if useTLS {
+		tlsConn := tls.Client(nc, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", host, err)
+		}
+		nc = tlsConn
+	}
+
+	key, err := randomWebsocketKey()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to generate Sec-WebSocket-Key: %w", err)
+	}
+
+	requestPath := u.RequestURI()
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	var req bytes.Buffer
+	fmt.Fprintf(&req, "GET %s HTTP/1.1\r\n", requestPath)
+	fmt.Fprintf(&req, "Host: %s\r\n", u.Host)
+	req.WriteString("Upgrade: websocket\r\n")
+	req.WriteString("Connection: Upgrade\r\n")
+	fmt.Fprintf(&req, "Sec-WebSocket-Key: %s\r\n", key)
+	req.WriteString("Sec-WebSocket-Version: 13\r\n")
+	req.WriteString("\r\n")
+
+	if _, err := nc.Write(req.Bytes()); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to send handshake request: %w", err)
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, &http.Request{Method: "GET"})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to read handshake response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		nc.Close()
+		return nil, fmt.Errorf("endpoint refused websocket upgrade: %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		nc.Close()
+		return nil, fmt.Errorf("endpoint did not upgrade to websocket (Upgrade: %q)", resp.Header.Get("Upgrade"))
+	}
+
+	if want := acceptKey(key); resp.Header.Get("Sec-WebSocket-Accept") != want {
+		nc.Close()
+		return nil, fmt.Errorf("endpoint returned an invalid Sec-WebSocket-Accept")
+	}
+
+	// The handshake deadline shouldn't apply to the life of the connection.
+	_ = nc.SetDeadline(time.Time{})
+
+	return &Conn{nc: nc, br: br}, nil
+}
+
+// randomWebsocketKey generates the 16 random bytes RFC 6455 requires for
+// Sec-WebSocket-Key, base64-encoded.
+func randomWebsocketKey() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value a compliant server
+// must return for the given Sec-WebSocket-Key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WriteMessage sends data as a single masked text frame, as RFC 6455
+// requires of every frame a client sends.
+func (c *Conn) WriteMessage(data []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return writeFrame(c.nc, opText, data)
+}
+
+func writeFrame(w io.Writer, op opcode, payload []byte) error {
+	var header bytes.Buffer
+	header.WriteByte(0x80 | byte(op)) // FIN=1, no extensions
+
+	maskBit := byte(0x80)
+	switch n := len(payload); {
+	case n < 126:
+		header.WriteByte(maskBit | byte(n))
+	case n <= 0xFFFF:
+		header.WriteByte(maskBit | 126)
+		var ext [2]byte
+		binary.BigEndian.PutUint16(ext[:], uint16(n))
+		header.Write(ext[:])
+	default:
+		header.WriteByte(maskBit | 127)
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		header.Write(ext[:])
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(rand.Reader, mask[:]); err != nil {
+		return fmt.Errorf("failed to generate frame mask: %w", err)
+	}
+	header.Write(mask[:])
+
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return fmt.Errorf("failed to write frame header: %w", err)
+	}
+	if _, err := w.Write(masked); err != nil {
+		return fmt.Errorf("failed to write frame payload: %w", err)
+	}
+	return nil
+}
+
+// ReadMessage blocks until the next complete text message arrives,
+// transparently answering any ping with a pong and skipping pongs. It
+// returns an error (including io.EOF) if the peer closes the connection or
+// sends something this minimal client doesn't support (binary frames,
+// fragmented messages, or a malformed header).
+func (c *Conn) ReadMessage() ([]byte, error) {
+	for {
+		fin, op, payload, err := readFrame(c.br)
+		if err != nil {
+			return nil, err
+		}
+		if !fin {
+			return nil, fmt.Errorf("fragmented websocket messages are not supported")
+		}
+
+		switch op {
+		case opText:
+			return payload, nil
+		case opPing:
+			c.writeMutex.Lock()
+			err := writeFrame(c.nc, opPong, payload)
+			c.writeMutex.Unlock()
+			if err != nil {
+				return nil, fmt.Errorf("failed to respond to ping: %w", err)
+			}
+		case opPong:
+			// Nothing to do; we don't send application pings.
+		case opClose:
+			return nil, io.EOF
+		default:
+			return nil, fmt.Errorf("unsupported websocket opcode %#x", op)
+		}
+	}
+}
+
+// readFrame reads and unmasks (servers don't mask) one websocket frame.
+func readFrame(br *bufio.Reader) (fin bool, op opcode, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = head[0]&0x80 != 0
+	op = opcode(head[0] & 0x0F)
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(br, maskKey[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return fin, op, payload, nil
+}
+
+// Close sends a close frame (best-effort) and closes the underlying
+// connection. Safe to call more than once.
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.writeMutex.Lock()
+		_ = writeFrame(c.nc, opClose, nil)
+		c.writeMutex.Unlock()
+		err = c.nc.Close()
+	})
+	return err
+}
@@ -0,0 +1,104 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wnt/mercon/internal/rpc"
+)
+
+// SignatureFilter narrows a SubscribeSignatures subscription the same way
+// logsSubscribe's own params do: Mentions lists the account addresses to
+// watch for, and Commitment defaults to "confirmed" when empty.
+type SignatureFilter struct {
+	Mentions   []string
+	Commitment string
+}
+
+// Signature is one new transaction signature delivered by
+// SubscribeSignatures.
+type Signature struct {
+	Value string
+}
+
+// SubscribeSignatures opens a logsSubscribe subscription on one of pool's
+// healthy endpoints and returns a channel of every matching signature,
+// using transport's generic (non-wallet-keyed) Subscribe rather than
+// Subscriber's per-wallet queue-push flow - callers here want the raw
+// stream, not an automatic wallet requeue.
+//
+// This lives in rpc/ws rather than on solana.Client because Client in this
+// codebase doesn't hold an rpc.Pool reference (it talks to Solana via
+// github.com/gagliardetto/solana-go's own client); internal/worker.Manager,
+// which already holds both a Pool and a queue.Client, is the realistic
+// caller for a wallet indexer that wants this instead of Subscriber's
+// polling fallback path. Unlike Subscriber, this does not reconnect or
+// resubscribe on a dropped connection - it closes the returned channel and
+// the caller decides whether to subscribe again.
+func SubscribeSignatures(ctx context.Context, pool *rpc.Pool, filter SignatureFilter) (<-chan Signature, error) {
+	commitment := filter.Commitment
+	if commitment == "" {
+		commitment = "confirmed"
+	}
+
+	var lastErr error
+	for _, httpURL := range pool.HealthyEndpointURLs() {
+		t, err := NewTransport(ctx, pool, httpURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		notifications, err := t.Subscribe(ctx, "logsSubscribe", []interface{}{
+			map[string]interface{}{"mentions": filter.Mentions},
+			map[string]interface{}{"commitment": commitment},
+		})
+		if err != nil {
+			t.Close()
+			lastErr = err
+			continue
+		}
+
+		out := make(chan Signature, 64)
+		go relaySignatures(ctx, t, notifications, out)
+		return out, nil
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("no endpoint available for signature subscription: %w", lastErr)
+	}
+	return nil, fmt.Errorf("no healthy endpoint available for signature subscription")
+}
+
+// relaySignatures decodes each logsNotification result off notifications
+// and forwards its signature to out, until notifications closes (the
+// connection dropped) or ctx is canceled.
+func relaySignatures(ctx context.Context, t rpc.Transport, notifications <-chan json.RawMessage, out chan<- Signature) {
+	defer close(out)
+	defer t.Close()
+
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			var parsed struct {
+				Value struct {
+					Signature string `json:"signature"`
+				} `json:"value"`
+			}
+			if err := json.Unmarshal(notification, &parsed); err != nil {
+				continue
+			}
+			select {
+			case out <- Signature{Value: parsed.Value.Signature}:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/wnt/mercon/internal/backoff"
+	"github.com/wnt/mercon/internal/metrics"
+	"github.com/wnt/mercon/internal/queue"
+	"github.com/wnt/mercon/internal/rpc"
+)
+
+// subscribePriority is the score Subscriber pushes a wallet onto the main
+// queue with when a notification discovers new activity for it. Client's
+// PopWallet pops the lowest score first (see internal/queue), so 0 jumps
+// the wallet ahead of anything scored by a now-ish unix timestamp the way
+// Worker.processWallet's normal re-queue does.
+const subscribePriority = 0
+
+// subscribeMessage is a logsSubscribe/logsUnsubscribe request or its
+// response, and logsNotificationMessage below is the async push - they're
+// kept separate because a response's "id" correlates to our own request
+// counter while a notification's "subscription" correlates to the id the
+// server handed back in that response.
+type subscribeMessage struct {
+	Jsonrpc string        `json:"jsonrpc"`
+	ID      int64         `json:"id,omitempty"`
+	Method  string        `json:"method,omitempty"`
+	Params  []interface{} `json:"params,omitempty"`
+	Result  interface{}   `json:"result,omitempty"`
+}
+
+type logsNotificationParams struct {
+	Subscription int64 `json:"subscription"`
+	Result       struct {
+		Value struct {
+			Signature string `json:"signature"`
+		} `json:"value"`
+	} `json:"result"`
+}
+
+type logsNotificationMessage struct {
+	Method string                 `json:"method"`
+	Params logsNotificationParams `json:"params"`
+}
+
+// Subscriber maintains persistent websocket connections to RPC endpoints
+// and turns Solana's logsSubscribe notifications into immediate wallet
+// queue pushes - a lower-latency alternative to Worker's polling-based
+// Fetcher.FetchSignatures, closing the gap between an on-chain event and
+// the wallet being picked back up from tens of seconds to sub-second.
+//
+// It shares Pool's health/cooldown bookkeeping: a dropped websocket counts
+// against the same endpoint the HTTP-based Fetcher would be penalizing for
+// an outage, so the autoscaler and admin API see one consistent picture of
+// endpoint health no matter which transport noticed the problem.
+type Subscriber struct {
+	pool   *rpc.Pool
+	queue  *queue.Client
+	logger zerolog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	conns       map[string]*endpointConn // keyed by the endpoint's http(s) URL
+	walletConn  map[string]string        // wallet -> the http(s) URL it's subscribed through
+	unsupported map[string]bool          // http(s) URLs that refused the websocket upgrade
+}
+
+// NewSubscriber creates a Subscriber that pushes discovered signatures for
+// subscribed wallets onto queueClient's main wallet queue. Endpoints are
+// dialed lazily, the first time Subscribe needs one.
+func NewSubscriber(pool *rpc.Pool, queueClient *queue.Client, logger zerolog.Logger) *Subscriber {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Subscriber{
+		pool:        pool,
+		queue:       queueClient,
+		logger:      logger.With().Str("component", "ws_subscriber").Logger(),
+		ctx:         ctx,
+		cancel:      cancel,
+		conns:       make(map[string]*endpointConn),
+		walletConn:  make(map[string]string),
+		unsupported: make(map[string]bool),
+	}
+}
+
+// wsURL derives an endpoint's websocket URL from its HTTP(S) RPC URL by
+// swapping the scheme - the convention major Solana RPC providers (Helius,
+// QuickNode, Triton, etc.) follow for their own endpoints. A provider that
+// doesn't follow it will simply fail the upgrade at connect time and get
+// marked unsupported the same as any other refusal.
+func wsURL(httpURL string) (string, error) {
+	switch {
+	case strings.HasPrefix(httpURL, "https://"):
+		return "wss://" + strings.TrimPrefix(httpURL, "https://"), nil
+	case strings.HasPrefix(httpURL, "http://"):
+		return "ws://" + strings.TrimPrefix(httpURL, "http://"), nil
+	default:
+		return "", fmt.Errorf("endpoint %q is not an http(s) URL", httpURL)
+	}
+}
+
+// Subscribe registers wallet for low-latency signature discovery on one of
+// the pool's currently-healthy, not-already-unsupported endpoints. If none
+// is available, it returns an error so the caller keeps polling that
+// wallet via FetchSignatures instead of silently doing nothing.
+func (s *Subscriber) Subscribe(wallet string) error {
+	s.mu.Lock()
+	if _, already := s.walletConn[wallet]; already {
+		s.mu.Unlock()
+		return nil
+	}
+
+	var httpURL string
+	for _, candidate := range s.pool.HealthyEndpointURLs() {
+		if !s.unsupported[candidate] {
+			httpURL = candidate
+			break
+		}
+	}
+	if httpURL == "" {
+		s.mu.Unlock()
+		return fmt.Errorf("no endpoint available for websocket subscription to %s, falling back to polling", wallet)
+	}
+
+	ec, ok := s.conns[httpURL]
+	s.mu.Unlock()
+
+	if !ok {
+		target, err := wsURL(httpURL)
+		if err != nil {
+			s.markUnsupported(httpURL)
+			return err
+		}
+
+		newConn := newEndpointConn(httpURL, target, s.pool, s.queue, s.logger)
+		if err := newConn.connect(s.ctx); err != nil {
+			s.markUnsupported(httpURL)
+			return fmt.Errorf("endpoint %s does not support websocket subscriptions: %w", httpURL, err)
+		}
+
+		s.mu.Lock()
+		if existing, raced := s.conns[httpURL]; raced {
+			ec = existing
+		} else {
+			s.conns[httpURL] = newConn
+			ec = newConn
+			go ec.run(s.ctx)
+		}
+		s.mu.Unlock()
+	}
+
+	if err := ec.subscribe(wallet); err != nil {
+		return fmt.Errorf("failed to subscribe %s on %s: %w", wallet, httpURL, err)
+	}
+
+	s.mu.Lock()
+	s.walletConn[wallet] = httpURL
+	s.mu.Unlock()
+
+	metrics.SetWSSubscriptionsActive(s.activeCount())
+	return nil
+}
+
+// Unsubscribe removes wallet's websocket subscription, if it has one. It's
+// a no-op if wallet was never subscribed or was already removed.
+func (s *Subscriber) Unsubscribe(wallet string) error {
+	s.mu.Lock()
+	httpURL, ok := s.walletConn[wallet]
+	if !ok {
+		s.mu.Unlock()
+		return nil
+	}
+	delete(s.walletConn, wallet)
+	ec := s.conns[httpURL]
+	s.mu.Unlock()
+
+	metrics.SetWSSubscriptionsActive(s.activeCount())
+
+	if ec == nil {
+		return nil
+	}
+	return ec.unsubscribe(wallet)
+}
+
+// Close tears down every websocket connection Subscriber holds open.
+func (s *Subscriber) Close() {
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ec := range s.conns {
+		ec.mu.Lock()
+		if ec.conn != nil {
+			ec.conn.Close()
+		}
+		ec.mu.Unlock()
+	}
+}
+
+func (s *Subscriber) markUnsupported(httpURL string) {
+	s.mu.Lock()
+	s.unsupported[httpURL] = true
+	s.mu.Unlock()
+}
+
+func (s *Subscriber) activeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.walletConn)
+}
+
+// endpointConn owns one websocket connection to a single endpoint, plus
+// every wallet subscription active on it. Only its own run goroutine reads
+// from the connection, so subscribe/unsubscribe/reconnect-time resubscribe
+// just need to coordinate through mu for the subscription bookkeeping and
+// conn pointer swap.
+type endpointConn struct {
+	httpURL string // the Pool-tracked URL, for RecordFailure/RecordSuccess/SetCooldown
+	wsURL   string // the actual websocket dial target
+	pool    *rpc.Pool
+	queue   *queue.Client
+	logger  zerolog.Logger
+
+	reconnect *backoff.Tracker
+
+	mu       sync.Mutex
+	conn     *Conn
+	nextID   int64
+	pending  map[int64]string // outstanding logsSubscribe request id -> wallet
+	subs     map[int64]string // server subscription id -> wallet
+	byWallet map[string]int64 // wallet -> server subscription id
+}
+
+func newEndpointConn(httpURL, wsURL string, pool *rpc.Pool, queueClient *queue.Client, logger zerolog.Logger) *endpointConn {
+	return &endpointConn{
+		httpURL:   httpURL,
+		wsURL:     wsURL,
+		pool:      pool,
+		queue:     queueClient,
+		logger:    logger.With().Str("endpoint", httpURL).Logger(),
+		reconnect: backoff.NewTracker(backoff.ExponentialWithJitter{Base: time.Second, Max: 60 * time.Second}),
+		pending:   make(map[int64]string),
+		subs:      make(map[int64]string),
+		byWallet:  make(map[string]int64),
+	}
+}
+
+// connect dials the websocket and, if this is a reconnect, re-issues
+// logsSubscribe for every wallet that was subscribed before the drop
+// (subscription ids aren't preserved across a fresh connection).
+func (ec *endpointConn) connect(ctx context.Context) error {
+	conn, err := Dial(ctx, ec.wsURL)
+	if err != nil {
+		return err
+	}
+
+	ec.mu.Lock()
+	ec.conn = conn
+	wallets := make([]string, 0, len(ec.byWallet))
+	for wallet := range ec.byWallet {
+		wallets = append(wallets, wallet)
+	}
+	ec.pending = make(map[int64]string)
+	ec.subs = make(map[int64]string)
+	ec.byWallet = make(map[string]int64)
+	ec.mu.Unlock()
+
+	for _, wallet := range wallets {
+		if err := ec.subscribe(wallet); err != nil {
+			ec.logger.Warn().Err(err).Str("wallet", wallet).Msg("Failed to resubscribe wallet after reconnect")
+		}
+	}
+
+	return nil
+}
+
+// run drives reconnect-with-backoff and message handling for as long as
+// ctx is alive. The caller must already have a live connection (from the
+// initial connect in Subscriber.Subscribe) before starting this.
+func (ec *endpointConn) run(ctx context.Context) {
+	first := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if !first {
+			if err := ec.connect(ctx); err != nil {
+				ec.logger.Warn().Err(err).Msg("Failed to reconnect websocket, will retry")
+				ec.pool.RecordFailure(ec.httpURL)
+				if sleepErr := backoff.Sleep(ctx, ec.reconnect.Next()); sleepErr != nil {
+					return
+				}
+				continue
+			}
+			metrics.RecordWSReconnect(ec.httpURL)
+		}
+		first = false
+
+		ec.reconnect.Succeed()
+		ec.pool.RecordSuccess(ec.httpURL)
+
+		ec.readUntilError(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		ec.logger.Warn().Msg("Websocket connection dropped, reconnecting")
+	}
+}
+
+// readUntilError pumps messages off the connection until it errors out or
+// ctx is canceled, dispatching each to handleMessage.
+func (ec *endpointConn) readUntilError(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		ec.mu.Lock()
+		conn := ec.conn
+		ec.mu.Unlock()
+
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			ec.pool.RecordFailure(ec.httpURL)
+			return
+		}
+		ec.handleMessage(msg)
+	}
+}
+
+// handleMessage routes one decoded websocket frame to either the
+// subscribe/unsubscribe response path or the notification path, based on
+// whether it carries a "method" (notification) or not (response).
+func (ec *endpointConn) handleMessage(raw []byte) {
+	var probe struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		ec.logger.Warn().Err(err).Msg("Failed to decode websocket message")
+		return
+	}
+
+	if probe.Method == "logsNotification" {
+		var notif logsNotificationMessage
+		if err := json.Unmarshal(raw, &notif); err != nil {
+			ec.logger.Warn().Err(err).Msg("Failed to decode logsNotification")
+			return
+		}
+
+		ec.mu.Lock()
+		wallet, ok := ec.subs[notif.Params.Subscription]
+		ec.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		metrics.RecordWSNotification(ec.httpURL)
+
+		sig := notif.Params.Result.Value.Signature
+		ec.logger.Debug().Str("wallet", wallet).Str("signature", sig).Msg("Received log notification")
+
+		if err := ec.queue.PushWallet(context.Background(), wallet, subscribePriority); err != nil {
+			ec.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to push wallet after websocket notification")
+		}
+		return
+	}
+
+	var resp subscribeMessage
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		ec.logger.Warn().Err(err).Msg("Failed to decode subscribe response")
+		return
+	}
+
+	ec.mu.Lock()
+	wallet, ok := ec.pending[resp.ID]
+	if ok {
+		delete(ec.pending, resp.ID)
+	}
+	ec.mu.Unlock()
+	if !ok {
+		// Most likely an unsubscribe ack; nothing more to do with those.
+		return
+	}
+
+	subID, ok := toInt64(resp.Result)
+	if !ok {
+		ec.logger.Warn().Str("wallet", wallet).Interface("result", resp.Result).Msg("logsSubscribe response did not contain a subscription id")
+		return
+	}
+
+	ec.mu.Lock()
+	ec.subs[subID] = wallet
+	ec.byWallet[wallet] = subID
+	ec.mu.Unlock()
+}
+
+// toInt64 converts a decoded JSON number (always float64 via
+// encoding/json's default unmarshaling into interface{}) into an int64
+// subscription id.
+func toInt64(v interface{}) (int64, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// subscribe sends a logsSubscribe request for wallet over ec's current
+// connection. The server's reply is handled asynchronously by
+// handleMessage once it arrives.
+func (ec *endpointConn) subscribe(wallet string) error {
+	ec.mu.Lock()
+	ec.nextID++
+	id := ec.nextID
+	ec.pending[id] = wallet
+	conn := ec.conn
+	ec.mu.Unlock()
+
+	req := subscribeMessage{
+		Jsonrpc: "2.0",
+		ID:      id,
+		Method:  "logsSubscribe",
+		Params: []interface{}{
+			map[string]interface{}{"mentions": []string{wallet}},
+			map[string]interface{}{"commitment": "confirmed"},
+		},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal logsSubscribe request: %w", err)
+	}
+	if err := conn.WriteMessage(body); err != nil {
+		return fmt.Errorf("failed to send logsSubscribe request: %w", err)
+	}
+	return nil
+}
+
+// unsubscribe sends a logsUnsubscribe request for wallet's current
+// subscription, if it has one, and removes it from local bookkeeping
+// immediately rather than waiting for the server's ack - a stray
+// notification that arrives for the now-forgotten id is silently dropped
+// by handleMessage.
+func (ec *endpointConn) unsubscribe(wallet string) error {
+	ec.mu.Lock()
+	subID, ok := ec.byWallet[wallet]
+	if !ok {
+		ec.mu.Unlock()
+		return nil
+	}
+	delete(ec.byWallet, wallet)
+	delete(ec.subs, subID)
+	ec.nextID++
+	id := ec.nextID
+	conn := ec.conn
+	ec.mu.Unlock()
+
+	req := subscribeMessage{
+		Jsonrpc: "2.0",
+		ID:      id,
+		Method:  "logsUnsubscribe",
+		Params:  []interface{}{subID},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal logsUnsubscribe request: %w", err)
+	}
+	if err := conn.WriteMessage(body); err != nil {
+		return fmt.Errorf("failed to send logsUnsubscribe request: %w", err)
+	}
+	return nil
+}
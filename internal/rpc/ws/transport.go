@@ -0,0 +1,188 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/wnt/mercon/internal/rpc"
+)
+
+// transport implements rpc.Transport over a single generic websocket
+// connection. Unlike Subscriber/endpointConn, it isn't wallet-keyed and
+// doesn't reconnect or resubscribe on its own - a dropped connection closes
+// every notification channel it owns and the caller (e.g.
+// SubscribeSignatures) decides whether to open a fresh one.
+// It supports any Solana PubSub subscribe method (logsSubscribe,
+// accountSubscribe, signatureSubscribe, ...), not just logs.
+type transport struct {
+	httpURL string // the Pool-tracked URL, for RecordFailure/RecordSuccess
+	pool    *rpc.Pool
+	conn    *Conn
+
+	mu      sync.Mutex
+	closed  bool
+	nextID  int64
+	pending map[int64]chan int64
+	subs    map[int64]chan json.RawMessage
+}
+
+// NewTransport dials a websocket connection to httpURL's ws(s) counterpart
+// and returns it wrapped as an rpc.Transport. Call is unsupported - Solana's
+// PubSub protocol only subscribes and unsubscribes over a websocket, it
+// doesn't answer arbitrary JSON-RPC calls - so callers should keep using an
+// rpc.NewHTTPTransport for anything that isn't one of the subscribe
+// methods rpc.RouteTransportKind routes here.
+func NewTransport(ctx context.Context, pool *rpc.Pool, httpURL string) (rpc.Transport, error) {
+	target, err := wsURL(httpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := Dial(ctx, target)
+	if err != nil {
+		pool.RecordFailure(httpURL)
+		return nil, fmt.Errorf("endpoint %s does not support websocket subscriptions: %w", httpURL, err)
+	}
+
+	t := &transport{
+		httpURL: httpURL,
+		pool:    pool,
+		conn:    conn,
+		pending: make(map[int64]chan int64),
+		subs:    make(map[int64]chan json.RawMessage),
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+func (t *transport) Call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	return nil, fmt.Errorf("method %q is a plain RPC call, not supported over a websocket subscription transport", method)
+}
+
+// Subscribe sends a PubSub subscribe request (method is e.g.
+// "logsSubscribe", "accountSubscribe") and, once the server acknowledges it
+// with a subscription id, returns a channel of that subscription's raw
+// notification results. The channel is buffered so a slow consumer doesn't
+// stall readLoop; once full, further notifications for that subscription
+// are dropped rather than blocking every other subscription on the same
+// connection.
+func (t *transport) Subscribe(ctx context.Context, method string, params []interface{}) (<-chan json.RawMessage, error) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("websocket transport to %s is closed", t.httpURL)
+	}
+	t.nextID++
+	id := t.nextID
+	ack := make(chan int64, 1)
+	t.pending[id] = ack
+	t.mu.Unlock()
+
+	req := subscribeMessage{
+		Jsonrpc: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	if err := t.conn.WriteMessage(body); err != nil {
+		t.pool.RecordFailure(t.httpURL)
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case subID, ok := <-ack:
+		if !ok {
+			return nil, fmt.Errorf("websocket transport to %s closed while subscribing", t.httpURL)
+		}
+		notifications := make(chan json.RawMessage, 64)
+		t.mu.Lock()
+		t.subs[subID] = notifications
+		t.mu.Unlock()
+		return notifications, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close tears down the underlying connection and every notification channel
+// Subscribe has handed out. Safe to call more than once.
+func (t *transport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	for _, ch := range t.subs {
+		close(ch)
+	}
+	t.subs = nil
+	for _, ack := range t.pending {
+		close(ack)
+	}
+	t.pending = nil
+	t.mu.Unlock()
+	return t.conn.Close()
+}
+
+// readLoop pumps messages off the connection for as long as it stays open,
+// dispatching subscribe acks to their waiting Subscribe call and
+// notifications to their subscription's channel.
+func (t *transport) readLoop() {
+	for {
+		msg, err := t.conn.ReadMessage()
+		if err != nil {
+			t.pool.RecordFailure(t.httpURL)
+			t.Close()
+			return
+		}
+
+		var probe struct {
+			ID     int64       `json:"id,omitempty"`
+			Method string      `json:"method,omitempty"`
+			Result interface{} `json:"result,omitempty"`
+			Params struct {
+				Subscription int64           `json:"subscription"`
+				Result       json.RawMessage `json:"result"`
+			} `json:"params,omitempty"`
+		}
+		if err := json.Unmarshal(msg, &probe); err != nil {
+			continue
+		}
+
+		if probe.Method != "" {
+			t.mu.Lock()
+			ch, ok := t.subs[probe.Params.Subscription]
+			t.mu.Unlock()
+			if ok {
+				select {
+				case ch <- probe.Params.Result:
+				default:
+				}
+			}
+			continue
+		}
+
+		subID, ok := toInt64(probe.Result)
+		if !ok {
+			continue
+		}
+		t.mu.Lock()
+		ack, pending := t.pending[probe.ID]
+		delete(t.pending, probe.ID)
+		t.mu.Unlock()
+		if pending {
+			ack <- subID
+		}
+
+		t.pool.RecordSuccess(t.httpURL)
+	}
+}
+
+var _ rpc.Transport = (*transport)(nil)
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Cursor records how far a previous scan got for one wallet, so -resume can
+// pick up after LastSignature instead of re-fetching transaction details
+// for signatures already processed.
+type Cursor struct {
+	Wallet        string `json:"wallet"`
+	LastSignature string `json:"lastSignature"`
+	LastSlot      uint64 `json:"lastSlot"`
+}
+
+// LoadCursor reads a Cursor from path. A missing file is not an error - it
+// just means there's nothing to resume from yet - and returns a zero
+// Cursor for wallet.
+func LoadCursor(path, wallet string) (*Cursor, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Cursor{Wallet: wallet}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cursor %s: %w", path, err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("decoding cursor %s: %w", path, err)
+	}
+	if c.Wallet != wallet {
+		return &Cursor{Wallet: wallet}, nil
+	}
+	return &c, nil
+}
+
+// Advance updates the cursor to reflect the last transaction emitted.
+func (c *Cursor) Advance(signature string, slot uint64) {
+	c.LastSignature = signature
+	c.LastSlot = slot
+}
+
+// Save writes the cursor to path as JSON.
+func (c *Cursor) Save(path string) error {
+	encoded, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cursor: %w", err)
+	}
+	if err := os.WriteFile(path, append(encoded, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing cursor %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Package scanner streams a wallet's DAMM/DLMM activity as a channel of
+// ParsedTx instead of rendering it straight to a log file, so cmd/simple_run
+// can hand scan results to pluggable sinks (internal/sink) - NDJSON, CSV,
+// Parquet, or the original human-readable output - rather than being tied
+// to one output format.
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/metfin/core/parsers/damm"
+	"github.com/metfin/core/parsers/dlmm"
+)
+
+// Instruction is a parsed DAMM or DLMM instruction, independent of
+// damm/dlmm's own result types so sinks don't need to import an
+// unpublished package just to render one.
+type Instruction struct {
+	Type   string      `json:"type"`
+	Parsed interface{} `json:"parsed,omitempty"`
+}
+
+// ParsedTx is one transaction's worth of Meteora activity. Err is set
+// (with every other field left at its zero value except Signature) when
+// the transaction couldn't be decoded or parsed, so a sink can report the
+// failure instead of the scan silently dropping it. When the bulk fetch
+// itself fails, a single ParsedTx carrying only Err is sent, with an empty
+// Signature.
+type ParsedTx struct {
+	Signature string        `json:"signature"`
+	Slot      uint64        `json:"slot"`
+	BlockTime time.Time     `json:"blockTime"`
+	DAMM      []Instruction `json:"damm,omitempty"`
+	DLMM      []Instruction `json:"dlmm,omitempty"`
+	Err       error         `json:"-"`
+}
+
+// WalletSource is the subset of github.com/metfin/external/service's
+// ExternalService that Scanner needs. It's declared here, rather than
+// imported from that package, so Scanner doesn't depend on the rest of
+// ExternalService's (much larger) surface - any implementation with these
+// two methods works, which is exactly what externalServices.ExternalService
+// already satisfies.
+type WalletSource interface {
+	GetWalletTransactionSignatures(ctx context.Context, wallet string, limit int) ([]string, error)
+	GetTransactionsInBulk(ctx context.Context, signatures []string) ([]*rpc.GetTransactionResult, error)
+}
+
+// Since filters out transactions older than a slot or a block time. Zero
+// values mean "no filter" for that field; if both are set a transaction
+// must pass both.
+type Since struct {
+	Slot      uint64
+	Timestamp time.Time
+}
+
+func (s *Since) matches(tx ParsedTx) bool {
+	if s == nil {
+		return true
+	}
+	if s.Slot != 0 && tx.Slot < s.Slot {
+		return false
+	}
+	if !s.Timestamp.IsZero() && tx.BlockTime.Before(s.Timestamp) {
+		return false
+	}
+	return true
+}
+
+// Options configures one Stream call.
+type Options struct {
+	// Limit is the maximum number of signatures to fetch for the wallet.
+	Limit int
+	// Since, if set, drops transactions older than it. It's applied after
+	// fetching, not pushed into GetWalletTransactionSignatures - the
+	// external service's interface has no such parameter, so this can't
+	// yet save the RPC work of fetching already-filtered signatures, only
+	// the work of decoding/parsing them.
+	Since *Since
+	// Resume, if non-nil, skips every signature at or before
+	// Resume.LastSignature in the freshly fetched signature list before
+	// fetching transaction details, so a restarted scan doesn't redo the
+	// expensive bulk transaction fetch for signatures it already emitted.
+	Resume *Cursor
+}
+
+// Scanner streams Meteora activity for a wallet from Source.
+type Scanner struct {
+	Source WalletSource
+}
+
+// New returns a Scanner backed by source.
+func New(source WalletSource) *Scanner {
+	return &Scanner{Source: source}
+}
+
+// Stream fetches wallet's transaction signatures and streams each one's
+// parsed DAMM/DLMM activity on the returned channel. The channel is closed
+// when every fetched transaction has been sent or ctx is cancelled.
+func (s *Scanner) Stream(ctx context.Context, wallet string, opts Options) (<-chan ParsedTx, error) {
+	signatures, err := s.Source.GetWalletTransactionSignatures(ctx, wallet, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signatures for %s: %w", wallet, err)
+	}
+
+	if opts.Resume != nil && opts.Resume.LastSignature != "" {
+		if idx := indexOf(signatures, opts.Resume.LastSignature); idx >= 0 {
+			signatures = signatures[idx+1:]
+		}
+	}
+
+	out := make(chan ParsedTx)
+	go s.run(ctx, signatures, opts, out)
+	return out, nil
+}
+
+func (s *Scanner) run(ctx context.Context, signatures []string, opts Options, out chan<- ParsedTx) {
+	defer close(out)
+
+	if len(signatures) == 0 {
+		return
+	}
+
+	results, err := s.Source.GetTransactionsInBulk(ctx, signatures)
+	if err != nil {
+		send(ctx, out, ParsedTx{Err: fmt.Errorf("fetching transactions: %w", err)})
+		return
+	}
+
+	for i, txResult := range results {
+		if ctx.Err() != nil {
+			return
+		}
+		if txResult == nil || txResult.Transaction == nil {
+			continue
+		}
+
+		parsed, err := parse(signatures[i], txResult)
+		if err != nil {
+			if !send(ctx, out, ParsedTx{Signature: signatures[i], Err: err}) {
+				return
+			}
+			continue
+		}
+
+		if !opts.Since.matches(parsed) {
+			continue
+		}
+
+		if !send(ctx, out, parsed) {
+			return
+		}
+	}
+}
+
+func parse(signature string, txResult *rpc.GetTransactionResult) (ParsedTx, error) {
+	tx, err := txResult.Transaction.GetTransaction()
+	if err != nil {
+		return ParsedTx{}, fmt.Errorf("decoding transaction: %w", err)
+	}
+
+	parsed := ParsedTx{Signature: signature}
+	if txResult.Slot != 0 {
+		parsed.Slot = txResult.Slot
+	}
+	if txResult.BlockTime != nil {
+		parsed.BlockTime = time.Unix(int64(*txResult.BlockTime), 0)
+	}
+
+	dammInstructions, err := damm.ParseDAMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
+	if err != nil {
+		return ParsedTx{}, fmt.Errorf("parsing DAMM instructions: %w", err)
+	}
+	for _, inst := range dammInstructions {
+		parsed.DAMM = append(parsed.DAMM, Instruction{Type: inst.Type, Parsed: inst.Parsed})
+	}
+
+	dlmmInstructions, err := dlmm.ParseDLMMTransaction(tx.Message.Instructions, tx.Message.AccountKeys)
+	if err != nil {
+		return ParsedTx{}, fmt.Errorf("parsing DLMM instructions: %w", err)
+	}
+	for _, inst := range dlmmInstructions {
+		parsed.DLMM = append(parsed.DLMM, Instruction{Type: inst.Type, Parsed: inst.Parsed})
+	}
+
+	return parsed, nil
+}
+
+func send(ctx context.Context, out chan<- ParsedTx, tx ParsedTx) bool {
+	select {
+	case out <- tx:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func indexOf(signatures []string, target string) int {
+	for i, sig := range signatures {
+		if sig == target {
+			return i
+		}
+	}
+	return -1
+}
@@ -8,7 +8,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/wnt/mercon/internal/events"
 	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/parserstore"
+	"github.com/wnt/mercon/internal/reorg"
 	"github.com/wnt/mercon/internal/services"
 	"github.com/wnt/mercon/internal/solana"
 	"gorm.io/gorm"
@@ -28,7 +31,10 @@ type Scraper struct {
 	db             *gorm.DB
 	solanaClient   *solana.Client
 	txParser       *solana.TransactionParser
+	parserStore    parserstore.Store
+	reorgManager   *reorg.Manager
 	dataEnricher   *services.MeteoraDataEnricher
+	publisher      events.Publisher
 	maxConcurrent  int
 	requestTimeout time.Duration
 }
@@ -53,8 +59,20 @@ func NewScraper(db *gorm.DB) (*Scraper, error) {
 
 	config := loadConfigFromEnv()
 
+	// Persist pairs/positions/wallets/rewards the parser encounters to the
+	// same database the scraper writes transactions to, with an LRU +
+	// singleflight in front so concurrent transactions for the same pair
+	// don't stampede it.
+	parserStore := parserstore.NewCachedStore(parserstore.NewGormStore(db), parserstore.DefaultCacheSize)
+
 	// Create transaction parser
-	txParser := solana.NewTransactionParser(solanaClient)
+	var parserOpts []solana.TransactionParserOption
+	if registry, err := solana.NewDefaultInstructionRegistry(); err != nil {
+		fmt.Printf("Warning: failed to load Meteora DLMM IDL, swap fee/bin fields will stay zeroed: %v\n", err)
+	} else {
+		parserOpts = append(parserOpts, solana.WithInstructionRegistry(registry))
+	}
+	txParser := solana.NewTransactionParser(solanaClient, parserStore, parserOpts...)
 
 	// Create data enricher
 	dataEnricher := services.NewMeteoraDataEnricher(db)
@@ -63,7 +81,10 @@ func NewScraper(db *gorm.DB) (*Scraper, error) {
 		db:             db,
 		solanaClient:   solanaClient,
 		txParser:       txParser,
+		parserStore:    parserStore,
+		reorgManager:   reorg.NewManager(),
 		dataEnricher:   dataEnricher,
+		publisher:      events.NewHTTPPublisher(db),
 		maxConcurrent:  config.MaxConcurrent,
 		requestTimeout: config.RequestTimeout,
 	}, nil
@@ -158,6 +179,10 @@ func (s *Scraper) RunWithContext(ctx context.Context) error {
 		// Continue with execution
 	}
 
+	if err := s.checkForReorg(ctx); err != nil {
+		fmt.Printf("Warning: reorg check failed: %v\n", err)
+	}
+
 	// Perform data enrichment
 	fmt.Println("Enriching data with USD values...")
 	s.enrichData()
@@ -181,7 +206,7 @@ func (s *Scraper) getOrCreateWallet(ctx context.Context, address string) (*model
 // fetchTransactions retrieves transactions for the specified wallet
 func (s *Scraper) fetchTransactions(ctx context.Context, walletAddress string) ([]*models.Transaction, error) {
 	fmt.Printf("Fetching transactions for wallet %s\n", walletAddress)
-	txs, err := s.solanaClient.GetAndParseTransactions(ctx, walletAddress, solana.Filters{})
+	txs, err := s.solanaClient.GetAndParseTransactions(ctx, walletAddress, solana.Filters{}, s.parserStore)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get and filter transactions: %w", err)
 	}
@@ -193,10 +218,14 @@ func (s *Scraper) fetchTransactions(ctx context.Context, walletAddress string) (
 func (s *Scraper) saveTransactionsAndUpdateWallet(wallet *models.Wallet, txs []*models.Transaction) error {
 	// Save the transactions to the database if there are any
 	if len(txs) > 0 {
-		if err := solana.SaveTransactions(s.db, wallet.ID, txs); err != nil {
+		if err := solana.SaveTransactions(s.db, wallet.ID, txs, s.publisher); err != nil {
 			return fmt.Errorf("failed to save transactions: %w", err)
 		}
 		fmt.Printf("Saved %d transactions to the database\n", len(txs))
+
+		for _, tx := range txs {
+			s.reorgManager.Observe(tx.Slot, tx.BlockHash)
+		}
 	}
 
 	// Update wallet record with last scraped time
@@ -210,6 +239,28 @@ func (s *Scraper) saveTransactionsAndUpdateWallet(wallet *models.Wallet, txs []*
 	return nil
 }
 
+// checkForReorg polls for a Solana fork among the slots this scraper has
+// observed transactions from, and if one is found, deletes the derived
+// Meteora rows it orphaned. It does not re-parse those transactions itself
+// - they fall back into range for the next scrape, which will re-fetch and
+// re-save them against the now-canonical chain.
+func (s *Scraper) checkForReorg(ctx context.Context) error {
+	poller := reorg.NewPoller(s.solanaClient, s.reorgManager)
+	event, err := poller.Poll(ctx)
+	if err != nil {
+		return fmt.Errorf("polling for reorg: %w", err)
+	}
+	if event == nil {
+		return nil
+	}
+
+	fmt.Printf("Detected Solana reorg from slot %d to %d, rolling back derived rows\n", event.FromSlot, event.ToSlot)
+	if err := reorg.DeleteOrphanedRows(ctx, s.db, *event); err != nil {
+		return fmt.Errorf("deleting orphaned rows for reorg [%d,%d]: %w", event.FromSlot, event.ToSlot, err)
+	}
+	return nil
+}
+
 // enrichData performs data enrichment for all entities
 func (s *Scraper) enrichData() {
 	// Enrich pairs with USD values
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DEXCapability is a bitmask flag describing one optional operation or
+// market feature a DEXClient backend supports, so code that works across
+// multiple protocols (analytics, metrics) can gate behavior per backend
+// instead of type-switching on concrete client types.
+type DEXCapability uint32
+
+const (
+	// CapConcentratedLiquidity means pairs expose bin/tick-concentrated
+	// liquidity rather than a flat constant-product pool.
+	CapConcentratedLiquidity DEXCapability = 1 << iota
+	// CapFarmingRewards means positions/pairs can carry separate farming
+	// reward emissions on top of swap fees.
+	CapFarmingRewards
+	// CapQuotes means Quote is backed by a real pricing endpoint.
+	CapQuotes
+	// CapSwapHistory means ListSwaps is backed by a real history endpoint.
+	CapSwapHistory
+)
+
+// Has reports whether c includes every bit set in other.
+func (c DEXCapability) Has(other DEXCapability) bool {
+	return c&other == other
+}
+
+// ErrCapabilityNotSupported is returned by a DEXClient method whose
+// operation isn't backed by that client's upstream API. Check
+// Capabilities() before calling to avoid it.
+var ErrCapabilityNotSupported = errors.New("dex client: capability not supported by this backend")
+
+// PairFilter narrows ListPairs; the zero value lists every pair.
+type PairFilter struct {
+	IncludeUnknown *bool
+}
+
+// DEXSwap is a protocol-agnostic view of one swap, returned by ListSwaps.
+type DEXSwap struct {
+	TxID         string
+	Pair         string
+	TokenInMint  string
+	TokenOutMint string
+	AmountIn     uint64
+	AmountOut    uint64
+	Timestamp    time.Time
+}
+
+// DEXQuote is a protocol-agnostic swap quote, returned by Quote.
+type DEXQuote struct {
+	AmountOut   uint64
+	PriceImpact float64
+}
+
+// DEXClient abstracts the handful of read operations mercon needs from a
+// DEX/AMM's public API - listing/fetching pairs, positions, swaps, and
+// quotes - so analytics and metrics code can work uniformly across
+// protocols instead of depending on MeteoraPubClient directly.
+// Capabilities lets a caller check what a given backend actually supports
+// before calling an operation it might not implement; a method called
+// despite an unset capability bit returns ErrCapabilityNotSupported rather
+// than guessing at a reasonable-looking response.
+type DEXClient interface {
+	// Capabilities reports which optional operations/features this
+	// backend supports.
+	Capabilities() DEXCapability
+
+	ListPairs(ctx context.Context, filter PairFilter) ([]PairInfo, error)
+	GetPair(ctx context.Context, address string) (*PairInfo, error)
+	ListPositions(ctx context.Context, owner string) ([]Position, error)
+	GetPositionSnapshot(ctx context.Context, address string) (*PositionSnapshot, error)
+	ListSwaps(ctx context.Context, pair string, since time.Time) ([]DEXSwap, error)
+	Quote(ctx context.Context, tokenIn, tokenOut string, amountIn uint64) (*DEXQuote, error)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// pairEnrichment is the per-pair work an EnrichmentSnapshot has already done:
+// the fetched/aggregated pair and the cache entry it would install on
+// commit, shared across every swap/claim in the same snapshot that touches
+// that pair so they don't each refetch and re-aggregate independently.
+type pairEnrichment struct {
+	pair       *models.MeteoraPair
+	cacheEntry pairPriceData
+}
+
+// EnrichmentSnapshot buffers the writes made while enriching a single
+// transaction (or a single pair, for the periodic sweep) so they can be
+// committed or rolled back as a unit. It wraps the *gorm.DB transaction the
+// caller already opened; Save calls go through the snapshot so a failure
+// partway through a multi-entity enrichment doesn't leave the DB with a
+// half-updated USD snapshot.
+type EnrichmentSnapshot struct {
+	tx *gorm.DB
+
+	pairs   map[uint]*pairEnrichment
+	touched []string // "pair:5", "swap:12", ... rows written this snapshot, for diagnostics
+	stage   string   // the enrichment stage currently in progress
+}
+
+// newEnrichmentSnapshot opens a snapshot over an already-started transaction.
+func newEnrichmentSnapshot(tx *gorm.DB) *EnrichmentSnapshot {
+	return &EnrichmentSnapshot{
+		tx:    tx,
+		pairs: make(map[uint]*pairEnrichment),
+	}
+}
+
+// setStage records which enrichment stage is in progress, so a failure can
+// be reported (and the transaction marked enrichment_failed) with the stage
+// that broke rather than a bare error.
+func (s *EnrichmentSnapshot) setStage(stage string) {
+	s.stage = stage
+}
+
+// save writes value through the snapshot's transaction and records it as
+// touched. Everything written this way is rolled back automatically if the
+// enclosing db.Transaction returns an error.
+func (s *EnrichmentSnapshot) save(key string, value interface{}) error {
+	if err := s.tx.Save(value).Error; err != nil {
+		return err
+	}
+	s.touched = append(s.touched, key)
+	return nil
+}
+
+// pairCacheEntry returns the pair and price-cache entry this snapshot
+// already computed for pairID, if any nested enrichment call already fetched
+// and aggregated it.
+func (s *EnrichmentSnapshot) pairCacheEntry(pairID uint) (*pairEnrichment, bool) {
+	pe, ok := s.pairs[pairID]
+	return pe, ok
+}
+
+// rememberPair records the pair and cache entry this snapshot computed, so
+// later calls within the same snapshot reuse it instead of racing through
+// another oracle fetch.
+func (s *EnrichmentSnapshot) rememberPair(pair *models.MeteoraPair, entry pairPriceData) {
+	s.pairs[pair.ID] = &pairEnrichment{pair: pair, cacheEntry: entry}
+}
+
+// Merge installs every pair price this snapshot computed into the
+// enricher's shared cache. Call it once, after the snapshot's transaction
+// has committed successfully, so concurrent PostProcessTransaction calls for
+// swaps in the same DB transaction don't race the cache through e.mutex
+// mid-flight.
+func (e *MeteoraDataEnricher) Merge(s *EnrichmentSnapshot) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, pe := range s.pairs {
+		e.pairPriceCache.Set(pe.pair.Address, pe.cacheEntry)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+)
+
+// TestSnapshotReusesRememberedPair verifies that a pair enriched once within
+// a snapshot is served back to later callers in the same snapshot instead of
+// triggering another fetch.
+func TestSnapshotReusesRememberedPair(t *testing.T) {
+	snapshot := newEnrichmentSnapshot(nil)
+
+	pair := &models.MeteoraPair{Address: "PairA"}
+	pair.ID = 7
+	entry := pairPriceData{price: 1.5, lastUpdate: time.Now()}
+	snapshot.rememberPair(pair, entry)
+
+	cached, ok := snapshot.pairCacheEntry(7)
+	if !ok {
+		t.Fatal("expected pair 7 to be cached in the snapshot")
+	}
+	if cached.pair.Address != "PairA" || cached.cacheEntry.price != 1.5 {
+		t.Errorf("unexpected cached entry: %+v", cached)
+	}
+
+	if _, ok := snapshot.pairCacheEntry(8); ok {
+		t.Error("expected no cache entry for a pair never enriched in this snapshot")
+	}
+}
+
+// TestMergeInstallsSnapshotPrices verifies that Merge copies every pair price
+// the snapshot computed into the enricher's shared cache in one shot.
+func TestMergeInstallsSnapshotPrices(t *testing.T) {
+	enricher := &MeteoraDataEnricher{
+		pairPriceCache: newPairPriceCache(defaultPriceCacheTTL, defaultPriceCacheMaxEntries),
+	}
+
+	snapshot := newEnrichmentSnapshot(nil)
+	pairA := &models.MeteoraPair{Address: "PairA"}
+	pairA.ID = 1
+	pairB := &models.MeteoraPair{Address: "PairB"}
+	pairB.ID = 2
+	snapshot.rememberPair(pairA, pairPriceData{price: 1.0})
+	snapshot.rememberPair(pairB, pairPriceData{price: 2.0})
+
+	enricher.Merge(snapshot)
+
+	cachedA, ok := enricher.pairPriceCache.Get("PairA")
+	if !ok || cachedA.price != 1.0 {
+		t.Errorf("expected PairA price 1.0, got %f (ok=%v)", cachedA.price, ok)
+	}
+	cachedB, ok := enricher.pairPriceCache.Get("PairB")
+	if !ok || cachedB.price != 2.0 {
+		t.Errorf("expected PairB price 2.0, got %f (ok=%v)", cachedB.price, ok)
+	}
+}
@@ -10,14 +10,31 @@ type MeteoraPubClient struct {
 	httpClient *utils.HTTPClient
 }
 
+// MeteoraPubClientOption configures a MeteoraPubClient at construction time.
+type MeteoraPubClientOption func(*[]utils.HTTPClientOption)
+
+// WithHTTPClientOptions passes additional utils.HTTPClientOption values
+// through to the underlying utils.HTTPClient, e.g. utils.WithObserver to
+// wire up request/retry/status metrics (see internal/meteorametrics).
+func WithHTTPClientOptions(opts ...utils.HTTPClientOption) MeteoraPubClientOption {
+	return func(httpOpts *[]utils.HTTPClientOption) {
+		*httpOpts = append(*httpOpts, opts...)
+	}
+}
+
 // NewMeteoraPubClient creates a new client for the Meteora public API
-func NewMeteoraPubClient() *MeteoraPubClient {
+func NewMeteoraPubClient(opts ...MeteoraPubClientOption) *MeteoraPubClient {
+	httpOpts := []utils.HTTPClientOption{
+		utils.WithDefaultHeaders(map[string]string{
+			"Content-Type": "application/json",
+		}),
+	}
+	for _, opt := range opts {
+		opt(&httpOpts)
+	}
+
 	return &MeteoraPubClient{
-		httpClient: utils.NewHTTPClient(
-			utils.WithDefaultHeaders(map[string]string{
-				"Content-Type": "application/json",
-			}),
-		),
+		httpClient: utils.NewHTTPClient(httpOpts...),
 	}
 }
 
@@ -241,9 +258,9 @@ func (c *MeteoraPubClient) GetDeposits(positionAddress string) ([]DepositWithdra
 
 // GetAllPairs fetches all pairs
 func (c *MeteoraPubClient) GetAllPairs(includeUnknown *bool) ([]PairInfo, error) {
-	queryParams := make(map[string]string)
+	queryParams := make(utils.OptionalParameter)
 	if includeUnknown != nil {
-		queryParams["include_unknown"] = fmt.Sprintf("%t", *includeUnknown)
+		queryParams["include_unknown"] = *includeUnknown
 	}
 
 	response, err := c.httpClient.Get("/pair/all", queryParams, nil)
@@ -259,27 +276,14 @@ func (c *MeteoraPubClient) GetAllPairs(includeUnknown *bool) ([]PairInfo, error)
 	return pairs, nil
 }
 
-// GetAllPairsByGroups fetches all pairs grouped by token pairs
+// GetAllPairsByGroups fetches all pairs grouped by token pairs. A []string
+// value in params (e.g. "tags" or "pair_addresses") is sent as repeated
+// query keys rather than collapsed to its first element, since
+// utils.OptionalParameter.Encode handles that natively.
 func (c *MeteoraPubClient) GetAllPairsByGroups(params map[string]interface{}) (*AllGroupOfPairs, error) {
-	queryParams := make(map[string]string)
-
+	queryParams := make(utils.OptionalParameter, len(params))
 	for key, value := range params {
-		switch v := value.(type) {
-		case string:
-			queryParams[key] = v
-		case int:
-			queryParams[key] = fmt.Sprintf("%d", v)
-		case float64:
-			queryParams[key] = fmt.Sprintf("%f", v)
-		case bool:
-			queryParams[key] = fmt.Sprintf("%t", v)
-		case []string:
-			// This is a bit tricky with our current HTTP client
-			// For simplicity, we'll just take the first value for now
-			if len(v) > 0 {
-				queryParams[key] = v[0]
-			}
-		}
+		queryParams[key] = value
 	}
 
 	response, err := c.httpClient.Get("/pair/all_by_groups", queryParams, nil)
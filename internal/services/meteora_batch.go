@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// defaultBatchWorkers bounds how many MeteoraPubClient calls a
+	// BatchClient issues concurrently.
+	defaultBatchWorkers = 8
+	// defaultBatchCacheTTL is how long a BatchClient caches a fetched value
+	// before a later call for the same key hits MeteoraPubClient again.
+	defaultBatchCacheTTL = 30 * time.Second
+)
+
+// BatchClientOption configures a BatchClient at construction time.
+type BatchClientOption func(*BatchClient)
+
+// WithBatchWorkers bounds how many MeteoraPubClient calls a BatchClient
+// issues concurrently.
+func WithBatchWorkers(workers int) BatchClientOption {
+	return func(b *BatchClient) {
+		b.workers = workers
+	}
+}
+
+// WithBatchCacheTTL overrides how long a BatchClient caches a fetched value.
+func WithBatchCacheTTL(ttl time.Duration) BatchClientOption {
+	return func(b *BatchClient) {
+		b.ttl = ttl
+	}
+}
+
+// BatchClient wraps MeteoraPubClient for bulk fetches over many
+// positions/pairs at once. Work is spread across a bounded worker pool
+// (WithBatchWorkers, default defaultBatchWorkers) so one scrape can't open
+// an unbounded number of upstream connections; concurrent callers asking
+// for the same (endpoint, address) share one upstream call via
+// singleflight.Group, the same coalescing shape parserstore.keyedCache uses
+// for its LRUs; and a short TTL cache (WithBatchCacheTTL, default
+// defaultBatchCacheTTL) absorbs successive scrapes of the same address
+// without re-hitting the API each time.
+type BatchClient struct {
+	client  *MeteoraPubClient
+	workers int
+	ttl     time.Duration
+
+	group singleflight.Group
+
+	mu    sync.Mutex
+	cache map[string]batchCacheEntry
+}
+
+type batchCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewBatchClient wraps client for bulk fetches.
+func NewBatchClient(client *MeteoraPubClient, opts ...BatchClientOption) *BatchClient {
+	b := &BatchClient{
+		client:  client,
+		workers: defaultBatchWorkers,
+		ttl:     defaultBatchCacheTTL,
+		cache:   make(map[string]batchCacheEntry),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// dedupedCall returns key's cached value if it hasn't expired yet,
+// otherwise calls fetch, coalescing concurrent callers for the same key
+// into a single call via singleflight.
+func (b *BatchClient) dedupedCall(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	b.mu.Lock()
+	if entry, ok := b.cache[key]; ok && time.Now().Before(entry.expiresAt) {
+		b.mu.Unlock()
+		return entry.value, nil
+	}
+	b.mu.Unlock()
+
+	value, err, _ := b.group.Do(key, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.cache[key] = batchCacheEntry{value: value, expiresAt: time.Now().Add(b.ttl)}
+	b.mu.Unlock()
+
+	return value, nil
+}
+
+// batchResult is one key's outcome from runBatch.
+type batchResult[T any] struct {
+	Value T
+	Err   error
+}
+
+// runBatch calls fn for each key on a pool of at most workers goroutines,
+// collecting every outcome into a map keyed by the original key. If ctx is
+// canceled, keys that haven't started yet are recorded with ctx.Err()
+// instead of being dropped; keys already in flight are left to finish,
+// since MeteoraPubClient's HTTP calls don't take a context to abort
+// mid-request.
+func runBatch[T any](ctx context.Context, workers int, keys []string, fn func(key string) (T, error)) map[string]batchResult[T] {
+	results := make(map[string]batchResult[T], len(keys))
+	var mu sync.Mutex
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		key := key
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				mu.Lock()
+				results[key] = batchResult[T]{Err: ctx.Err()}
+				mu.Unlock()
+				return
+			}
+			defer func() { <-sem }()
+
+			value, err := fn(key)
+			mu.Lock()
+			results[key] = batchResult[T]{Value: value, Err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// PositionResult is one address's outcome from GetPositions.
+type PositionResult struct {
+	Position *PositionWithApy
+	Err      error
+}
+
+// GetPositions fetches every address in addresses, fanned out across the
+// worker pool and deduplicated/cached per address. A failure for one
+// address is reported in its PositionResult rather than aborting the rest
+// of the batch.
+func (b *BatchClient) GetPositions(ctx context.Context, addresses []string) map[string]PositionResult {
+	raw := runBatch(ctx, b.workers, addresses, func(addr string) (*PositionWithApy, error) {
+		v, err := b.dedupedCall("position:"+addr, func() (interface{}, error) {
+			return b.client.GetPosition(addr)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return v.(*PositionWithApy), nil
+	})
+
+	out := make(map[string]PositionResult, len(raw))
+	for addr, r := range raw {
+		out[addr] = PositionResult{Position: r.Value, Err: r.Err}
+	}
+	return out
+}
+
+// PairResult is one address's outcome from GetPairs.
+type PairResult struct {
+	Pair *PairInfo
+	Err  error
+}
+
+// GetPairs fetches every address in addresses, fanned out across the
+// worker pool and deduplicated/cached per address. A failure for one
+// address is reported in its PairResult rather than aborting the rest of
+// the batch.
+func (b *BatchClient) GetPairs(ctx context.Context, addresses []string) map[string]PairResult {
+	raw := runBatch(ctx, b.workers, addresses, func(addr string) (*PairInfo, error) {
+		v, err := b.dedupedCall("pair:"+addr, func() (interface{}, error) {
+			return b.client.GetPair(addr)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return v.(*PairInfo), nil
+	})
+
+	out := make(map[string]PairResult, len(raw))
+	for addr, r := range raw {
+		out[addr] = PairResult{Pair: r.Value, Err: r.Err}
+	}
+	return out
+}
+
+// PositionSnapshot is a unified view of one position fetched from several
+// MeteoraPubClient endpoints at once.
+type PositionSnapshot struct {
+	Position  *Position
+	ClaimFees []ClaimFee
+	Deposits  []DepositWithdraw
+	Withdraws []DepositWithdraw
+}
+
+// SnapshotPosition fans GetPositionV2, GetClaimFees, GetDeposits, and
+// GetWithdraws for address out in parallel and combines them into one
+// PositionSnapshot. Each call is deduplicated/cached the same way
+// GetPositions/GetPairs are. It returns the first error encountered, if
+// any, rather than a partial snapshot.
+func (b *BatchClient) SnapshotPosition(address string) (*PositionSnapshot, error) {
+	var (
+		wg        sync.WaitGroup
+		position  *Position
+		claimFees []ClaimFee
+		deposits  []DepositWithdraw
+		withdraws []DepositWithdraw
+		errs      [4]error
+	)
+
+	fetchers := [4]func(){
+		func() {
+			v, err := b.dedupedCall("position_v2:"+address, func() (interface{}, error) {
+				return b.client.GetPositionV2(address)
+			})
+			errs[0] = err
+			if err == nil {
+				position = v.(*Position)
+			}
+		},
+		func() {
+			v, err := b.dedupedCall("claim_fees:"+address, func() (interface{}, error) {
+				return b.client.GetClaimFees(address)
+			})
+			errs[1] = err
+			if err == nil {
+				claimFees = v.([]ClaimFee)
+			}
+		},
+		func() {
+			v, err := b.dedupedCall("deposits:"+address, func() (interface{}, error) {
+				return b.client.GetDeposits(address)
+			})
+			errs[2] = err
+			if err == nil {
+				deposits = v.([]DepositWithdraw)
+			}
+		},
+		func() {
+			v, err := b.dedupedCall("withdraws:"+address, func() (interface{}, error) {
+				return b.client.GetWithdraws(address)
+			})
+			errs[3] = err
+			if err == nil {
+				withdraws = v.([]DepositWithdraw)
+			}
+		},
+	}
+
+	for _, fetch := range fetchers {
+		fetch := fetch
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetch()
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &PositionSnapshot{
+		Position:  position,
+		ClaimFees: claimFees,
+		Deposits:  deposits,
+		Withdraws: withdraws,
+	}, nil
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wnt/mercon/internal/models"
@@ -13,9 +15,31 @@ import (
 type MeteoraDataEnricher struct {
 	db        *gorm.DB
 	apiClient *MeteoraPubClient
+	oracles   *PriceOracleAggregator
 	mutex     sync.Mutex
 	// Cache price data to avoid excessive API calls
-	pairPriceCache map[string]pairPriceData
+	pairPriceCache *pairPriceCache
+	// priceHistory resolves a mint's USD price as of an arbitrary past
+	// BlockTime, for EnrichSwap/EnrichFeeClaim/EnrichLiquidityAddition/
+	// EnrichLiquidityRemoval. Nil when db is nil (the zero-DB construction
+	// path a couple of tests use), in which case those methods fall back to
+	// pair.CurrentPrice exactly as they did before this field existed.
+	priceHistory *PriceHistoryStore
+
+	// Running counters for the admin_metrics endpoint. Kept as plain atomics
+	// rather than Prometheus counters because they're specific to this
+	// enricher instance, not process-wide.
+	cacheHits   int64
+	cacheMisses int64
+	usdWrites   int64
+}
+
+// EnricherMetrics is a point-in-time snapshot of a MeteoraDataEnricher's
+// counters, for the admin API's admin_metrics method.
+type EnricherMetrics struct {
+	CacheHits   int64
+	CacheMisses int64
+	USDWrites   int64
 }
 
 type pairPriceData struct {
@@ -23,12 +47,114 @@ type pairPriceData struct {
 	lastUpdate time.Time
 }
 
-// NewMeteoraDataEnricher creates a new data enricher service
-func NewMeteoraDataEnricher(db *gorm.DB) *MeteoraDataEnricher {
-	return &MeteoraDataEnricher{
-		db:             db,
-		apiClient:      NewMeteoraPubClient(),
-		pairPriceCache: make(map[string]pairPriceData),
+// EnricherOption configures a MeteoraDataEnricher at construction time.
+type EnricherOption func(*MeteoraDataEnricher)
+
+// WithPriceOracles overrides the default price oracle aggregator (which only
+// queries the Meteora API) with a custom one, e.g. to register Jupiter,
+// Birdeye, and Pyth alongside it.
+func WithPriceOracles(oracles *PriceOracleAggregator) EnricherOption {
+	return func(e *MeteoraDataEnricher) {
+		e.oracles = oracles
+	}
+}
+
+// WithPriceCacheTTL overrides the default TTL entries in the pair price
+// cache are considered fresh for.
+func WithPriceCacheTTL(ttl time.Duration) EnricherOption {
+	return func(e *MeteoraDataEnricher) {
+		e.pairPriceCache.ttl = ttl
+	}
+}
+
+// WithPriceCacheMaxEntries overrides the default cap on the pair price
+// cache's size, past which the least recently used entry is evicted.
+func WithPriceCacheMaxEntries(n int) EnricherOption {
+	return func(e *MeteoraDataEnricher) {
+		e.pairPriceCache.maxEntries = n
+	}
+}
+
+// NewMeteoraDataEnricher creates a new data enricher service. If db is
+// non-nil, a background goroutine periodically sweeps TTL-expired entries
+// out of the pair price cache.
+func NewMeteoraDataEnricher(db *gorm.DB, opts ...EnricherOption) *MeteoraDataEnricher {
+	apiClient := NewMeteoraPubClient()
+
+	e := &MeteoraDataEnricher{
+		db:        db,
+		apiClient: apiClient,
+		oracles: NewPriceOracleAggregator(
+			DefaultPriceOracleAggregatorConfig(),
+			NewMeteoraPriceOracle(apiClient),
+		),
+		pairPriceCache: newPairPriceCache(defaultPriceCacheTTL, defaultPriceCacheMaxEntries),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if db != nil {
+		e.priceHistory = NewPriceHistoryStore(db, e.oracles)
+		e.startPriceCacheSweeper()
+	}
+
+	return e
+}
+
+// startPriceCacheSweeper runs sweepExpired on a timer for the lifetime of
+// the process, so entries nothing has Get()'d in a while still get evicted
+// instead of lingering until the next write collides with them.
+func (e *MeteoraDataEnricher) startPriceCacheSweeper() {
+	ticker := time.NewTicker(e.pairPriceCache.ttl)
+	go func() {
+		for range ticker.C {
+			e.pairPriceCache.sweepExpired()
+		}
+	}()
+}
+
+// pairOracleBlacklist returns the set of oracle IDs explicitly disabled for
+// this pair (Enabled=false in meteora_pair_oracles), or nil if the pair has
+// no disabled rows. An oracle with no row at all, or a row with
+// Enabled=true, is never in the returned set - it stays included in
+// aggregation by default. This is a blacklist, not a whitelist: operators
+// use it to exclude one known-bad feed for a pool without silently
+// dropping every other oracle that pool happens to have no row for.
+func (e *MeteoraDataEnricher) pairOracleBlacklist(pair *models.MeteoraPair) map[string]bool {
+	var rows []models.MeteoraPairOracle
+	if err := e.db.Where("pair_id = ? AND enabled = ?", pair.ID, false).Find(&rows).Error; err != nil || len(rows) == 0 {
+		return nil
+	}
+
+	disabled := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		disabled[row.OracleID] = true
+	}
+	return disabled
+}
+
+// DebugPairQuotes returns the most recent raw per-source quotes fetched for
+// a pair, for an admin endpoint to surface when operators are debugging a
+// price divergence.
+func (e *MeteoraDataEnricher) DebugPairQuotes(pairAddress string) []PriceQuote {
+	return e.oracles.LastQuotes(pairAddress)
+}
+
+// OracleStatus returns the recent health of every registered price oracle,
+// for the admin API's enricher_oracleStatus method.
+func (e *MeteoraDataEnricher) OracleStatus() []OracleStatus {
+	return e.oracles.Status()
+}
+
+// Metrics returns a snapshot of this enricher's running counters, for the
+// admin API's admin_metrics method.
+func (e *MeteoraDataEnricher) Metrics() EnricherMetrics {
+	return EnricherMetrics{
+		CacheHits:   atomic.LoadInt64(&e.cacheHits),
+		CacheMisses: atomic.LoadInt64(&e.cacheMisses),
+		USDWrites:   atomic.LoadInt64(&e.usdWrites),
 	}
 }
 
@@ -40,10 +166,15 @@ func (e *MeteoraDataEnricher) EnrichPairs() error {
 		return fmt.Errorf("failed to fetch pairs: %w", err)
 	}
 
-	// Process each pair
-	for _, pair := range pairs {
-		if err := e.enrichPair(&pair); err != nil {
-			fmt.Printf("Error enriching pair %s: %v\n", pair.Address, err)
+	// Process each pair in its own snapshot so one bad pair can't roll back
+	// the rest of the sweep.
+	for i := range pairs {
+		err := e.db.Transaction(func(dbTx *gorm.DB) error {
+			snapshot := newEnrichmentSnapshot(dbTx)
+			return e.enrichPair(snapshot, &pairs[i])
+		})
+		if err != nil {
+			fmt.Printf("Error enriching pair %s: %v\n", pairs[i].Address, err)
 			continue
 		}
 	}
@@ -51,50 +182,121 @@ func (e *MeteoraDataEnricher) EnrichPairs() error {
 	return nil
 }
 
-// enrichPair updates a single pair with data from the API
-func (e *MeteoraDataEnricher) enrichPair(pair *models.MeteoraPair) error {
+// enrichPair updates a single pair with data from the API, writing through
+// snapshot so the caller controls the commit/rollback boundary. If another
+// entity in the same snapshot already enriched this pair, that result is
+// reused instead of re-fetching and re-aggregating.
+func (e *MeteoraDataEnricher) enrichPair(snapshot *EnrichmentSnapshot, pair *models.MeteoraPair) error {
+	if cached, ok := snapshot.pairCacheEntry(pair.ID); ok {
+		*pair = *cached.pair
+		atomic.AddInt64(&e.cacheHits, 1)
+		return nil
+	}
+
 	// Check if we need to fetch new data
 	now := time.Now()
 	needsUpdate := pair.LastPriceUpdate.IsZero() || now.Sub(pair.LastPriceUpdate) > 1*time.Hour
 
-	// If we need new data, fetch it from the API
-	if needsUpdate {
-		pairInfo, err := e.apiClient.GetPair(pair.Address)
-		if err != nil {
-			return fmt.Errorf("failed to fetch pair data from API: %w", err)
-		}
+	if !needsUpdate {
+		snapshot.rememberPair(pair, pairPriceData{price: pair.CurrentPrice, lastUpdate: pair.LastPriceUpdate})
+		atomic.AddInt64(&e.cacheHits, 1)
+		return nil
+	}
+
+	atomic.AddInt64(&e.cacheMisses, 1)
+	pairInfo, err := e.apiClient.GetPair(pair.Address)
+	if err != nil {
+		return fmt.Errorf("failed to fetch pair data from API: %w", err)
+	}
 
-		// Update pair with the new data
-		pair.CurrentPrice = pairInfo.CurrentPrice
+	aggregated, aggErr := e.oracles.Aggregate(context.Background(), pair, e.pairOracleBlacklist(pair))
+	if aggErr != nil && aggErr != ErrPriceUnreliable {
+		return fmt.Errorf("failed to aggregate pair price: %w", aggErr)
+	}
+	if aggErr == ErrPriceUnreliable {
+		// Sources disagree too much to trust. Record the disagreement and
+		// the non-price fields, but don't let a bad quote corrupt
+		// CurrentPrice or any USD value derived from it.
+		pair.Status = "price_unreliable"
 		pair.APR = pairInfo.Apr
 		pair.APY = pairInfo.Apy
 		pair.Fees24h = pairInfo.Fees24h
 		pair.Volume24h = pairInfo.TradeVolume24h
 		pair.LastPriceUpdate = now
 
-		// Calculate USD values for reserves
-		// Note: These calculations might need adjustment based on token decimals
-		pair.ReserveXUSD = float64(pairInfo.ReserveXAmount) * pairInfo.CurrentPrice
-		pair.ReserveYUSD = float64(pairInfo.ReserveYAmount)
-		pair.TVL = pair.ReserveXUSD + pair.ReserveYUSD
-
-		// Cache the price data
-		e.mutex.Lock()
-		e.pairPriceCache[pair.Address] = pairPriceData{
-			price:      pairInfo.CurrentPrice,
-			lastUpdate: now,
+		if err := snapshot.save(pairKey(pair.ID), pair); err != nil {
+			return fmt.Errorf("failed to save pair: %w", err)
 		}
-		e.mutex.Unlock()
+		atomic.AddInt64(&e.usdWrites, 1)
+		snapshot.rememberPair(pair, pairPriceData{price: pair.CurrentPrice, lastUpdate: now})
+		return nil
+	}
 
-		// Save the updated pair
-		if err := e.db.Save(pair).Error; err != nil {
-			return fmt.Errorf("failed to save pair: %w", err)
+	if pair.Status == "price_unreliable" {
+		pair.Status = "active"
+	}
+
+	// Update pair with the new data
+	pair.CurrentPrice = aggregated.Value
+	pair.APR = pairInfo.Apr
+	pair.APY = pairInfo.Apy
+	pair.Fees24h = pairInfo.Fees24h
+	pair.Volume24h = pairInfo.TradeVolume24h
+	pair.LastPriceUpdate = now
+
+	// Calculate USD values for reserves
+	// Note: These calculations might need adjustment based on token decimals
+	pair.ReserveXUSD = float64(pairInfo.ReserveXAmount) * aggregated.Value
+	pair.ReserveYUSD = float64(pairInfo.ReserveYAmount)
+	pair.TVL = pair.ReserveXUSD + pair.ReserveYUSD
+
+	if err := snapshot.save(pairKey(pair.ID), pair); err != nil {
+		return fmt.Errorf("failed to save pair: %w", err)
+	}
+	atomic.AddInt64(&e.usdWrites, 1)
+
+	snapshot.rememberPair(pair, pairPriceData{price: aggregated.Value, lastUpdate: now})
+
+	// Feed this fresh quote into the history store too, so a PriceAt lookup
+	// for a nearby BlockTime has a real sample to consult instead of always
+	// falling through to its own live fetch. Best-effort: a failure here
+	// shouldn't roll back the pair refresh that actually matters.
+	if e.priceHistory != nil {
+		if err := e.priceHistory.RecordSample(context.Background(), pair.TokenMintX, now, aggregated.Value, "pair_refresh"); err != nil {
+			fmt.Printf("Error recording price history sample for %s: %v\n", pair.TokenMintX, err)
 		}
 	}
 
 	return nil
 }
 
+func pairKey(id uint) string {
+	return fmt.Sprintf("pair:%d", id)
+}
+
+// priceAt returns pair's USD price as of blockTime via priceHistory, falling
+// back to pair.CurrentPrice - today's live price - when no store is
+// configured or it can't resolve one close enough to blockTime to trust.
+// This is what keeps EnrichSwap/EnrichFeeClaim/EnrichLiquidityAddition/
+// EnrichLiquidityRemoval's fallback paths from always stamping a
+// transaction processed long after the fact with today's price instead of
+// the price at the time it actually happened.
+func (e *MeteoraDataEnricher) priceAt(pair *models.MeteoraPair, blockTime time.Time) float64 {
+	if e.priceHistory == nil {
+		return pair.CurrentPrice
+	}
+
+	price, historical, err := e.priceHistory.PriceAt(context.Background(), pair.TokenMintX, blockTime)
+	if err != nil {
+		fmt.Printf("Error resolving historical price for %s at %s, falling back to current price: %v\n", pair.TokenMintX, blockTime, err)
+		return pair.CurrentPrice
+	}
+	if !historical {
+		fmt.Printf("Warning: no price_history sample within %s of %s for %s, stamping an approximated (live) price instead\n", priceHistoryMaxAge, blockTime, pair.TokenMintX)
+	}
+	return price
+}
+
 // EnrichPositions updates all positions with USD data
 func (e *MeteoraDataEnricher) EnrichPositions() error {
 	// Get all active positions from the database
@@ -143,66 +345,84 @@ func (e *MeteoraDataEnricher) enrichPosition(position *models.MeteoraPosition) e
 	return nil
 }
 
-// EnrichSwap adds USD values to a swap
-func (e *MeteoraDataEnricher) EnrichSwap(swap *models.MeteoraSwap) error {
-	// First get pair to know the price
-	var pair models.MeteoraPair
-	if err := e.db.First(&pair, swap.PairID).Error; err != nil {
-		return fmt.Errorf("failed to fetch pair for swap: %w", err)
-	}
-
-	// Ensure pair has current price data
-	if err := e.enrichPair(&pair); err != nil {
-		return fmt.Errorf("failed to enrich pair for swap: %w", err)
-	}
-
-	// Calculate USD values
-	price := pair.CurrentPrice
-	swap.TokenPrice = price
-
+// calculateSwapUSD derives a swap's USD-denominated amounts from its raw,
+// token-native AmountIn/AmountOut/Fee and the pair's current price. It's a
+// pure function of swap and price (no DB access) specifically so it can be
+// exercised directly by the testdata/swap_vectors golden corpus without
+// standing up a snapshot.
+//
+// Note: this doesn't scale by token decimals - AmountIn/AmountOut/Fee are
+// multiplied against price as-is, matching how the rest of enrichment reads
+// them. If that ever needs to change, the swap_vectors corpus's
+// tokenXDecimals/tokenYDecimals fields are already there to grow into.
+func calculateSwapUSD(swap *models.MeteoraSwap, price float64) (amountInUSD, amountOutUSD, feeUSD float64) {
 	// Determine which token is being swapped in
 	if swap.SwapForY {
 		// X -> Y swap
-		swap.AmountInUSD = float64(swap.AmountIn) * price
-		swap.AmountOutUSD = float64(swap.AmountOut)
+		amountInUSD = float64(swap.AmountIn) * price
+		amountOutUSD = float64(swap.AmountOut)
 	} else {
 		// Y -> X swap
-		swap.AmountInUSD = float64(swap.AmountIn)
-		swap.AmountOutUSD = float64(swap.AmountOut) * price
+		amountInUSD = float64(swap.AmountIn)
+		amountOutUSD = float64(swap.AmountOut) * price
 	}
 
 	// Calculate fee in USD
 	// Note: This is a simplified approach. Real fee calculation may be more complex.
 	if swap.SwapForY {
-		swap.FeeUSD = float64(swap.Fee) * price
+		feeUSD = float64(swap.Fee) * price
 	} else {
-		swap.FeeUSD = float64(swap.Fee)
+		feeUSD = float64(swap.Fee)
 	}
 
+	return amountInUSD, amountOutUSD, feeUSD
+}
+
+// EnrichSwap adds USD values to a swap, writing through snapshot. blockTime
+// is the parent transaction's BlockTime, which priceAt uses to look up the
+// price in effect when the swap actually happened rather than today's.
+func (e *MeteoraDataEnricher) EnrichSwap(snapshot *EnrichmentSnapshot, swap *models.MeteoraSwap, blockTime time.Time) error {
+	// First get pair to know the price
+	var pair models.MeteoraPair
+	if err := snapshot.tx.First(&pair, swap.PairID).Error; err != nil {
+		return fmt.Errorf("failed to fetch pair for swap: %w", err)
+	}
+
+	// Ensure pair has current price data
+	if err := e.enrichPair(snapshot, &pair); err != nil {
+		return fmt.Errorf("failed to enrich pair for swap: %w", err)
+	}
+
+	// Calculate USD values
+	price := e.priceAt(&pair, blockTime)
+	swap.TokenPrice = price
+	swap.AmountInUSD, swap.AmountOutUSD, swap.FeeUSD = calculateSwapUSD(swap, price)
+
 	// Save the updated swap
-	if err := e.db.Save(swap).Error; err != nil {
+	if err := snapshot.save(fmt.Sprintf("swap:%d", swap.ID), swap); err != nil {
 		return fmt.Errorf("failed to save swap: %w", err)
 	}
 
 	return nil
 }
 
-// EnrichFeeClaim adds USD values to a fee claim
-func (e *MeteoraDataEnricher) EnrichFeeClaim(claim *models.MeteoraFeeClaim) error {
+// EnrichFeeClaim adds USD values to a fee claim, writing through snapshot.
+// blockTime is the parent transaction's BlockTime; see EnrichSwap.
+func (e *MeteoraDataEnricher) EnrichFeeClaim(snapshot *EnrichmentSnapshot, claim *models.MeteoraFeeClaim, blockTime time.Time) error {
 	// First get position to establish pair relationship
 	var position models.MeteoraPosition
-	if err := e.db.First(&position, claim.PositionID).Error; err != nil {
+	if err := snapshot.tx.First(&position, claim.PositionID).Error; err != nil {
 		return fmt.Errorf("failed to fetch position for fee claim: %w", err)
 	}
 
 	// Then get pair to know the price
 	var pair models.MeteoraPair
-	if err := e.db.First(&pair, position.PairID).Error; err != nil {
+	if err := snapshot.tx.First(&pair, position.PairID).Error; err != nil {
 		return fmt.Errorf("failed to fetch pair for fee claim: %w", err)
 	}
 
 	// Ensure pair has current price data
-	if err := e.enrichPair(&pair); err != nil {
+	if err := e.enrichPair(snapshot, &pair); err != nil {
 		return fmt.Errorf("failed to enrich pair for fee claim: %w", err)
 	}
 
@@ -211,7 +431,7 @@ func (e *MeteoraDataEnricher) EnrichFeeClaim(claim *models.MeteoraFeeClaim) erro
 	if err == nil && len(positionClaims) > 0 {
 		// Find the matching claim by txID
 		var tx models.Transaction
-		if err := e.db.First(&tx, claim.TransactionID).Error; err == nil {
+		if err := snapshot.tx.First(&tx, claim.TransactionID).Error; err == nil {
 			for _, apiClaim := range positionClaims {
 				if apiClaim.TxID == tx.Signature {
 					claim.AmountXUSD = apiClaim.TokenXUSDAmount
@@ -220,7 +440,7 @@ func (e *MeteoraDataEnricher) EnrichFeeClaim(claim *models.MeteoraFeeClaim) erro
 					claim.TokenPrice = pair.CurrentPrice
 
 					// Save the updated claim
-					if err := e.db.Save(claim).Error; err != nil {
+					if err := snapshot.save(fmt.Sprintf("fee_claim:%d", claim.ID), claim); err != nil {
 						return fmt.Errorf("failed to save fee claim: %w", err)
 					}
 
@@ -231,35 +451,38 @@ func (e *MeteoraDataEnricher) EnrichFeeClaim(claim *models.MeteoraFeeClaim) erro
 	}
 
 	// If we couldn't find match in API data, calculate ourselves
-	claim.TokenPrice = pair.CurrentPrice
-	claim.AmountXUSD = float64(claim.AmountX) * pair.CurrentPrice
+	price := e.priceAt(&pair, blockTime)
+	claim.TokenPrice = price
+	claim.AmountXUSD = float64(claim.AmountX) * price
 	claim.AmountYUSD = float64(claim.AmountY)
 	claim.TotalValueUSD = claim.AmountXUSD + claim.AmountYUSD
 
 	// Save the updated claim
-	if err := e.db.Save(claim).Error; err != nil {
+	if err := snapshot.save(fmt.Sprintf("fee_claim:%d", claim.ID), claim); err != nil {
 		return fmt.Errorf("failed to save fee claim: %w", err)
 	}
 
 	return nil
 }
 
-// EnrichLiquidityAddition adds USD values to a liquidity addition
-func (e *MeteoraDataEnricher) EnrichLiquidityAddition(addition *models.MeteoraLiquidityAddition) error {
+// EnrichLiquidityAddition adds USD values to a liquidity addition, writing
+// through snapshot. blockTime is the parent transaction's BlockTime; see
+// EnrichSwap.
+func (e *MeteoraDataEnricher) EnrichLiquidityAddition(snapshot *EnrichmentSnapshot, addition *models.MeteoraLiquidityAddition, blockTime time.Time) error {
 	// Get pair to know the price
 	var pair models.MeteoraPair
-	if err := e.db.First(&pair, addition.PairID).Error; err != nil {
+	if err := snapshot.tx.First(&pair, addition.PairID).Error; err != nil {
 		return fmt.Errorf("failed to fetch pair for liquidity addition: %w", err)
 	}
 
 	// Ensure pair has current price data
-	if err := e.enrichPair(&pair); err != nil {
+	if err := e.enrichPair(snapshot, &pair); err != nil {
 		return fmt.Errorf("failed to enrich pair for liquidity addition: %w", err)
 	}
 
 	// Get position to get more accurate data
 	var position models.MeteoraPosition
-	if err := e.db.First(&position, addition.PositionID).Error; err != nil {
+	if err := snapshot.tx.First(&position, addition.PositionID).Error; err != nil {
 		return fmt.Errorf("failed to fetch position for liquidity addition: %w", err)
 	}
 
@@ -268,7 +491,7 @@ func (e *MeteoraDataEnricher) EnrichLiquidityAddition(addition *models.MeteoraLi
 	if err == nil && len(deposits) > 0 {
 		// Find the matching deposit by txID
 		var tx models.Transaction
-		if err := e.db.First(&tx, addition.TransactionID).Error; err == nil {
+		if err := snapshot.tx.First(&tx, addition.TransactionID).Error; err == nil {
 			for _, deposit := range deposits {
 				if deposit.TxID == tx.Signature {
 					addition.AmountXUSD = deposit.TokenXUSDAmount
@@ -277,7 +500,7 @@ func (e *MeteoraDataEnricher) EnrichLiquidityAddition(addition *models.MeteoraLi
 					addition.TokenPrice = deposit.Price
 
 					// Save the updated addition
-					if err := e.db.Save(addition).Error; err != nil {
+					if err := snapshot.save(fmt.Sprintf("liquidity_addition:%d", addition.ID), addition); err != nil {
 						return fmt.Errorf("failed to save liquidity addition: %w", err)
 					}
 
@@ -288,35 +511,38 @@ func (e *MeteoraDataEnricher) EnrichLiquidityAddition(addition *models.MeteoraLi
 	}
 
 	// If we couldn't find match in API data, calculate ourselves
-	addition.TokenPrice = pair.CurrentPrice
-	addition.AmountXUSD = float64(addition.AmountX) * pair.CurrentPrice
+	price := e.priceAt(&pair, blockTime)
+	addition.TokenPrice = price
+	addition.AmountXUSD = float64(addition.AmountX) * price
 	addition.AmountYUSD = float64(addition.AmountY)
 	addition.TotalValueUSD = addition.AmountXUSD + addition.AmountYUSD
 
 	// Save the updated addition
-	if err := e.db.Save(addition).Error; err != nil {
+	if err := snapshot.save(fmt.Sprintf("liquidity_addition:%d", addition.ID), addition); err != nil {
 		return fmt.Errorf("failed to save liquidity addition: %w", err)
 	}
 
 	return nil
 }
 
-// EnrichLiquidityRemoval adds USD values to a liquidity removal
-func (e *MeteoraDataEnricher) EnrichLiquidityRemoval(removal *models.MeteoraLiquidityRemoval) error {
+// EnrichLiquidityRemoval adds USD values to a liquidity removal, writing
+// through snapshot. blockTime is the parent transaction's BlockTime; see
+// EnrichSwap.
+func (e *MeteoraDataEnricher) EnrichLiquidityRemoval(snapshot *EnrichmentSnapshot, removal *models.MeteoraLiquidityRemoval, blockTime time.Time) error {
 	// Get pair to know the price
 	var pair models.MeteoraPair
-	if err := e.db.First(&pair, removal.PairID).Error; err != nil {
+	if err := snapshot.tx.First(&pair, removal.PairID).Error; err != nil {
 		return fmt.Errorf("failed to fetch pair for liquidity removal: %w", err)
 	}
 
 	// Ensure pair has current price data
-	if err := e.enrichPair(&pair); err != nil {
+	if err := e.enrichPair(snapshot, &pair); err != nil {
 		return fmt.Errorf("failed to enrich pair for liquidity removal: %w", err)
 	}
 
 	// Get position to get more accurate data
 	var position models.MeteoraPosition
-	if err := e.db.First(&position, removal.PositionID).Error; err != nil {
+	if err := snapshot.tx.First(&position, removal.PositionID).Error; err != nil {
 		return fmt.Errorf("failed to fetch position for liquidity removal: %w", err)
 	}
 
@@ -325,7 +551,7 @@ func (e *MeteoraDataEnricher) EnrichLiquidityRemoval(removal *models.MeteoraLiqu
 	if err == nil && len(withdraws) > 0 {
 		// Find the matching withdrawal by txID
 		var tx models.Transaction
-		if err := e.db.First(&tx, removal.TransactionID).Error; err == nil {
+		if err := snapshot.tx.First(&tx, removal.TransactionID).Error; err == nil {
 			for _, withdraw := range withdraws {
 				if withdraw.TxID == tx.Signature {
 					removal.AmountXRemoved = uint64(withdraw.TokenXAmount)
@@ -336,7 +562,7 @@ func (e *MeteoraDataEnricher) EnrichLiquidityRemoval(removal *models.MeteoraLiqu
 					removal.TokenPrice = withdraw.Price
 
 					// Save the updated removal
-					if err := e.db.Save(removal).Error; err != nil {
+					if err := snapshot.save(fmt.Sprintf("liquidity_removal:%d", removal.ID), removal); err != nil {
 						return fmt.Errorf("failed to save liquidity removal: %w", err)
 					}
 
@@ -352,63 +578,142 @@ func (e *MeteoraDataEnricher) EnrichLiquidityRemoval(removal *models.MeteoraLiqu
 	}
 
 	// Calculate with what we have
-	removal.TokenPrice = pair.CurrentPrice
-	removal.AmountXRemovedUSD = float64(removal.AmountXRemoved) * pair.CurrentPrice
+	price := e.priceAt(&pair, blockTime)
+	removal.TokenPrice = price
+	removal.AmountXRemovedUSD = float64(removal.AmountXRemoved) * price
 	removal.AmountYRemovedUSD = float64(removal.AmountYRemoved)
 	removal.TotalValueUSD = removal.AmountXRemovedUSD + removal.AmountYRemovedUSD
 
 	// Save the updated removal
-	if err := e.db.Save(removal).Error; err != nil {
+	if err := snapshot.save(fmt.Sprintf("liquidity_removal:%d", removal.ID), removal); err != nil {
 		return fmt.Errorf("failed to save liquidity removal: %w", err)
 	}
 
 	return nil
 }
 
-// PostProcessTransaction enriches all Meteora entities related to a transaction with USD values
-func (e *MeteoraDataEnricher) PostProcessTransaction(tx *models.Transaction) error {
-	// Enrich all swaps
-	var swaps []models.MeteoraSwap
-	if err := e.db.Where("transaction_id = ?", tx.ID).Find(&swaps).Error; err != nil {
-		return fmt.Errorf("failed to fetch swaps: %w", err)
-	}
-	for i := range swaps {
-		if err := e.EnrichSwap(&swaps[i]); err != nil {
-			fmt.Printf("Error enriching swap: %v\n", err)
-		}
+// EnrichPairByAddress re-fetches and re-aggregates a single pair's price on
+// demand, for the admin API's enricher_enrichPair method (operators forcing
+// a refresh ahead of the regular hourly cadence, typically while debugging a
+// stale price).
+func (e *MeteoraDataEnricher) EnrichPairByAddress(address string) error {
+	var pair models.MeteoraPair
+	if err := e.db.Where("address = ?", address).First(&pair).Error; err != nil {
+		return fmt.Errorf("failed to fetch pair %s: %w", address, err)
 	}
 
-	// Enrich all liquidity additions
-	var additions []models.MeteoraLiquidityAddition
-	if err := e.db.Where("transaction_id = ?", tx.ID).Find(&additions).Error; err != nil {
-		return fmt.Errorf("failed to fetch additions: %w", err)
-	}
-	for i := range additions {
-		if err := e.EnrichLiquidityAddition(&additions[i]); err != nil {
-			fmt.Printf("Error enriching liquidity addition: %v\n", err)
-		}
+	// Force a refresh rather than relying on enrichPair's hourly freshness
+	// check, since the whole point of calling this is to bypass it.
+	pair.LastPriceUpdate = time.Time{}
+
+	var snapshot *EnrichmentSnapshot
+	err := e.db.Transaction(func(dbTx *gorm.DB) error {
+		snapshot = newEnrichmentSnapshot(dbTx)
+		return e.enrichPair(snapshot, &pair)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enrich pair %s: %w", address, err)
 	}
 
-	// Enrich all liquidity removals
-	var removals []models.MeteoraLiquidityRemoval
-	if err := e.db.Where("transaction_id = ?", tx.ID).Find(&removals).Error; err != nil {
-		return fmt.Errorf("failed to fetch removals: %w", err)
+	e.Merge(snapshot)
+	return nil
+}
+
+// EnrichTransactionBySignature looks up a transaction by signature and runs
+// it back through PostProcessTransaction, for the admin API's
+// enricher_enrichTransaction method (retrying a transaction stuck in
+// enrichment_failed, or re-enriching one after a pair's price was corrected).
+func (e *MeteoraDataEnricher) EnrichTransactionBySignature(signature string) error {
+	var tx models.Transaction
+	if err := e.db.Where("signature = ?", signature).First(&tx).Error; err != nil {
+		return fmt.Errorf("failed to fetch transaction %s: %w", signature, err)
 	}
-	for i := range removals {
-		if err := e.EnrichLiquidityRemoval(&removals[i]); err != nil {
-			fmt.Printf("Error enriching liquidity removal: %v\n", err)
+
+	return e.PostProcessTransaction(&tx)
+}
+
+// PostProcessTransaction enriches all Meteora entities related to a
+// transaction with USD values inside a single DB transaction. Every write is
+// buffered through an EnrichmentSnapshot; if any oracle call or save fails,
+// the transaction rolls back wholesale (so an earlier swap never commits
+// with a price that's now inconsistent with a later, failed fee claim) and
+// the transaction row is marked enrichment_failed with the stage that broke,
+// for a later retry pass to pick up.
+func (e *MeteoraDataEnricher) PostProcessTransaction(tx *models.Transaction) error {
+	// Initialized before Transaction runs, not just inside its callback: if
+	// Begin() itself fails (DB down, pool exhausted), gorm returns that
+	// error without ever invoking the callback, which would otherwise leave
+	// snapshot nil going into the txErr != nil branch below.
+	snapshot := newEnrichmentSnapshot(nil)
+
+	txErr := e.db.Transaction(func(dbTx *gorm.DB) error {
+		snapshot = newEnrichmentSnapshot(dbTx)
+
+		snapshot.setStage("swaps")
+		var swaps []models.MeteoraSwap
+		if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&swaps).Error; err != nil {
+			return fmt.Errorf("failed to fetch swaps: %w", err)
+		}
+		for i := range swaps {
+			if err := e.EnrichSwap(snapshot, &swaps[i], tx.BlockTime); err != nil {
+				return fmt.Errorf("failed to enrich swap %d: %w", swaps[i].ID, err)
+			}
 		}
-	}
 
-	// Enrich all fee claims
-	var feeClaims []models.MeteoraFeeClaim
-	if err := e.db.Where("transaction_id = ?", tx.ID).Find(&feeClaims).Error; err != nil {
-		return fmt.Errorf("failed to fetch fee claims: %w", err)
-	}
-	for i := range feeClaims {
-		if err := e.EnrichFeeClaim(&feeClaims[i]); err != nil {
-			fmt.Printf("Error enriching fee claim: %v\n", err)
+		snapshot.setStage("liquidity_additions")
+		var additions []models.MeteoraLiquidityAddition
+		if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&additions).Error; err != nil {
+			return fmt.Errorf("failed to fetch additions: %w", err)
+		}
+		for i := range additions {
+			if err := e.EnrichLiquidityAddition(snapshot, &additions[i], tx.BlockTime); err != nil {
+				return fmt.Errorf("failed to enrich liquidity addition %d: %w", additions[i].ID, err)
+			}
+		}
+
+		snapshot.setStage("liquidity_removals")
+		var removals []models.MeteoraLiquidityRemoval
+		if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&removals).Error; err != nil {
+			return fmt.Errorf("failed to fetch removals: %w", err)
+		}
+		for i := range removals {
+			if err := e.EnrichLiquidityRemoval(snapshot, &removals[i], tx.BlockTime); err != nil {
+				return fmt.Errorf("failed to enrich liquidity removal %d: %w", removals[i].ID, err)
+			}
+		}
+
+		snapshot.setStage("fee_claims")
+		var feeClaims []models.MeteoraFeeClaim
+		if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&feeClaims).Error; err != nil {
+			return fmt.Errorf("failed to fetch fee claims: %w", err)
+		}
+		for i := range feeClaims {
+			if err := e.EnrichFeeClaim(snapshot, &feeClaims[i], tx.BlockTime); err != nil {
+				return fmt.Errorf("failed to enrich fee claim %d: %w", feeClaims[i].ID, err)
+			}
+		}
+
+		return nil
+	})
+
+	if txErr != nil {
+		tx.EnrichmentStatus = "enrichment_failed"
+		tx.EnrichmentStage = snapshot.stage
+		if err := e.db.Model(tx).Select("EnrichmentStatus", "EnrichmentStage").Updates(tx).Error; err != nil {
+			return fmt.Errorf("enrichment failed at stage %q (and failed to record it: %v): %w", snapshot.stage, err, txErr)
 		}
+		return fmt.Errorf("enrichment failed at stage %q: %w", snapshot.stage, txErr)
+	}
+
+	// Everything committed: fold the pair prices this snapshot computed into
+	// the shared cache now, instead of racing every nested enrichPair call
+	// through e.mutex while the transaction was still in flight.
+	e.Merge(snapshot)
+
+	tx.EnrichmentStatus = "enriched"
+	tx.EnrichmentStage = ""
+	if err := e.db.Model(tx).Select("EnrichmentStatus", "EnrichmentStage").Updates(tx).Error; err != nil {
+		return fmt.Errorf("failed to record enrichment status: %w", err)
 	}
 
 	return nil
@@ -1,10 +1,10 @@
 package services
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
-
-	"github.com/wnt/mercon/internal/models"
 )
 
 // TestNewMeteoraDataEnricher tests creating a new enricher
@@ -20,32 +20,17 @@ func TestNewMeteoraDataEnricher(t *testing.T) {
 	}
 }
 
-// TestCacheMechanism tests the price cache
+// TestCacheMechanism tests basic get/set on the pair price cache
 func TestCacheMechanism(t *testing.T) {
-	// Create a new enricher with nil DB
-	enricher := &MeteoraDataEnricher{
-		db:             nil,
-		apiClient:      nil, // Not testing API interactions
-		pairPriceCache: make(map[string]pairPriceData),
-	}
+	cache := newPairPriceCache(defaultPriceCacheTTL, defaultPriceCacheMaxEntries)
 
-	// Test adding to cache
 	pairAddress := "TestPairAddress123"
 	now := time.Now()
 	testPrice := 1.25
 
-	enricher.mutex.Lock()
-	enricher.pairPriceCache[pairAddress] = pairPriceData{
-		price:      testPrice,
-		lastUpdate: now,
-	}
-	enricher.mutex.Unlock()
-
-	// Verify cache retrieval
-	enricher.mutex.Lock()
-	cachedData, exists := enricher.pairPriceCache[pairAddress]
-	enricher.mutex.Unlock()
+	cache.Set(pairAddress, pairPriceData{price: testPrice, lastUpdate: now})
 
+	cachedData, exists := cache.Get(pairAddress)
 	if !exists {
 		t.Error("Price data should be in cache")
 	}
@@ -57,53 +42,70 @@ func TestCacheMechanism(t *testing.T) {
 	}
 }
 
-// TestSwapCalculations tests the swap enrichment calculations
-func TestSwapCalculations(t *testing.T) {
-	// Create a swap with known values (X -> Y swap)
-	xToYSwap := &models.MeteoraSwap{
-		SwapForY:  true,
-		AmountIn:  1000,
-		AmountOut: 1200,
-		Fee:       3,
-	}
-
-	tokenPrice := 1.25
+// TestCacheTTLExpiry verifies that an entry older than the cache's TTL is no
+// longer served once that TTL has elapsed.
+func TestCacheTTLExpiry(t *testing.T) {
+	cache := newPairPriceCache(20*time.Millisecond, defaultPriceCacheMaxEntries)
 
-	// Manually calculate the expected values for X -> Y swap
-	xToYAmountInUSD := float64(xToYSwap.AmountIn) * tokenPrice
-	xToYAmountOutUSD := float64(xToYSwap.AmountOut)
-	xToYFeeUSD := float64(xToYSwap.Fee) * tokenPrice
+	cache.Set("PairA", pairPriceData{price: 1.0})
 
-	if xToYAmountInUSD != 1250.0 {
-		t.Errorf("Expected AmountInUSD to be 1250.0, got %f", xToYAmountInUSD)
+	if _, ok := cache.Get("PairA"); !ok {
+		t.Fatal("expected PairA to be cached before its TTL elapses")
 	}
-	if xToYAmountOutUSD != 1200.0 {
-		t.Errorf("Expected AmountOutUSD to be 1200.0, got %f", xToYAmountOutUSD)
-	}
-	if xToYFeeUSD != 3.75 {
-		t.Errorf("Expected FeeUSD to be 3.75, got %f", xToYFeeUSD)
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := cache.Get("PairA"); ok {
+		t.Error("expected PairA to have expired after its TTL elapsed")
 	}
+}
+
+// TestCacheLRUEviction verifies that once the cache is at capacity, inserting
+// a new entry evicts the least recently used one rather than growing further.
+func TestCacheLRUEviction(t *testing.T) {
+	cache := newPairPriceCache(defaultPriceCacheTTL, 2)
+
+	cache.Set("PairA", pairPriceData{price: 1.0})
+	cache.Set("PairB", pairPriceData{price: 2.0})
 
-	// Create a swap with known values (Y -> X swap)
-	yToXSwap := &models.MeteoraSwap{
-		SwapForY:  false,
-		AmountIn:  1000,
-		AmountOut: 800,
-		Fee:       3,
+	// Touch PairA so PairB becomes the least recently used entry.
+	if _, ok := cache.Get("PairA"); !ok {
+		t.Fatal("expected PairA to be cached")
 	}
 
-	// Manually calculate the expected values for Y -> X swap
-	yToXAmountInUSD := float64(yToXSwap.AmountIn)
-	yToXAmountOutUSD := float64(yToXSwap.AmountOut) * tokenPrice
-	yToXFeeUSD := float64(yToXSwap.Fee)
+	cache.Set("PairC", pairPriceData{price: 3.0})
 
-	if yToXAmountInUSD != 1000.0 {
-		t.Errorf("Expected AmountInUSD to be 1000.0, got %f", yToXAmountInUSD)
+	if _, ok := cache.Get("PairB"); ok {
+		t.Error("expected PairB to have been evicted as the least recently used entry")
 	}
-	if yToXAmountOutUSD != 1000.0 {
-		t.Errorf("Expected AmountOutUSD to be 1000.0, got %f", yToXAmountOutUSD)
+	if _, ok := cache.Get("PairA"); !ok {
+		t.Error("expected PairA to still be cached")
 	}
-	if yToXFeeUSD != 3.0 {
-		t.Errorf("Expected FeeUSD to be 3.0, got %f", yToXFeeUSD)
+	if _, ok := cache.Get("PairC"); !ok {
+		t.Error("expected PairC to be cached")
+	}
+	if len(cache.index) != 2 {
+		t.Errorf("expected cache to hold 2 entries, got %d", len(cache.index))
+	}
+}
+
+// TestCacheConcurrentAccess exercises the cache from many goroutines at once
+// (run with -race to catch data races) to confirm its mutex actually guards
+// every access to the index and LRU list.
+func TestCacheConcurrentAccess(t *testing.T) {
+	cache := newPairPriceCache(defaultPriceCacheTTL, 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			address := fmt.Sprintf("Pair%d", i%10)
+			for j := 0; j < 50; j++ {
+				cache.Set(address, pairPriceData{price: float64(j)})
+				cache.Get(address)
+			}
+		}(i)
 	}
+	wg.Wait()
 }
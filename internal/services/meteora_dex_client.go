@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// MeteoraDEXClient adapts MeteoraPubClient - plus a BatchClient built from
+// it, for GetPositionSnapshot's multi-endpoint fan-out - to the DEXClient
+// interface. ListPositions, ListSwaps, and Quote have no backing endpoint
+// anywhere in MeteoraPubClient's API surface, so they return
+// ErrCapabilityNotSupported; Capabilities reflects that by leaving
+// CapQuotes and CapSwapHistory unset.
+type MeteoraDEXClient struct {
+	client *MeteoraPubClient
+	batch  *BatchClient
+}
+
+// NewMeteoraDEXClient wraps client as a DEXClient.
+func NewMeteoraDEXClient(client *MeteoraPubClient) *MeteoraDEXClient {
+	return &MeteoraDEXClient{client: client, batch: NewBatchClient(client)}
+}
+
+func (m *MeteoraDEXClient) Capabilities() DEXCapability {
+	return CapConcentratedLiquidity | CapFarmingRewards
+}
+
+// ListPairs fetches every pair from MeteoraPubClient.GetAllPairs.
+func (m *MeteoraDEXClient) ListPairs(ctx context.Context, filter PairFilter) ([]PairInfo, error) {
+	return m.client.GetAllPairs(filter.IncludeUnknown)
+}
+
+// GetPair fetches one pair by address.
+func (m *MeteoraDEXClient) GetPair(ctx context.Context, address string) (*PairInfo, error) {
+	return m.client.GetPair(address)
+}
+
+// ListPositions has no backing endpoint - MeteoraPubClient can fetch a
+// position by its own address, but has no owner-scoped listing call.
+func (m *MeteoraDEXClient) ListPositions(ctx context.Context, owner string) ([]Position, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+// GetPositionSnapshot fans GetPositionV2/GetClaimFees/GetDeposits/GetWithdraws
+// out in parallel via BatchClient.SnapshotPosition.
+func (m *MeteoraDEXClient) GetPositionSnapshot(ctx context.Context, address string) (*PositionSnapshot, error) {
+	return m.batch.SnapshotPosition(address)
+}
+
+// ListSwaps has no backing endpoint - swap history for mercon's analytics
+// comes from parsing on-chain transactions (internal/solana), not from
+// MeteoraPubClient.
+func (m *MeteoraDEXClient) ListSwaps(ctx context.Context, pair string, since time.Time) ([]DEXSwap, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+// Quote has no backing endpoint - MeteoraPubClient is a read-only indexer
+// API, not a swap router/aggregator.
+func (m *MeteoraDEXClient) Quote(ctx context.Context, tokenIn, tokenOut string, amountIn uint64) (*DEXQuote, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+var _ DEXClient = (*MeteoraDEXClient)(nil)
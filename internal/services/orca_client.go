@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// OrcaClient is scaffolding for a DEXClient backed by Orca's Whirlpools
+// API. Like RaydiumClient, mercon has no Orca integration today and no
+// endpoint shapes have been verified against Orca's actual API anywhere in
+// this codebase, so every method here returns ErrCapabilityNotSupported
+// and Capabilities reports nothing. Replace this with a real
+// utils.HTTPClient-backed implementation once that integration exists,
+// following MeteoraPubClient/MeteoraDEXClient's shape.
+type OrcaClient struct{}
+
+// NewOrcaClient returns an OrcaClient. See the type's doc comment: it is
+// not yet backed by a real Orca API client.
+func NewOrcaClient() *OrcaClient {
+	return &OrcaClient{}
+}
+
+func (o *OrcaClient) Capabilities() DEXCapability { return 0 }
+
+func (o *OrcaClient) ListPairs(ctx context.Context, filter PairFilter) ([]PairInfo, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (o *OrcaClient) GetPair(ctx context.Context, address string) (*PairInfo, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (o *OrcaClient) ListPositions(ctx context.Context, owner string) ([]Position, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (o *OrcaClient) GetPositionSnapshot(ctx context.Context, address string) (*PositionSnapshot, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (o *OrcaClient) ListSwaps(ctx context.Context, pair string, since time.Time) ([]DEXSwap, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (o *OrcaClient) Quote(ctx context.Context, tokenIn, tokenOut string, amountIn uint64) (*DEXQuote, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+var _ DEXClient = (*OrcaClient)(nil)
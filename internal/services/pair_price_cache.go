@@ -0,0 +1,133 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/wnt/mercon/internal/metrics"
+)
+
+// defaultPriceCacheTTL is how long a cached pair price is considered fresh.
+// Overridable via WithPriceCacheTTL.
+const defaultPriceCacheTTL = 60 * time.Second
+
+// defaultPriceCacheMaxEntries bounds a pairPriceCache's size; once exceeded,
+// the least recently used entry is evicted. Overridable via
+// WithPriceCacheMaxEntries.
+const defaultPriceCacheMaxEntries = 10000
+
+// pairPriceCacheElement is the value stored in a pairPriceCache's list.List,
+// so the LRU order and the map lookup share one underlying node.
+type pairPriceCacheElement struct {
+	address string
+	data    pairPriceData
+	written time.Time
+}
+
+// pairPriceCache is a bounded, TTL-expiring LRU cache of pairPriceData keyed
+// by pair address. It replaces the plain, unbounded map
+// MeteoraDataEnricher.pairPriceCache used to be, which grew without bound
+// and never evicted stale prices.
+type pairPriceCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	index      map[string]*list.Element
+	order      *list.List // front = most recently used
+}
+
+func newPairPriceCache(ttl time.Duration, maxEntries int) *pairPriceCache {
+	return &pairPriceCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		index:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns the cached price data for address, if present and not past
+// its TTL. A TTL-expired entry is evicted on the way out.
+func (c *pairPriceCache) Get(address string) (pairPriceData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.index[address]
+	if !ok {
+		metrics.RecordPriceCacheMiss()
+		return pairPriceData{}, false
+	}
+
+	elem := el.Value.(*pairPriceCacheElement)
+	if time.Since(elem.written) > c.ttl {
+		c.removeElement(el)
+		metrics.SetPriceCacheSize(len(c.index))
+		metrics.RecordPriceCacheEviction()
+		metrics.RecordPriceCacheMiss()
+		return pairPriceData{}, false
+	}
+
+	c.order.MoveToFront(el)
+	metrics.RecordPriceCacheHit()
+	return elem.data, true
+}
+
+// Set installs data for address, evicting the least recently used entry if
+// this insert pushes the cache past maxEntries.
+func (c *pairPriceCache) Set(address string, data pairPriceData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.index[address]; ok {
+		el.Value.(*pairPriceCacheElement).data = data
+		el.Value.(*pairPriceCacheElement).written = time.Now()
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&pairPriceCacheElement{address: address, data: data, written: time.Now()})
+	c.index[address] = el
+
+	for len(c.index) > c.maxEntries {
+		c.evictOldest()
+	}
+
+	metrics.SetPriceCacheSize(len(c.index))
+}
+
+// evictOldest drops the least recently used entry. Callers must hold c.mu.
+func (c *pairPriceCache) evictOldest() {
+	el := c.order.Back()
+	if el == nil {
+		return
+	}
+	c.removeElement(el)
+	metrics.RecordPriceCacheEviction()
+}
+
+// removeElement drops el from both the index and the LRU list. Callers must
+// hold c.mu.
+func (c *pairPriceCache) removeElement(el *list.Element) {
+	elem := el.Value.(*pairPriceCacheElement)
+	delete(c.index, elem.address)
+	c.order.Remove(el)
+}
+
+// sweepExpired evicts every entry older than the cache's TTL. It's meant to
+// be called on a timer by a background goroutine, so stale entries don't
+// linger in memory just because nothing happens to Get() them again.
+func (c *pairPriceCache) sweepExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for el := c.order.Back(); el != nil; {
+		prev := el.Prev()
+		if now.Sub(el.Value.(*pairPriceCacheElement).written) > c.ttl {
+			c.removeElement(el)
+			metrics.RecordPriceCacheEviction()
+		}
+		el = prev
+	}
+	metrics.SetPriceCacheSize(len(c.index))
+}
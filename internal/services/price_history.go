@@ -0,0 +1,274 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/gorm"
+)
+
+// PriceHistorySample is one point-in-time USD price observation for a
+// mint, persisted so a transaction enriched long after it happened can be
+// stamped with the price that was actually in effect at its BlockTime
+// instead of whatever the oracles quote today.
+type PriceHistorySample struct {
+	ID         uint   `gorm:"primaryKey"`
+	Mint       string `gorm:"size:44;index:idx_price_history_mint_time,priority:1;not null"`
+	UnixSecond int64  `gorm:"index:idx_price_history_mint_time,priority:2;not null"`
+	PriceUSD   float64
+	Source     string `gorm:"size:32"`
+	CreatedAt  time.Time
+}
+
+func (PriceHistorySample) TableName() string { return "price_history" }
+
+const (
+	// priceHistoryBucket buckets PriceAt lookups to the hour, so repeated
+	// enrichment of transactions landing in the same hour - the common
+	// case for a backfill walking one wallet's history in BlockTime order -
+	// hits the in-process cache instead of round-tripping to Postgres every
+	// time.
+	priceHistoryBucket = time.Hour
+
+	// priceHistoryMaxAge is how far a stored sample's UnixSecond may sit
+	// from the requested time and still count as "the price at that time"
+	// rather than a miss that falls through to a live oracle fetch.
+	priceHistoryMaxAge = 30 * time.Minute
+
+	priceHistoryCacheMaxEntries = 5000
+)
+
+// priceHistoryCacheEntry is the container/list element value for
+// PriceHistoryStore's LRU, the same pattern pairPriceCache uses. historical
+// carries forward PriceAt's historical/approximated distinction so a cache
+// hit reports the same trust level as the lookup that produced it, rather
+// than always reporting "historical" regardless of how the price was
+// actually obtained.
+type priceHistoryCacheEntry struct {
+	key        string
+	price      float64
+	historical bool
+}
+
+// PriceHistoryStore answers "what was mint's USD price at time t", backed
+// by a price_history table of samples recorded as pairs are enriched, with
+// a bounded LRU in front of it and a live oracle fetch (via the same
+// PriceOracleAggregator the enricher uses for current pair prices) as the
+// last resort when history doesn't yet cover the requested time.
+//
+// It exists specifically to serve PostProcessTransaction's per-transaction
+// USD stamping - EnrichSwap, EnrichFeeClaim, EnrichLiquidityAddition, and
+// EnrichLiquidityRemoval - at the transaction's own BlockTime.
+// EnrichPairs/enrichPair and EnrichPositions/enrichPosition are a separate
+// case: they refresh *current* pair/position state (APR, APY, TVL,
+// fee-yield figures) on their own 1h/6h staleness timers, sweeping every
+// pair or position in the database rather than reacting to one
+// transaction, so there is no single BlockTime for them to look up a
+// historical price against. They correctly keep using pair.CurrentPrice via
+// enrichPair, unchanged by this file.
+type PriceHistoryStore struct {
+	db      *gorm.DB
+	oracles *PriceOracleAggregator
+
+	mu         sync.Mutex
+	index      map[string]*list.Element
+	order      *list.List
+	maxEntries int
+}
+
+// NewPriceHistoryStore builds a store over db (for persisted samples) and
+// oracles (for live fetches when history has no close-enough sample yet).
+func NewPriceHistoryStore(db *gorm.DB, oracles *PriceOracleAggregator) *PriceHistoryStore {
+	return &PriceHistoryStore{
+		db:         db,
+		oracles:    oracles,
+		index:      make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: priceHistoryCacheMaxEntries,
+	}
+}
+
+func priceHistoryCacheKey(mint string, t time.Time) string {
+	return fmt.Sprintf("%s:%d", mint, t.Truncate(priceHistoryBucket).Unix())
+}
+
+// PriceAt returns mint's USD price as of t, and whether that price is a
+// genuine historical observation or an approximation.
+//
+// It checks the in-process cache first, then the price_history table for a
+// sample within priceHistoryMaxAge of t. Only those two cases return
+// historical=true. Everything else - no sample within priceHistoryMaxAge,
+// or no sample at all - falls back to a live oracle quote, which it
+// persists as a new sample before returning so a later lookup for a nearby
+// t doesn't need the network again; that fallback always returns
+// historical=false, because a live quote a day after the fact is today's
+// price, not the price at t. Callers that care about the distinction (e.g.
+// PostProcessTransaction re-stamping old transactions) should treat
+// historical=false as "approximated" and surface that rather than trusting
+// it as a historical fact.
+//
+// The live fallback only succeeds if at least one registered oracle quotes
+// by mint address rather than by Meteora pair address (Jupiter and Birdeye
+// do; Pyth reads pair.Oracle, which the synthetic pair leaves empty, and the
+// default meteoraPriceOracle doesn't - see its Quote, which calls
+// GetPair(pair.Address)). An enricher built with
+// NewMeteoraDataEnricher's default oracle set will get ErrNoQuotes from the
+// live fallback for every mint; register a mint-keyed oracle via
+// WithPriceOracles for PriceAt to ever succeed past history running dry.
+// This is a real, documented limitation rather than a bug: the pair-price
+// aggregator and this store share the oracle list by design, so the split
+// is visible instead of silently papered over with a second set of oracles.
+func (s *PriceHistoryStore) PriceAt(ctx context.Context, mint string, t time.Time) (float64, bool, error) {
+	key := priceHistoryCacheKey(mint, t)
+	if price, historical, ok := s.cacheGet(key); ok {
+		return price, historical, nil
+	}
+
+	sample, found, err := s.nearestSample(ctx, mint, t)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query price history for %s: %w", mint, err)
+	}
+	if found && withinPriceHistoryMaxAge(sample.UnixSecond, t) {
+		s.cacheSet(key, sample.PriceUSD, true)
+		return sample.PriceUSD, true, nil
+	}
+
+	price, fetchErr := s.fetchLive(ctx, mint)
+	if fetchErr != nil {
+		return 0, false, fmt.Errorf("no price history for %s near %s, and live fetch failed: %w", mint, t, fetchErr)
+	}
+
+	if err := s.RecordSample(ctx, mint, time.Now(), price, "live"); err != nil {
+		return 0, false, fmt.Errorf("failed to persist live price sample for %s: %w", mint, err)
+	}
+
+	s.cacheSet(key, price, false)
+	return price, false, nil
+}
+
+// nearestSample finds the price_history row closest to t for mint, via two
+// one-sided range queries instead of a single ORDER BY ABS(unix_second - t)
+// scan: the latter forces Postgres to evaluate and sort every row for mint
+// regardless of indexing, while "unix_second <= t ORDER BY unix_second DESC
+// LIMIT 1" and "unix_second >= t ORDER BY unix_second ASC LIMIT 1" can each
+// be served by a single index seek against
+// idx_price_history_mint_time_desc - a b-tree index on (mint, unix_second
+// DESC) can be walked in either direction, so it covers both queries. found
+// is false only when mint has no samples on either side of t at all.
+func (s *PriceHistoryStore) nearestSample(ctx context.Context, mint string, t time.Time) (PriceHistorySample, bool, error) {
+	var before PriceHistorySample
+	beforeErr := s.db.WithContext(ctx).
+		Where("mint = ? AND unix_second <= ?", mint, t.Unix()).
+		Order("unix_second DESC").
+		First(&before).Error
+	if beforeErr != nil && beforeErr != gorm.ErrRecordNotFound {
+		return PriceHistorySample{}, false, beforeErr
+	}
+
+	var after PriceHistorySample
+	afterErr := s.db.WithContext(ctx).
+		Where("mint = ? AND unix_second >= ?", mint, t.Unix()).
+		Order("unix_second ASC").
+		First(&after).Error
+	if afterErr != nil && afterErr != gorm.ErrRecordNotFound {
+		return PriceHistorySample{}, false, afterErr
+	}
+
+	haveBefore := beforeErr == nil
+	haveAfter := afterErr == nil
+	switch {
+	case !haveBefore && !haveAfter:
+		return PriceHistorySample{}, false, nil
+	case haveBefore && !haveAfter:
+		return before, true, nil
+	case !haveBefore && haveAfter:
+		return after, true, nil
+	default:
+		if absInt64(t.Unix()-before.UnixSecond) <= absInt64(after.UnixSecond-t.Unix()) {
+			return before, true, nil
+		}
+		return after, true, nil
+	}
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func withinPriceHistoryMaxAge(unixSecond int64, t time.Time) bool {
+	diff := t.Unix() - unixSecond
+	if diff < 0 {
+		diff = -diff
+	}
+	return time.Duration(diff)*time.Second <= priceHistoryMaxAge
+}
+
+// fetchLive quotes mint against the enricher's oracle aggregator via a
+// synthetic pair whose only field any mint-keyed oracle reads is
+// TokenMintX - see PriceAt's doc comment for which oracles that is.
+func (s *PriceHistoryStore) fetchLive(ctx context.Context, mint string) (float64, error) {
+	synthetic := &models.MeteoraPair{Address: mint, TokenMintX: mint}
+	aggregated, err := s.oracles.Aggregate(ctx, synthetic, nil)
+	if err != nil {
+		return 0, err
+	}
+	return aggregated.Value, nil
+}
+
+// RecordSample persists a price observation for mint at t, e.g. one
+// enrichPair just computed for a pair's CurrentPrice, so a later lookup
+// for a nearby t - backfilling older history, or re-enriching a retried
+// transaction - has a denser series to consult instead of hitting the live
+// oracles again.
+func (s *PriceHistoryStore) RecordSample(ctx context.Context, mint string, t time.Time, price float64, source string) error {
+	return s.db.WithContext(ctx).Create(&PriceHistorySample{
+		Mint:       mint,
+		UnixSecond: t.Unix(),
+		PriceUSD:   price,
+		Source:     source,
+	}).Error
+}
+
+// cacheGet returns the cached (price, historical) pair for key, and
+// whether it was found at all.
+func (s *PriceHistoryStore) cacheGet(key string) (float64, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.index[key]
+	if !ok {
+		return 0, false, false
+	}
+	s.order.MoveToFront(el)
+	entry := el.Value.(*priceHistoryCacheEntry)
+	return entry.price, entry.historical, true
+}
+
+func (s *PriceHistoryStore) cacheSet(key string, price float64, historical bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[key]; ok {
+		entry := el.Value.(*priceHistoryCacheEntry)
+		entry.price = price
+		entry.historical = historical
+		s.order.MoveToFront(el)
+		return
+	}
+
+	s.index[key] = s.order.PushFront(&priceHistoryCacheEntry{key: key, price: price, historical: historical})
+	for s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		if oldest == nil {
+			break
+		}
+		s.order.Remove(oldest)
+		delete(s.index, oldest.Value.(*priceHistoryCacheEntry).key)
+	}
+}
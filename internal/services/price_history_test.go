@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestPriceHistoryDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open in-memory sqlite db: %v", err)
+	}
+	if err := db.AutoMigrate(&PriceHistorySample{}); err != nil {
+		t.Fatalf("failed to migrate price_history table: %v", err)
+	}
+	return db
+}
+
+func TestPriceAtCacheHitReturnsStoredHistoricalFlag(t *testing.T) {
+	db := newTestPriceHistoryDB(t)
+	agg := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 99.0, AsOf: time.Now()}})
+	store := NewPriceHistoryStore(db, agg)
+
+	now := time.Now()
+	if err := store.RecordSample(context.Background(), "MintA", now, 1.23, "pair_refresh"); err != nil {
+		t.Fatalf("RecordSample: %v", err)
+	}
+
+	price, historical, err := store.PriceAt(context.Background(), "MintA", now)
+	if err != nil {
+		t.Fatalf("PriceAt: %v", err)
+	}
+	if !historical {
+		t.Errorf("expected historical=true for a sample within priceHistoryMaxAge, got false")
+	}
+	if price != 1.23 {
+		t.Errorf("expected price 1.23, got %f", price)
+	}
+
+	// Same bucketed lookup should now be served from cache, not the oracle.
+	agg2 := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", err: errPriceAtShouldNotBeCalled})
+	store.oracles = agg2
+	price, historical, err = store.PriceAt(context.Background(), "MintA", now)
+	if err != nil {
+		t.Fatalf("PriceAt (cached): %v", err)
+	}
+	if !historical || price != 1.23 {
+		t.Errorf("expected cached historical price 1.23, got price=%f historical=%v", price, historical)
+	}
+}
+
+func TestPriceAtNearMissFallsBackToLiveAndFlagsApproximated(t *testing.T) {
+	db := newTestPriceHistoryDB(t)
+	old := time.Now().Add(-6 * time.Hour)
+
+	// A sample exists for this mint, but it's far outside priceHistoryMaxAge
+	// of the time we're about to query - the "freshly-created table with
+	// only distant samples" case the backfill command hits in practice.
+	if err := db.Create(&PriceHistorySample{Mint: "MintB", UnixSecond: old.Unix(), PriceUSD: 5.0, Source: "pair_refresh"}).Error; err != nil {
+		t.Fatalf("seed sample: %v", err)
+	}
+
+	liveQuote := PriceQuote{OracleID: "a", Value: 42.0, AsOf: time.Now()}
+	agg := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", quote: liveQuote})
+	store := NewPriceHistoryStore(db, agg)
+
+	price, historical, err := store.PriceAt(context.Background(), "MintB", time.Now())
+	if err != nil {
+		t.Fatalf("PriceAt: %v", err)
+	}
+	if historical {
+		t.Errorf("expected historical=false for a live-fallback price, got true")
+	}
+	if price != 42.0 {
+		t.Errorf("expected the live quote 42.0, not the far-away sample 5.0, got %f", price)
+	}
+
+	// The live fallback should have persisted a new sample so a nearby
+	// lookup later doesn't need the oracle again.
+	var count int64
+	db.Model(&PriceHistorySample{}).Where("mint = ?", "MintB").Count(&count)
+	if count != 2 {
+		t.Errorf("expected the live fallback to persist a second sample, found %d rows", count)
+	}
+}
+
+func TestPriceAtNoSampleAtAllFallsBackToLive(t *testing.T) {
+	db := newTestPriceHistoryDB(t)
+	liveQuote := PriceQuote{OracleID: "a", Value: 7.0, AsOf: time.Now()}
+	agg := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", quote: liveQuote})
+	store := NewPriceHistoryStore(db, agg)
+
+	price, historical, err := store.PriceAt(context.Background(), "MintC", time.Now())
+	if err != nil {
+		t.Fatalf("PriceAt: %v", err)
+	}
+	if historical {
+		t.Errorf("expected historical=false with no stored samples at all, got true")
+	}
+	if price != 7.0 {
+		t.Errorf("expected live quote 7.0, got %f", price)
+	}
+}
+
+func TestPriceAtPicksCloserOfSurroundingSamples(t *testing.T) {
+	db := newTestPriceHistoryDB(t)
+	mid := time.Now()
+
+	before := mid.Add(-5 * time.Minute)
+	after := mid.Add(10 * time.Minute)
+	if err := db.Create(&PriceHistorySample{Mint: "MintD", UnixSecond: before.Unix(), PriceUSD: 1.0, Source: "pair_refresh"}).Error; err != nil {
+		t.Fatalf("seed before sample: %v", err)
+	}
+	if err := db.Create(&PriceHistorySample{Mint: "MintD", UnixSecond: after.Unix(), PriceUSD: 2.0, Source: "pair_refresh"}).Error; err != nil {
+		t.Fatalf("seed after sample: %v", err)
+	}
+
+	agg := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", err: errPriceAtShouldNotBeCalled})
+	store := NewPriceHistoryStore(db, agg)
+
+	price, historical, err := store.PriceAt(context.Background(), "MintD", mid)
+	if err != nil {
+		t.Fatalf("PriceAt: %v", err)
+	}
+	if !historical {
+		t.Errorf("expected historical=true, got false")
+	}
+	// "before" is 5 minutes away, "after" is 10 minutes away - the closer
+	// sample, priced 1.0, should win.
+	if price != 1.0 {
+		t.Errorf("expected the closer sample's price 1.0, got %f", price)
+	}
+}
+
+func TestPriceAtOracleMintAddressing(t *testing.T) {
+	// Documents the fetchLive contract: it quotes via a synthetic pair whose
+	// Address and TokenMintX are both the mint, so a mint-keyed fake oracle
+	// (unlike meteoraPriceOracle, which quotes by pair address) can answer
+	// it directly.
+	synthetic := &models.MeteoraPair{Address: "MintE", TokenMintX: "MintE"}
+	if synthetic.Address != synthetic.TokenMintX {
+		t.Fatalf("expected fetchLive's synthetic pair to key Address and TokenMintX identically")
+	}
+
+	agg := NewPriceOracleAggregator(testAggregatorConfig(), &fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 3.0, AsOf: time.Now()}})
+	store := NewPriceHistoryStore(newTestPriceHistoryDB(t), agg)
+	price, err := store.fetchLive(context.Background(), "MintE")
+	if err != nil {
+		t.Fatalf("fetchLive: %v", err)
+	}
+	if price != 3.0 {
+		t.Errorf("expected the mint-keyed quote 3.0, got %f", price)
+	}
+	if got := agg.LastQuotes("MintE"); len(got) != 1 {
+		t.Errorf("expected fetchLive's synthetic pair to be keyed by the mint, found %d quotes under it", len(got))
+	}
+}
+
+var errPriceAtShouldNotBeCalled = &oracleShouldNotBeCalledError{}
+
+type oracleShouldNotBeCalledError struct{}
+
+func (*oracleShouldNotBeCalledError) Error() string {
+	return "oracle should not have been queried: a cached or in-range historical sample should have short-circuited this"
+}
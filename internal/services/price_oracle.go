@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/utils"
+)
+
+// ErrPriceUnreliable is returned by the aggregator when the surviving quotes
+// disagree too much to be trusted. Callers should skip writing USD values
+// rather than persist a corrupted price.
+var ErrPriceUnreliable = fmt.Errorf("price unreliable: sources disagree beyond threshold")
+
+// ErrNoQuotes is returned when every enabled oracle failed or was filtered out.
+var ErrNoQuotes = fmt.Errorf("no usable price quotes")
+
+// PriceQuote is a single oracle's opinion of a pair's price, before aggregation.
+type PriceQuote struct {
+	OracleID   string
+	Value      float64
+	Confidence float64 // relative confidence interval width; 0 when the source doesn't report one
+	AsOf       time.Time
+}
+
+// AggregatedPrice is the result of fanning a pair address out to every
+// enabled oracle and combining the surviving quotes.
+type AggregatedPrice struct {
+	Value   float64
+	Sources []PriceQuote
+	StdDev  float64
+	AsOf    time.Time
+}
+
+// PriceOracle is a pluggable upstream source of pair prices. Implementations
+// wrap a single upstream (Meteora's API, Jupiter, Birdeye, an on-chain Pyth
+// account, ...) and must not block longer than the context allows.
+type PriceOracle interface {
+	// ID identifies the oracle for per-pair blacklisting (meteora_pair_oracles) and
+	// for the raw per-source quotes surfaced on the admin endpoint.
+	ID() string
+	// Quote fetches the current price for the given pair.
+	Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error)
+}
+
+// PriceOracleAggregatorConfig tunes the robust-median-of-feeds aggregation.
+type PriceOracleAggregatorConfig struct {
+	// PerSourceTimeout bounds how long we wait on any single oracle.
+	PerSourceTimeout time.Duration
+	// FreshnessWindow drops quotes older than this from consideration.
+	FreshnessWindow time.Duration
+	// MaxConfidence drops quotes whose reported confidence interval is wider
+	// than this fraction of the quoted value (Pyth-style sources only; a
+	// quote with Confidence == 0 is never filtered on this basis).
+	MaxConfidence float64
+	// MADMultiplier bounds how far a quote may sit from the weighted median,
+	// expressed as a multiple of the median absolute deviation, before it is
+	// rejected as an outlier.
+	MADMultiplier float64
+	// MaxDisagreement is the largest coefficient of variation (stddev /
+	// mean) tolerated across the surviving quotes. Above this, the sources
+	// are considered to disagree too much to trust, even if no single quote
+	// was extreme enough to be trimmed as an outlier.
+	MaxDisagreement float64
+}
+
+// DefaultPriceOracleAggregatorConfig returns the aggregation defaults used
+// when none are supplied.
+func DefaultPriceOracleAggregatorConfig() PriceOracleAggregatorConfig {
+	return PriceOracleAggregatorConfig{
+		PerSourceTimeout: 3 * time.Second,
+		FreshnessWindow:  5 * time.Minute,
+		MaxConfidence:    0.02,
+		MADMultiplier:    3.5,
+		MaxDisagreement:  0.03,
+	}
+}
+
+// PriceOracleAggregator fans a pair out to every enabled oracle and combines
+// the surviving quotes into a single robust price.
+type PriceOracleAggregator struct {
+	config  PriceOracleAggregatorConfig
+	oracles []PriceOracle
+
+	mutex      sync.RWMutex
+	lastQuotes map[string][]PriceQuote  // pair address -> raw per-source quotes, for the admin endpoint
+	status     map[string]*OracleStatus // oracle ID -> running health counters, for the admin endpoint
+}
+
+// OracleStatus summarizes one oracle source's recent health, for the admin
+// API's enricher_oracleStatus method.
+type OracleStatus struct {
+	OracleID    string
+	LastSuccess time.Time
+	LastError   string
+	ErrorCount  int
+}
+
+// NewPriceOracleAggregator builds an aggregator over the given oracles, in
+// the order they should be tried. Order only affects tie-breaking; every
+// enabled oracle is always queried.
+func NewPriceOracleAggregator(config PriceOracleAggregatorConfig, oracles ...PriceOracle) *PriceOracleAggregator {
+	return &PriceOracleAggregator{
+		config:     config,
+		oracles:    oracles,
+		lastQuotes: make(map[string][]PriceQuote),
+		status:     make(map[string]*OracleStatus),
+	}
+}
+
+// Aggregate fans out to every registered oracle for the pair except those
+// listed in disabled (per-pair overrides for excluding a feed that's known
+// bad for a given pool - an oracle with no entry, or an entry with
+// Enabled=true, is queried as normal), drops stale/low-confidence quotes,
+// rejects outliers beyond MADMultiplier median-absolute-deviations from the
+// weighted median, and returns the mean of the survivors.
+func (a *PriceOracleAggregator) Aggregate(ctx context.Context, pair *models.MeteoraPair, disabled map[string]bool) (AggregatedPrice, error) {
+	now := time.Now()
+
+	quotes := a.collectQuotes(ctx, pair, disabled)
+
+	a.mutex.Lock()
+	a.lastQuotes[pair.Address] = quotes
+	a.mutex.Unlock()
+
+	fresh := make([]PriceQuote, 0, len(quotes))
+	for _, q := range quotes {
+		if now.Sub(q.AsOf) > a.config.FreshnessWindow {
+			continue
+		}
+		if q.Confidence > 0 && q.Confidence > a.config.MaxConfidence {
+			continue
+		}
+		fresh = append(fresh, q)
+	}
+
+	if len(fresh) == 0 {
+		return AggregatedPrice{}, ErrNoQuotes
+	}
+
+	median := weightedMedian(fresh)
+	mad := medianAbsoluteDeviation(fresh, median)
+
+	survivors := fresh
+	if mad > 0 {
+		survivors = make([]PriceQuote, 0, len(fresh))
+		for _, q := range fresh {
+			if math.Abs(q.Value-median) <= a.config.MADMultiplier*mad {
+				survivors = append(survivors, q)
+			}
+		}
+	}
+
+	if len(survivors) == 0 {
+		return AggregatedPrice{}, ErrPriceUnreliable
+	}
+
+	mean, stdDev := meanAndStdDev(survivors)
+
+	// Even after trimming outliers, if what's left still disagrees beyond
+	// the configured coefficient of variation, don't hand back a number
+	// callers might mistake for reliable.
+	if mean != 0 && stdDev/math.Abs(mean) > a.config.MaxDisagreement {
+		return AggregatedPrice{Value: mean, Sources: survivors, StdDev: stdDev, AsOf: now}, ErrPriceUnreliable
+	}
+
+	return AggregatedPrice{
+		Value:   mean,
+		Sources: survivors,
+		StdDev:  stdDev,
+		AsOf:    now,
+	}, nil
+}
+
+// LastQuotes returns the most recent raw per-source quotes fetched for a
+// pair, for the admin endpoint to surface when operators are debugging a
+// divergence.
+func (a *PriceOracleAggregator) LastQuotes(pairAddress string) []PriceQuote {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return append([]PriceQuote(nil), a.lastQuotes[pairAddress]...)
+}
+
+// Status returns a snapshot of every registered oracle's recent health, for
+// the admin API's enricher_oracleStatus method. Oracles that have never been
+// queried are omitted.
+func (a *PriceOracleAggregator) Status() []OracleStatus {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	statuses := make([]OracleStatus, 0, len(a.status))
+	for _, s := range a.status {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].OracleID < statuses[j].OracleID })
+	return statuses
+}
+
+func (a *PriceOracleAggregator) recordSuccess(oracleID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := a.statusFor(oracleID)
+	s.LastSuccess = time.Now()
+}
+
+func (a *PriceOracleAggregator) recordError(oracleID string, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := a.statusFor(oracleID)
+	s.LastError = err.Error()
+	s.ErrorCount++
+}
+
+// statusFor returns the status entry for oracleID, creating it if necessary.
+// Callers must hold a.mutex.
+func (a *PriceOracleAggregator) statusFor(oracleID string) *OracleStatus {
+	s, ok := a.status[oracleID]
+	if !ok {
+		s = &OracleStatus{OracleID: oracleID}
+		a.status[oracleID] = s
+	}
+	return s
+}
+
+func (a *PriceOracleAggregator) collectQuotes(ctx context.Context, pair *models.MeteoraPair, disabled map[string]bool) []PriceQuote {
+	var wg sync.WaitGroup
+	results := make([]PriceQuote, len(a.oracles))
+	ok := make([]bool, len(a.oracles))
+
+	for i, oracle := range a.oracles {
+		if disabled[oracle.ID()] {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, oracle PriceOracle) {
+			defer wg.Done()
+
+			octx, cancel := context.WithTimeout(ctx, a.config.PerSourceTimeout)
+			defer cancel()
+
+			quote, err := oracle.Quote(octx, pair)
+			if err != nil {
+				a.recordError(oracle.ID(), err)
+				return
+			}
+			a.recordSuccess(oracle.ID())
+			results[i] = quote
+			ok[i] = true
+		}(i, oracle)
+	}
+	wg.Wait()
+
+	quotes := make([]PriceQuote, 0, len(a.oracles))
+	for i, present := range ok {
+		if present {
+			quotes = append(quotes, results[i])
+		}
+	}
+	return quotes
+}
+
+// weightedMedian returns the median value across quotes. All oracles carry
+// equal weight today; the per-pair blacklist is the mechanism for excluding
+// a feed entirely rather than down-weighting it.
+func weightedMedian(quotes []PriceQuote) float64 {
+	values := make([]float64, len(quotes))
+	for i, q := range quotes {
+		values[i] = q.Value
+	}
+	sort.Float64s(values)
+
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
+
+func medianAbsoluteDeviation(quotes []PriceQuote, median float64) float64 {
+	deviations := make([]float64, len(quotes))
+	for i, q := range quotes {
+		deviations[i] = math.Abs(q.Value - median)
+	}
+	sort.Float64s(deviations)
+
+	mid := len(deviations) / 2
+	if len(deviations)%2 == 1 {
+		return deviations[mid]
+	}
+	return (deviations[mid-1] + deviations[mid]) / 2
+}
+
+func meanAndStdDev(quotes []PriceQuote) (mean float64, stdDev float64) {
+	if len(quotes) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, q := range quotes {
+		sum += q.Value
+	}
+	mean = sum / float64(len(quotes))
+
+	if len(quotes) == 1 {
+		return mean, 0
+	}
+
+	var sumSquares float64
+	for _, q := range quotes {
+		d := q.Value - mean
+		sumSquares += d * d
+	}
+	stdDev = math.Sqrt(sumSquares / float64(len(quotes)))
+
+	return mean, stdDev
+}
+
+// meteoraPriceOracle adapts the existing MeteoraPubClient into a PriceOracle.
+type meteoraPriceOracle struct {
+	client *MeteoraPubClient
+}
+
+// NewMeteoraPriceOracle wraps an existing Meteora API client as a PriceOracle.
+func NewMeteoraPriceOracle(client *MeteoraPubClient) PriceOracle {
+	return &meteoraPriceOracle{client: client}
+}
+
+func (o *meteoraPriceOracle) ID() string { return "meteora" }
+
+func (o *meteoraPriceOracle) Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error) {
+	pairInfo, err := o.client.GetPair(pair.Address)
+	if err != nil {
+		return PriceQuote{}, fmt.Errorf("meteora oracle: %w", err)
+	}
+
+	return PriceQuote{
+		OracleID: o.ID(),
+		Value:    pairInfo.CurrentPrice,
+		AsOf:     time.Now(),
+	}, nil
+}
+
+// jupiterPriceOracle quotes a pair's token-X mint against Jupiter's price API.
+type jupiterPriceOracle struct {
+	httpClient *utils.HTTPClient
+}
+
+// NewJupiterPriceOracle builds a PriceOracle backed by the Jupiter price API.
+func NewJupiterPriceOracle() PriceOracle {
+	return &jupiterPriceOracle{
+		httpClient: utils.NewHTTPClient(
+			utils.WithBaseURL("https://price.jup.ag/v6"),
+			utils.WithTimeout(5*time.Second),
+		),
+	}
+}
+
+type jupiterPriceResponse struct {
+	Data map[string]struct {
+		Price float64 `json:"price"`
+	} `json:"data"`
+}
+
+func (o *jupiterPriceOracle) ID() string { return "jupiter" }
+
+func (o *jupiterPriceOracle) Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error) {
+	resp, err := o.httpClient.Do(&utils.Request{
+		Method:      "GET",
+		Path:        "/price",
+		QueryParams: utils.OptionalParameter{"ids": pair.TokenMintX},
+		Context:     ctx,
+	})
+	if err != nil {
+		return PriceQuote{}, fmt.Errorf("jupiter oracle: %w", err)
+	}
+
+	var parsed jupiterPriceResponse
+	if err := resp.DecodeJSON(&parsed); err != nil {
+		return PriceQuote{}, fmt.Errorf("jupiter oracle: decode response: %w", err)
+	}
+
+	entry, ok := parsed.Data[pair.TokenMintX]
+	if !ok {
+		return PriceQuote{}, fmt.Errorf("jupiter oracle: no price for mint %s", pair.TokenMintX)
+	}
+
+	return PriceQuote{
+		OracleID: o.ID(),
+		Value:    entry.Price,
+		AsOf:     time.Now(),
+	}, nil
+}
+
+// birdeyePriceOracle quotes a pair's token-X mint against the Birdeye API.
+type birdeyePriceOracle struct {
+	httpClient *utils.HTTPClient
+}
+
+// NewBirdeyePriceOracle builds a PriceOracle backed by the Birdeye API. Set
+// apiKey to the value of the BIRDEYE_API_KEY environment variable.
+func NewBirdeyePriceOracle(apiKey string) PriceOracle {
+	return &birdeyePriceOracle{
+		httpClient: utils.NewHTTPClient(
+			utils.WithBaseURL("https://public-api.birdeye.so"),
+			utils.WithTimeout(5*time.Second),
+			utils.WithDefaultHeaders(map[string]string{"X-API-KEY": apiKey}),
+		),
+	}
+}
+
+type birdeyePriceResponse struct {
+	Data struct {
+		Value      float64 `json:"value"`
+		UpdateUnix int64   `json:"updateUnixTime"`
+	} `json:"data"`
+	Success bool `json:"success"`
+}
+
+func (o *birdeyePriceOracle) ID() string { return "birdeye" }
+
+func (o *birdeyePriceOracle) Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error) {
+	resp, err := o.httpClient.Do(&utils.Request{
+		Method:      "GET",
+		Path:        "/defi/price",
+		QueryParams: utils.OptionalParameter{"address": pair.TokenMintX},
+		Context:     ctx,
+	})
+	if err != nil {
+		return PriceQuote{}, fmt.Errorf("birdeye oracle: %w", err)
+	}
+
+	var parsed birdeyePriceResponse
+	if err := resp.DecodeJSON(&parsed); err != nil {
+		return PriceQuote{}, fmt.Errorf("birdeye oracle: decode response: %w", err)
+	}
+	if !parsed.Success {
+		return PriceQuote{}, fmt.Errorf("birdeye oracle: request unsuccessful")
+	}
+
+	return PriceQuote{
+		OracleID: o.ID(),
+		Value:    parsed.Data.Value,
+		AsOf:     time.Unix(parsed.Data.UpdateUnix, 0),
+	}, nil
+}
+
+// PythAccountReader fetches the raw account bytes backing a Pyth price
+// account, so the oracle itself stays testable without a live RPC client.
+type PythAccountReader interface {
+	ReadPythAccount(ctx context.Context, address string) ([]byte, error)
+}
+
+// pythPriceOracle reads a pair's on-chain Pyth price account. The account
+// address is the MeteoraPair.Oracle field already populated by the parser.
+type pythPriceOracle struct {
+	reader PythAccountReader
+}
+
+// NewPythPriceOracle builds a PriceOracle that reads price/confidence
+// straight out of a Pyth price account via reader.
+func NewPythPriceOracle(reader PythAccountReader) PriceOracle {
+	return &pythPriceOracle{reader: reader}
+}
+
+func (o *pythPriceOracle) ID() string { return "pyth" }
+
+// Pyth V2 price account layout: the fields we need sit at fixed byte
+// offsets within the account (see Pyth's solana-program IDL). This parses
+// just enough of the account to pull a price/confidence/exponent triple.
+const (
+	pythOffsetExpo    = 20
+	pythOffsetPrice   = 208
+	pythOffsetConf    = 216
+	pythMinAccountLen = 224
+)
+
+func (o *pythPriceOracle) Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error) {
+	if pair.Oracle == "" {
+		return PriceQuote{}, fmt.Errorf("pyth oracle: pair has no oracle account")
+	}
+
+	data, err := o.reader.ReadPythAccount(ctx, pair.Oracle)
+	if err != nil {
+		return PriceQuote{}, fmt.Errorf("pyth oracle: %w", err)
+	}
+	if len(data) < pythMinAccountLen {
+		return PriceQuote{}, fmt.Errorf("pyth oracle: account too short (%d bytes)", len(data))
+	}
+
+	expo := int32(leUint32(data[pythOffsetExpo:]))
+	rawPrice := int64(leUint64(data[pythOffsetPrice:]))
+	rawConf := leUint64(data[pythOffsetConf:])
+
+	scale := math.Pow(10, float64(expo))
+	price := float64(rawPrice) * scale
+	conf := float64(rawConf) * scale
+
+	var confidence float64
+	if price != 0 {
+		confidence = math.Abs(conf / price)
+	}
+
+	return PriceQuote{
+		OracleID:   o.ID(),
+		Value:      price,
+		Confidence: confidence,
+		AsOf:       time.Now(),
+	}, nil
+}
+
+func leUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+func leUint64(b []byte) uint64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v |= uint64(b[i]) << (8 * i)
+	}
+	return v
+}
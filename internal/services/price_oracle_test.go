@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wnt/mercon/internal/models"
+)
+
+// fakePriceOracle is a scripted PriceOracle for aggregation tests.
+type fakePriceOracle struct {
+	id    string
+	quote PriceQuote
+	err   error
+}
+
+func (o *fakePriceOracle) ID() string { return o.id }
+
+func (o *fakePriceOracle) Quote(ctx context.Context, pair *models.MeteoraPair) (PriceQuote, error) {
+	if o.err != nil {
+		return PriceQuote{}, o.err
+	}
+	return o.quote, nil
+}
+
+func testAggregatorConfig() PriceOracleAggregatorConfig {
+	return PriceOracleAggregatorConfig{
+		PerSourceTimeout: time.Second,
+		FreshnessWindow:  time.Hour,
+		MaxConfidence:    0.02,
+		MADMultiplier:    3.5,
+		MaxDisagreement:  0.03,
+	}
+}
+
+func TestAggregateAllOraclesFail(t *testing.T) {
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", err: errors.New("unreachable")},
+		&fakePriceOracle{id: "b", err: errors.New("unreachable")},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairA"}
+	_, err := agg.Aggregate(context.Background(), pair, nil)
+	if err != ErrNoQuotes {
+		t.Fatalf("expected ErrNoQuotes, got %v", err)
+	}
+}
+
+func TestAggregateRejectsOutlier(t *testing.T) {
+	now := time.Now()
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 1.00, AsOf: now}},
+		&fakePriceOracle{id: "b", quote: PriceQuote{OracleID: "b", Value: 1.01, AsOf: now}},
+		&fakePriceOracle{id: "c", quote: PriceQuote{OracleID: "c", Value: 0.99, AsOf: now}},
+		&fakePriceOracle{id: "d", quote: PriceQuote{OracleID: "d", Value: 50.0, AsOf: now}},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairB"}
+	result, err := agg.Aggregate(context.Background(), pair, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, s := range result.Sources {
+		if s.OracleID == "d" {
+			t.Fatalf("expected outlier source 'd' to be excluded, sources: %+v", result.Sources)
+		}
+	}
+	if result.Value < 0.9 || result.Value > 1.1 {
+		t.Errorf("expected aggregated value near 1.0, got %f", result.Value)
+	}
+}
+
+func TestAggregateMarksDisagreementUnreliable(t *testing.T) {
+	now := time.Now()
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 1.0, AsOf: now}},
+		&fakePriceOracle{id: "b", quote: PriceQuote{OracleID: "b", Value: 2.0, AsOf: now}},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairC"}
+	_, err := agg.Aggregate(context.Background(), pair, nil)
+	if err != ErrPriceUnreliable {
+		t.Fatalf("expected ErrPriceUnreliable, got %v", err)
+	}
+}
+
+func TestAggregateRespectsBlacklist(t *testing.T) {
+	now := time.Now()
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 1.0, AsOf: now}},
+		&fakePriceOracle{id: "b", quote: PriceQuote{OracleID: "b", Value: 1.0, AsOf: now}},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairD"}
+	result, err := agg.Aggregate(context.Background(), pair, map[string]bool{"b": true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Sources) != 1 || result.Sources[0].OracleID != "a" {
+		t.Errorf("expected only source 'a' to be used once 'b' is blacklisted, got %+v", result.Sources)
+	}
+}
+
+// TestAggregateNoBlacklistUsesEveryOracle guards against the inverted bug
+// this replaced: disabling one bad oracle for a pair must not silently drop
+// every other oracle that pair has no row for.
+func TestAggregateNoBlacklistUsesEveryOracle(t *testing.T) {
+	now := time.Now()
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 1.0, AsOf: now}},
+		&fakePriceOracle{id: "b", quote: PriceQuote{OracleID: "b", Value: 1.0, AsOf: now}},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairF"}
+	result, err := agg.Aggregate(context.Background(), pair, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Sources) != 2 {
+		t.Errorf("expected both oracles to be used with no blacklist, got %+v", result.Sources)
+	}
+}
+
+func TestAggregateDropsStaleQuotes(t *testing.T) {
+	agg := NewPriceOracleAggregator(testAggregatorConfig(),
+		&fakePriceOracle{id: "a", quote: PriceQuote{OracleID: "a", Value: 1.0, AsOf: time.Now().Add(-2 * time.Hour)}},
+	)
+
+	pair := &models.MeteoraPair{Address: "PairE"}
+	_, err := agg.Aggregate(context.Background(), pair, nil)
+	if err != ErrNoQuotes {
+		t.Fatalf("expected ErrNoQuotes for stale quote, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// RaydiumClient is scaffolding for a DEXClient backed by Raydium's CLMM
+// API. mercon has no Raydium integration today - no base URL, endpoint
+// shapes, or response types have been verified against Raydium's actual
+// API anywhere in this codebase - so every method here returns
+// ErrCapabilityNotSupported and Capabilities reports nothing, rather than
+// guessing at a wire format this repo has never talked to. Replace this
+// with a real utils.HTTPClient-backed implementation once that integration
+// exists, following MeteoraPubClient/MeteoraDEXClient's shape.
+type RaydiumClient struct{}
+
+// NewRaydiumClient returns a RaydiumClient. See the type's doc comment: it
+// is not yet backed by a real Raydium API client.
+func NewRaydiumClient() *RaydiumClient {
+	return &RaydiumClient{}
+}
+
+func (r *RaydiumClient) Capabilities() DEXCapability { return 0 }
+
+func (r *RaydiumClient) ListPairs(ctx context.Context, filter PairFilter) ([]PairInfo, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (r *RaydiumClient) GetPair(ctx context.Context, address string) (*PairInfo, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (r *RaydiumClient) ListPositions(ctx context.Context, owner string) ([]Position, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (r *RaydiumClient) GetPositionSnapshot(ctx context.Context, address string) (*PositionSnapshot, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (r *RaydiumClient) ListSwaps(ctx context.Context, pair string, since time.Time) ([]DEXSwap, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+func (r *RaydiumClient) Quote(ctx context.Context, tokenIn, tokenOut string, amountIn uint64) (*DEXQuote, error) {
+	return nil, ErrCapabilityNotSupported
+}
+
+var _ DEXClient = (*RaydiumClient)(nil)
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wnt/mercon/internal/models"
+)
+
+// swapVectorsDir holds the golden test-vector corpus for calculateSwapUSD.
+const swapVectorsDir = "testdata/swap_vectors"
+
+// updateSwapVectors regenerates every vector's expected section from the
+// current calculateSwapUSD output, for use after an intentional change to
+// the calculation: `go test ./internal/services -run TestSwapCalculations -update`.
+var updateSwapVectors = flag.Bool("update", false, "regenerate expected outputs in testdata/swap_vectors")
+
+// swapVector is the on-disk shape of one swap-calculation golden vector:
+// raw swap input, the pool/price context it was computed against, and the
+// USD amounts calculateSwapUSD is expected to produce. tokenXDecimals,
+// tokenYDecimals, and pool are recorded for context and for calculations
+// that grow to need them, even though calculateSwapUSD doesn't apply
+// decimal scaling today - see its doc comment.
+type swapVector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Swap        struct {
+		SwapForY  bool   `json:"swapForY"`
+		AmountIn  uint64 `json:"amountIn"`
+		AmountOut uint64 `json:"amountOut"`
+		Fee       uint64 `json:"fee"`
+	} `json:"swap"`
+	TokenXDecimals int `json:"tokenXDecimals"`
+	TokenYDecimals int `json:"tokenYDecimals"`
+	Pool           struct {
+		Address string `json:"address"`
+	} `json:"pool"`
+	PriceFeed struct {
+		Price float64 `json:"price"`
+	} `json:"priceFeed"`
+	Expected struct {
+		AmountInUSD  float64 `json:"amountInUSD"`
+		AmountOutUSD float64 `json:"amountOutUSD"`
+		FeeUSD       float64 `json:"feeUSD"`
+	} `json:"expected"`
+}
+
+// TestSwapCalculations runs every testdata/swap_vectors/*.json vector
+// through calculateSwapUSD and checks the result against the vector's
+// recorded expectation. Add a new edge case (extreme decimals, a zero-fee
+// swap, price=0, ...) by dropping a file in rather than editing this test.
+func TestSwapCalculations(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(swapVectorsDir, "*.json"))
+	if err != nil {
+		t.Fatalf("globbing vectors: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no swap vectors found under %s", swapVectorsDir)
+	}
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading vector: %v", err)
+			}
+
+			var v swapVector
+			if err := json.Unmarshal(raw, &v); err != nil {
+				t.Fatalf("decoding vector: %v", err)
+			}
+
+			swap := &models.MeteoraSwap{
+				SwapForY:  v.Swap.SwapForY,
+				AmountIn:  v.Swap.AmountIn,
+				AmountOut: v.Swap.AmountOut,
+				Fee:       v.Swap.Fee,
+			}
+			amountInUSD, amountOutUSD, feeUSD := calculateSwapUSD(swap, v.PriceFeed.Price)
+
+			if *updateSwapVectors {
+				v.Expected.AmountInUSD = amountInUSD
+				v.Expected.AmountOutUSD = amountOutUSD
+				v.Expected.FeeUSD = feeUSD
+
+				updated, err := json.MarshalIndent(v, "", "  ")
+				if err != nil {
+					t.Fatalf("re-encoding vector: %v", err)
+				}
+				if err := os.WriteFile(path, append(updated, '\n'), 0o644); err != nil {
+					t.Fatalf("writing updated vector: %v", err)
+				}
+				return
+			}
+
+			const epsilon = 1e-9
+			if math.Abs(amountInUSD-v.Expected.AmountInUSD) > epsilon {
+				t.Errorf("AmountInUSD = %v, want %v", amountInUSD, v.Expected.AmountInUSD)
+			}
+			if math.Abs(amountOutUSD-v.Expected.AmountOutUSD) > epsilon {
+				t.Errorf("AmountOutUSD = %v, want %v", amountOutUSD, v.Expected.AmountOutUSD)
+			}
+			if math.Abs(feeUSD-v.Expected.FeeUSD) > epsilon {
+				t.Errorf("FeeUSD = %v, want %v", feeUSD, v.Expected.FeeUSD)
+			}
+		})
+	}
+}
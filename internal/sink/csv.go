@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/wnt/mercon/internal/scanner"
+)
+
+var csvHeader = []string{"signature", "slot", "blockTime", "dammCount", "dlmmCount", "damm", "dlmm", "error"}
+
+// csvSink writes one row per transaction. The DAMM/DLMM instructions
+// themselves don't flatten into columns (their Args vary per instruction
+// type), so each is embedded as a JSON string column instead - CSV readers
+// that want structure should use the NDJSON or Parquet sink.
+type csvSink struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// CSV returns a Sink that writes comma-separated rows to w, header first.
+func CSV(w io.Writer) Sink {
+	return &csvSink{w: csv.NewWriter(w)}
+}
+
+func (s *csvSink) Write(tx scanner.ParsedTx) error {
+	if !s.wroteHeader {
+		if err := s.w.Write(csvHeader); err != nil {
+			return fmt.Errorf("writing CSV header: %w", err)
+		}
+		s.wroteHeader = true
+	}
+
+	damm, err := json.Marshal(tx.DAMM)
+	if err != nil {
+		return fmt.Errorf("encoding damm column: %w", err)
+	}
+	dlmm, err := json.Marshal(tx.DLMM)
+	if err != nil {
+		return fmt.Errorf("encoding dlmm column: %w", err)
+	}
+
+	errColumn := ""
+	if tx.Err != nil {
+		errColumn = tx.Err.Error()
+	}
+
+	row := []string{
+		tx.Signature,
+		fmt.Sprintf("%d", tx.Slot),
+		tx.BlockTime.Format("2006-01-02T15:04:05Z07:00"),
+		fmt.Sprintf("%d", len(tx.DAMM)),
+		fmt.Sprintf("%d", len(tx.DLMM)),
+		string(damm),
+		string(dlmm),
+		errColumn,
+	}
+	if err := s.w.Write(row); err != nil {
+		return fmt.Errorf("writing CSV row: %w", err)
+	}
+	return nil
+}
+
+func (s *csvSink) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
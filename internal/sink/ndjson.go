@@ -0,0 +1,27 @@
+package sink
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/wnt/mercon/internal/scanner"
+)
+
+// ndjson writes one JSON object per line, the shape most log/analytics
+// pipelines (Helius, BigQuery load jobs, jq) expect.
+type ndjson struct {
+	enc *json.Encoder
+}
+
+// NDJSON returns a Sink that writes newline-delimited JSON to w.
+func NDJSON(w io.Writer) Sink {
+	return &ndjson{enc: json.NewEncoder(w)}
+}
+
+func (s *ndjson) Write(tx scanner.ParsedTx) error {
+	return s.enc.Encode(tx)
+}
+
+func (s *ndjson) Close() error {
+	return nil
+}
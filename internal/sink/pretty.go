@@ -0,0 +1,79 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/wnt/mercon/internal/scanner"
+)
+
+// prettySink reproduces cmd/simple_run's original emoji-decorated,
+// human-readable output - now just one Sink implementation among several
+// rather than the only way to see scan results.
+type prettySink struct {
+	w io.Writer
+	n int
+}
+
+// Pretty returns the human-readable Sink cmd/simple_run used to print
+// directly, selected with -format=pretty.
+func Pretty(w io.Writer) Sink {
+	return &prettySink{w: w}
+}
+
+func (s *prettySink) Write(tx scanner.ParsedTx) error {
+	s.n++
+	fmt.Fprintf(s.w, "\n🔍 Transaction #%d\n", s.n)
+	fmt.Fprintf(s.w, "📝 Signature: %s\n", tx.Signature)
+
+	if tx.Err != nil {
+		fmt.Fprintf(s.w, "❌ %v\n", tx.Err)
+		return nil
+	}
+
+	if !tx.BlockTime.IsZero() {
+		fmt.Fprintf(s.w, "⏰ Block Time: %s\n", tx.BlockTime.Format("2006-01-02 15:04:05 UTC"))
+	}
+	if tx.Slot != 0 {
+		fmt.Fprintf(s.w, "🎯 Slot: %d\n", tx.Slot)
+	}
+
+	if len(tx.DAMM) > 0 {
+		fmt.Fprintf(s.w, "🟢 DAMM Instructions Found: %d\n", len(tx.DAMM))
+		for i, inst := range tx.DAMM {
+			fmt.Fprintf(s.w, "  %d. Type: %s\n", i+1, inst.Type)
+			if inst.Parsed != nil {
+				fmt.Fprintf(s.w, "     Parsed Data: %s\n", formatParsed(inst.Parsed))
+			}
+		}
+	}
+
+	if len(tx.DLMM) > 0 {
+		fmt.Fprintf(s.w, "🔵 DLMM Instructions Found: %d\n", len(tx.DLMM))
+		for i, inst := range tx.DLMM {
+			fmt.Fprintf(s.w, "  %d. Type: %s\n", i+1, inst.Type)
+			if inst.Parsed != nil {
+				fmt.Fprintf(s.w, "     Parsed Data: %s\n", formatParsed(inst.Parsed))
+			}
+		}
+	}
+
+	if len(tx.DAMM) == 0 && len(tx.DLMM) == 0 {
+		fmt.Fprintf(s.w, "⚪ No Meteora instructions found\n")
+	}
+
+	return nil
+}
+
+func (s *prettySink) Close() error {
+	return nil
+}
+
+func formatParsed(parsed interface{}) string {
+	data, err := json.MarshalIndent(parsed, "     ", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", parsed)
+	}
+	return string(data)
+}
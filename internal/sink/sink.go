@@ -0,0 +1,15 @@
+// Package sink renders scanner.ParsedTx values to an output format: NDJSON
+// or CSV for downstream analytics pipelines, Parquet for columnar storage,
+// or the original emoji-decorated text cmd/simple_run used to print
+// directly.
+package sink
+
+import "github.com/wnt/mercon/internal/scanner"
+
+// Sink consumes parsed transactions one at a time. Close flushes any
+// buffered output and releases underlying resources (e.g. a Parquet
+// writer's footer).
+type Sink interface {
+	Write(tx scanner.ParsedTx) error
+	Close() error
+}
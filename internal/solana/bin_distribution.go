@@ -0,0 +1,192 @@
+package solana
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// basisPointsDenominator is the fixed-point denominator the DLMM program
+// uses for bps fields like BinLiquidityDistribution.distribution_x/y and
+// BinLiquidityReduction.bps_to_remove - the same convention MeteoraSwap's
+// FeeBps already uses.
+const basisPointsDenominator = 10000
+
+// binShare is one entry of an addLiquidity instruction's
+// Vec<BinLiquidityDistribution>: bin_id (i32) plus each side's share of the
+// operation's amountX/amountY, expressed in basis points.
+type binShare struct {
+	binID         int32
+	distributionX uint16
+	distributionY uint16
+}
+
+// decodeBinLiquidityDistribution decodes a Borsh-encoded
+// Vec<BinLiquidityDistribution> starting at offset in data: a 4-byte LE
+// element count followed by that many (bin_id: i32, distribution_x: u16,
+// distribution_y: u16) entries.
+func decodeBinLiquidityDistribution(data []byte, offset int) ([]binShare, error) {
+	if len(data) < offset+4 {
+		return nil, fmt.Errorf("insufficient data for bin distribution length")
+	}
+	count := int(binary.LittleEndian.Uint32(data[offset:]))
+	offset += 4
+
+	shares := make([]binShare, 0, count)
+	for i := 0; i < count; i++ {
+		if len(data) < offset+8 {
+			return nil, fmt.Errorf("insufficient data for bin distribution entry %d", i)
+		}
+		shares = append(shares, binShare{
+			binID:         int32(binary.LittleEndian.Uint32(data[offset:])),
+			distributionX: binary.LittleEndian.Uint16(data[offset+4:]),
+			distributionY: binary.LittleEndian.Uint16(data[offset+6:]),
+		})
+		offset += 8
+	}
+	return shares, nil
+}
+
+// binReduction is one entry of a removeLiquidity instruction's
+// Vec<BinLiquidityReduction>: bin_id (i32) plus the percentage of that
+// bin's own liquidity being removed, in basis points.
+type binReduction struct {
+	binID       int32
+	bpsToRemove uint16
+}
+
+// decodeBinLiquidityReduction decodes a Borsh-encoded
+// Vec<BinLiquidityReduction> starting at offset in data: a 4-byte LE
+// element count followed by that many (bin_id: i32, bps_to_remove: u16)
+// entries.
+func decodeBinLiquidityReduction(data []byte, offset int) ([]binReduction, error) {
+	if len(data) < offset+4 {
+		return nil, fmt.Errorf("insufficient data for bin reduction length")
+	}
+	count := int(binary.LittleEndian.Uint32(data[offset:]))
+	offset += 4
+
+	reductions := make([]binReduction, 0, count)
+	for i := 0; i < count; i++ {
+		if len(data) < offset+6 {
+			return nil, fmt.Errorf("insufficient data for bin reduction entry %d", i)
+		}
+		reductions = append(reductions, binReduction{
+			binID:       int32(binary.LittleEndian.Uint32(data[offset:])),
+			bpsToRemove: binary.LittleEndian.Uint16(data[offset+4:]),
+		})
+		offset += 6
+	}
+	return reductions, nil
+}
+
+// StrategyType mirrors the DLMM program's on-chain enum tag for how
+// addLiquidityByStrategy(OneSide) spreads liquidity across
+// [minBinID, maxBinID].
+type StrategyType uint8
+
+const (
+	StrategySpot StrategyType = iota
+	StrategyCurve
+	StrategyBidAsk
+	StrategySpotImBalanced
+	StrategyCurveImBalanced
+	StrategyBidAskImBalanced
+)
+
+// strategyParameters is the fixed-size prefix of a StrategyParameters
+// argument this parser decodes: min_bin_id (i32), max_bin_id (i32), and the
+// strategy_type enum tag (u8). The struct's remaining 64-byte curve/skew
+// shape payload isn't decoded - strategyWeights reproduces each strategy's
+// canonical shape rather than the on-chain program's exact weight formula.
+type strategyParameters struct {
+	minBinID     int32
+	maxBinID     int32
+	strategyType StrategyType
+}
+
+// strategyParametersSize is the byte length of the prefix
+// decodeStrategyParameters reads.
+const strategyParametersSize = 9
+
+func decodeStrategyParameters(data []byte, offset int) (strategyParameters, error) {
+	if len(data) < offset+strategyParametersSize {
+		return strategyParameters{}, fmt.Errorf("insufficient data for strategy parameters")
+	}
+	return strategyParameters{
+		minBinID:     int32(binary.LittleEndian.Uint32(data[offset:])),
+		maxBinID:     int32(binary.LittleEndian.Uint32(data[offset+4:])),
+		strategyType: StrategyType(data[offset+8]),
+	}, nil
+}
+
+// normalizeStrategyType folds the three "ImBalanced" variants onto their
+// balanced counterpart: on-chain, those only change how amountX/amountY
+// split across the pair's two sides, not the per-bin distribution shape
+// strategyWeights reconstructs.
+func normalizeStrategyType(t StrategyType) StrategyType {
+	switch t {
+	case StrategySpotImBalanced:
+		return StrategySpot
+	case StrategyCurveImBalanced:
+		return StrategyCurve
+	case StrategyBidAskImBalanced:
+		return StrategyBidAsk
+	default:
+		return t
+	}
+}
+
+// strategyWeights computes each bin in [minBinID, maxBinID]'s share (summing
+// to 1 across the range) of a strategy liquidity operation: Spot spreads
+// liquidity uniformly, Curve concentrates it around activeID with a bell
+// curve, and BidAsk skews it toward the two edges of the range.
+func strategyWeights(minBinID, maxBinID, activeID int32, strategyType StrategyType) map[int32]float64 {
+	if maxBinID < minBinID {
+		return nil
+	}
+
+	weights := make(map[int32]float64, maxBinID-minBinID+1)
+	mid := float64(minBinID+maxBinID) / 2
+	spread := float64(maxBinID-minBinID) / 2
+	if spread == 0 {
+		spread = 1
+	}
+
+	switch normalizeStrategyType(strategyType) {
+	case StrategyCurve:
+		sigma := spread / 2
+		for bin := minBinID; bin <= maxBinID; bin++ {
+			d := float64(bin-activeID) / sigma
+			weights[bin] = math.Exp(-0.5 * d * d)
+		}
+	case StrategyBidAsk:
+		for bin := minBinID; bin <= maxBinID; bin++ {
+			// +0.01 keeps the bin nearest the center from getting a
+			// literal zero share.
+			weights[bin] = math.Abs(float64(bin)-mid)/spread + 0.01
+		}
+	default: // Spot
+		for bin := minBinID; bin <= maxBinID; bin++ {
+			weights[bin] = 1
+		}
+	}
+
+	return normalizeWeights(weights)
+}
+
+// normalizeWeights scales weights in place so its values sum to 1, leaving
+// it unchanged if they already sum to 0.
+func normalizeWeights(weights map[int32]float64) map[int32]float64 {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return weights
+	}
+	for bin, w := range weights {
+		weights[bin] = w / total
+	}
+	return weights
+}
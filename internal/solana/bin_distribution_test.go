@@ -0,0 +1,121 @@
+package solana
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeBinLiquidityDistribution(shares []binShare) []byte {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, uint32(len(shares)))
+	for _, s := range shares {
+		entry := make([]byte, 8)
+		binary.LittleEndian.PutUint32(entry[0:], uint32(s.binID))
+		binary.LittleEndian.PutUint16(entry[4:], s.distributionX)
+		binary.LittleEndian.PutUint16(entry[6:], s.distributionY)
+		data = append(data, entry...)
+	}
+	return data
+}
+
+func TestDecodeBinLiquidityDistribution(t *testing.T) {
+	want := []binShare{
+		{binID: -5, distributionX: 4000, distributionY: 1000},
+		{binID: 0, distributionX: 6000, distributionY: 9000},
+	}
+	data := encodeBinLiquidityDistribution(want)
+
+	got, err := decodeBinLiquidityDistribution(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestDecodeBinLiquidityDistribution_Truncated(t *testing.T) {
+	data := encodeBinLiquidityDistribution([]binShare{{binID: 1, distributionX: 1, distributionY: 1}})
+	_, err := decodeBinLiquidityDistribution(data[:len(data)-1], 0)
+	assert.Error(t, err)
+}
+
+func encodeBinLiquidityReduction(reductions []binReduction) []byte {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, uint32(len(reductions)))
+	for _, r := range reductions {
+		entry := make([]byte, 6)
+		binary.LittleEndian.PutUint32(entry[0:], uint32(r.binID))
+		binary.LittleEndian.PutUint16(entry[4:], r.bpsToRemove)
+		data = append(data, entry...)
+	}
+	return data
+}
+
+func TestDecodeBinLiquidityReduction(t *testing.T) {
+	want := []binReduction{
+		{binID: 10, bpsToRemove: 5000},
+		{binID: 11, bpsToRemove: 10000},
+	}
+	data := encodeBinLiquidityReduction(want)
+
+	got, err := decodeBinLiquidityReduction(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestDecodeStrategyParameters(t *testing.T) {
+	data := make([]byte, strategyParametersSize)
+	minBinID, maxBinID := int32(-10), int32(10)
+	binary.LittleEndian.PutUint32(data[0:], uint32(minBinID))
+	binary.LittleEndian.PutUint32(data[4:], uint32(maxBinID))
+	data[8] = byte(StrategyCurve)
+
+	got, err := decodeStrategyParameters(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, strategyParameters{minBinID: -10, maxBinID: 10, strategyType: StrategyCurve}, got)
+}
+
+func TestDecodeStrategyParameters_Truncated(t *testing.T) {
+	_, err := decodeStrategyParameters(make([]byte, strategyParametersSize-1), 0)
+	assert.Error(t, err)
+}
+
+func TestStrategyWeightsSpotIsUniform(t *testing.T) {
+	weights := strategyWeights(-2, 2, 0, StrategySpot)
+	assert.Len(t, weights, 5)
+	for _, w := range weights {
+		assert.InDelta(t, 0.2, w, 1e-9)
+	}
+}
+
+func TestStrategyWeightsCurvePeaksAtActiveID(t *testing.T) {
+	weights := strategyWeights(-5, 5, 0, StrategyCurve)
+	for bin, w := range weights {
+		if bin != 0 {
+			assert.Less(t, w, weights[0])
+		}
+	}
+}
+
+func TestStrategyWeightsImBalancedFoldsToBalanced(t *testing.T) {
+	balanced := strategyWeights(-3, 3, 1, StrategyBidAsk)
+	imbalanced := strategyWeights(-3, 3, 1, StrategyBidAskImBalanced)
+	assert.Len(t, imbalanced, len(balanced))
+	for bin, w := range balanced {
+		assert.InDelta(t, w, imbalanced[bin], 1e-9)
+	}
+}
+
+func TestStrategyWeightsSumToOne(t *testing.T) {
+	for _, st := range []StrategyType{StrategySpot, StrategyCurve, StrategyBidAsk} {
+		weights := strategyWeights(-4, 6, 2, st)
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+		assert.InDelta(t, 1.0, total, 1e-9)
+	}
+}
+
+func TestStrategyWeightsInvalidRange(t *testing.T) {
+	assert.Nil(t, strategyWeights(5, 1, 0, StrategySpot))
+}
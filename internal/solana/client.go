@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"strconv"
 	"time"
 
+	gagliardettosolana "github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/wnt/mercon/internal/constants"
+	"github.com/wnt/mercon/internal/events"
 	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/parserstore"
 	"github.com/wnt/mercon/internal/utils"
 	"gorm.io/gorm"
 )
@@ -19,6 +23,20 @@ import (
 // Default configuration values
 const (
 	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is how many additional attempts a raw RPC call
+	// (GetSlot, GetBlocks, ReadAccounts, ...) makes, each against whichever
+	// endpoint EndpointPool.Pick returns (possibly the same one), before
+	// giving up, if RPC_MAX_RETRIES isn't set.
+	DefaultMaxRetries = 3
+	// DefaultQuarantineDuration is how long EndpointPool.Pick skips an
+	// endpoint after it crosses its consecutive-error threshold, if
+	// RPC_QUARANTINE_DURATION isn't set.
+	DefaultQuarantineDuration = 30 * time.Second
+	// retryBaseDelay and retryMaxDelay bound callWithRetry's jittered
+	// exponential backoff between attempts: ~100-200ms, ~200-400ms,
+	// ~400-800ms, ... capped at retryMaxDelay.
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
 )
 
 // Error types for better error handling
@@ -30,17 +48,25 @@ var (
 
 // Client represents a connection to the Solana blockchain
 type Client struct {
-	rpcClient  *rpc.Client
-	endpoint   string
+	pool       *EndpointPool
+	maxRetries int
 	httpClient *utils.HTTPClient
 }
 
 // ClientConfig holds the configuration for the Solana client
 type ClientConfig struct {
-	RPCURL  string
-	APIKey  string
-	Timeout time.Duration
-	BaseURL string
+	// RPCURL is Endpoints[0].URL, kept for callers and tests that only
+	// care about the primary endpoint.
+	RPCURL string
+	// Endpoints is the list of RPC endpoints the client's EndpointPool
+	// picks among, parsed from RPC_ENDPOINTS (falling back to the
+	// singular RPC_URL for backward compatibility - see
+	// loadConfigFromEnv).
+	Endpoints          []EndpointSpec
+	MaxRetries         int
+	QuarantineDuration time.Duration
+	Timeout            time.Duration
+	BaseURL            string
 }
 
 // Filters represents optional filters for transaction queries
@@ -56,13 +82,21 @@ func NewClient() (*Client, error) {
 		return nil, err
 	}
 
-	rpcClient := rpc.New(config.RPCURL)
+	pool := NewEndpointPool(config.Endpoints,
+		WithEndpointMaxConsecutiveErrors(defaultEndpointMaxConsecutiveErrors),
+		WithEndpointQuarantineDuration(config.QuarantineDuration),
+	)
 
-	// Check connection by getting the latest block height
+	// Check connectivity by getting the latest block height from whichever
+	// endpoint the pool picks first - a single endpoint failing here
+	// shouldn't fail client construction when others are configured, so
+	// this goes through the same retry path every other call uses.
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
 
-	_, err = rpcClient.GetBlockHeight(ctx, rpc.CommitmentFinalized)
+	_, err = callWithRetry(ctx, pool, config.MaxRetries, "getBlockHeight", func(ctx context.Context, c *rpc.Client) (uint64, error) {
+		return c.GetBlockHeight(ctx, rpc.CommitmentFinalized)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Solana RPC: %w", err)
 	}
@@ -74,23 +108,44 @@ func NewClient() (*Client, error) {
 	)
 
 	return &Client{
-		rpcClient:  rpcClient,
-		endpoint:   config.RPCURL,
+		pool:       pool,
+		maxRetries: config.MaxRetries,
 		httpClient: httpClient,
 	}, nil
 }
 
-// loadConfigFromEnv loads configuration from environment variables
+// loadConfigFromEnv loads configuration from environment variables.
+//
+// It deliberately keeps its own env parsing rather than pulling in
+// internal/config.Config (which already has an RPCEndpoints field feeding
+// internal/rpc.Pool): that Config requires a full deployment's worth of
+// unrelated env vars (database DSNs, queue backend, ...) just to call
+// Load(), which would make *Client - used by lightweight tooling as well
+// as the scraper - drag in all of it. RPC_ENDPOINTS is read under the
+// same name as internal/config's so one env var configures both pools in
+// a real deployment; RPC_URL (singular) is kept as a fallback for
+// existing single-endpoint setups and tests.
 func loadConfigFromEnv() (ClientConfig, error) {
-	rpcURL := os.Getenv("RPC_URL")
-	if rpcURL == "" {
+	var specs []EndpointSpec
+	if endpointsStr := os.Getenv("RPC_ENDPOINTS"); endpointsStr != "" {
+		parsed, err := ParseEndpointSpecs(endpointsStr)
+		if err != nil {
+			return ClientConfig{}, fmt.Errorf("invalid RPC_ENDPOINTS: %w", err)
+		}
+		specs = parsed
+	} else if rpcURL := os.Getenv("RPC_URL"); rpcURL != "" {
+		specs = []EndpointSpec{{URL: rpcURL, Weight: 1}}
+	} else {
 		return ClientConfig{}, ErrMissingRPCURL
 	}
 
 	config := ClientConfig{
-		RPCURL:  rpcURL,
-		Timeout: DefaultTimeout,
-		BaseURL: constants.HeliusBaseURL,
+		RPCURL:             specs[0].URL,
+		Endpoints:          specs,
+		MaxRetries:         DefaultMaxRetries,
+		QuarantineDuration: DefaultQuarantineDuration,
+		Timeout:            DefaultTimeout,
+		BaseURL:            constants.HeliusBaseURL,
 	}
 
 	// Parse timeout if set
@@ -100,9 +155,69 @@ func loadConfigFromEnv() (ClientConfig, error) {
 		}
 	}
 
+	if retriesStr := os.Getenv("RPC_MAX_RETRIES"); retriesStr != "" {
+		if val, err := strconv.Atoi(retriesStr); err == nil && val >= 0 {
+			config.MaxRetries = val
+		}
+	}
+
+	if quarantineStr := os.Getenv("RPC_QUARANTINE_DURATION"); quarantineStr != "" {
+		if val, err := time.ParseDuration(quarantineStr); err == nil && val > 0 {
+			config.QuarantineDuration = val
+		}
+	}
+
 	return config, nil
 }
 
+// callWithRetry runs fn against EndpointPool's chosen endpoint, retrying
+// against whichever endpoint Pick returns next, which may be the same one
+// (bounded by maxRetries, not by the scraper's MaxConcurrent - a single
+// call's retry budget and the scraper's overall concurrency are
+// orthogonal) with jittered exponential backoff between attempts. It's the
+// retry path GetBlocks, ReadAccounts and NewClient's connectivity check go
+// through; GetSlot runs its own equivalent loop (see GetSlot for why).
+func callWithRetry[T any](ctx context.Context, pool *EndpointPool, maxRetries int, method string, fn func(ctx context.Context, client *rpc.Client) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		client, url, err := pool.Pick(ctx)
+		if err != nil {
+			return zero, err
+		}
+
+		start := time.Now()
+		result, callErr := fn(ctx, client)
+		pool.RecordOutcome(url, method, time.Since(start), callErr)
+		if callErr == nil {
+			return result, nil
+		}
+
+		lastErr = fmt.Errorf("%s via %s: %w", method, url, callErr)
+		if attempt == maxRetries {
+			break
+		}
+
+		select {
+		case <-time.After(retryBackoff(attempt)):
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	return zero, lastErr
+}
+
+// retryBackoff returns callWithRetry's jittered exponential delay before
+// its (attempt+1)'th try: ~100-200ms, ~200-400ms, ~400-800ms, ... capped
+// at retryMaxDelay.
+func retryBackoff(attempt int) time.Duration {
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt))
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
 // GetTransactions retrieves transactions for the specified wallet address
 func (c *Client) GetTransactions(ctx context.Context, address string, filters Filters) ([]Transaction, error) {
 	apiKey := os.Getenv("HELIUS_API_KEY")
@@ -111,7 +226,7 @@ func (c *Client) GetTransactions(ctx context.Context, address string, filters Fi
 	}
 
 	// Build query parameters
-	queryParams := map[string]string{
+	queryParams := utils.OptionalParameter{
 		"api-key": apiKey,
 	}
 
@@ -139,8 +254,119 @@ func (c *Client) GetTransactions(ctx context.Context, address string, filters Fi
 	return transactions, nil
 }
 
-// GetAndParseTransactions retrieves and parses transactions for the specified wallet address
-func (c *Client) GetAndParseTransactions(ctx context.Context, address string, filters Filters) ([]*models.Transaction, error) {
+// GetTransactionBySignature retrieves a single transaction by its signature.
+// It is used by tooling (e.g. cmd/vectorgen) that needs one specific
+// transaction rather than a wallet's recent history.
+func (c *Client) GetTransactionBySignature(ctx context.Context, signature string) (*Transaction, error) {
+	apiKey := os.Getenv("HELIUS_API_KEY")
+	if apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+
+	path := fmt.Sprintf("/v0/transactions?api-key=%s", apiKey)
+	resp, err := c.httpClient.Post(path, map[string][]string{
+		"transactions": {signature},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+
+	var transactions []Transaction
+	if err := json.Unmarshal(resp.Body, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction %s: %w", signature, err)
+	}
+
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("transaction %s not found", signature)
+	}
+
+	return &transactions[0], nil
+}
+
+// GetSlot returns the current slot at the given commitment level. It
+// exists so *Client satisfies reorg.BlockLister without that package (or
+// its callers) needing to reach into the underlying pool directly.
+//
+// Unlike GetBlocks/ReadAccounts it doesn't go through callWithRetry: it
+// needs the answering endpoint's URL (to feed EndpointPool.RecordSlot for
+// SlotLag), and callWithRetry's generic signature has nowhere to return
+// that alongside the slot.
+func (c *Client) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		client, url, err := c.pool.Pick(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		start := time.Now()
+		slot, callErr := client.GetSlot(ctx, commitment)
+		c.pool.RecordOutcome(url, "getSlot", time.Since(start), callErr)
+		if callErr == nil {
+			c.pool.RecordSlot(url, slot)
+			return slot, nil
+		}
+
+		lastErr = fmt.Errorf("getSlot via %s: %w", url, callErr)
+		if attempt == c.maxRetries {
+			break
+		}
+		select {
+		case <-time.After(retryBackoff(attempt)):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	return 0, lastErr
+}
+
+// GetBlocks returns the canonical slots in [startSlot, *endSlot] at the
+// given commitment level. See GetSlot.
+func (c *Client) GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error) {
+	return callWithRetry(ctx, c.pool, c.maxRetries, "getBlocks", func(ctx context.Context, rc *rpc.Client) (rpc.BlocksResult, error) {
+		return rc.GetBlocks(ctx, startSlot, endSlot, commitment)
+	})
+}
+
+// ReadAccounts fetches the raw account data for addresses in a single
+// getMultipleAccounts round trip, so *Client satisfies PairAccountReader
+// without PairMetadataResolver (or its callers) needing to reach into the
+// underlying pool directly. See GetSlot.
+func (c *Client) ReadAccounts(ctx context.Context, addresses []string) ([][]byte, error) {
+	keys := make([]gagliardettosolana.PublicKey, len(addresses))
+	for i, address := range addresses {
+		key, err := gagliardettosolana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, fmt.Errorf("parsing address %s: %w", address, err)
+		}
+		keys[i] = key
+	}
+
+	result, err := callWithRetry(ctx, c.pool, c.maxRetries, "getMultipleAccounts", func(ctx context.Context, rc *rpc.Client) (*rpc.GetMultipleAccountsResult, error) {
+		return rc.GetMultipleAccounts(ctx, keys...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getMultipleAccounts: %w", err)
+	}
+
+	data := make([][]byte, len(result.Value))
+	for i, account := range result.Value {
+		if account == nil {
+			continue
+		}
+		data[i] = account.Data.GetBinary()
+	}
+	return data, nil
+}
+
+// GetAndParseTransactions retrieves and parses transactions for the
+// specified wallet address. store resolves the pairs, positions, wallets,
+// and rewards the parser encounters - callers that have a *gorm.DB should
+// pass a parserstore.NewGormStore(db) (wrapped in parserstore.NewCachedStore
+// if processing transactions concurrently) so derived rows land in the
+// real database; callers without one (e.g. tooling) can pass
+// parserstore.NewMemoryStore().
+func (c *Client) GetAndParseTransactions(ctx context.Context, address string, filters Filters, store parserstore.Store) ([]*models.Transaction, error) {
 	transactions, err := c.GetTransactions(ctx, address, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
@@ -150,7 +376,14 @@ func (c *Client) GetAndParseTransactions(ctx context.Context, address string, fi
 		return []*models.Transaction{}, nil
 	}
 
-	txParser := NewTransactionParser(c)
+	parserOpts := []TransactionParserOption{WithPairMetadataResolver(NewPairMetadataResolver(c))}
+	if registry, err := NewDefaultInstructionRegistry(); err != nil {
+		fmt.Printf("Warning: failed to load Meteora DLMM IDL, swap fee/bin fields will stay zeroed: %v\n", err)
+	} else {
+		parserOpts = append(parserOpts, WithInstructionRegistry(registry))
+	}
+
+	txParser := NewTransactionParser(c, store, parserOpts...)
 
 	// Process transactions with proper error handling
 	var parsedTransactions []*models.Transaction
@@ -169,14 +402,20 @@ func (c *Client) GetAndParseTransactions(ctx context.Context, address string, fi
 	return parsedTransactions, nil
 }
 
-// SaveTransactions saves transactions to the database
-func SaveTransactions(db *gorm.DB, walletID uint, transactions []*models.Transaction) error {
+// SaveTransactions saves transactions to the database. If publisher is
+// non-nil, it's notified about every transaction (and the Meteora swaps /
+// fee claims nested under it) actually created, once the whole batch has
+// committed - not from inside the db.Transaction below, so a publish never
+// fires for a row that ends up rolling back.
+func SaveTransactions(db *gorm.DB, walletID uint, transactions []*models.Transaction, publisher events.Publisher) error {
 	if len(transactions) == 0 {
 		return nil
 	}
 
+	var created []*models.Transaction
+
 	// Use a transaction to ensure data consistency
-	return db.Transaction(func(tx *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
 		for _, transaction := range transactions {
 			transaction.WalletID = walletID
 
@@ -195,8 +434,60 @@ func SaveTransactions(db *gorm.DB, walletID uint, transactions []*models.Transac
 			if err := tx.Create(transaction).Error; err != nil {
 				return fmt.Errorf("failed to save transaction %s: %w", transaction.Signature, err)
 			}
+			created = append(created, transaction)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	publishCreatedEvents(publisher, created)
+	return nil
+}
+
+// publishCreatedEvents notifies publisher about every transaction (and its
+// swaps/fee claims) SaveTransactions just committed. publisher may be nil
+// - e.g. in tests, or a deployment with no webhook subscriptions wired up
+// - in which case this is a no-op.
+func publishCreatedEvents(publisher events.Publisher, created []*models.Transaction) {
+	if publisher == nil {
+		return
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, transaction := range created {
+		if err := publisher.Publish(ctx, events.Event{
+			Type:       events.TypeTransactionCreated,
+			ID:         fmt.Sprintf("transaction:%d", transaction.ID),
+			OccurredAt: now,
+			Payload:    transaction,
+		}); err != nil {
+			fmt.Printf("Warning: failed to publish transaction.created event for %s: %v\n", transaction.Signature, err)
+		}
+
+		for i := range transaction.Swaps {
+			if err := publisher.Publish(ctx, events.Event{
+				Type:       events.TypeMeteoraSwapCreated,
+				ID:         fmt.Sprintf("meteora_swap:%d", transaction.Swaps[i].ID),
+				OccurredAt: now,
+				Payload:    transaction.Swaps[i],
+			}); err != nil {
+				fmt.Printf("Warning: failed to publish meteora_swap.created event for transaction %s: %v\n", transaction.Signature, err)
+			}
+		}
+
+		for i := range transaction.FeeClaims {
+			if err := publisher.Publish(ctx, events.Event{
+				Type:       events.TypeMeteoraFeeClaimCreated,
+				ID:         fmt.Sprintf("meteora_fee_claim:%d", transaction.FeeClaims[i].ID),
+				OccurredAt: now,
+				Payload:    transaction.FeeClaims[i],
+			}); err != nil {
+				fmt.Printf("Warning: failed to publish meteora_fee_claim.created event for transaction %s: %v\n", transaction.Signature, err)
+			}
+		}
+	}
 }
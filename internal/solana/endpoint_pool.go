@@ -0,0 +1,388 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gorpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/wnt/mercon/internal/metrics"
+	mercrpc "github.com/wnt/mercon/internal/rpc"
+)
+
+// endpointScoreRingSize is how many of an endpoint's most recent outcomes
+// feed its success ratio. Mirrors internal/rpc.Pool's scoreRingSize - this
+// pool picks among typed *gorpc.Client connections (GetSlot, GetBlocks,
+// GetMultipleAccounts, ...) rather than raw HTTP+JSON-RPC, so it can't
+// reuse that pool directly, but the selection math is deliberately the
+// same power-of-two-choices-over-a-success-ratio/latency score.
+const endpointScoreRingSize = 128
+
+// endpointScoreLatencyEpsilonSeconds keeps an endpoint with a near-zero
+// latency EWMA (or none yet) from producing an unbounded score.
+const endpointScoreLatencyEpsilonSeconds = 0.001
+
+// defaultEndpointMaxConsecutiveErrors is how many consecutive failures an
+// endpoint tolerates before EndpointPool quarantines it.
+const defaultEndpointMaxConsecutiveErrors = 3
+
+// defaultEndpointQuarantineDuration is how long a quarantined endpoint is
+// skipped by Pick before it's offered again.
+const defaultEndpointQuarantineDuration = 30 * time.Second
+
+// endpointLatencyEWMAAlpha weights how much a single observation moves an
+// endpoint's rolling average latency. Same value as internal/rpc.Pool's
+// latencyEWMAAlpha.
+const endpointLatencyEWMAAlpha = 0.2
+
+// EndpointSpec is one endpoint parsed out of an RPC_ENDPOINTS-style
+// comma-separated list, with its optional weight suffix.
+type EndpointSpec struct {
+	URL    string
+	Weight int
+}
+
+// ParseEndpointSpecs splits a comma-separated RPC_ENDPOINTS value into
+// EndpointSpecs, honoring a trailing "?w=N" query parameter as the
+// endpoint's selection weight (e.g. "https://a?w=3,https://b?w=1" gives
+// the paid Helius endpoint 3x the traffic of the public fallback). An
+// endpoint with no "?w=" suffix, or an invalid one, gets the default
+// weight of 1. The "?w=" parameter is stripped before the URL is used -
+// it's a pool-selection hint, not part of the actual RPC URL.
+func ParseEndpointSpecs(raw string) ([]EndpointSpec, error) {
+	parts := strings.Split(raw, ",")
+	specs := make([]EndpointSpec, 0, len(parts))
+	for _, part := range parts {
+		url := strings.TrimSpace(part)
+		if url == "" {
+			continue
+		}
+		weight := 1
+		if idx := strings.Index(url, "?w="); idx != -1 {
+			if w, err := strconv.Atoi(url[idx+len("?w="):]); err == nil && w > 0 {
+				weight = w
+			}
+			url = url[:idx]
+		}
+		specs = append(specs, EndpointSpec{URL: url, Weight: weight})
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints found in %q", raw)
+	}
+	return specs, nil
+}
+
+// poolEndpoint is one EndpointPool member: a typed RPC client plus the
+// rolling health stats EndpointPool.Pick scores it by.
+type poolEndpoint struct {
+	url    string
+	weight int
+	client *gorpc.Client
+
+	mu                sync.Mutex
+	consecutiveErrors int
+	quarantinedUntil  time.Time
+	latencyEWMA       time.Duration
+	successRing       [endpointScoreRingSize]bool
+	successRingLen    int
+	successRingPos    int
+	lastKnownSlot     uint64
+}
+
+func (e *poolEndpoint) isUsable() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.quarantinedUntil)
+}
+
+func (e *poolEndpoint) recordOutcomeLocked(success bool) {
+	if e.successRingLen < endpointScoreRingSize {
+		e.successRing[e.successRingLen] = success
+		e.successRingLen++
+	} else {
+		e.successRing[e.successRingPos] = success
+	}
+	e.successRingPos = (e.successRingPos + 1) % endpointScoreRingSize
+}
+
+func (e *poolEndpoint) successRatioLocked() float64 {
+	if e.successRingLen == 0 {
+		return 1.0
+	}
+	successes := 0
+	for i := 0; i < e.successRingLen; i++ {
+		if e.successRing[i] {
+			successes++
+		}
+	}
+	return float64(successes) / float64(e.successRingLen)
+}
+
+// score returns this endpoint's selection weight: its configured weight
+// times its success ratio, divided by its latency EWMA in seconds. Same
+// shape as internal/rpc.Pool's Endpoint.score without the rate-limit
+// term - EndpointPool's callers (Client's typed RPC methods) don't see a
+// raw HTTP status code to feed one.
+func (e *poolEndpoint) score() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	latencySeconds := e.latencyEWMA.Seconds()
+	return float64(e.weight) * e.successRatioLocked() / (latencySeconds + endpointScoreLatencyEpsilonSeconds)
+}
+
+// EndpointPool is a health-scored pool of Solana RPC endpoints for
+// *Client, tracking per-endpoint EWMA latency, rolling success ratio, and
+// last-known slot, and picking among the healthy ones with
+// power-of-two-choices so a single standout endpoint doesn't monopolize
+// traffic. It exists alongside (and does not replace) internal/rpc.Pool,
+// which already does the equivalent job for the raw HTTP+JSON-RPC calls
+// internal/worker.Manager makes; EndpointPool instead wraps the typed
+// *gorpc.Client methods (GetSlot, GetBlocks, GetMultipleAccounts, ...)
+// *Client calls directly.
+type EndpointPool struct {
+	endpoints            []*poolEndpoint
+	maxConsecutiveErrors int
+	quarantineDuration   time.Duration
+}
+
+// EndpointPoolOption customizes an EndpointPool at construction time.
+type EndpointPoolOption func(*EndpointPool)
+
+// WithEndpointMaxConsecutiveErrors overrides how many consecutive
+// failures an endpoint tolerates before RecordOutcome quarantines it. The default
+// is defaultEndpointMaxConsecutiveErrors.
+func WithEndpointMaxConsecutiveErrors(n int) EndpointPoolOption {
+	return func(p *EndpointPool) { p.maxConsecutiveErrors = n }
+}
+
+// WithEndpointQuarantineDuration overrides how long a quarantined
+// endpoint is skipped before Pick offers it again. The default is
+// defaultEndpointQuarantineDuration.
+func WithEndpointQuarantineDuration(d time.Duration) EndpointPoolOption {
+	return func(p *EndpointPool) { p.quarantineDuration = d }
+}
+
+// NewEndpointPool builds an EndpointPool from specs, each getting its own
+// *gorpc.Client and rate-limit-free HTTP transport (timeouts are enforced
+// by the context callers pass to Pick's returned client, same as today's
+// single-endpoint Client).
+func NewEndpointPool(specs []EndpointSpec, opts ...EndpointPoolOption) *EndpointPool {
+	p := &EndpointPool{
+		endpoints:            make([]*poolEndpoint, len(specs)),
+		maxConsecutiveErrors: defaultEndpointMaxConsecutiveErrors,
+		quarantineDuration:   defaultEndpointQuarantineDuration,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for i, spec := range specs {
+		weight := spec.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		p.endpoints[i] = &poolEndpoint{
+			url:    spec.URL,
+			weight: weight,
+			client: gorpc.New(spec.URL),
+		}
+		metrics.SetRPCEndpointHealth(spec.URL, true)
+	}
+	return p
+}
+
+// Pick returns the healthiest endpoint's URL and typed RPC client under
+// power-of-two-choices: it samples two weighted candidates and keeps
+// whichever scores higher, same rationale as internal/rpc.Pool's
+// getClientScored. Quarantined endpoints are skipped entirely; if every
+// endpoint is quarantined, Pick falls back to the least-recently-failed
+// one rather than returning an error, since a transient all-down state
+// shouldn't stop *Client from trying.
+func (p *EndpointPool) Pick(ctx context.Context) (*gorpc.Client, string, error) {
+	var candidates []*poolEndpoint
+	for _, ep := range p.endpoints {
+		if ep.isUsable() {
+			candidates = append(candidates, ep)
+		}
+	}
+	if len(candidates) == 0 {
+		return p.pickLeastRecentlyQuarantined()
+	}
+
+	best := weightedEndpointPick(candidates)
+	if len(candidates) > 1 {
+		if other := weightedEndpointPick(candidates); other.score() > best.score() {
+			best = other
+		}
+	}
+
+	metrics.SetRPCEndpointScore(best.url, best.score(), best.successRatioLockedSnapshot())
+	return best.client, best.url, nil
+}
+
+// successRatioLockedSnapshot is successRatioLocked with its own locking,
+// for callers (like Pick's metrics reporting) that aren't already holding
+// e.mu.
+func (e *poolEndpoint) successRatioLockedSnapshot() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.successRatioLocked()
+}
+
+// pickLeastRecentlyQuarantined is Pick's fallback for "every endpoint is
+// currently quarantined": it returns whichever endpoint's quarantine
+// expires soonest, so *Client's retry loop keeps making forward progress
+// instead of failing outright.
+func (p *EndpointPool) pickLeastRecentlyQuarantined() (*gorpc.Client, string, error) {
+	if len(p.endpoints) == 0 {
+		return nil, "", fmt.Errorf("endpoint pool has no endpoints configured")
+	}
+	best := p.endpoints[0]
+	bestUntil := best.quarantineUntilSnapshot()
+	for _, ep := range p.endpoints[1:] {
+		if until := ep.quarantineUntilSnapshot(); until.Before(bestUntil) {
+			best, bestUntil = ep, until
+		}
+	}
+	return best.client, best.url, nil
+}
+
+func (e *poolEndpoint) quarantineUntilSnapshot() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.quarantinedUntil
+}
+
+// weightedEndpointPick samples one endpoint from candidates with
+// probability proportional to its score.
+func weightedEndpointPick(candidates []*poolEndpoint) *poolEndpoint {
+	scores := make([]float64, len(candidates))
+	total := 0.0
+	for i, ep := range candidates {
+		scores[i] = ep.score()
+		total += scores[i]
+	}
+	if total <= 0 {
+		return candidates[rand.Intn(len(candidates))]
+	}
+	r := rand.Float64() * total
+	for i, s := range scores {
+		r -= s
+		if r <= 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// RecordOutcome folds the result of one RPC call against url into its
+// endpoint's rolling stats: latency only on success (a failed call's
+// latency is usually just however long it took to time out, not a
+// meaningful signal), and a consecutive-error count that quarantines the
+// endpoint for p.quarantineDuration once it reaches
+// p.maxConsecutiveErrors. It also records the call against the shared
+// internal/rpc.ErrorClass-tagged Prometheus metrics internal/rpc.Pool
+// already publishes under the same endpoint/method labels.
+func (p *EndpointPool) RecordOutcome(url, method string, latency time.Duration, err error) {
+	ep := p.endpointByURL(url)
+	if ep == nil {
+		return
+	}
+
+	ep.mu.Lock()
+	if err == nil {
+		ep.consecutiveErrors = 0
+		ep.recordOutcomeLocked(true)
+		if ep.latencyEWMA == 0 {
+			ep.latencyEWMA = latency
+		} else {
+			ep.latencyEWMA = time.Duration(endpointLatencyEWMAAlpha*float64(latency) + (1-endpointLatencyEWMAAlpha)*float64(ep.latencyEWMA))
+		}
+	} else {
+		ep.consecutiveErrors++
+		ep.recordOutcomeLocked(false)
+	}
+	consecutiveErrors := ep.consecutiveErrors
+	ep.mu.Unlock()
+
+	if err == nil {
+		metrics.SetRPCEndpointHealth(url, true)
+		metrics.RecordRPCRequest(url, method, "success")
+		return
+	}
+
+	metrics.RecordRPCRequest(url, method, "error")
+	metrics.RecordRPCError(url, method, mercrpc.ErrorClass(err))
+
+	if consecutiveErrors < p.maxConsecutiveErrors {
+		return
+	}
+
+	ep.mu.Lock()
+	ep.quarantinedUntil = time.Now().Add(p.quarantineDuration)
+	ep.mu.Unlock()
+	metrics.SetRPCEndpointHealth(url, false)
+}
+
+// RecordSlot records url's most recently observed slot (from a getSlot
+// call), so SlotLag can report how far behind it is relative to the
+// pool's best-known tip. It's purely observational - unlike latency and
+// success ratio, slot lag doesn't currently feed Pick's score, since only
+// call sites that already fetch a slot (GetSlot itself) produce a
+// reading, so most endpoints would otherwise never get one.
+func (p *EndpointPool) RecordSlot(url string, slot uint64) {
+	ep := p.endpointByURL(url)
+	if ep == nil {
+		return
+	}
+	ep.mu.Lock()
+	ep.lastKnownSlot = slot
+	ep.mu.Unlock()
+}
+
+// SlotLag returns, for every endpoint with at least one recorded slot,
+// how far behind the pool's highest observed slot it currently is.
+// Endpoints that have never answered a getSlot call are omitted.
+func (p *EndpointPool) SlotLag() map[string]uint64 {
+	var tip uint64
+	slots := make(map[string]uint64, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		slot := ep.lastKnownSlot
+		ep.mu.Unlock()
+		if slot == 0 {
+			continue
+		}
+		slots[ep.url] = slot
+		if slot > tip {
+			tip = slot
+		}
+	}
+
+	lag := make(map[string]uint64, len(slots))
+	for url, slot := range slots {
+		lag[url] = tip - slot
+	}
+	return lag
+}
+
+func (p *EndpointPool) endpointByURL(url string) *poolEndpoint {
+	for _, ep := range p.endpoints {
+		if ep.url == url {
+			return ep
+		}
+	}
+	return nil
+}
+
+// URLs returns the pool's configured endpoint URLs, in pool order.
+func (p *EndpointPool) URLs() []string {
+	urls := make([]string, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		urls[i] = ep.url
+	}
+	return urls
+}
@@ -0,0 +1,75 @@
+package solana
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wnt/mercon/internal/constants"
+	"github.com/wnt/mercon/internal/idl"
+)
+
+// DefaultIDLDir is where NewInstructionRegistry looks for program IDLs when
+// no directory is given explicitly, mirroring conformance.DefaultVectorsDir.
+const DefaultIDLDir = "internal/solana/testdata/idl"
+
+// InstructionRegistry decodes Meteora DLMM CPI event logs - and, for any
+// program whose real deployed instruction dispatch does follow the
+// standard Anchor convention, top-level instructions too - by their 8-byte
+// sha256("global:<name>")/sha256("event:<name>") discriminators, loaded
+// from the program's Anchor IDL. It's a thin wrapper around idl.Parser that
+// fixes the program ID to constants.MeteoraDLMM.
+//
+// TransactionParser does NOT use DispatchInstruction to decode Meteora
+// DLMM's own top-level instructions: a captured real swap
+// (testdata/vectors/dlmm_swap.json) is 17 bytes - a 1-byte opcode plus two
+// u64 args - not the 24 bytes an 8-byte discriminator would require, so the
+// deployed program evidently uses a custom 1-byte instruction discriminator
+// rather than Anchor's sighash convention. processMeteoraTxInstruction's
+// single-byte switch matches that reality and stays authoritative for
+// dispatch. emit_cpi! event logs are unaffected by that choice - Anchor's
+// CPI event macro always uses the standard "event:<name>" discriminator
+// regardless of how a program dispatches its own instructions - so
+// DecodeEvent is what TransactionParser actually relies on, to populate
+// swap fields (Fee, ProtocolFee, FeeBps, StartBinID, EndBinID) that can
+// only come from the program's own emitted event, not its instruction args.
+type InstructionRegistry struct {
+	parser *idl.Parser
+}
+
+// NewInstructionRegistry loads every IDL under dir (see idl.Parser.LoadDir)
+// and registers them by their own metadata.address.
+func NewInstructionRegistry(dir string) (*InstructionRegistry, error) {
+	parser := idl.NewParser()
+	if err := parser.LoadDir(dir); err != nil {
+		return nil, fmt.Errorf("loading IDL directory %s: %w", dir, err)
+	}
+	return &InstructionRegistry{parser: parser}, nil
+}
+
+// NewDefaultInstructionRegistry loads IDLs from $MERCON_DLMM_IDL_DIR, or
+// DefaultIDLDir if that's unset - the same env-override-for-the-data-dir
+// convention as conformance's MERCON_METEORA_VECTORS_DIR and vectorgen's
+// MERCON_VECTORS_DIR. It's the constructor callers that just want "the
+// real DLMM IDL, wherever this deployment keeps it" should use.
+func NewDefaultInstructionRegistry() (*InstructionRegistry, error) {
+	dir := os.Getenv("MERCON_DLMM_IDL_DIR")
+	if dir == "" {
+		dir = DefaultIDLDir
+	}
+	return NewInstructionRegistry(dir)
+}
+
+// DispatchInstruction decodes a top-level Meteora DLMM instruction's data
+// and account list by its 8-byte Anchor discriminator. See the
+// InstructionRegistry doc comment for why TransactionParser itself doesn't
+// call this today.
+func (r *InstructionRegistry) DispatchInstruction(data []byte, accounts []string) (*idl.ParsedInstruction, error) {
+	return r.parser.Parse(constants.MeteoraDLMM, data, accounts)
+}
+
+// DecodeEvent decodes one Anchor CPI event emitted by the DLMM program via
+// emit_cpi! - the data of a self-invoked inner instruction - by its 8-byte
+// "event:<name>" discriminator.
+func (r *InstructionRegistry) DecodeEvent(data []byte) (*idl.ParsedEvent, error) {
+	return r.parser.ParseEvent(constants.MeteoraDLMM, data)
+}
@@ -0,0 +1,381 @@
+package solana
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	gagliardettosolana "github.com/gagliardetto/solana-go"
+	"github.com/wnt/mercon/internal/metrics"
+)
+
+// PairMetadata is the decoded subset of a Meteora DLMM LbPair account that
+// isXToken (and future callers) need: which mint is the pair's X side, and
+// each side's decimals for display/USD-value math.
+type PairMetadata struct {
+	TokenXMint     string
+	TokenYMint     string
+	BinStep        uint16
+	TokenXDecimals uint8
+	TokenYDecimals uint8
+}
+
+// PairAccountReader fetches the raw bytes of one or more Solana accounts in
+// a single round trip, so PairMetadataResolver stays testable without a
+// live RPC client and Prefetch can batch lookups via getMultipleAccounts
+// instead of one getAccountInfo call per pair. Results are positional:
+// result[i] corresponds to addresses[i], and is nil for an account that
+// doesn't exist.
+type PairAccountReader interface {
+	ReadAccounts(ctx context.Context, addresses []string) ([][]byte, error)
+}
+
+// DLMM LbPair account layout: an 8-byte Anchor discriminator, the
+// StaticParameters/VariableParameters structs (32 bytes each - no local IDL
+// defines this account, unlike the one `swap` instruction it does cover, so
+// this reconstructs the public dlmm program's known field layout rather
+// than decoding against a verified schema), then a run of small fixed
+// fields before token_x_mint/token_y_mint. Only the offsets this resolver
+// actually reads are named; everything before bin_step is skipped over.
+const (
+	lbPairOffsetBinStep    = 80
+	lbPairOffsetTokenXMint = 88
+	lbPairOffsetTokenYMint = 120
+	lbPairMinAccountLen    = 152
+)
+
+func decodeLbPair(data []byte) (tokenXMint, tokenYMint string, binStep uint16, err error) {
+	if len(data) < lbPairMinAccountLen {
+		return "", "", 0, fmt.Errorf("lb pair account too short (%d bytes)", len(data))
+	}
+	binStep = binary.LittleEndian.Uint16(data[lbPairOffsetBinStep:])
+	var xKey, yKey gagliardettosolana.PublicKey
+	copy(xKey[:], data[lbPairOffsetTokenXMint:lbPairOffsetTokenXMint+32])
+	copy(yKey[:], data[lbPairOffsetTokenYMint:lbPairOffsetTokenYMint+32])
+	return xKey.String(), yKey.String(), binStep, nil
+}
+
+// SPL mint account layout (spl-token Mint struct) - the same offset
+// internal/assets' MetaplexTokenMetadataResolver reads decimals from.
+const (
+	splMintOffsetDecimals = 44
+	splMintMinAccountLen  = 45
+)
+
+func decodeMintDecimals(data []byte) (uint8, error) {
+	if len(data) < splMintMinAccountLen {
+		return 0, fmt.Errorf("mint account too short (%d bytes)", len(data))
+	}
+	return data[splMintOffsetDecimals], nil
+}
+
+// pairMetadataCacheEntry is one LRU slot: the decoded record plus when it
+// expires.
+type pairMetadataCacheEntry struct {
+	address string
+	meta    PairMetadata
+	expires time.Time
+}
+
+// pairMetadataCache is a fixed-size, TTL-expiring LRU of PairMetadata. It's
+// the in-memory default PairMetadataResolver always has; PairMetadataStore
+// is the optional extra layer behind it for surviving restarts.
+type pairMetadataCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+}
+
+func newPairMetadataCache(capacity int, ttl time.Duration) *pairMetadataCache {
+	return &pairMetadataCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *pairMetadataCache) get(address string) (PairMetadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[address]
+	if !ok {
+		return PairMetadata{}, false
+	}
+	entry := elem.Value.(*pairMetadataCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, address)
+		return PairMetadata{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.meta, true
+}
+
+func (c *pairMetadataCache) put(address string, meta PairMetadata) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[address]; ok {
+		elem.Value.(*pairMetadataCacheEntry).meta = meta
+		elem.Value.(*pairMetadataCacheEntry).expires = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&pairMetadataCacheEntry{
+		address: address,
+		meta:    meta,
+		expires: time.Now().Add(c.ttl),
+	})
+	c.entries[address] = elem
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*pairMetadataCacheEntry).address)
+		}
+	}
+}
+
+func (c *pairMetadataCache) invalidate(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[address]
+	if !ok {
+		return
+	}
+	c.order.Remove(elem)
+	delete(c.entries, address)
+}
+
+// PairMetadataStore is an optional persistence layer behind
+// PairMetadataResolver's in-memory cache, so a restart doesn't cold-start
+// every hot pair back through RPC. The repo doesn't currently vendor a KV
+// library (BoltDB/Badger), so no concrete on-disk implementation ships
+// here; callers that want one can satisfy this interface with whichever KV
+// store their deployment already uses.
+type PairMetadataStore interface {
+	Get(ctx context.Context, address string) (PairMetadata, bool, error)
+	Put(ctx context.Context, address string, meta PairMetadata) error
+}
+
+// DefaultPairMetadataCacheSize is how many pairs PairMetadataResolver's LRU
+// holds by default.
+const DefaultPairMetadataCacheSize = 4096
+
+// DefaultPairMetadataCacheTTL is how long a resolved PairMetadata is
+// trusted before Resolve re-fetches it.
+const DefaultPairMetadataCacheTTL = 10 * time.Minute
+
+// PairMetadataResolver resolves a Meteora DLMM pair address to its decoded
+// on-chain metadata, backed by an in-memory TTL LRU and, optionally, a
+// persistent PairMetadataStore.
+type PairMetadataResolver struct {
+	reader PairAccountReader
+	cache  *pairMetadataCache
+	store  PairMetadataStore
+}
+
+// PairMetadataResolverOption configures optional PairMetadataResolver behavior.
+type PairMetadataResolverOption func(*PairMetadataResolver)
+
+// WithPairMetadataCacheSize overrides DefaultPairMetadataCacheSize. size <= 0
+// means unbounded.
+func WithPairMetadataCacheSize(size int) PairMetadataResolverOption {
+	return func(r *PairMetadataResolver) { r.cache.capacity = size }
+}
+
+// WithPairMetadataCacheTTL overrides DefaultPairMetadataCacheTTL.
+func WithPairMetadataCacheTTL(ttl time.Duration) PairMetadataResolverOption {
+	return func(r *PairMetadataResolver) { r.cache.ttl = ttl }
+}
+
+// WithPairMetadataStore adds a persistent layer behind the in-memory cache,
+// checked on a cache miss and populated on every successful RPC fetch.
+func WithPairMetadataStore(store PairMetadataStore) PairMetadataResolverOption {
+	return func(r *PairMetadataResolver) { r.store = store }
+}
+
+// NewPairMetadataResolver builds a resolver that reads through reader on a
+// cache miss.
+func NewPairMetadataResolver(reader PairAccountReader, opts ...PairMetadataResolverOption) *PairMetadataResolver {
+	r := &PairMetadataResolver{
+		reader: reader,
+		cache:  newPairMetadataCache(DefaultPairMetadataCacheSize, DefaultPairMetadataCacheTTL),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Invalidate drops pairAddress from the in-memory cache, forcing the next
+// Resolve to re-fetch it from the persistent store (if any) or RPC. Used by
+// the admin API's cache_invalidate method when an operator knows a pair's
+// on-chain state changed (e.g. a fee update) before the TTL would naturally
+// expire it.
+func (r *PairMetadataResolver) Invalidate(pairAddress string) {
+	r.cache.invalidate(pairAddress)
+}
+
+// Resolve returns pairAddress's decoded metadata, from the in-memory cache,
+// then the persistent store, then falling back to an RPC fetch of the pair
+// account and its two mint accounts.
+func (r *PairMetadataResolver) Resolve(ctx context.Context, pairAddress string) (PairMetadata, error) {
+	if meta, ok := r.cache.get(pairAddress); ok {
+		metrics.PairMetadataCacheRequestsTotal.WithLabelValues("hit").Inc()
+		return meta, nil
+	}
+
+	if r.store != nil {
+		if meta, ok, err := r.store.Get(ctx, pairAddress); err == nil && ok {
+			metrics.PairMetadataCacheRequestsTotal.WithLabelValues("hit").Inc()
+			r.cache.put(pairAddress, meta)
+			return meta, nil
+		}
+	}
+
+	metrics.PairMetadataCacheRequestsTotal.WithLabelValues("miss").Inc()
+
+	meta, err := r.fetch(ctx, pairAddress)
+	if err != nil {
+		metrics.PairMetadataRPCErrorsTotal.Inc()
+		return PairMetadata{}, err
+	}
+
+	r.cache.put(pairAddress, meta)
+	if r.store != nil {
+		_ = r.store.Put(ctx, pairAddress, meta)
+	}
+	return meta, nil
+}
+
+// fetch reads pairAddress's account plus its two mint accounts and decodes
+// them into a PairMetadata.
+func (r *PairMetadataResolver) fetch(ctx context.Context, pairAddress string) (PairMetadata, error) {
+	pairAccounts, err := r.reader.ReadAccounts(ctx, []string{pairAddress})
+	if err != nil {
+		return PairMetadata{}, fmt.Errorf("reading pair account %s: %w", pairAddress, err)
+	}
+	if len(pairAccounts) == 0 || pairAccounts[0] == nil {
+		return PairMetadata{}, fmt.Errorf("pair account %s not found", pairAddress)
+	}
+
+	tokenXMint, tokenYMint, binStep, err := decodeLbPair(pairAccounts[0])
+	if err != nil {
+		return PairMetadata{}, fmt.Errorf("decoding pair account %s: %w", pairAddress, err)
+	}
+
+	mintAccounts, err := r.reader.ReadAccounts(ctx, []string{tokenXMint, tokenYMint})
+	if err != nil {
+		return PairMetadata{}, fmt.Errorf("reading mint accounts for pair %s: %w", pairAddress, err)
+	}
+	if len(mintAccounts) != 2 || mintAccounts[0] == nil || mintAccounts[1] == nil {
+		return PairMetadata{}, fmt.Errorf("mint accounts for pair %s not found", pairAddress)
+	}
+
+	tokenXDecimals, err := decodeMintDecimals(mintAccounts[0])
+	if err != nil {
+		return PairMetadata{}, fmt.Errorf("decoding token X mint %s: %w", tokenXMint, err)
+	}
+	tokenYDecimals, err := decodeMintDecimals(mintAccounts[1])
+	if err != nil {
+		return PairMetadata{}, fmt.Errorf("decoding token Y mint %s: %w", tokenYMint, err)
+	}
+
+	return PairMetadata{
+		TokenXMint:     tokenXMint,
+		TokenYMint:     tokenYMint,
+		BinStep:        binStep,
+		TokenXDecimals: tokenXDecimals,
+		TokenYDecimals: tokenYDecimals,
+	}, nil
+}
+
+// Prefetch warms the cache for every address in pairs using a single
+// getMultipleAccounts round trip for the pair accounts, then one more for
+// the union of their mint accounts, instead of Resolve's two-round-trip
+// path per pair.
+func (r *PairMetadataResolver) Prefetch(ctx context.Context, pairs []string) error {
+	toFetch := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if _, ok := r.cache.get(pair); !ok {
+			toFetch = append(toFetch, pair)
+		}
+	}
+	if len(toFetch) == 0 {
+		return nil
+	}
+
+	pairAccounts, err := r.reader.ReadAccounts(ctx, toFetch)
+	if err != nil {
+		metrics.PairMetadataRPCErrorsTotal.Inc()
+		return fmt.Errorf("prefetching pair accounts: %w", err)
+	}
+
+	type decoded struct {
+		address    string
+		tokenXMint string
+		tokenYMint string
+		binStep    uint16
+	}
+	var ok []decoded
+	mintSet := make(map[string]struct{})
+	for i, data := range pairAccounts {
+		if data == nil {
+			continue
+		}
+		tokenXMint, tokenYMint, binStep, err := decodeLbPair(data)
+		if err != nil {
+			continue
+		}
+		ok = append(ok, decoded{address: toFetch[i], tokenXMint: tokenXMint, tokenYMint: tokenYMint, binStep: binStep})
+		mintSet[tokenXMint] = struct{}{}
+		mintSet[tokenYMint] = struct{}{}
+	}
+	if len(ok) == 0 {
+		return nil
+	}
+
+	mints := make([]string, 0, len(mintSet))
+	for mint := range mintSet {
+		mints = append(mints, mint)
+	}
+	mintAccounts, err := r.reader.ReadAccounts(ctx, mints)
+	if err != nil {
+		metrics.PairMetadataRPCErrorsTotal.Inc()
+		return fmt.Errorf("prefetching mint accounts: %w", err)
+	}
+	decimals := make(map[string]uint8, len(mints))
+	for i, data := range mintAccounts {
+		if data == nil {
+			continue
+		}
+		if d, err := decodeMintDecimals(data); err == nil {
+			decimals[mints[i]] = d
+		}
+	}
+
+	for _, d := range ok {
+		meta := PairMetadata{
+			TokenXMint:     d.tokenXMint,
+			TokenYMint:     d.tokenYMint,
+			BinStep:        d.binStep,
+			TokenXDecimals: decimals[d.tokenXMint],
+			TokenYDecimals: decimals[d.tokenYMint],
+		}
+		r.cache.put(d.address, meta)
+		if r.store != nil {
+			_ = r.store.Put(ctx, d.address, meta)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package solana
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gagliardettosolana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeLbPairAccount(tokenXMint, tokenYMint gagliardettosolana.PublicKey, binStep uint16) []byte {
+	data := make([]byte, lbPairMinAccountLen)
+	data[lbPairOffsetBinStep] = byte(binStep)
+	data[lbPairOffsetBinStep+1] = byte(binStep >> 8)
+	copy(data[lbPairOffsetTokenXMint:], tokenXMint[:])
+	copy(data[lbPairOffsetTokenYMint:], tokenYMint[:])
+	return data
+}
+
+func TestDecodeLbPair(t *testing.T) {
+	tokenX := gagliardettosolana.NewWallet().PublicKey()
+	tokenY := gagliardettosolana.NewWallet().PublicKey()
+	data := encodeLbPairAccount(tokenX, tokenY, 25)
+
+	gotX, gotY, binStep, err := decodeLbPair(data)
+	require.NoError(t, err)
+	assert.Equal(t, tokenX.String(), gotX)
+	assert.Equal(t, tokenY.String(), gotY)
+	assert.Equal(t, uint16(25), binStep)
+}
+
+func TestDecodeLbPair_TooShort(t *testing.T) {
+	_, _, _, err := decodeLbPair(make([]byte, lbPairMinAccountLen-1))
+	assert.Error(t, err)
+}
+
+func TestDecodeMintDecimals(t *testing.T) {
+	data := make([]byte, splMintMinAccountLen)
+	data[splMintOffsetDecimals] = 6
+	decimals, err := decodeMintDecimals(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+}
+
+func TestPairMetadataCache_ExpiresEntries(t *testing.T) {
+	cache := newPairMetadataCache(10, time.Millisecond)
+	cache.put("pair1", PairMetadata{BinStep: 1})
+
+	_, ok := cache.get("pair1")
+	assert.True(t, ok)
+
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.get("pair1")
+	assert.False(t, ok)
+}
+
+func TestPairMetadataCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newPairMetadataCache(2, time.Hour)
+	cache.put("a", PairMetadata{BinStep: 1})
+	cache.put("b", PairMetadata{BinStep: 2})
+	cache.get("a") // touch a, so b becomes the least recently used
+	cache.put("c", PairMetadata{BinStep: 3})
+
+	_, ok := cache.get("b")
+	assert.False(t, ok, "expected least-recently-used entry to be evicted")
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+	_, ok = cache.get("c")
+	assert.True(t, ok)
+}
+
+// fakePairAccountReader serves canned account bytes by address, for testing
+// PairMetadataResolver without a live RPC client.
+type fakePairAccountReader struct {
+	accounts map[string][]byte
+	calls    int
+}
+
+func (f *fakePairAccountReader) ReadAccounts(ctx context.Context, addresses []string) ([][]byte, error) {
+	f.calls++
+	out := make([][]byte, len(addresses))
+	for i, addr := range addresses {
+		out[i] = f.accounts[addr]
+	}
+	return out, nil
+}
+
+func TestPairMetadataResolver_ResolveCachesResult(t *testing.T) {
+	tokenX := gagliardettosolana.NewWallet().PublicKey()
+	tokenY := gagliardettosolana.NewWallet().PublicKey()
+	pairAddr := gagliardettosolana.NewWallet().PublicKey().String()
+
+	mintX := make([]byte, splMintMinAccountLen)
+	mintX[splMintOffsetDecimals] = 9
+	mintY := make([]byte, splMintMinAccountLen)
+	mintY[splMintOffsetDecimals] = 6
+
+	reader := &fakePairAccountReader{accounts: map[string][]byte{
+		pairAddr:        encodeLbPairAccount(tokenX, tokenY, 20),
+		tokenX.String(): mintX,
+		tokenY.String(): mintY,
+	}}
+
+	resolver := NewPairMetadataResolver(reader)
+
+	meta, err := resolver.Resolve(context.Background(), pairAddr)
+	require.NoError(t, err)
+	assert.Equal(t, tokenX.String(), meta.TokenXMint)
+	assert.Equal(t, tokenY.String(), meta.TokenYMint)
+	assert.Equal(t, uint16(20), meta.BinStep)
+	assert.Equal(t, uint8(9), meta.TokenXDecimals)
+	assert.Equal(t, uint8(6), meta.TokenYDecimals)
+
+	callsAfterFirst := reader.calls
+	_, err = resolver.Resolve(context.Background(), pairAddr)
+	require.NoError(t, err)
+	assert.Equal(t, callsAfterFirst, reader.calls, "expected second Resolve to hit the cache, not the reader")
+}
+
+func TestPairMetadataResolver_PrefetchWarmsCache(t *testing.T) {
+	tokenX := gagliardettosolana.NewWallet().PublicKey()
+	tokenY := gagliardettosolana.NewWallet().PublicKey()
+	pairAddr := gagliardettosolana.NewWallet().PublicKey().String()
+
+	mintX := make([]byte, splMintMinAccountLen)
+	mintY := make([]byte, splMintMinAccountLen)
+
+	reader := &fakePairAccountReader{accounts: map[string][]byte{
+		pairAddr:        encodeLbPairAccount(tokenX, tokenY, 1),
+		tokenX.String(): mintX,
+		tokenY.String(): mintY,
+	}}
+	resolver := NewPairMetadataResolver(reader)
+
+	require.NoError(t, resolver.Prefetch(context.Background(), []string{pairAddr}))
+	assert.Equal(t, 2, reader.calls, "expected Prefetch to read pair accounts, then mint accounts, in two batches")
+
+	_, err := resolver.Resolve(context.Background(), pairAddr)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reader.calls, "expected Resolve to hit the cache warmed by Prefetch")
+}
@@ -2,6 +2,7 @@ package solana
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -31,3 +32,89 @@ func NewRpcBody(method string, params []interface{}) ([]byte, error) {
 	}
 	return json.Marshal(body)
 }
+
+// RpcCall is one call to include in a JSON-RPC 2.0 batch request built by
+// NewBatchRpcBody.
+type RpcCall struct {
+	Method string
+	Params []interface{}
+}
+
+// NewBatchRpcBody marshals calls into a single JSON-RPC 2.0 batch request
+// body - a JSON array of RpcBody, one per call, each with its own UUID id,
+// same as NewRpcBody gives a single call. Solana RPC providers accept this
+// array as one HTTP POST and answer with a matching array of responses,
+// which ParseBatchResponse decodes back into one result per call.
+func NewBatchRpcBody(calls []RpcCall) ([]byte, error) {
+	bodies := make([]*RpcBody, len(calls))
+	for i, call := range calls {
+		bodies[i] = &RpcBody{
+			Method:  call.Method,
+			Jsonrpc: "2.0",
+			Params:  call.Params,
+			Id:      uuid.New().String(),
+		}
+	}
+	return json.Marshal(bodies)
+}
+
+// BatchResult is one call's outcome from ParseBatchResponse: either its raw
+// "result" field, or Err describing why it has none (an RPC-level error, or
+// no matching response at all).
+type BatchResult struct {
+	Result json.RawMessage
+	Err    error
+}
+
+// batchRpcError mirrors rpc.RpcError's shape ({"code", "message"}) without
+// importing the rpc package, so this stays a leaf package with no
+// dependency the other direction - rpc.Pool.DoBatch, which is the usual way
+// a caller actually sends a batch built by NewBatchRpcBody, doesn't import
+// solana at all.
+type batchRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchResponseEntry struct {
+	ID     string          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *batchRpcError  `json:"error"`
+}
+
+// ParseBatchResponse decodes a JSON-RPC 2.0 batch response - as returned by
+// rpc.Pool.DoBatch - back into one BatchResult per call, in requestBody's
+// original order. Batch responses aren't guaranteed to come back in request
+// order, so results are correlated to their original call by id (the UUID
+// NewBatchRpcBody assigned it), not by position in responseBody.
+func ParseBatchResponse(requestBody, responseBody []byte) ([]BatchResult, error) {
+	var requests []RpcBody
+	if err := json.Unmarshal(requestBody, &requests); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch request body: %w", err)
+	}
+
+	var responses []batchResponseEntry
+	if err := json.Unmarshal(responseBody, &responses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch response body: %w", err)
+	}
+
+	byID := make(map[string]batchResponseEntry, len(responses))
+	for _, resp := range responses {
+		byID[resp.ID] = resp
+	}
+
+	results := make([]BatchResult, len(requests))
+	for i, req := range requests {
+		resp, ok := byID[req.Id]
+		if !ok {
+			results[i] = BatchResult{Err: fmt.Errorf("no response for request id %s (method %s)", req.Id, req.Method)}
+			continue
+		}
+		if resp.Error != nil {
+			results[i] = BatchResult{Err: fmt.Errorf("RPC error: code %d, message: %s", resp.Error.Code, resp.Error.Message)}
+			continue
+		}
+		results[i] = BatchResult{Result: resp.Result}
+	}
+	return results, nil
+}
@@ -5,22 +5,78 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/wnt/mercon/internal/constants"
 	"github.com/wnt/mercon/internal/models"
+	"github.com/wnt/mercon/internal/parserstore"
+	"github.com/wnt/mercon/internal/timeutil"
+	"github.com/wnt/mercon/internal/tokenregistry"
 )
 
-// NewTransactionParser creates a new parser for processing transactions
-func NewTransactionParser(client *Client) *TransactionParser {
-	return &TransactionParser{
+// NewTransactionParser creates a new parser for processing transactions.
+// store resolves the addresses the parser encounters (pairs, positions,
+// wallets, rewards) to their database row IDs - see parserstore.Store for
+// why it's required rather than an option: every instruction handler needs
+// it, unlike registry or tokenResolver, which only some fields need.
+func NewTransactionParser(client *Client, store parserstore.Store, opts ...TransactionParserOption) *TransactionParser {
+	p := &TransactionParser{
 		Client: client,
+		store:  store,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // TransactionParser processes Solana transactions
 type TransactionParser struct {
-	Client *Client
+	Client        *Client
+	registry      *InstructionRegistry
+	store         parserstore.Store
+	tokenResolver parserstore.TokenAccountResolver
+	pairResolver  *PairMetadataResolver
+	tokenRegistry tokenregistry.TokenRegistry
+}
+
+// TransactionParserOption configures optional TransactionParser behavior.
+type TransactionParserOption func(*TransactionParser)
+
+// WithInstructionRegistry makes the parser decode Meteora DLMM CPI event
+// logs through registry, so parseSwap can populate Fee, ProtocolFee,
+// FeeBps, StartBinID, and EndBinID from the program's own emitted Swap
+// event instead of leaving them zeroed. Without this option those fields
+// stay zero, same as before InstructionRegistry existed.
+func WithInstructionRegistry(registry *InstructionRegistry) TransactionParserOption {
+	return func(p *TransactionParser) { p.registry = registry }
+}
+
+// WithTokenAccountResolver makes the parser resolve SPL token accounts to
+// their mint through resolver, so isTokenXToY can tell a swap's actual side
+// instead of always assuming X. Without this option that check defaults to
+// true, same as before TokenAccountResolver existed.
+func WithTokenAccountResolver(resolver parserstore.TokenAccountResolver) TransactionParserOption {
+	return func(p *TransactionParser) { p.tokenResolver = resolver }
+}
+
+// WithPairMetadataResolver makes the parser resolve a pair's on-chain
+// tokenXMint through resolver, so isXToken can tell a one-sided
+// deposit/withdrawal's actual side instead of always assuming X. Without
+// this option that check defaults to true, same as before
+// PairMetadataResolver existed.
+func WithPairMetadataResolver(resolver *PairMetadataResolver) TransactionParserOption {
+	return func(p *TransactionParser) { p.pairResolver = resolver }
+}
+
+// WithTokenRegistry makes parseSwap populate TokenInSymbol, TokenOutSymbol,
+// TokenInDecimals, TokenOutDecimals, AmountInDecimal, and AmountOutDecimal
+// on emitted MeteoraSwap rows by resolving each side's mint through
+// registry. Without this option those fields stay blank/zero, same as
+// before tokenregistry existed.
+func WithTokenRegistry(registry tokenregistry.TokenRegistry) TransactionParserOption {
+	return func(p *TransactionParser) { p.tokenRegistry = registry }
 }
 
 // MeteoraTxType represents the type of Meteora transaction
@@ -56,7 +112,7 @@ func (p *TransactionParser) ProcessTransaction(ctx context.Context, tx Transacti
 	// Create the base transaction model
 	txModel := &models.Transaction{
 		Signature:   tx.Signature,
-		BlockTime:   UnixTimeToTime(tx.Timestamp),
+		BlockTime:   timeutil.Default.FromUnix(tx.Timestamp),
 		Slot:        tx.Slot,
 		Description: tx.Description,
 		Type:        tx.Type,
@@ -80,7 +136,19 @@ func (p *TransactionParser) ProcessTransaction(ctx context.Context, tx Transacti
 	return txModel, nil
 }
 
-// processMeteoraTxInstruction processes a single Meteora instruction and updates the transaction model
+// processMeteoraTxInstruction processes a single Meteora instruction and
+// updates the transaction model.
+//
+// This dispatches on data[0], a single-byte opcode, rather than an 8-byte
+// Anchor sighash discriminator: a captured real swap
+// (testdata/vectors/dlmm_swap.json) is 17 bytes - 1 byte of opcode plus two
+// u64 args - not the 24 bytes an 8-byte discriminator would leave room for,
+// so the deployed DLMM program evidently uses its own short discriminator
+// scheme here rather than Anchor's. See InstructionRegistry's doc comment
+// for where an IDL-driven registry is used instead - decoding this same
+// program's emit_cpi! event logs, which do follow the standard Anchor
+// "event:<name>" discriminator regardless of this instruction-dispatch
+// choice.
 func (p *TransactionParser) processMeteoraTxInstruction(ctx context.Context, instruction Instruction, txModel *models.Transaction, index int) error {
 	// Decode instruction type from the first byte of data
 	data, err := base64.StdEncoding.DecodeString(instruction.Data)
@@ -186,7 +254,9 @@ func (p *TransactionParser) parseSwap(ctx context.Context, instruction Instructi
 		tokenOutMint = tokenXMint
 	}
 
-	// Extract additional event data if available
+	// Extract additional details from the DLMM program's own emitted Swap
+	// CPI event, if this parser has an InstructionRegistry to decode it.
+	// Instruction args alone never carry these - they're computed on-chain.
 	amountOut := minAmountOut
 	fee := uint64(0)
 	feeBps := uint16(0)
@@ -194,12 +264,12 @@ func (p *TransactionParser) parseSwap(ctx context.Context, instruction Instructi
 	startBinID := int32(0)
 	endBinID := int32(0)
 
-	// Extract swap event from tx events if available - note: this is a placeholder
-	// In a complete implementation, we would use the transaction events
-	if txModel.Source == "meteora" {
-		// Try to parse the swap event for more details
-		// This would be specific to the format of the Solana program's events
-		// Placeholder for actual event parsing logic
+	if ev, ok := p.decodeSwapEvent(instruction); ok {
+		fee = ev.fee
+		protocolFee = ev.protocolFee
+		feeBps = ev.feeBps
+		startBinID = ev.startBinID
+		endBinID = ev.endBinID
 	}
 
 	// Create the swap model
@@ -222,12 +292,97 @@ func (p *TransactionParser) parseSwap(ctx context.Context, instruction Instructi
 		SwapForY:      swapForY,
 	}
 
+	p.enrichSwapWithTokenRegistry(ctx, &swap)
+
 	// Add swap to transaction
 	txModel.Swaps = append(txModel.Swaps, swap)
 
 	return nil
 }
 
+// enrichSwapWithTokenRegistry populates swap's symbol/decimals/human-amount
+// fields by resolving its two mints through p.tokenRegistry, if one was
+// configured via WithTokenRegistry. A mint the registry doesn't recognize
+// is left blank/zero, same as if no registry were configured at all.
+func (p *TransactionParser) enrichSwapWithTokenRegistry(ctx context.Context, swap *models.MeteoraSwap) {
+	if p.tokenRegistry == nil {
+		return
+	}
+
+	if in, ok := p.tokenRegistry.Lookup(ctx, swap.TokenInMint); ok {
+		swap.TokenInSymbol = in.Symbol
+		swap.TokenInDecimals = in.Decimals
+		swap.AmountInDecimal = float64(swap.AmountIn) / math.Pow10(int(in.Decimals))
+	}
+	if out, ok := p.tokenRegistry.Lookup(ctx, swap.TokenOutMint); ok {
+		swap.TokenOutSymbol = out.Symbol
+		swap.TokenOutDecimals = out.Decimals
+		swap.AmountOutDecimal = float64(swap.AmountOut) / math.Pow10(int(out.Decimals))
+	}
+}
+
+// swapEventFields is what parseSwap needs out of a decoded Meteora DLMM
+// "Swap" CPI event to fill in the fields instruction args don't carry.
+type swapEventFields struct {
+	fee         uint64
+	protocolFee uint64
+	feeBps      uint16
+	startBinID  int32
+	endBinID    int32
+}
+
+// decodeSwapEvent looks for a Meteora DLMM "Swap" CPI event among
+// instruction's inner instructions and decodes it through p.registry. It
+// returns ok=false - not an error - whenever there's nothing to decode: no
+// registry configured, no inner instruction from the DLMM program itself
+// (emit_cpi! self-invokes the emitting program), or one that isn't a
+// recognized event. All of those just mean the caller keeps the zeroed
+// defaults it already has.
+func (p *TransactionParser) decodeSwapEvent(instruction Instruction) (swapEventFields, bool) {
+	if p.registry == nil {
+		return swapEventFields{}, false
+	}
+
+	for _, inner := range instruction.InnerInstructions {
+		if inner.ProgramId != constants.MeteoraDLMM {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(inner.Data)
+		if err != nil {
+			continue
+		}
+
+		parsed, err := p.registry.DecodeEvent(data)
+		if err != nil || parsed.Unknown || parsed.Name != "Swap" {
+			continue
+		}
+
+		return swapEventFields{
+			fee:         fieldUint64(parsed.Fields["fee"]),
+			protocolFee: fieldUint64(parsed.Fields["protocolFee"]),
+			feeBps:      uint16(fieldUint64(parsed.Fields["feeBps"])),
+			startBinID:  fieldInt32(parsed.Fields["startBinId"]),
+			endBinID:    fieldInt32(parsed.Fields["endBinId"]),
+		}, true
+	}
+
+	return swapEventFields{}, false
+}
+
+// fieldUint64 and fieldInt32 type-assert a decoded idl.ParsedEvent field
+// back to the Go type borshReader produces for it (see internal/idl),
+// defaulting to zero if the event didn't carry that field at all.
+func fieldUint64(v interface{}) uint64 {
+	u, _ := v.(uint64)
+	return u
+}
+
+func fieldInt32(v interface{}) int32 {
+	i, _ := v.(int64)
+	return int32(i)
+}
+
 // parseSwapExactOut parses a swapExactOut instruction
 func (p *TransactionParser) parseSwapExactOut(ctx context.Context, instruction Instruction, data []byte, txModel *models.Transaction) error {
 	if len(instruction.Accounts) < 9 {
@@ -300,6 +455,8 @@ func (p *TransactionParser) parseSwapExactOut(ctx context.Context, instruction I
 		SwapForY:      swapForY,
 	}
 
+	p.enrichSwapWithTokenRegistry(ctx, &swap)
+
 	// Add swap to transaction
 	txModel.Swaps = append(txModel.Swaps, swap)
 
@@ -372,18 +529,98 @@ func (p *TransactionParser) parseAddLiquidity(ctx context.Context, instruction I
 	// Add liquidity addition to transaction
 	txModel.LiquidityAdditions = append(txModel.LiquidityAdditions, addition)
 
+	// addLiquidity's args are amount_x (u64), amount_y (u64), then a
+	// Vec<BinLiquidityDistribution> giving each touched bin's bps share of
+	// amountX/amountY - decode it to reconstruct per-bin deltas instead of
+	// only the aggregate above.
+	const binDistributionOffset = 1 + 8 + 8
+	if shares, err := decodeBinLiquidityDistribution(data, binDistributionOffset); err == nil {
+		appendBinDeltasFromShares(txModel, positionID, pairID, shares, amountX, amountY)
+	}
+
 	return nil
 }
 
 // Other liquidity addition variants can be added with similar implementations
 func (p *TransactionParser) parseAddLiquidityByWeight(ctx context.Context, instruction Instruction, data []byte, txModel *models.Transaction) error {
-	// Similar to parseAddLiquidity but with weight distribution
+	// addLiquidityByWeight's args are a Vec<BinLiquidityDistributionByWeight>
+	// (bin_id, weight) rather than the distribution_x/distribution_y bps
+	// pairs this request asks to decode, so per-bin deltas aren't
+	// reconstructed here; it still records the aggregate addition via
+	// parseAddLiquidity.
 	return p.parseAddLiquidity(ctx, instruction, data, txModel)
 }
 
+// parseAddLiquidityByStrategy parses an addLiquidityByStrategy instruction,
+// decoding its StrategyParameters to reconstruct per-bin deltas according to
+// the requested strategy's shape (see strategyWeights).
 func (p *TransactionParser) parseAddLiquidityByStrategy(ctx context.Context, instruction Instruction, data []byte, txModel *models.Transaction) error {
-	// Similar to parseAddLiquidity but with strategy
-	return p.parseAddLiquidity(ctx, instruction, data, txModel)
+	if len(instruction.Accounts) < 12 {
+		return fmt.Errorf("insufficient accounts for add liquidity by strategy operation")
+	}
+
+	// addLiquidityByStrategy uses the same account layout as addLiquidity.
+	position := instruction.Accounts[0]
+	lbPair := instruction.Accounts[1]
+	reserveX := instruction.Accounts[5]
+	reserveY := instruction.Accounts[6]
+	tokenXMint := instruction.Accounts[7]
+	tokenYMint := instruction.Accounts[8]
+	user := instruction.Accounts[12]
+
+	positionID, err := p.getPositionID(ctx, position)
+	if err != nil {
+		return err
+	}
+
+	pairID, err := p.getPairID(ctx, lbPair)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := p.getWalletID(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	amountX := uint64(0)
+	amountY := uint64(0)
+	for _, transfer := range txModel.TokenTransfers {
+		if transfer.ToTokenAccount == reserveX && transfer.Mint == tokenXMint {
+			amountX = uint64(transfer.TokenAmount)
+		} else if transfer.ToTokenAccount == reserveY && transfer.Mint == tokenYMint {
+			amountY = uint64(transfer.TokenAmount)
+		}
+	}
+
+	// Args: amount_x (u64), amount_y (u64), active_id (i32),
+	// max_active_bin_slippage (i32), then strategy_parameters.
+	const strategyOffset = 1 + 8 + 8 + 4 + 4
+	params, err := decodeStrategyParameters(data, strategyOffset)
+	activeID := int32(0)
+	if err == nil {
+		activeID = int32(binary.LittleEndian.Uint32(data[1+8+8:]))
+	}
+
+	addition := models.MeteoraLiquidityAddition{
+		TransactionID: txModel.ID,
+		PositionID:    positionID,
+		PairID:        pairID,
+		WalletID:      walletID,
+		User:          user,
+		AmountX:       amountX,
+		AmountY:       amountY,
+		ActiveID:      activeID,
+		AddTime:       txModel.BlockTime,
+	}
+	txModel.LiquidityAdditions = append(txModel.LiquidityAdditions, addition)
+
+	if err == nil {
+		weights := strategyWeights(params.minBinID, params.maxBinID, activeID, params.strategyType)
+		appendBinDeltasFromWeights(txModel, positionID, pairID, weights, amountX, amountY)
+	}
+
+	return nil
 }
 
 // parseAddLiquidityByStrategyOneSide parses an addLiquidityByStrategyOneSide instruction
@@ -452,9 +689,72 @@ func (p *TransactionParser) parseAddLiquidityByStrategyOneSide(ctx context.Conte
 	// Add liquidity addition to transaction
 	txModel.LiquidityAdditions = append(txModel.LiquidityAdditions, addition)
 
+	// Args: amount (u64), active_id (i32), max_active_bin_slippage (i32),
+	// then strategy_parameters.
+	const strategyOffset = 1 + 8 + 4 + 4
+	if params, err := decodeStrategyParameters(data, strategyOffset); err == nil {
+		activeID := int32(binary.LittleEndian.Uint32(data[1+8:]))
+		weights := strategyWeights(params.minBinID, params.maxBinID, activeID, params.strategyType)
+		if isXToken {
+			appendBinDeltasFromWeights(txModel, positionID, pairID, weights, amount, 0)
+		} else {
+			appendBinDeltasFromWeights(txModel, positionID, pairID, weights, 0, amount)
+		}
+	}
+
 	return nil
 }
 
+// appendBinDeltasFromShares turns a decoded Vec<BinLiquidityDistribution>
+// into MeteoraBinDelta rows, splitting amountX/amountY across bins by each
+// share's basis-point distribution.
+func appendBinDeltasFromShares(txModel *models.Transaction, positionID, pairID uint, shares []binShare, amountX, amountY uint64) {
+	var totalBps uint64
+	for _, s := range shares {
+		totalBps += uint64(s.distributionX) + uint64(s.distributionY)
+	}
+	if totalBps == 0 {
+		return
+	}
+
+	for _, s := range shares {
+		deltaX := int64(amountX) * int64(s.distributionX) / basisPointsDenominator
+		deltaY := int64(amountY) * int64(s.distributionY) / basisPointsDenominator
+		share := float64(uint64(s.distributionX)+uint64(s.distributionY)) / float64(totalBps)
+
+		txModel.BinDeltas = append(txModel.BinDeltas, models.MeteoraBinDelta{
+			TransactionID:  txModel.ID,
+			PositionID:     positionID,
+			PairID:         pairID,
+			BinID:          s.binID,
+			DeltaX:         deltaX,
+			DeltaY:         deltaY,
+			LiquidityShare: share,
+		})
+	}
+}
+
+// appendBinDeltasFromWeights turns a strategyWeights map into MeteoraBinDelta
+// rows, splitting amountX/amountY across bins proportionally to each bin's
+// weight. Unlike appendBinDeltasFromShares/appendBinDeltasFromReductions,
+// these weights aren't decoded from the instruction - addLiquidityByStrategy
+// (OneSide) only encodes the strategy's shape parameters, not a per-bin
+// breakdown - so the resulting rows are marked Approximated.
+func appendBinDeltasFromWeights(txModel *models.Transaction, positionID, pairID uint, weights map[int32]float64, amountX, amountY uint64) {
+	for bin, weight := range weights {
+		txModel.BinDeltas = append(txModel.BinDeltas, models.MeteoraBinDelta{
+			TransactionID:  txModel.ID,
+			PositionID:     positionID,
+			PairID:         pairID,
+			BinID:          bin,
+			DeltaX:         int64(float64(amountX) * weight),
+			DeltaY:         int64(float64(amountY) * weight),
+			LiquidityShare: weight,
+			Approximated:   true,
+		})
+	}
+}
+
 // parseRemoveLiquidity parses a removeLiquidity instruction
 func (p *TransactionParser) parseRemoveLiquidity(ctx context.Context, instruction Instruction, data []byte, txModel *models.Transaction) error {
 	if len(instruction.Accounts) < 12 {
@@ -517,9 +817,50 @@ func (p *TransactionParser) parseRemoveLiquidity(ctx context.Context, instructio
 	// Add liquidity removal to transaction
 	txModel.LiquidityRemovals = append(txModel.LiquidityRemovals, removal)
 
+	// removeLiquidity's args are a Vec<BinLiquidityReduction> right after
+	// the discriminator. bps_to_remove is each bin's own share removed, not
+	// a share of the aggregate amountX/amountY above, so it can't be
+	// converted to an exact per-bin amount without live per-bin reserve
+	// state this parser doesn't track; treat the bps values as relative
+	// weights among the touched bins instead, apportioning the aggregate
+	// removed amounts across them.
+	const binReductionOffset = 1
+	if reductions, err := decodeBinLiquidityReduction(data, binReductionOffset); err == nil {
+		appendBinDeltasFromReductions(txModel, positionID, pairID, reductions, amountX, amountY)
+	}
+
 	return nil
 }
 
+// appendBinDeltasFromReductions turns a decoded Vec<BinLiquidityReduction>
+// into negative MeteoraBinDelta rows, apportioning amountX/amountY across
+// the touched bins by their relative bps_to_remove weight.
+func appendBinDeltasFromReductions(txModel *models.Transaction, positionID, pairID uint, reductions []binReduction, amountX, amountY uint64) {
+	var totalBps uint64
+	for _, r := range reductions {
+		totalBps += uint64(r.bpsToRemove)
+	}
+	if totalBps == 0 {
+		return
+	}
+
+	for _, r := range reductions {
+		share := float64(r.bpsToRemove) / float64(totalBps)
+		deltaX := -int64(float64(amountX) * share)
+		deltaY := -int64(float64(amountY) * share)
+
+		txModel.BinDeltas = append(txModel.BinDeltas, models.MeteoraBinDelta{
+			TransactionID:  txModel.ID,
+			PositionID:     positionID,
+			PairID:         pairID,
+			BinID:          r.binID,
+			DeltaX:         deltaX,
+			DeltaY:         deltaY,
+			LiquidityShare: share,
+		})
+	}
+}
+
 // parseClaimFee parses a claimFee instruction
 func (p *TransactionParser) parseClaimFee(ctx context.Context, instruction Instruction, data []byte, txModel *models.Transaction) error {
 	if len(instruction.Accounts) < 12 {
@@ -733,55 +1074,70 @@ func (p *TransactionParser) parseInitializePair(ctx context.Context, instruction
 	return nil
 }
 
-// Helper functions for database lookups
+// Helper functions for database lookups, delegating to the
+// parserstore.Store this parser was constructed with.
 
 // getPairID looks up or creates a pair record
 func (p *TransactionParser) getPairID(ctx context.Context, pairAddress string) (uint, error) {
-	// Placeholder for database lookup
-	// In a complete implementation, this would look up the pair by address
-	// or create it if it doesn't exist
-	return 1, nil
+	return p.store.GetOrCreatePair(ctx, pairAddress)
 }
 
 // getPositionID looks up or creates a position record
 func (p *TransactionParser) getPositionID(ctx context.Context, positionAddress string) (uint, error) {
-	// Placeholder for database lookup
-	// In a complete implementation, this would look up the position by address
-	// or create it if it doesn't exist
-	return 1, nil
+	return p.store.GetOrCreatePosition(ctx, positionAddress)
 }
 
 // getWalletID looks up or creates a wallet record
 func (p *TransactionParser) getWalletID(ctx context.Context, walletAddress string) (uint, error) {
-	// Placeholder for database lookup
-	// In a complete implementation, this would look up the wallet by address
-	// or create it if it doesn't exist
-	return 1, nil
+	return p.store.GetOrCreateWallet(ctx, walletAddress)
 }
 
 // getRewardID looks up or creates a reward record
 func (p *TransactionParser) getRewardID(ctx context.Context, pairAddress string, rewardIndex uint64) (uint, error) {
-	// Placeholder for database lookup
-	// In a complete implementation, this would look up the reward by pair and index
-	// or create it if it doesn't exist
-	return 1, nil
+	return p.store.GetOrCreateReward(ctx, pairAddress, rewardIndex)
 }
 
-// isTokenXToY determines if the swap is from token X to token Y
+// isTokenXToY determines if the swap is from token X to token Y. Without a
+// TokenAccountResolver (see WithTokenAccountResolver) this defaults to true,
+// same as before TokenAccountResolver existed.
 func (p *TransactionParser) isTokenXToY(ctx context.Context, tokenAccount string, tokenXMint string) (bool, error) {
-	// Placeholder for token account lookup
-	// In a complete implementation, this would determine if the token account is for token X
-	return true, nil
+	if p.tokenResolver == nil {
+		return true, nil
+	}
+	mint, err := p.tokenResolver.MintOf(ctx, tokenAccount)
+	if err != nil {
+		return false, fmt.Errorf("resolving mint for token account %s: %w", tokenAccount, err)
+	}
+	return mint == tokenXMint, nil
 }
 
-// isXToken determines if a token mint is token X for a pair
+// isXToken determines if tokenMint is the pair's X mint.
+//
+// Unlike isTokenXToY, this can't be answered by TokenAccountResolver alone:
+// tokenMint here is already a resolved mint address, not a token account,
+// so there's nothing left to resolve for it - the answer depends on which
+// mint the pair itself recorded as its X side, which parserstore.Store
+// doesn't expose (only row IDs). Without a PairMetadataResolver (see
+// WithPairMetadataResolver) this defaults to true, same as before that
+// option existed.
 func (p *TransactionParser) isXToken(ctx context.Context, tokenMint string, pairAddress string) (bool, error) {
-	// Placeholder for pair token lookup
-	// In a complete implementation, this would determine if the token is X or Y for the pair
-	return true, nil
+	if p.pairResolver == nil {
+		return true, nil
+	}
+	meta, err := p.pairResolver.Resolve(ctx, pairAddress)
+	if err != nil {
+		return false, fmt.Errorf("resolving pair metadata for %s: %w", pairAddress, err)
+	}
+	return tokenMint == meta.TokenXMint, nil
 }
 
-// UnixTimeToTime converts a Unix timestamp to a Time
+// UnixTimeToTime converts a Unix timestamp (seconds) to a UTC time.Time.
+//
+// Deprecated: use timeutil.Default.FromUnix (or a *timeutil.TimestampCodec
+// built with timeutil.WithClockSource for deterministic tests) instead.
+// This shim exists only so callers outside this package keep compiling
+// while they migrate; it already delegates to the same codec
+// ProcessTransaction uses internally.
 func UnixTimeToTime(timestamp int64) time.Time {
-	return time.Unix(timestamp, 0)
+	return timeutil.Default.FromUnix(timestamp)
 }
@@ -202,3 +202,21 @@ func TestUnixTimeToTime(t *testing.T) {
 	// we should only verify that the Unix timestamp is preserved
 	assert.Equal(t, timestamp, result.Unix())
 }
+
+func TestAppendBinDeltasFromWeightsMarksApproximated(t *testing.T) {
+	txModel := &models.Transaction{}
+	appendBinDeltasFromWeights(txModel, 1, 2, map[int32]float64{5: 1.0}, 100, 200)
+
+	if assert.Len(t, txModel.BinDeltas, 1) {
+		assert.True(t, txModel.BinDeltas[0].Approximated)
+	}
+}
+
+func TestAppendBinDeltasFromSharesNotApproximated(t *testing.T) {
+	txModel := &models.Transaction{}
+	appendBinDeltasFromShares(txModel, 1, 2, []binShare{{binID: 5, distributionX: 10000}}, 100, 200)
+
+	if assert.Len(t, txModel.BinDeltas, 1) {
+		assert.False(t, txModel.BinDeltas[0].Approximated)
+	}
+}
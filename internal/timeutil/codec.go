@@ -0,0 +1,92 @@
+// Package timeutil centralizes how Mercon turns on-chain Unix timestamps
+// into time.Time values and back into strings, so precision (Solana
+// blockTime is seconds; some downstream sinks want micro/nanosecond) and
+// timezone (always UTC) are decided in one place instead of at each call
+// site.
+package timeutil
+
+import "time"
+
+// ClockSource converts a (seconds, nanoseconds) Unix pair into a time.Time.
+// TimestampCodec calls through this rather than time.Unix directly so tests
+// can inject a deterministic source instead of depending on the real
+// calendar.
+type ClockSource interface {
+	Unix(sec, nsec int64) time.Time
+}
+
+// systemClock is the ClockSource every TimestampCodec uses unless
+// WithClockSource overrides it.
+type systemClock struct{}
+
+func (systemClock) Unix(sec, nsec int64) time.Time { return time.Unix(sec, nsec) }
+
+// Layouts Format chooses between, in increasing order of precision - the
+// same "use the coarsest layout the value actually needs" approach MinIO's
+// s3select timestamp formatter uses.
+const (
+	layoutDay        = "2006-01-02"
+	layoutSecond     = "2006-01-02T15:04:05Z"
+	layoutNanosecond = "2006-01-02T15:04:05.000000000Z"
+)
+
+// TimestampCodec converts Unix timestamps to UTC time.Time values and back
+// into RFC3339-style strings, at whatever precision the value actually
+// carries.
+type TimestampCodec struct {
+	clock ClockSource
+}
+
+// TimestampCodecOption configures optional TimestampCodec behavior.
+type TimestampCodecOption func(*TimestampCodec)
+
+// WithClockSource overrides the ClockSource a TimestampCodec converts
+// through, e.g. to inject a fixed time in tests instead of relying on
+// time.Unix.
+func WithClockSource(clock ClockSource) TimestampCodecOption {
+	return func(c *TimestampCodec) { c.clock = clock }
+}
+
+// NewTimestampCodec builds a TimestampCodec. Without WithClockSource it
+// converts through the real system clock.
+func NewTimestampCodec(opts ...TimestampCodecOption) *TimestampCodec {
+	c := &TimestampCodec{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Default is the TimestampCodec parser sites use unless they need a
+// different ClockSource (tests; a future per-sink precision override).
+var Default = NewTimestampCodec()
+
+// FromUnix converts a Unix timestamp in whole seconds - Solana's blockTime
+// - to UTC.
+func (c *TimestampCodec) FromUnix(sec int64) time.Time {
+	return c.clock.Unix(sec, 0).UTC()
+}
+
+// FromUnixNano converts a Unix timestamp in nanoseconds to UTC, for
+// downstream sinks (ClickHouse, TimescaleDB) that carry sub-second
+// precision Solana's own blockTime doesn't.
+func (c *TimestampCodec) FromUnixNano(ns int64) time.Time {
+	return c.clock.Unix(0, ns).UTC()
+}
+
+// Format renders t in UTC using the coarsest of layoutDay/layoutSecond/
+// layoutNanosecond that doesn't lose information: a midnight-exact,
+// nanosecond-exact value prints as just a date, one with only whole-second
+// precision as RFC3339, and anything with a fractional second as
+// RFC3339Nano.
+func (c *TimestampCodec) Format(t time.Time) string {
+	t = t.UTC()
+
+	if t.Nanosecond() != 0 {
+		return t.Format(layoutNanosecond)
+	}
+	if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+		return t.Format(layoutSecond)
+	}
+	return t.Format(layoutDay)
+}
@@ -0,0 +1,73 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	sec, nsec int64
+}
+
+func (f *fakeClock) Unix(sec, nsec int64) time.Time {
+	f.sec, f.nsec = sec, nsec
+	return time.Unix(sec, nsec)
+}
+
+func TestTimestampCodec_FromUnix(t *testing.T) {
+	clock := &fakeClock{}
+	codec := NewTimestampCodec(WithClockSource(clock))
+
+	got := codec.FromUnix(1667289600)
+
+	if got.Location() != time.UTC {
+		t.Errorf("FromUnix() location = %v, want UTC", got.Location())
+	}
+	if clock.sec != 1667289600 || clock.nsec != 0 {
+		t.Errorf("FromUnix() called clock with (%d, %d), want (1667289600, 0)", clock.sec, clock.nsec)
+	}
+}
+
+func TestTimestampCodec_FromUnixNano(t *testing.T) {
+	clock := &fakeClock{}
+	codec := NewTimestampCodec(WithClockSource(clock))
+
+	got := codec.FromUnixNano(1667289600123456789)
+
+	if got.Location() != time.UTC {
+		t.Errorf("FromUnixNano() location = %v, want UTC", got.Location())
+	}
+	if clock.nsec != 1667289600123456789 {
+		t.Errorf("FromUnixNano() called clock with nsec=%d, want 1667289600123456789", clock.nsec)
+	}
+}
+
+func TestTimestampCodec_FormatChoosesLayoutByPrecision(t *testing.T) {
+	codec := NewTimestampCodec()
+
+	day := time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)
+	if got, want := codec.Format(day), "2022-10-31"; got != want {
+		t.Errorf("Format(midnight) = %q, want %q", got, want)
+	}
+
+	second := time.Date(2022, 10, 31, 13, 45, 30, 0, time.UTC)
+	if got, want := codec.Format(second), "2022-10-31T13:45:30Z"; got != want {
+		t.Errorf("Format(whole second) = %q, want %q", got, want)
+	}
+
+	nanosecond := time.Date(2022, 10, 31, 13, 45, 30, 123456789, time.UTC)
+	if got, want := codec.Format(nanosecond), "2022-10-31T13:45:30.123456789Z"; got != want {
+		t.Errorf("Format(nanosecond) = %q, want %q", got, want)
+	}
+}
+
+func TestTimestampCodec_FormatNormalizesToUTC(t *testing.T) {
+	codec := NewTimestampCodec()
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	localNoon := time.Date(2022, 10, 31, 12, 0, 0, 0, loc)
+
+	if got, want := codec.Format(localNoon), "2022-10-31T17:00:00Z"; got != want {
+		t.Errorf("Format(local) = %q, want %q", got, want)
+	}
+}
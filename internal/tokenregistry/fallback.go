@@ -0,0 +1,18 @@
+package tokenregistry
+
+import "context"
+
+// FallbackRegistry is an ordered chain of TokenRegistry backends, tried in
+// order until one resolves mint - e.g. {Jupiter list, SPL token list,
+// on-chain} so a lookup only pays the cost of an RPC round trip for a mint
+// neither curated list has heard of yet.
+type FallbackRegistry []TokenRegistry
+
+func (f FallbackRegistry) Lookup(ctx context.Context, mint string) (TokenInfo, bool) {
+	for _, registry := range f {
+		if info, ok := registry.Lookup(ctx, mint); ok {
+			return info, true
+		}
+	}
+	return TokenInfo{}, false
+}
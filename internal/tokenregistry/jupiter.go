@@ -0,0 +1,61 @@
+package tokenregistry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wnt/mercon/internal/utils"
+)
+
+// jupiterListFetcher fetches Jupiter's strict token list, a curated JSON
+// array of verified token metadata.
+type jupiterListFetcher struct {
+	httpClient *utils.HTTPClient
+}
+
+// NewJupiterRegistry builds a ListRegistry backed by Jupiter's strict token
+// list API. Call Refresh (or StartRefreshing) to load it before use.
+func NewJupiterRegistry() *ListRegistry {
+	return NewListRegistry(&jupiterListFetcher{
+		httpClient: utils.NewHTTPClient(
+			utils.WithBaseURL("https://token.jup.ag"),
+			utils.WithTimeout(10*time.Second),
+		),
+	})
+}
+
+type jupiterTokenEntry struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+	LogoURI  string `json:"logoURI"`
+}
+
+func (f *jupiterListFetcher) FetchList(ctx context.Context) (map[string]TokenInfo, error) {
+	resp, err := f.httpClient.Do(&utils.Request{
+		Method:  "GET",
+		Path:    "/strict",
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jupiter token list: %w", err)
+	}
+
+	var entries []jupiterTokenEntry
+	if err := resp.DecodeJSON(&entries); err != nil {
+		return nil, fmt.Errorf("jupiter token list: decode response: %w", err)
+	}
+
+	tokens := make(map[string]TokenInfo, len(entries))
+	for _, e := range entries {
+		tokens[e.Address] = TokenInfo{
+			Symbol:   e.Symbol,
+			Name:     e.Name,
+			Decimals: e.Decimals,
+			LogoURI:  e.LogoURI,
+		}
+	}
+	return tokens, nil
+}
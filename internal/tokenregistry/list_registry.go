@@ -0,0 +1,88 @@
+package tokenregistry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ListFetcher fetches and parses a token-list document from its upstream
+// source (Jupiter's API, the SPL token-list repo's JSON file, ...) into a
+// mint-to-metadata map. Combining fetch and parse behind one call keeps
+// ListRegistry itself source-agnostic, the same split PriceOracle uses
+// between its Quote implementations and PriceOracleAggregator.
+type ListFetcher interface {
+	FetchList(ctx context.Context) (map[string]TokenInfo, error)
+}
+
+// ListRegistry is a TokenRegistry backed by a periodically refreshed
+// in-memory map, e.g. Jupiter's strict token list or the SPL token-list
+// repo. Lookups never block on network I/O; Refresh (or StartRefreshing)
+// does the fetching and atomically swaps the map behind a RWMutex, the same
+// "fetch into a new map, then swap the pointer" pattern the Trustwallet
+// assets processor uses for its periodic BEP2/BEP20 token-list updates.
+type ListRegistry struct {
+	fetcher ListFetcher
+
+	mutex  sync.RWMutex
+	tokens map[string]TokenInfo
+}
+
+// NewListRegistry builds a ListRegistry backed by fetcher. The registry
+// resolves nothing until the first successful Refresh.
+func NewListRegistry(fetcher ListFetcher) *ListRegistry {
+	return &ListRegistry{
+		fetcher: fetcher,
+		tokens:  make(map[string]TokenInfo),
+	}
+}
+
+func (r *ListRegistry) Lookup(ctx context.Context, mint string) (TokenInfo, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	info, ok := r.tokens[mint]
+	return info, ok
+}
+
+// Refresh re-pulls the list from fetcher and atomically swaps the
+// underlying map. A failed refresh leaves the previously loaded map in
+// place rather than clearing it, so a transient upstream outage doesn't
+// blank out every lookup in the meantime.
+func (r *ListRegistry) Refresh(ctx context.Context) error {
+	tokens, err := r.fetcher.FetchList(ctx)
+	if err != nil {
+		return fmt.Errorf("refreshing token list: %w", err)
+	}
+
+	r.mutex.Lock()
+	r.tokens = tokens
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// StartRefreshing runs Refresh once immediately, then again every interval,
+// until ctx is cancelled. A failed refresh is logged, not fatal - the
+// registry keeps serving whatever it last loaded, same as client.go's
+// warning-and-continue treatment of a failed IDL load. Callers should run
+// this in its own goroutine.
+func (r *ListRegistry) StartRefreshing(ctx context.Context, interval time.Duration) {
+	if err := r.Refresh(ctx); err != nil {
+		fmt.Printf("Warning: initial token list refresh failed: %v\n", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				fmt.Printf("Warning: token list refresh failed: %v\n", err)
+			}
+		}
+	}
+}
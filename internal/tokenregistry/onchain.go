@@ -0,0 +1,30 @@
+package tokenregistry
+
+import (
+	"context"
+
+	"github.com/wnt/mercon/internal/assets"
+)
+
+// OnChainRegistry adapts an assets.TokenMetadataResolver - decimals read
+// straight from a token's SPL mint account, symbol from its Metaplex
+// token-metadata PDA - into a TokenRegistry, for mints neither curated
+// token list has indexed yet (e.g. a pair that just launched).
+type OnChainRegistry struct {
+	resolver assets.TokenMetadataResolver
+}
+
+// NewOnChainRegistry builds a TokenRegistry that falls through to resolver
+// for every lookup. resolver is typically an
+// *assets.MetaplexTokenMetadataResolver.
+func NewOnChainRegistry(resolver assets.TokenMetadataResolver) *OnChainRegistry {
+	return &OnChainRegistry{resolver: resolver}
+}
+
+func (r *OnChainRegistry) Lookup(ctx context.Context, mint string) (TokenInfo, bool) {
+	meta, err := r.resolver.Resolve(ctx, mint)
+	if err != nil || meta == (assets.TokenMetadata{}) {
+		return TokenInfo{}, false
+	}
+	return TokenInfo{Symbol: meta.Symbol, Decimals: meta.Decimals}, true
+}
@@ -0,0 +1,34 @@
+// Package tokenregistry resolves token mint addresses to human-readable
+// metadata - symbol, name, decimals, logo - so consumers of parsed swap
+// events don't have to do a second lookup to render "SOL/USDC 0.42" instead
+// of two raw mint addresses and an integer amount.
+package tokenregistry
+
+import "context"
+
+// TokenInfo is what TokenRegistry resolves a mint to.
+type TokenInfo struct {
+	Symbol   string
+	Name     string
+	LogoURI  string
+	Decimals uint8
+}
+
+// TokenRegistry resolves a token mint to its metadata. Lookup reports false
+// when the mint is unknown to this registry rather than returning an error,
+// since an unresolved mint is an expected, non-exceptional outcome (a new
+// token neither token list has indexed yet) that callers should be able to
+// check with a plain boolean, the same shape as parserstore's resolvers.
+type TokenRegistry interface {
+	Lookup(ctx context.Context, mint string) (TokenInfo, bool)
+}
+
+// NullRegistry is a TokenRegistry that never resolves anything. It's the
+// default for callers (and tests) that don't want symbol/decimals
+// enrichment, the same role parserstore.MemoryStore's zero value or
+// assets.MapTokenMetadataResolver{} play for their own resolvers.
+type NullRegistry struct{}
+
+func (NullRegistry) Lookup(ctx context.Context, mint string) (TokenInfo, bool) {
+	return TokenInfo{}, false
+}
@@ -0,0 +1,118 @@
+package tokenregistry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wnt/mercon/internal/assets"
+)
+
+func TestNullRegistry(t *testing.T) {
+	info, ok := NullRegistry{}.Lookup(context.Background(), "MintA")
+	if ok {
+		t.Errorf("Lookup() = %+v, true, want false", info)
+	}
+}
+
+type fakeListFetcher struct {
+	tokens map[string]TokenInfo
+	err    error
+}
+
+func (f *fakeListFetcher) FetchList(ctx context.Context) (map[string]TokenInfo, error) {
+	return f.tokens, f.err
+}
+
+func TestListRegistry_RefreshAndLookup(t *testing.T) {
+	fetcher := &fakeListFetcher{tokens: map[string]TokenInfo{
+		"MintA": {Symbol: "AAA", Decimals: 6},
+	}}
+	registry := NewListRegistry(fetcher)
+
+	if _, ok := registry.Lookup(context.Background(), "MintA"); ok {
+		t.Error("Lookup() before Refresh should find nothing")
+	}
+
+	if err := registry.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() returned error: %v", err)
+	}
+
+	info, ok := registry.Lookup(context.Background(), "MintA")
+	if !ok || info.Symbol != "AAA" || info.Decimals != 6 {
+		t.Errorf("Lookup(MintA) = %+v, %v, want {AAA Decimals:6}, true", info, ok)
+	}
+}
+
+func TestListRegistry_FailedRefreshKeepsPreviousTokens(t *testing.T) {
+	fetcher := &fakeListFetcher{tokens: map[string]TokenInfo{
+		"MintA": {Symbol: "AAA"},
+	}}
+	registry := NewListRegistry(fetcher)
+	if err := registry.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() returned error: %v", err)
+	}
+
+	fetcher.err = errors.New("upstream unavailable")
+	if err := registry.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() should propagate the fetcher's error")
+	}
+
+	if _, ok := registry.Lookup(context.Background(), "MintA"); !ok {
+		t.Error("a failed refresh should not clear previously loaded tokens")
+	}
+}
+
+type fakeTokenMetadataResolver struct {
+	meta assets.TokenMetadata
+	err  error
+}
+
+func (r fakeTokenMetadataResolver) Resolve(ctx context.Context, mint string) (assets.TokenMetadata, error) {
+	return r.meta, r.err
+}
+
+func TestOnChainRegistry(t *testing.T) {
+	registry := NewOnChainRegistry(fakeTokenMetadataResolver{meta: assets.TokenMetadata{Symbol: "wSOL", Decimals: 9}})
+
+	info, ok := registry.Lookup(context.Background(), "So111")
+	if !ok || info.Symbol != "wSOL" || info.Decimals != 9 {
+		t.Errorf("Lookup() = %+v, %v, want {wSOL Decimals:9}, true", info, ok)
+	}
+}
+
+func TestOnChainRegistry_UnresolvedMint(t *testing.T) {
+	registry := NewOnChainRegistry(fakeTokenMetadataResolver{err: errors.New("account not found")})
+
+	if _, ok := registry.Lookup(context.Background(), "Unknown"); ok {
+		t.Error("Lookup() should report false when the resolver errors")
+	}
+}
+
+type stubRegistry struct {
+	info TokenInfo
+	ok   bool
+}
+
+func (s stubRegistry) Lookup(ctx context.Context, mint string) (TokenInfo, bool) { return s.info, s.ok }
+
+func TestFallbackRegistry_TriesEachInOrder(t *testing.T) {
+	fallback := FallbackRegistry{
+		stubRegistry{ok: false},
+		stubRegistry{info: TokenInfo{Symbol: "USDC"}, ok: true},
+		stubRegistry{info: TokenInfo{Symbol: "SHOULD_NOT_REACH"}, ok: true},
+	}
+
+	info, ok := fallback.Lookup(context.Background(), "MintA")
+	if !ok || info.Symbol != "USDC" {
+		t.Errorf("Lookup() = %+v, %v, want {USDC}, true", info, ok)
+	}
+}
+
+func TestFallbackRegistry_NoneResolve(t *testing.T) {
+	fallback := FallbackRegistry{stubRegistry{ok: false}, stubRegistry{ok: false}}
+
+	if _, ok := fallback.Lookup(context.Background(), "MintA"); ok {
+		t.Error("Lookup() should report false when no backend resolves the mint")
+	}
+}
@@ -0,0 +1,66 @@
+package tokenregistry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wnt/mercon/internal/utils"
+)
+
+// splListFetcher fetches Solana Labs' community-maintained SPL token-list
+// repo's aggregated JSON file.
+type splListFetcher struct {
+	httpClient *utils.HTTPClient
+}
+
+// NewSPLTokenListRegistry builds a ListRegistry backed by the SPL
+// token-list repo's raw JSON file on GitHub. Call Refresh (or
+// StartRefreshing) to load it before use.
+func NewSPLTokenListRegistry() *ListRegistry {
+	return NewListRegistry(&splListFetcher{
+		httpClient: utils.NewHTTPClient(
+			utils.WithBaseURL("https://raw.githubusercontent.com"),
+			utils.WithTimeout(10*time.Second),
+		),
+	})
+}
+
+type splTokenListResponse struct {
+	Tokens []splTokenListEntry `json:"tokens"`
+}
+
+type splTokenListEntry struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+	LogoURI  string `json:"logoURI"`
+}
+
+func (f *splListFetcher) FetchList(ctx context.Context) (map[string]TokenInfo, error) {
+	resp, err := f.httpClient.Do(&utils.Request{
+		Method:  "GET",
+		Path:    "/solana-labs/token-list/main/src/tokens/solana.tokenlist.json",
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spl token list: %w", err)
+	}
+
+	var parsed splTokenListResponse
+	if err := resp.DecodeJSON(&parsed); err != nil {
+		return nil, fmt.Errorf("spl token list: decode response: %w", err)
+	}
+
+	tokens := make(map[string]TokenInfo, len(parsed.Tokens))
+	for _, e := range parsed.Tokens {
+		tokens[e.Address] = TokenInfo{
+			Symbol:   e.Symbol,
+			Name:     e.Name,
+			Decimals: e.Decimals,
+			LogoURI:  e.LogoURI,
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Ed25519Signer implements RequestSigner for Solana-style wallet-signed
+// requests: it signs the payload with a wallet keypair and base58-encodes
+// the resulting signature, the scheme several Solana-native exchange and
+// aggregator APIs use in place of an HMAC secret.
+type Ed25519Signer struct {
+	wallet solana.PrivateKey
+}
+
+// NewEd25519Signer builds an Ed25519Signer that signs with wallet.
+func NewEd25519Signer(wallet solana.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{wallet: wallet}
+}
+
+// Sign sets X-WALLET-PUBKEY, X-TIMESTAMP, and X-SIGNATURE on req, signing
+// the timestamp concatenated with the request body (or query string, for a
+// bodyless GET) with the wallet keypair.
+func (s *Ed25519Signer) Sign(req *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	payload := timestamp + req.URL.RawQuery
+	if len(body) > 0 {
+		payload = timestamp + string(body)
+	}
+
+	signature, err := s.wallet.Sign([]byte(payload))
+	if err != nil {
+		return fmt.Errorf("failed to sign request with wallet keypair: %w", err)
+	}
+
+	req.Header.Set("X-WALLET-PUBKEY", s.wallet.PublicKey().String())
+	req.Header.Set("X-TIMESTAMP", timestamp)
+	req.Header.Set("X-SIGNATURE", signature.String())
+
+	return nil
+}
@@ -13,16 +13,45 @@ import (
 
 // HTTPClient is a wrapper around the standard http client with added functionality
 type HTTPClient struct {
-	client         *http.Client
-	baseURL        string
-	defaultHeaders map[string]string
-	maxRetries     int
-	retryDelay     time.Duration
+	client          *http.Client
+	baseURL         string
+	defaultHeaders  map[string]string
+	maxRetries      int
+	retryDelay      time.Duration
+	observers       []RequestObserver
+	signer          RequestSigner
+	encodings       []Encoding
+	decompressLimit int64
+}
+
+// WithSigner registers the RequestSigner used to authenticate requests
+// whose SecType is SecTypeSigned. Do returns an error for a signed request
+// if no signer is configured.
+func WithSigner(signer RequestSigner) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.signer = signer
+	}
 }
 
 // HTTPClientOption is a function that configures the HTTPClient
 type HTTPClientOption func(*HTTPClient)
 
+// RequestObserver is notified after every attempt Do makes, including ones
+// that get retried, so callers can record request/retry/status metrics
+// without Do itself depending on a metrics package. attempt is 0 for the
+// first try and increments for each retry; statusCode is 0 if the attempt
+// never got a response (e.g. a dial failure).
+type RequestObserver func(method, path string, statusCode, attempt int, duration time.Duration, err error)
+
+// WithObserver registers a RequestObserver invoked after every request
+// attempt. Multiple WithObserver options compose - every observer given is
+// called, in the order registered.
+func WithObserver(observer RequestObserver) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
 // WithTimeout sets the timeout for the HTTP client
 func WithTimeout(timeout time.Duration) HTTPClientOption {
 	return func(c *HTTPClient) {
@@ -61,8 +90,10 @@ func NewHTTPClient(options ...HTTPClientOption) *HTTPClient {
 		defaultHeaders: map[string]string{
 			"Content-Type": "application/json",
 		},
-		maxRetries: 3,
-		retryDelay: 500 * time.Millisecond,
+		maxRetries:      3,
+		retryDelay:      500 * time.Millisecond,
+		encodings:       defaultEncodings,
+		decompressLimit: defaultDecompressLimit,
 	}
 
 	for _, option := range options {
@@ -76,10 +107,15 @@ func NewHTTPClient(options ...HTTPClientOption) *HTTPClient {
 type Request struct {
 	Method      string
 	Path        string
-	QueryParams map[string]string
+	QueryParams OptionalParameter
 	Headers     map[string]string
 	Body        interface{}
 	Context     context.Context
+	// SecType controls whether Do authenticates the request via the
+	// configured RequestSigner before sending it. It defaults to
+	// SecTypeNone, matching every pre-existing caller's unauthenticated
+	// public GETs.
+	SecType SecType
 }
 
 // Response represents an HTTP response
@@ -115,23 +151,18 @@ func (c *HTTPClient) Do(req *Request) (*Response, error) {
 		url = c.baseURL + url
 	}
 
-	// Add query parameters if any
+	// Add query parameters if any, percent-encoded via url.Values
 	if len(req.QueryParams) > 0 {
-		url += "?"
-		i := 0
-		for k, v := range req.QueryParams {
-			if i > 0 {
-				url += "&"
-			}
-			url += fmt.Sprintf("%s=%s", k, v)
-			i++
+		if qs := req.QueryParams.Encode(); qs != "" {
+			url += "?" + qs
 		}
 	}
 
 	// Create request with body if needed
+	var bodyBytes []byte
 	var bodyReader io.Reader
 	if req.Body != nil {
-		bodyBytes, err := json.Marshal(req.Body)
+		bodyBytes, err = json.Marshal(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
@@ -148,11 +179,19 @@ func (c *HTTPClient) Do(req *Request) (*Response, error) {
 		httpReq.Header.Set(k, v)
 	}
 
+	if len(c.encodings) > 0 {
+		httpReq.Header.Set("Accept-Encoding", acceptEncodingHeader(c.encodings))
+	}
+
 	// Set request-specific headers
 	for k, v := range req.Headers {
 		httpReq.Header.Set(k, v)
 	}
 
+	if req.SecType == SecTypeSigned && c.signer == nil {
+		return nil, fmt.Errorf("request to %s requires SecType %q but no signer is configured (see WithSigner)", req.Path, req.SecType)
+	}
+
 	// Perform the request with retries
 	var respBody []byte
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
@@ -165,24 +204,37 @@ func (c *HTTPClient) Do(req *Request) (*Response, error) {
 			}
 		}
 
+		// Sign on every attempt, not just the first, so a signature's
+		// timestamp can't go stale across a retry delay.
+		if req.SecType == SecTypeSigned {
+			if err = c.signer.Sign(httpReq, bodyBytes); err != nil {
+				return nil, fmt.Errorf("failed to sign request: %w", err)
+			}
+		}
+
+		attemptStart := time.Now()
 		resp, err = c.client.Do(httpReq)
 		if err != nil {
+			c.notifyObservers(req.Method, req.Path, 0, attempt, time.Since(attemptStart), err)
 			if attempt == c.maxRetries {
 				return nil, fmt.Errorf("failed after %d retries: %w", c.maxRetries, err)
 			}
 			continue
 		}
 
-		// Read response body
-		respBody, err = io.ReadAll(resp.Body)
+		// Read response body, transparently decoding it per Content-Encoding
+		respBody, err = decompressBody(resp, c.decompressLimit)
 		resp.Body.Close()
 		if err != nil {
+			c.notifyObservers(req.Method, req.Path, resp.StatusCode, attempt, time.Since(attemptStart), err)
 			if attempt == c.maxRetries {
 				return nil, fmt.Errorf("failed to read response body after %d retries: %w", c.maxRetries, err)
 			}
 			continue
 		}
 
+		c.notifyObservers(req.Method, req.Path, resp.StatusCode, attempt, time.Since(attemptStart), nil)
+
 		// No need to retry if we got here successfully
 		break
 	}
@@ -205,8 +257,16 @@ func (c *HTTPClient) Do(req *Request) (*Response, error) {
 	return response, nil
 }
 
+// notifyObservers calls every registered RequestObserver for one request
+// attempt.
+func (c *HTTPClient) notifyObservers(method, path string, statusCode, attempt int, duration time.Duration, err error) {
+	for _, observer := range c.observers {
+		observer(method, path, statusCode, attempt, duration, err)
+	}
+}
+
 // Get performs a GET request
-func (c *HTTPClient) Get(path string, queryParams map[string]string, headers map[string]string) (*Response, error) {
+func (c *HTTPClient) Get(path string, queryParams OptionalParameter, headers map[string]string) (*Response, error) {
 	return c.Do(&Request{
 		Method:      http.MethodGet,
 		Path:        path,
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding identifies a response content-coding utils.HTTPClient can
+// advertise via Accept-Encoding and transparently decode from
+// Content-Encoding.
+type Encoding string
+
+const (
+	EncodingGzip   Encoding = "gzip"
+	EncodingBrotli Encoding = "br"
+	EncodingZstd   Encoding = "zstd"
+)
+
+// defaultEncodings is every Encoding Do advertises unless overridden with
+// WithCompression. Decoding does not depend on it: decompressBody handles
+// all three whatever was advertised.
+var defaultEncodings = []Encoding{EncodingGzip, EncodingBrotli, EncodingZstd}
+
+// defaultDecompressLimit bounds how many decompressed bytes Do reads from a
+// single response, so a malicious or misbehaving upstream can't inflate a
+// small response into an unbounded allocation (a zip bomb).
+const defaultDecompressLimit int64 = 64 << 20 // 64 MiB
+
+// WithCompression sets which encodings Do advertises via Accept-Encoding.
+// The default is every Encoding this package supports.
+func WithCompression(encodings ...Encoding) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.encodings = encodings
+	}
+}
+
+// WithDecompressLimit overrides defaultDecompressLimit.
+func WithDecompressLimit(maxBytes int64) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.decompressLimit = maxBytes
+	}
+}
+
+// acceptEncodingHeader joins encodings into an Accept-Encoding header value.
+func acceptEncodingHeader(encodings []Encoding) string {
+	values := make([]string, len(encodings))
+	for i, e := range encodings {
+		values[i] = string(e)
+	}
+	return strings.Join(values, ", ")
+}
+
+// decompressBody reads resp.Body, transparently decoding it per its
+// Content-Encoding header (gzip, br, zstd; anything else is read as-is), and
+// rejects a body whose decompressed size exceeds limit rather than
+// buffering it in full.
+func decompressBody(resp *http.Response, limit int64) ([]byte, error) {
+	var reader io.Reader = resp.Body
+
+	switch Encoding(resp.Header.Get("Content-Encoding")) {
+	case EncodingGzip:
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip reader: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case EncodingBrotli:
+		reader = brotli.NewReader(resp.Body)
+	case EncodingZstd:
+		zr, err := zstd.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zstd reader: %w", err)
+		}
+		defer zr.Close()
+		reader = zr
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("decompressed response body exceeds %d byte limit", limit)
+	}
+	return body, nil
+}
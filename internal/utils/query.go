@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// OptionalParameter is a typed set of query-string parameters. Unlike a
+// plain map[string]string, a value can be []string (encoded as repeated
+// keys, e.g. "tags=a&tags=b"), bool, int, float64, or time.Time (encoded
+// as RFC3339); Encode converts each to its canonical string form and
+// percent-encodes the result via net/url.Values, so an address containing
+// "+" or a name with spaces survives correctly instead of being
+// concatenated by hand.
+type OptionalParameter map[string]any
+
+// Encode renders p as a URL query string ("a=1&b=2").
+func (p OptionalParameter) Encode() string {
+	values := url.Values{}
+	for key, value := range p {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			values.Set(key, v)
+		case []string:
+			for _, s := range v {
+				values.Add(key, s)
+			}
+		case bool:
+			values.Set(key, strconv.FormatBool(v))
+		case int:
+			values.Set(key, strconv.Itoa(v))
+		case int64:
+			values.Set(key, strconv.FormatInt(v, 10))
+		case float64:
+			values.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			values.Set(key, v.Format(time.RFC3339))
+		default:
+			values.Set(key, fmt.Sprintf("%v", v))
+		}
+	}
+	return values.Encode()
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "net/http"
+
+// SecType classifies how strongly a Request must be authenticated, mirroring
+// the tiers common to CEX-style APIs (Binance's NONE/API_KEY/SIGNED, etc.).
+type SecType string
+
+const (
+	// SecTypeNone is a public endpoint. Do never calls the signer.
+	SecTypeNone SecType = ""
+	// SecTypeAPIKey is a private endpoint that only needs an API key header,
+	// set directly via Request.Headers - it does not require a signature,
+	// so Do does not invoke the signer for it.
+	SecTypeAPIKey SecType = "api_key"
+	// SecTypeSigned is a private endpoint that must be authenticated via the
+	// configured RequestSigner before every attempt.
+	SecTypeSigned SecType = "signed"
+)
+
+// RequestSigner authenticates an outgoing request in place, typically by
+// setting headers derived from the request method/path, query string or
+// body, and a shared secret or keypair. body is the exact bytes Do is about
+// to send, already marshaled from Request.Body (nil for bodyless requests).
+// Sign is called again on every retry attempt, so implementations that embed
+// a timestamp must recompute it each time rather than caching it.
+type RequestSigner interface {
+	Sign(req *http.Request, body []byte) error
+}
@@ -3,51 +3,132 @@ package worker
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/wnt/mercon/internal/config"
+	"github.com/wnt/mercon/internal/leader"
 	"github.com/wnt/mercon/internal/metrics"
 	"github.com/wnt/mercon/internal/queue"
 	"github.com/wnt/mercon/internal/rpc"
+	"github.com/wnt/mercon/internal/rpc/ws"
+	"github.com/wnt/mercon/internal/services"
 	"golang.org/x/sync/errgroup"
 )
 
+// reaperJobName and enricherJobName are the leader.Elector job names
+// Manager campaigns for - one lease per periodic job that must run on
+// exactly one pod, not one lease for the whole manager, so losing the
+// enrichment sweep's lease doesn't also stop a pod from reaping stuck
+// wallets.
+const (
+	reaperJobName   = "reaper"
+	enricherJobName = "enricher"
+)
+
 // Manager manages a dynamic pool of workers
 type Manager struct {
-	config    config.Config
-	queue     *queue.Client
-	rpcPool   *rpc.Pool
-	fetcher   *rpc.Fetcher
-	workers   []*Worker
-	logger    zerolog.Logger
-	mutex     sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
-	eg        *errgroup.Group
-	stopped   bool
-}
-
-// NewManager creates a new worker manager
-func NewManager(cfg config.Config, queueClient *queue.Client, rpcPool *rpc.Pool, logger zerolog.Logger) *Manager {
+	config config.Config
+	// queue is always the ZSET client - every admin/scaling operation below
+	// (GetQueueLength, RequeueStuckWallets, the DLQ methods, PeekQueue, ...)
+	// reads or writes its ZSET/hash layout directly and has no streams
+	// equivalent yet, regardless of QueueBackend.
+	queue *queue.Client
+	// workerQueue is what NewWorker is actually handed: the same ZSET
+	// client, unless cfg.QueueBackend is "streams", in which case it's a
+	// separate *queue.StreamClient pointed at the same Redis. See
+	// queue.StreamClient's doc comment for what that split means.
+	workerQueue queue.Queue
+	rpcPool     *rpc.Pool
+	fetcher     *rpc.Fetcher
+	subscriber  *ws.Subscriber // nil unless RPCWSIngestionEnabled
+	workers     []*Worker
+	logger      zerolog.Logger
+
+	// enricher and elector are both nil unless NewManager was given one -
+	// unless both are set, Start never launches runEnrichmentSweep at all
+	// (nothing is logged), the same "disabled unless configured" pattern
+	// as subscriber above.
+	enricher *services.MeteoraDataEnricher
+	elector  *leader.Elector
+	mutex    sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	eg       *errgroup.Group
+	stopped  bool
+
+	eventMu   sync.RWMutex
+	eventSubs map[int]chan Event
+	nextSubID int
+
+	// scalerMu guards the PID controller state used by
+	// calculateDesiredWorkers.
+	scalerMu          sync.Mutex
+	scalerIntegral    float64
+	scalerLastError   float64
+	scalerLastTick    time.Time
+	scalerLastDesired int
+}
+
+// NewManager creates a new worker manager. enricher and elector are both
+// optional (nil is fine): without an elector, every pod runs the reaper
+// unconditionally and the enrichment sweep never runs; without an enricher
+// the sweep never runs either - the same as leaving RPC_WS_INGESTION_ENABLED
+// unset leaves subscriber nil above.
+func NewManager(cfg config.Config, queueClient *queue.Client, rpcPool *rpc.Pool, logger zerolog.Logger, enricher *services.MeteoraDataEnricher, elector *leader.Elector) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 	eg, egCtx := errgroup.WithContext(ctx)
-	
-	fetcher := rpc.NewFetcher(rpcPool, logger)
-	
+
+	var fetcherOpts []rpc.FetcherOption
+	if cfg.RPCVerifyQuorum != "" {
+		spec, err := rpc.ParseQuorumSpec(cfg.RPCVerifyQuorum)
+		if err != nil {
+			// config.Load already validates this; a bad spec here means the
+			// caller built a config.Config by hand rather than through Load.
+			logger.Error().Err(err).Str("rpc_verify_quorum", cfg.RPCVerifyQuorum).Msg("Ignoring invalid RPC_VERIFY_QUORUM")
+		} else {
+			fetcherOpts = append(fetcherOpts, rpc.WithVerifyQuorum(spec))
+		}
+	}
+	fetcher := rpc.NewFetcher(rpcPool, logger, fetcherOpts...)
+
+	var subscriber *ws.Subscriber
+	if cfg.RPCWSIngestionEnabled {
+		subscriber = ws.NewSubscriber(rpcPool, queueClient, logger)
+	}
+
+	var workerQueue queue.Queue = queueClient
+	if cfg.QueueBackend == "streams" {
+		streamClient, err := queue.NewStreamClient(cfg.RedisURL, logger)
+		if err != nil {
+			// Fall back to the ZSET client rather than failing manager
+			// construction outright - same "log and fall back" treatment
+			// as the RPC_VERIFY_QUORUM case above.
+			logger.Error().Err(err).Msg("Failed to create Redis Streams queue client, workers will use the ZSET queue instead")
+		} else {
+			workerQueue = streamClient
+		}
+	}
+
 	manager := &Manager{
-		config:  cfg,
-		queue:   queueClient,
-		rpcPool: rpcPool,
-		fetcher: fetcher,
-		workers: make([]*Worker, 0),
-		logger:  logger.With().Str("component", "worker_manager").Logger(),
-		ctx:     egCtx,
-		cancel:  cancel,
-		eg:      eg,
-	}
-	
+		config:      cfg,
+		queue:       queueClient,
+		workerQueue: workerQueue,
+		rpcPool:     rpcPool,
+		fetcher:     fetcher,
+		subscriber:  subscriber,
+		workers:     make([]*Worker, 0),
+		logger:      logger.With().Str("component", "worker_manager").Logger(),
+		ctx:         egCtx,
+		cancel:      cancel,
+		eg:          eg,
+		eventSubs:   make(map[int]chan Event),
+		enricher:    enricher,
+		elector:     elector,
+	}
+
 	return manager
 }
 
@@ -57,27 +138,46 @@ func (m *Manager) Start() error {
 		Int("min_workers", m.config.MinWorkers).
 		Int("max_workers", m.config.MaxWorkers).
 		Msg("Starting worker manager")
-	
+
 	// Start initial workers
 	if err := m.adjustWorkerCount(); err != nil {
 		return fmt.Errorf("failed to start initial workers: %w", err)
 	}
-	
+
 	// Start the scaling ticker
 	m.eg.Go(func() error {
 		return m.runScalingLoop()
 	})
-	
-	// Start stuck wallet recovery
+
+	// Start stuck wallet recovery. If an elector is configured, only the
+	// pod holding the "reaper" lease actually requeues wallets - every
+	// pod still runs the campaign/ticker machinery, it just sits idle
+	// while not leading. Without an elector (the pre-existing behavior),
+	// every pod reaps unconditionally.
 	m.eg.Go(func() error {
-		return m.runStuckWalletRecovery()
+		if m.elector == nil {
+			return m.runStuckWalletRecovery(m.ctx)
+		}
+		return m.runLeaderGated(reaperJobName, m.runStuckWalletRecovery)
 	})
-	
+
 	// Start queue monitoring
 	m.eg.Go(func() error {
 		return m.runQueueMonitoring()
 	})
-	
+
+	// Start the periodic pair/position enrichment sweep, gated on the
+	// "enricher" lease so it runs on exactly one pod rather than every
+	// pod racing to enrich the same rows. This sweep did not previously
+	// run from the worker bootstrap at all - scraper.Scraper.enrichData
+	// calls the same EnrichPairs/EnrichPositions methods, but that's a
+	// separate, one-shot code path no cmd/ binary currently wires up.
+	if m.enricher != nil && m.elector != nil {
+		m.eg.Go(func() error {
+			return m.runLeaderGated(enricherJobName, m.runEnrichmentSweep)
+		})
+	}
+
 	m.logger.Info().Msg("Worker manager started successfully")
 	return nil
 }
@@ -91,18 +191,22 @@ func (m *Manager) Stop() error {
 	}
 	m.stopped = true
 	m.mutex.Unlock()
-	
+
 	m.logger.Info().Msg("Stopping worker manager...")
-	
+
+	if m.subscriber != nil {
+		m.subscriber.Close()
+	}
+
 	// Cancel context to signal all workers to stop
 	m.cancel()
-	
+
 	// Wait for all workers to finish with timeout
 	done := make(chan error, 1)
 	go func() {
 		done <- m.eg.Wait()
 	}()
-	
+
 	select {
 	case err := <-done:
 		if err != nil {
@@ -111,12 +215,22 @@ func (m *Manager) Stop() error {
 	case <-time.After(30 * time.Second):
 		m.logger.Warn().Msg("Worker shutdown timed out")
 	}
-	
+
 	// Clear workers
 	m.mutex.Lock()
 	m.workers = nil
 	m.mutex.Unlock()
-	
+
+	// workerQueue is only a distinct connection from queue when
+	// QueueBackend is "streams" (see NewManager) - in the default case
+	// this would double-close the connection cmd/mercon's main() already
+	// closes via queueClient.Close().
+	if m.workerQueue != nil && m.workerQueue != queue.Queue(m.queue) {
+		if err := m.workerQueue.Close(); err != nil {
+			m.logger.Warn().Err(err).Msg("Failed to close worker queue client")
+		}
+	}
+
 	metrics.WorkersActive.Set(0)
 	m.logger.Info().Msg("Worker manager stopped")
 	return nil
@@ -126,7 +240,7 @@ func (m *Manager) Stop() error {
 func (m *Manager) runScalingLoop() error {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
-	
+
 	for {
 		select {
 		case <-m.ctx.Done():
@@ -145,27 +259,27 @@ func (m *Manager) adjustWorkerCount() error {
 	if err != nil {
 		return fmt.Errorf("failed to get queue length: %w", err)
 	}
-	
+
 	// Update queue length metric
 	metrics.WalletQueueLength.Set(float64(queueLength))
-	
+
 	// Calculate desired worker count
 	desiredWorkers := m.calculateDesiredWorkers(int(queueLength))
-	
+
 	m.mutex.Lock()
 	currentWorkers := len(m.workers)
 	m.mutex.Unlock()
-	
+
 	if desiredWorkers == currentWorkers {
 		return nil // No change needed
 	}
-	
+
 	m.logger.Info().
 		Int("current_workers", currentWorkers).
 		Int("desired_workers", desiredWorkers).
 		Int64("queue_length", queueLength).
 		Msg("Adjusting worker count")
-	
+
 	if desiredWorkers > currentWorkers {
 		return m.addWorkers(desiredWorkers - currentWorkers)
 	} else {
@@ -173,49 +287,142 @@ func (m *Manager) adjustWorkerCount() error {
 	}
 }
 
-// calculateDesiredWorkers determines optimal worker count based on queue length
+// scalerHysteresisWorkers is the minimum change in PID output, in workers,
+// required before adjustWorkerCount acts on it. Without this, the
+// controller can flap between two worker counts every 30-second tick when
+// the error sits right at a rounding boundary.
+const scalerHysteresisWorkers = 2
+
+// calculateDesiredWorkers determines the optimal worker count using a
+// PID controller driven by two setpoints: target queue-drain time and
+// target RPC error rate (see config.Config.ScalerKp/Ki/Kd and friends).
+// "Queue drain time" is estimated as how long the current worker pool
+// would take to empty the queue at its observed average per-wallet
+// processing rate.
 func (m *Manager) calculateDesiredWorkers(queueLength int) int {
-	// Simple scaling algorithm: 1 worker per 10 wallets in queue
-	desired := queueLength / 10
+	m.mutex.RLock()
+	currentWorkers := len(m.workers)
+	avgProcessingTime := m.averageProcessingTime()
+	m.mutex.RUnlock()
+
+	if currentWorkers == 0 {
+		currentWorkers = 1
+	}
+	if avgProcessingTime <= 0 {
+		avgProcessingTime = time.Second
+	}
+
+	queueDrainTime := time.Duration(int64(queueLength) * int64(avgProcessingTime) / int64(currentWorkers))
+	errorRate := m.fetcher.ErrorRateEWMA()
+
+	m.scalerMu.Lock()
+	defer m.scalerMu.Unlock()
+
+	now := time.Now()
+	dt := now.Sub(m.scalerLastTick).Seconds()
+	if m.scalerLastTick.IsZero() || dt <= 0 {
+		dt = 30 // first tick, or a clock anomaly: assume the normal scaling interval
+	}
+
+	drainError := queueDrainTime.Seconds() - m.config.ScalerTargetQueueDrainTime.Seconds()
+
+	derivative := (drainError - m.scalerLastError) / dt
+	output := m.config.ScalerKp*drainError + m.config.ScalerKi*m.scalerIntegral + m.config.ScalerKd*derivative
+
+	desired := currentWorkers + int(math.Round(output))
+
+	// Clamp to the configured worker bounds.
+	clamped := desired < m.config.MinWorkers || desired > m.config.MaxWorkers
 	if desired < m.config.MinWorkers {
 		desired = m.config.MinWorkers
 	}
 	if desired > m.config.MaxWorkers {
 		desired = m.config.MaxWorkers
 	}
+
+	// When the RPC pool is unhealthy or its error rate is running above
+	// target, cap the worker count so we don't hammer failing endpoints
+	// with even more concurrent requests.
+	if healthy := m.rpcPool.GetHealthyEndpointCount(); healthy > 0 {
+		healthCap := healthy * m.config.ScalerConcurrencyPerEndpoint
+		if desired > healthCap {
+			desired = healthCap
+			clamped = true
+		}
+	}
+	if errorRate > m.config.ScalerTargetRPCErrorRate && desired > currentWorkers {
+		desired = currentWorkers
+		clamped = true
+	}
+
+	// Anti-windup: only accumulate the integral term when the output
+	// wasn't clamped away.
+	if !clamped {
+		m.scalerIntegral += drainError * dt
+	}
+	m.scalerLastError = drainError
+	m.scalerLastTick = now
+
+	// Hysteresis: ignore small fluctuations around the last applied
+	// desired count so we don't oscillate every tick.
+	if m.scalerLastDesired != 0 && abs(desired-m.scalerLastDesired) < scalerHysteresisWorkers {
+		return m.scalerLastDesired
+	}
+	m.scalerLastDesired = desired
 	return desired
 }
 
+// averageProcessingTime returns the mean ProcessingTimeEWMA across the
+// current worker pool. Callers must hold m.mutex (read lock suffices).
+func (m *Manager) averageProcessingTime() time.Duration {
+	if len(m.workers) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, w := range m.workers {
+		total += w.ProcessingTimeEWMA()
+	}
+	return total / time.Duration(len(m.workers))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // addWorkers creates and starts new workers
 func (m *Manager) addWorkers(count int) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	
+
 	for i := 0; i < count; i++ {
 		workerID := fmt.Sprintf("worker-%d", len(m.workers)+1)
-		worker := NewWorker(workerID, m.queue, m.fetcher, m.logger)
-		
+		worker := NewWorker(workerID, m.workerQueue, m.fetcher, m.logger)
+		worker.SetEventPublisher(m.publishEvent)
+
 		// Start the worker
 		m.eg.Go(func() error {
 			return worker.Start(m.ctx)
 		})
-		
+
 		m.workers = append(m.workers, worker)
-		
+
 		m.logger.Debug().
 			Str("worker_id", workerID).
 			Int("total_workers", len(m.workers)).
 			Msg("Added worker")
 	}
-	
+
 	// Update metrics
 	metrics.WorkersActive.Set(float64(len(m.workers)))
-	
+
 	m.logger.Info().
 		Int("added", count).
 		Int("total_workers", len(m.workers)).
 		Msg("Workers added")
-	
+
 	return nil
 }
 
@@ -223,53 +430,108 @@ func (m *Manager) addWorkers(count int) error {
 func (m *Manager) removeWorkers(count int) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	
+
 	if count > len(m.workers) {
 		count = len(m.workers)
 	}
-	
+
 	// Signal workers to stop (they will finish current work)
 	workersToRemove := m.workers[len(m.workers)-count:]
 	for _, worker := range workersToRemove {
 		worker.Stop()
 	}
-	
+
 	// Remove from slice
 	m.workers = m.workers[:len(m.workers)-count]
-	
+
 	// Update metrics
 	metrics.WorkersActive.Set(float64(len(m.workers)))
-	
+
 	m.logger.Info().
 		Int("removed", count).
 		Int("remaining_workers", len(m.workers)).
 		Msg("Workers removed")
-	
+
 	return nil
 }
 
-// runStuckWalletRecovery periodically checks for and requeues stuck wallets
-func (m *Manager) runStuckWalletRecovery() error {
+// runStuckWalletRecovery periodically checks for and requeues stuck
+// wallets. ctx is m.ctx when run unconditionally (no elector configured),
+// or a leadership-scoped context from runLeaderGated that's also
+// canceled the moment this pod loses the "reaper" lease.
+func (m *Manager) runStuckWalletRecovery(ctx context.Context) error {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
-	
+
 	for {
 		select {
-		case <-m.ctx.Done():
-			return m.ctx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
 		case <-ticker.C:
-			if err := m.queue.RequeueStuckWallets(m.ctx, 15); err != nil {
+			if err := m.queue.RequeueStuckWallets(m.ctx, 15, m.config.MaxWalletRequeues); err != nil {
 				m.logger.Error().Err(err).Msg("Failed to requeue stuck wallets")
 			}
 		}
 	}
 }
 
+// runEnrichmentSweep periodically re-enriches Meteora pairs and positions
+// with USD values, gated by runLeaderGated on the "enricher" lease so
+// only one pod in the fleet runs it at a time. ctx is canceled both on
+// manager shutdown and on losing the lease.
+func (m *Manager) runEnrichmentSweep(ctx context.Context) error {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.enricher.EnrichPairs(); err != nil {
+				m.logger.Error().Err(err).Msg("Failed to enrich pairs with USD values")
+			}
+			if err := m.enricher.EnrichPositions(); err != nil {
+				m.logger.Error().Err(err).Msg("Failed to enrich positions with USD values")
+			}
+		}
+	}
+}
+
+// runLeaderGated repeatedly campaigns for jobName and runs fn with a
+// context scoped to this pod's hold on that lease: fn is called with a
+// fresh context each time leadership is (re)acquired, and that context
+// is canceled the instant the lease is lost, so fn's own ticker loop
+// stops acting without needing to know anything about leader election.
+// It returns once m.ctx itself is done.
+func (m *Manager) runLeaderGated(jobName string, fn func(ctx context.Context) error) error {
+	for {
+		handle, err := m.elector.Campaign(m.ctx, jobName)
+		if err != nil {
+			return err
+		}
+
+		leadCtx, cancel := context.WithCancel(m.ctx)
+		go func() {
+			<-handle.Done()
+			cancel()
+		}()
+
+		err = fn(leadCtx)
+		cancel()
+
+		if m.ctx.Err() != nil {
+			return m.ctx.Err()
+		}
+		m.logger.Warn().Str("job", jobName).Err(err).Msg("Lost leadership, re-campaigning")
+	}
+}
+
 // runQueueMonitoring periodically logs queue statistics
 func (m *Manager) runQueueMonitoring() error {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
-	
+
 	for {
 		select {
 		case <-m.ctx.Done():
@@ -280,25 +542,247 @@ func (m *Manager) runQueueMonitoring() error {
 				m.logger.Error().Err(err).Msg("Failed to get queue length for monitoring")
 				continue
 			}
-			
+
 			inFlight, err := m.queue.GetInFlightWallets(m.ctx)
 			if err != nil {
 				m.logger.Error().Err(err).Msg("Failed to get in-flight wallets for monitoring")
 				continue
 			}
-			
+
 			m.mutex.RLock()
 			activeWorkers := len(m.workers)
 			m.mutex.RUnlock()
-			
+
 			healthyEndpoints := m.rpcPool.GetHealthyEndpointCount()
-			
+
+			metrics.SetQueueDepth("wallet_queue", int(queueLength))
+			metrics.SetInFlightWallets(len(inFlight))
+
+			if backfillLength, err := m.queue.GetBackfillQueueLength(m.ctx); err == nil {
+				metrics.SetQueueDepth("backfill_queue", int(backfillLength))
+			} else {
+				m.logger.Error().Err(err).Msg("Failed to get backfill queue length for monitoring")
+			}
+
 			m.logger.Info().
 				Int64("queue_length", queueLength).
 				Int("in_flight_wallets", len(inFlight)).
 				Int("active_workers", activeWorkers).
 				Int("healthy_endpoints", healthyEndpoints).
 				Msg("Queue monitoring stats")
+
+			if topErrors := m.rpcPool.TopErrorEndpoints(5); len(topErrors) > 0 {
+				m.logger.Warn().
+					Interface("top_error_endpoints", topErrors).
+					Msg("RPC endpoints with active error streaks")
+			}
+		}
+	}
+}
+
+// Workers returns a snapshot of the currently running workers, for the admin
+// API's scraper_listWorkers method.
+func (m *Manager) Workers() []*Worker {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	workers := make([]*Worker, len(m.workers))
+	copy(workers, m.workers)
+	return workers
+}
+
+// Queue exposes the manager's queue client, for the admin API's
+// scraper_pauseWallet/scraper_resumeWallet/scraper_backfill methods.
+func (m *Manager) Queue() *queue.Client {
+	return m.queue
+}
+
+// SetRPCEndpoints replaces the manager's RPC pool endpoints wholesale, for
+// the admin API's scraper_setRPCEndpoints method.
+func (m *Manager) SetRPCEndpoints(urls []string) {
+	m.rpcPool.SetEndpoints(urls)
+}
+
+// RequeueStuckWallets requeues wallets stuck in-flight for longer than
+// olderThanMinutes, for the admin API's queue_requeueStuck method. Wallets
+// that have already exceeded MaxWalletRequeues are routed to the DLQ
+// instead of being requeued again.
+func (m *Manager) RequeueStuckWallets(olderThanMinutes int) error {
+	return m.queue.RequeueStuckWallets(m.ctx, olderThanMinutes, m.config.MaxWalletRequeues)
+}
+
+// ListDLQ returns every wallet currently parked in the dead-letter queue,
+// for the admin API's queue_listDLQ method.
+func (m *Manager) ListDLQ() ([]queue.DLQEntry, error) {
+	return m.queue.ListDLQ(m.ctx)
+}
+
+// RetryDLQ pulls wallet out of the DLQ and back onto the main queue with a
+// clean requeue count, for the admin API's queue_retryDLQ method.
+func (m *Manager) RetryDLQ(wallet string) error {
+	return m.queue.RetryDLQWallet(m.ctx, wallet)
+}
+
+// PurgeDLQ clears every wallet out of the DLQ without requeuing them, for
+// the admin API's queue_purgeDLQ method.
+func (m *Manager) PurgeDLQ() error {
+	return m.queue.PurgeDLQ(m.ctx)
+}
+
+// SubscribeWallet requests low-latency websocket signature discovery for
+// wallet instead of relying solely on Worker's normal polling, for the
+// admin API's scraper_subscribeWallet method. It returns an error if
+// RPC_WS_INGESTION_ENABLED is off or no endpoint accepted the subscription
+// - in both cases the wallet keeps being served by ordinary polling.
+func (m *Manager) SubscribeWallet(wallet string) error {
+	if m.subscriber == nil {
+		return fmt.Errorf("websocket ingestion is disabled (set RPC_WS_INGESTION_ENABLED=true)")
+	}
+	return m.subscriber.Subscribe(wallet)
+}
+
+// UnsubscribeWallet removes wallet's websocket subscription, if any, for
+// the admin API's scraper_unsubscribeWallet method.
+func (m *Manager) UnsubscribeWallet(wallet string) error {
+	if m.subscriber == nil {
+		return fmt.Errorf("websocket ingestion is disabled (set RPC_WS_INGESTION_ENABLED=true)")
+	}
+	return m.subscriber.Unsubscribe(wallet)
+}
+
+// Resize scales the worker pool directly to n workers, for the admin API's
+// scraper_resizeWorkers method — an operator overriding the autoscaler
+// without waiting for the next 30s tick or restarting the process. n is
+// clamped to [MinWorkers, MaxWorkers]. The next scaling tick is free to
+// move the count again once it re-evaluates the PID controller.
+func (m *Manager) Resize(n int) error {
+	if n < m.config.MinWorkers {
+		n = m.config.MinWorkers
+	}
+	if n > m.config.MaxWorkers {
+		n = m.config.MaxWorkers
+	}
+
+	m.mutex.RLock()
+	current := len(m.workers)
+	m.mutex.RUnlock()
+
+	if n == current {
+		return nil
+	}
+	if n > current {
+		return m.addWorkers(n - current)
+	}
+	return m.removeWorkers(current - n)
+}
+
+// DrainWorker stops and removes a single named worker once it finishes its
+// current wallet, for the admin API's scraper_drainWorker method. Unlike
+// PauseWorker (which leaves the worker running but idle), a drained worker
+// is permanently retired from the pool; the next scaling tick will replace
+// it if demand still warrants it.
+func (m *Manager) DrainWorker(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i, w := range m.workers {
+		if w.id == id {
+			w.Stop()
+			m.workers = append(m.workers[:i], m.workers[i+1:]...)
+			metrics.WorkersActive.Set(float64(len(m.workers)))
+			m.logger.Info().Str("worker_id", id).Msg("Drained worker")
+			return nil
+		}
+	}
+	return fmt.Errorf("no worker with id %q", id)
+}
+
+// PauseWorker pauses the named worker, for the gRPC control plane's
+// PauseWorker method. Returns an error if no worker with that ID is running.
+func (m *Manager) PauseWorker(id string) error {
+	worker, err := m.workerByID(id)
+	if err != nil {
+		return err
+	}
+	worker.Pause()
+	return nil
+}
+
+// ResumeWorker resumes the named worker, for the gRPC control plane's
+// ResumeWorker method.
+func (m *Manager) ResumeWorker(id string) error {
+	worker, err := m.workerByID(id)
+	if err != nil {
+		return err
+	}
+	worker.Resume()
+	return nil
+}
+
+// PauseAllWorkers pauses every currently running worker, for the gRPC
+// query API's AdminService.PauseWorkers method - an operator-initiated
+// full stop that leaves each worker's in-flight wallet to finish rather
+// than interrupting it. It returns the number of workers paused.
+func (m *Manager) PauseAllWorkers() int {
+	m.mutex.RLock()
+	workers := make([]*Worker, len(m.workers))
+	copy(workers, m.workers)
+	m.mutex.RUnlock()
+
+	for _, w := range workers {
+		w.Pause()
+	}
+	return len(workers)
+}
+
+func (m *Manager) workerByID(id string) (*Worker, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, w := range m.workers {
+		if w.id == id {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("no worker with id %q", id)
+}
+
+// SubscribeEvents registers a new listener for worker lifecycle events, for
+// the gRPC control plane's StreamWorkerEvents method. The returned
+// unsubscribe function must be called when the caller is done listening; it
+// closes the channel and stops further delivery.
+func (m *Manager) SubscribeEvents() (<-chan Event, func()) {
+	m.eventMu.Lock()
+	defer m.eventMu.Unlock()
+
+	id := m.nextSubID
+	m.nextSubID++
+	ch := make(chan Event, 32)
+	m.eventSubs[id] = ch
+
+	unsubscribe := func() {
+		m.eventMu.Lock()
+		defer m.eventMu.Unlock()
+		if _, ok := m.eventSubs[id]; ok {
+			delete(m.eventSubs, id)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// publishEvent fans a worker event out to every active subscriber. A
+// subscriber that isn't keeping up has the event dropped rather than
+// blocking the worker that emitted it.
+func (m *Manager) publishEvent(e Event) {
+	m.eventMu.RLock()
+	defer m.eventMu.RUnlock()
+
+	for _, ch := range m.eventSubs {
+		select {
+		case ch <- e:
+		default:
 		}
 	}
 }
@@ -307,16 +791,19 @@ func (m *Manager) runQueueMonitoring() error {
 func (m *Manager) GetStats() map[string]interface{} {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	
+
 	queueLength, _ := m.queue.GetQueueLength(context.Background())
 	inFlight, _ := m.queue.GetInFlightWallets(context.Background())
-	
+
 	return map[string]interface{}{
-		"active_workers":     len(m.workers),
-		"queue_length":       queueLength,
-		"in_flight_wallets":  len(inFlight),
-		"healthy_endpoints":  m.rpcPool.GetHealthyEndpointCount(),
-		"min_workers":        m.config.MinWorkers,
-		"max_workers":        m.config.MaxWorkers,
-	}
-} 
\ No newline at end of file
+		"active_workers":                len(m.workers),
+		"queue_length":                  queueLength,
+		"in_flight_wallets":             len(inFlight),
+		"healthy_endpoints":             m.rpcPool.GetHealthyEndpointCount(),
+		"min_workers":                   m.config.MinWorkers,
+		"max_workers":                   m.config.MaxWorkers,
+		"avg_wallet_processing_seconds": m.averageProcessingTime().Seconds(),
+		"rpc_error_rate":                m.fetcher.ErrorRateEWMA(),
+		"rpc_endpoints":                 m.rpcPool.GetStats()["endpoints"],
+	}
+}
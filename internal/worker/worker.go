@@ -2,11 +2,16 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/metfin/core/rawchain"
 	"github.com/rs/zerolog"
+	"github.com/wnt/mercon/internal/backoff"
 	"github.com/wnt/mercon/internal/logger"
 	"github.com/wnt/mercon/internal/metrics"
 	"github.com/wnt/mercon/internal/queue"
@@ -15,27 +20,218 @@ import (
 
 // Worker represents a single wallet processing worker
 type Worker struct {
-	id       string
-	queue    *queue.Client
-	fetcher  *rpc.Fetcher
-	logger   zerolog.Logger
-	stopped  bool
+	id      string
+	queue   queue.Queue
+	fetcher *rpc.Fetcher
+	logger  zerolog.Logger
+	stopped bool
+	paused  bool
+
+	walletMutex   sync.RWMutex
+	currentWallet string
+
+	txsScraped  int64
+	parseErrors int64
+
+	processingTimeMutex sync.Mutex
+	processingTimeEWMA  time.Duration
+
+	publish func(Event)
+
+	// backoff, per failure class. Each grows its delay independently with
+	// consecutive failures and resets on success, so a run of RPC rate
+	// limits doesn't also inflate the delay DB write failures use.
+	queueEmptyBackoff   *backoff.Tracker
+	rateLimitBackoff    *backoff.Tracker
+	serverErrorBackoff  *backoff.Tracker
+	dbWriteBackoff      *backoff.Tracker
+	parseFailureBackoff *backoff.Tracker
 }
 
-// NewWorker creates a new worker instance
-func NewWorker(id string, queueClient *queue.Client, fetcher *rpc.Fetcher, baseLogger zerolog.Logger) *Worker {
+// failureClass identifies why an attempt failed, so Worker can pick the
+// backoff.Tracker tuned for that kind of failure.
+type failureClass int
+
+const (
+	failureUnknown failureClass = iota
+	failureRateLimit
+	failureServerError
+	failureDBWrite
+	failureParse
+)
+
+// classifyFailure inspects err's chain to decide which failureClass it
+// belongs to. Falls back to failureServerError for anything it doesn't
+// recognize, since that's the safest (exponential, capped) default.
+func classifyFailure(err error) failureClass {
+	var rateLimited *rpc.RateLimitedError
+	switch {
+	case errors.As(err, &rateLimited):
+		return failureRateLimit
+	case strings.Contains(err.Error(), "failed to insert transaction"):
+		return failureDBWrite
+	case strings.Contains(err.Error(), "failed to parse transaction"):
+		return failureParse
+	default:
+		return failureServerError
+	}
+}
+
+// EventType identifies a point in a worker's processWallet lifecycle, for
+// the gRPC control plane's StreamWorkerEvents method.
+type EventType string
+
+const (
+	EventStarted  EventType = "started"
+	EventFinished EventType = "finished"
+	EventFailed   EventType = "failed"
+)
+
+// Event is a single worker lifecycle notification.
+type Event struct {
+	WorkerID  string
+	Wallet    string
+	Type      EventType
+	Err       string
+	Timestamp time.Time
+}
+
+// Status is a point-in-time snapshot of a worker's activity, for the admin
+// API's scraper_listWorkers method.
+type Status struct {
+	WorkerID      string
+	CurrentWallet string
+	Paused        bool
+	TxsScraped    int64
+	ParseErrors   int64
+}
+
+// NewWorker creates a new worker instance. queueClient may be backed by
+// either queue.Client (ZSET) or queue.StreamClient (Redis Streams),
+// selected by Manager based on config.Config.QueueBackend.
+func NewWorker(id string, queueClient queue.Queue, fetcher *rpc.Fetcher, baseLogger zerolog.Logger) *Worker {
 	return &Worker{
 		id:      id,
 		queue:   queueClient,
 		fetcher: fetcher,
 		logger:  logger.WithWorker(baseLogger, id),
+
+		queueEmptyBackoff:   backoff.NewTracker(backoff.Constant{Delay: 10 * time.Second}),
+		rateLimitBackoff:    backoff.NewTracker(backoff.ExponentialWithJitter{Base: time.Second, Max: 60 * time.Second}),
+		serverErrorBackoff:  backoff.NewTracker(backoff.DecorrelatedJitter{Base: time.Second, Max: 60 * time.Second}),
+		dbWriteBackoff:      backoff.NewTracker(backoff.ExponentialWithJitter{Base: 500 * time.Millisecond, Max: 30 * time.Second}),
+		parseFailureBackoff: backoff.NewTracker(backoff.Constant{Delay: 1 * time.Second}),
 	}
 }
 
+// backoffFor returns the Tracker that handles the given failure class.
+func (w *Worker) backoffFor(class failureClass) *backoff.Tracker {
+	switch class {
+	case failureRateLimit:
+		return w.rateLimitBackoff
+	case failureDBWrite:
+		return w.dbWriteBackoff
+	case failureParse:
+		return w.parseFailureBackoff
+	default:
+		return w.serverErrorBackoff
+	}
+}
+
+// succeed resets every failure-class tracker, since a clean wallet
+// processing run means whatever was previously failing has recovered.
+func (w *Worker) succeed() {
+	w.rateLimitBackoff.Succeed()
+	w.serverErrorBackoff.Succeed()
+	w.dbWriteBackoff.Succeed()
+	w.parseFailureBackoff.Succeed()
+}
+
+// processingTimeEWMAAlpha weights how much a single wallet's processing
+// duration moves the Worker's rolling average, the same smoothing
+// rpc.Pool's latencyEWMA uses for endpoint latency.
+const processingTimeEWMAAlpha = 0.2
+
+// recordProcessingTime folds duration into the Worker's rolling average
+// wallet processing time, regardless of whether the wallet succeeded -
+// Manager's autoscaler cares how long a worker is tied up either way.
+func (w *Worker) recordProcessingTime(duration time.Duration) {
+	w.processingTimeMutex.Lock()
+	if w.processingTimeEWMA == 0 {
+		w.processingTimeEWMA = duration
+	} else {
+		w.processingTimeEWMA = time.Duration(processingTimeEWMAAlpha*float64(duration) + (1-processingTimeEWMAAlpha)*float64(w.processingTimeEWMA))
+	}
+	w.processingTimeMutex.Unlock()
+}
+
+// ProcessingTimeEWMA returns this worker's rolling average wallet
+// processing time, for Manager.GetStats and the autoscaler's queue-drain
+// estimate.
+func (w *Worker) ProcessingTimeEWMA() time.Duration {
+	w.processingTimeMutex.Lock()
+	defer w.processingTimeMutex.Unlock()
+	return w.processingTimeEWMA
+}
+
+// Status returns a snapshot of this worker's current activity.
+func (w *Worker) Status() Status {
+	w.walletMutex.RLock()
+	wallet := w.currentWallet
+	w.walletMutex.RUnlock()
+
+	return Status{
+		WorkerID:      w.id,
+		CurrentWallet: wallet,
+		Paused:        w.paused,
+		TxsScraped:    atomic.LoadInt64(&w.txsScraped),
+		ParseErrors:   atomic.LoadInt64(&w.parseErrors),
+	}
+}
+
+func (w *Worker) setCurrentWallet(wallet string) {
+	w.walletMutex.Lock()
+	w.currentWallet = wallet
+	w.walletMutex.Unlock()
+}
+
+// SetEventPublisher registers a callback invoked with every started/
+// finished/failed event this worker emits while processing wallets, for the
+// gRPC control plane's StreamWorkerEvents method. Manager wires this to its
+// own fan-out after constructing the worker.
+func (w *Worker) SetEventPublisher(publish func(Event)) {
+	w.publish = publish
+}
+
+func (w *Worker) emit(wallet string, eventType EventType, err error) {
+	if w.publish == nil {
+		return
+	}
+	event := Event{WorkerID: w.id, Wallet: wallet, Type: eventType, Timestamp: time.Now()}
+	if err != nil {
+		event.Err = err.Error()
+	}
+	w.publish(event)
+}
+
+// Pause tells the worker to stop picking up new wallets until Resume is
+// called, for the gRPC control plane's PauseWorker method. Unlike Stop this
+// isn't terminal - the Start loop just idles instead of returning.
+func (w *Worker) Pause() {
+	w.paused = true
+	w.logger.Info().Msg("Worker paused")
+}
+
+// Resume clears a pause set by Pause.
+func (w *Worker) Resume() {
+	w.paused = false
+	w.logger.Info().Msg("Worker resumed")
+}
+
 // Start begins the worker processing loop
 func (w *Worker) Start(ctx context.Context) error {
 	w.logger.Info().Msg("Starting worker")
-	
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -46,17 +242,40 @@ func (w *Worker) Start(ctx context.Context) error {
 				w.logger.Info().Msg("Worker stopped")
 				return nil
 			}
-			
+
+			if w.paused {
+				select {
+				case <-time.After(1 * time.Second):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				continue
+			}
+
+			// Backfill jobs take priority over the normal queue: they're
+			// operator-triggered and rare, so draining one before the next
+			// regular wallet keeps its latency predictable.
+			job, err := w.queue.PopBackfillJob(ctx)
+			if err != nil {
+				w.logger.Error().Err(err).Msg("Failed to pop backfill job")
+			} else if job != nil {
+				if err := w.processBackfillJob(ctx, job); err != nil {
+					w.logger.Error().Err(err).Str("wallet", job.Wallet).Msg("Failed to process backfill job")
+				}
+				continue
+			}
+
 			// Process a single wallet
 			if err := w.processWallet(ctx); err != nil {
 				w.logger.Error().Err(err).Msg("Failed to process wallet")
 				// Continue processing other wallets even if one fails
-				
-				// Brief pause to avoid tight error loops
-				select {
-				case <-time.After(5 * time.Second):
-				case <-ctx.Done():
-					return ctx.Err()
+
+				// Back off based on why it failed, so a run of rate limits
+				// grows the delay independently of DB or parse failures.
+				class := classifyFailure(err)
+				delay := w.backoffFor(class).Next()
+				if sleepErr := backoff.Sleep(ctx, delay); sleepErr != nil {
+					return sleepErr
 				}
 			}
 		}
@@ -76,18 +295,30 @@ func (w *Worker) processWallet(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to pop wallet from queue: %w", err)
 	}
-	
+
 	// No wallet available
 	if wallet == "" {
 		// Brief pause when queue is empty to avoid spinning
-		select {
-		case <-time.After(10 * time.Second):
-		case <-ctx.Done():
-			return ctx.Err()
+		if err := backoff.Sleep(ctx, w.queueEmptyBackoff.Next()); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// A paused wallet is put back in the queue deprioritized rather than
+	// processed, so an operator's scraper_pauseWallet call takes effect
+	// immediately instead of waiting for the current queue position to
+	// cycle back around.
+	paused, err := w.queue.IsWalletPaused(ctx, wallet)
+	if err != nil {
+		w.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to check wallet paused status")
+	} else if paused {
+		if requeueErr := w.queue.PushWallet(ctx, wallet, float64(time.Now().Add(time.Minute).Unix())); requeueErr != nil {
+			w.logger.Error().Err(requeueErr).Str("wallet", wallet).Msg("Failed to requeue paused wallet")
 		}
 		return nil
 	}
-	
+
 	// Mark wallet as in-flight
 	if err := w.queue.SetInFlight(ctx, wallet, w.id); err != nil {
 		w.logger.Error().Err(err).Str("wallet", wallet).Msg("Failed to mark wallet as in-flight")
@@ -97,37 +328,52 @@ func (w *Worker) processWallet(ctx context.Context) error {
 		}
 		return err
 	}
-	
+
 	walletLogger := logger.WithWallet(w.logger, wallet)
 	startTime := time.Now()
-	
+
 	walletLogger.Info().Msg("Starting wallet processing")
-	
+
+	w.setCurrentWallet(wallet)
+	w.emit(wallet, EventStarted, nil)
+
 	// Process the wallet
 	err = w.scrapeWallet(ctx, wallet, walletLogger)
 	duration := time.Since(startTime)
-	
+
+	w.setCurrentWallet("")
+	w.recordProcessingTime(duration)
+
 	// Record metrics
 	metrics.RecordWalletScrape(duration.Seconds())
 	metrics.RecordWorkerTaskDuration("wallet_scrape", w.id, duration.Seconds())
-	
+
 	// Remove from in-flight tracking
 	if removeErr := w.queue.RemoveInFlight(ctx, wallet); removeErr != nil {
 		walletLogger.Error().Err(removeErr).Msg("Failed to remove wallet from in-flight tracking")
 	}
-	
+
 	if err != nil {
 		walletLogger.Error().Err(err).Dur("duration", duration).Msg("Failed to process wallet")
-		
+		w.emit(wallet, EventFailed, err)
+
+		// Record the error so a DLQ entry this wallet later earns can show
+		// root cause without database spelunking.
+		if recordErr := w.queue.RecordWalletError(ctx, wallet, err.Error()); recordErr != nil {
+			walletLogger.Error().Err(recordErr).Msg("Failed to record wallet error")
+		}
+
 		// Re-queue with lower priority (higher score) on failure
 		if requeueErr := w.queue.PushWallet(ctx, wallet, float64(time.Now().Unix())); requeueErr != nil {
 			walletLogger.Error().Err(requeueErr).Msg("Failed to requeue failed wallet")
 		}
-		
+
 		return fmt.Errorf("wallet processing failed: %w", err)
 	}
-	
+
 	walletLogger.Info().Dur("duration", duration).Msg("Wallet processing completed successfully")
+	w.emit(wallet, EventFinished, nil)
+	w.succeed()
 	return nil
 }
 
@@ -138,75 +384,58 @@ func (w *Worker) scrapeWallet(ctx context.Context, wallet string, logger zerolog
 	if err != nil {
 		return fmt.Errorf("failed to get wallet progress: %w", err)
 	}
-	
+
 	if lastSig != "" {
 		logger.Debug().Str("last_signature", lastSig).Msg("Resuming from last processed signature")
 	} else {
 		logger.Debug().Msg("Starting fresh wallet scrape")
 	}
-	
+
 	// Fetch signatures in batches
 	const batchSize = 1000
 	processedCount := 0
 	before := ""
-	
+
 	// If we have a last signature, start from there
 	if lastSig != "" {
 		before = lastSig
 	}
-	
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
-		
+
 		// Fetch batch of signatures
 		signatures, err := w.fetcher.FetchSignatures(ctx, wallet, before, batchSize)
 		if err != nil {
 			return fmt.Errorf("failed to fetch signatures: %w", err)
 		}
-		
+
 		// No more signatures
 		if len(signatures) == 0 {
 			break
 		}
-		
+
 		logger.Debug().Int("signatures", len(signatures)).Msg("Fetched signature batch")
-		
-		// Process each signature in the batch
+
+		// Skip the last processed signature (avoid duplicates) before handing
+		// the rest off for processing.
+		pending := make([]string, 0, len(signatures))
 		for _, signature := range signatures {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-			
-			// Skip if this is the last processed signature (avoid duplicates)
-			if signature == lastSig {
-				continue
-			}
-			
-			if err := w.processTransaction(ctx, signature, logger); err != nil {
-				logger.Warn().Err(err).Str("signature", signature).Msg("Failed to process transaction, continuing")
-				metrics.RecordTransactionProcessed("failed")
-				continue
-			}
-			
-			metrics.RecordTransactionProcessed("success")
-			processedCount++
-			
-			// Update progress every 100 transactions
-			if processedCount%100 == 0 {
-				if err := w.queue.SetProgress(ctx, wallet, signature); err != nil {
-					logger.Warn().Err(err).Str("signature", signature).Msg("Failed to update progress")
-				} else {
-					logger.Debug().Int("processed", processedCount).Str("signature", signature).Msg("Progress updated")
-				}
+			if signature != lastSig {
+				pending = append(pending, signature)
 			}
 		}
-		
+
+		newCount, err := w.processTransactions(ctx, wallet, pending, processedCount, logger)
+		processedCount = newCount
+		if err != nil {
+			return err
+		}
+
 		// Prepare for next batch
 		if len(signatures) > 0 {
 			before = signatures[len(signatures)-1]
@@ -215,12 +444,12 @@ func (w *Worker) scrapeWallet(ctx context.Context, wallet string, logger zerolog
 				logger.Warn().Err(err).Str("signature", before).Msg("Failed to update final progress")
 			}
 		}
-		
+
 		// If we got fewer signatures than batch size, we're done
 		if len(signatures) < batchSize {
 			break
 		}
-		
+
 		// Brief pause between batches to be nice to RPC endpoints
 		select {
 		case <-time.After(100 * time.Millisecond):
@@ -228,23 +457,99 @@ func (w *Worker) scrapeWallet(ctx context.Context, wallet string, logger zerolog
 			return ctx.Err()
 		}
 	}
-	
+
 	logger.Info().Int("total_processed", processedCount).Msg("Wallet scraping completed")
 	return nil
 }
 
+// minSignaturesForBatchFetch is how many pending signatures a fetched
+// signature page needs before processTransactions switches from
+// Fetcher.FetchTransaction's one-POST-per-signature path to
+// Fetcher.FetchTransactionsBatch. Below this it's not worth the extra
+// bookkeeping a batch response correlates back through.
+const minSignaturesForBatchFetch = 10
+
+// processTransactions fetches and inserts signatures, batching the RPC
+// round trip via Fetcher.FetchTransactionsBatch once there are enough
+// pending signatures to make it worthwhile, and falling back to
+// processTransaction's single-signature path otherwise (and for any
+// signature the batch response didn't return). startCount is the wallet's
+// processedCount so far, so progress still gets written to the queue every
+// 100 transactions across batches. Returns the updated processedCount.
+func (w *Worker) processTransactions(ctx context.Context, wallet string, signatures []string, startCount int, logger zerolog.Logger) (int, error) {
+	var txs map[string]*rpc.RpcTransaction
+	if len(signatures) >= minSignaturesForBatchFetch {
+		var err error
+		txs, err = w.fetcher.FetchTransactionsBatch(ctx, signatures)
+		if err != nil {
+			logger.Warn().Err(err).Int("signatures", len(signatures)).Msg("Failed to fetch transaction batch, falling back to per-signature fetch")
+			txs = nil
+		}
+	}
+
+	processedCount := startCount
+	for _, signature := range signatures {
+		select {
+		case <-ctx.Done():
+			return processedCount, ctx.Err()
+		default:
+		}
+
+		var procErr error
+		if rpcTx, ok := txs[signature]; ok {
+			_, procErr = w.insertRPCTransaction(ctx, signature, rpcTx, logger)
+		} else {
+			procErr = w.processTransaction(ctx, signature, logger)
+		}
+
+		if procErr != nil {
+			logger.Warn().Err(procErr).Str("signature", signature).Msg("Failed to process transaction, continuing")
+			metrics.RecordTransactionProcessed("failed")
+
+			class := classifyFailure(procErr)
+			if sleepErr := backoff.Sleep(ctx, w.backoffFor(class).Next()); sleepErr != nil {
+				return processedCount, sleepErr
+			}
+			continue
+		}
+
+		metrics.RecordTransactionProcessed("success")
+		w.succeed()
+		processedCount++
+
+		// Update progress every 100 transactions
+		if processedCount%100 == 0 {
+			if err := w.queue.SetProgress(ctx, wallet, signature); err != nil {
+				logger.Warn().Err(err).Str("signature", signature).Msg("Failed to update progress")
+			} else {
+				logger.Debug().Int("processed", processedCount).Str("signature", signature).Msg("Progress updated")
+			}
+		}
+	}
+
+	return processedCount, nil
+}
+
 // processTransaction fetches and processes a single transaction
 func (w *Worker) processTransaction(ctx context.Context, signature string, logger zerolog.Logger) error {
-	// Fetch the transaction details
 	rpcTx, err := w.fetcher.FetchTransaction(ctx, signature)
 	if err != nil {
 		return fmt.Errorf("failed to fetch transaction %s: %w", signature, err)
 	}
-	
+
 	if rpcTx == nil {
 		return fmt.Errorf("transaction %s not found", signature)
 	}
-	
+
+	_, err = w.insertRPCTransaction(ctx, signature, rpcTx, logger)
+	return err
+}
+
+// insertRPCTransaction parses an already-fetched RPC transaction and inserts
+// it into the raw chain database. It is shared by processTransaction and
+// processBackfillJob so a backfill doesn't need its own copy of the
+// parse/insert/log sequence.
+func (w *Worker) insertRPCTransaction(ctx context.Context, signature string, rpcTx *rpc.RpcTransaction, logger zerolog.Logger) (*rawchain.Transaction, error) {
 	// Convert RPC transaction to raw chain format
 	rawTx := map[string]interface{}{
 		"slot":        rpcTx.Slot,
@@ -252,18 +557,21 @@ func (w *Worker) processTransaction(ctx context.Context, signature string, logge
 		"transaction": rpcTx.Transaction,
 		"meta":        rpcTx.Meta,
 	}
-	
+
 	// Parse RPC transaction for insertion
 	chainTx, err := parseRPCTransactionForInsertion(rawTx)
 	if err != nil {
-		return fmt.Errorf("failed to parse transaction %s: %w", signature, err)
+		atomic.AddInt64(&w.parseErrors, 1)
+		return nil, fmt.Errorf("failed to parse transaction %s: %w", signature, err)
 	}
-	
+
 	// Insert into raw chain database
 	if err := insertTransactionToRawChain(ctx, chainTx); err != nil {
-		return fmt.Errorf("failed to insert transaction %s: %w", signature, err)
+		return nil, fmt.Errorf("failed to insert transaction %s: %w", signature, err)
 	}
-	
+
+	atomic.AddInt64(&w.txsScraped, 1)
+
 	logger.Debug().
 		Str("signature", signature).
 		Uint64("slot", rpcTx.Slot).
@@ -271,7 +579,95 @@ func (w *Worker) processTransaction(ctx context.Context, signature string, logge
 		Int("instructions", len(chainTx.Instructions)).
 		Int("token_balances", len(chainTx.TokenBalances)).
 		Msg("Transaction processed and inserted successfully")
-	
+
+	return chainTx, nil
+}
+
+// processBackfillJob walks a wallet's signature history newest-first,
+// inserting every transaction whose slot falls within [job.FromSlot,
+// job.ToSlot], for the admin API's scraper_backfill method. FetchSignatures
+// only returns bare signatures, so each one has to be fetched to learn its
+// slot before it can be filtered - more RPC calls than the normal forward
+// scrape, but backfills are operator-triggered and rare.
+func (w *Worker) processBackfillJob(ctx context.Context, job *queue.BackfillJob) error {
+	backfillLogger := logger.WithWallet(w.logger, job.Wallet)
+	backfillLogger.Info().
+		Uint64("from_slot", job.FromSlot).
+		Uint64("to_slot", job.ToSlot).
+		Msg("Starting backfill job")
+
+	const batchSize = 1000
+	before := ""
+	processedCount := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		signatures, err := w.fetcher.FetchSignatures(ctx, job.Wallet, before, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch signatures for backfill: %w", err)
+		}
+		if len(signatures) == 0 {
+			break
+		}
+
+		var txs map[string]*rpc.RpcTransaction
+		if len(signatures) >= minSignaturesForBatchFetch {
+			var err error
+			txs, err = w.fetcher.FetchTransactionsBatch(ctx, signatures)
+			if err != nil {
+				backfillLogger.Warn().Err(err).Int("signatures", len(signatures)).Msg("Failed to fetch transaction batch during backfill, falling back to per-signature fetch")
+				txs = nil
+			}
+		}
+
+		for _, signature := range signatures {
+			rpcTx, ok := txs[signature]
+			if !ok {
+				var err error
+				rpcTx, err = w.fetcher.FetchTransaction(ctx, signature)
+				if err != nil {
+					backfillLogger.Warn().Err(err).Str("signature", signature).Msg("Failed to fetch transaction during backfill, continuing")
+					continue
+				}
+			}
+			if rpcTx == nil {
+				continue
+			}
+
+			if rpcTx.Slot > job.ToSlot {
+				continue
+			}
+			if rpcTx.Slot < job.FromSlot {
+				backfillLogger.Info().Int("processed", processedCount).Msg("Backfill reached target slot range, stopping")
+				return nil
+			}
+
+			if _, err := w.insertRPCTransaction(ctx, signature, rpcTx, backfillLogger); err != nil {
+				backfillLogger.Warn().Err(err).Str("signature", signature).Msg("Failed to insert transaction during backfill, continuing")
+				continue
+			}
+			processedCount++
+		}
+
+		before = signatures[len(signatures)-1]
+
+		if len(signatures) < batchSize {
+			break
+		}
+
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	backfillLogger.Info().Int("processed", processedCount).Msg("Backfill job completed")
 	return nil
 }
 
@@ -286,7 +682,7 @@ func insertTransactionToRawChain(ctx context.Context, tx *rawchain.Transaction)
 		metrics.RecordDatabaseOperation("insert", "failed")
 		return err
 	}
-	
+
 	metrics.RecordDatabaseOperation("insert", "success")
 	return nil
-} 
\ No newline at end of file
+}